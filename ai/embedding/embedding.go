@@ -20,6 +20,10 @@ type Embedding struct {
 
 	// Usage contains token usage statistics
 	Usage Usage
+
+	// Provider names the embedder that produced the vector when it was chosen
+	// by a router such as FallbackEmbedder
+	Provider string
 }
 
 // Usage represents token usage statistics for embeddings
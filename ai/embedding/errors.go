@@ -0,0 +1,38 @@
+package embedding
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// Registry is the error registry for embedding operations
+var Registry = errx.NewRegistry("EMBEDDING")
+
+// Error codes for embedding operations
+var (
+	ErrDimensionMismatch = Registry.Register(
+		"DIMENSION_MISMATCH",
+		errx.TypeValidation,
+		http.StatusUnprocessableEntity,
+		"Embedding dimensions differ from the expected dimensions",
+	)
+
+	ErrAllEmbeddersFailed = Registry.Register(
+		"ALL_EMBEDDERS_FAILED",
+		errx.TypeUnavailable,
+		http.StatusServiceUnavailable,
+		"Every embedder failed",
+	)
+)
+
+// IsDimensionMismatch reports whether an embedder returned vectors of another
+// size than the index expects. Mixing them would corrupt similarity search.
+func IsDimensionMismatch(err error) bool {
+	return errx.IsCode(err, ErrDimensionMismatch)
+}
+
+// IsAllEmbeddersFailed reports whether a FallbackEmbedder ran out of embedders
+func IsAllEmbeddersFailed(err error) bool {
+	return errx.IsCode(err, ErrAllEmbeddersFailed)
+}
@@ -0,0 +1,152 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// FallbackEmbedder tries its embedders in order, moving on when one fails
+// with a retryable error. All embedders must produce vectors of the same
+// dimensions: the first successful response fixes them (unless set with
+// WithExpectedDimensions) and any embedder answering with other dimensions
+// fails with ErrDimensionMismatch instead of silently mixing vector spaces.
+type FallbackEmbedder struct {
+	embedders []Embedder
+	options   [][]Option
+	retryable func(error) bool
+
+	dimensionsMutex sync.Mutex
+	dimensions      int
+}
+
+// NewFallbackEmbedder creates an embedder that falls back through embedders in order
+func NewFallbackEmbedder(embedders ...Embedder) *FallbackEmbedder {
+	return &FallbackEmbedder{
+		embedders: embedders,
+		options:   make([][]Option, len(embedders)),
+		retryable: IsRetryable,
+	}
+}
+
+// WithEmbedderOptions sets options applied only when calling the embedder at
+// index, after the caller's options. Use it to pick each provider's model, and
+// its dimensions where the model supports shortening them.
+func (f *FallbackEmbedder) WithEmbedderOptions(index int, opts ...Option) *FallbackEmbedder {
+	if index >= 0 && index < len(f.options) {
+		f.options[index] = append(f.options[index], opts...)
+	}
+	return f
+}
+
+// WithExpectedDimensions fixes the vector size up front, e.g. to the
+// dimensions of an existing index
+func (f *FallbackEmbedder) WithExpectedDimensions(dimensions int) *FallbackEmbedder {
+	f.dimensions = dimensions
+	return f
+}
+
+// WithRetryable replaces the rule deciding which errors fall back to the next
+// embedder. The default is IsRetryable.
+func (f *FallbackEmbedder) WithRetryable(retryable func(error) bool) *FallbackEmbedder {
+	f.retryable = retryable
+	return f
+}
+
+// EmbedDocuments embeds documents with the first embedder that succeeds
+func (f *FallbackEmbedder) EmbedDocuments(ctx context.Context, documents []string, opts ...Option) ([]Embedding, error) {
+	return fallback(f, ctx, func(e Embedder, opts []Option) ([]Embedding, error) {
+		return e.EmbedDocuments(ctx, documents, opts...)
+	}, opts)
+}
+
+// EmbedQuery embeds text with the first embedder that succeeds
+func (f *FallbackEmbedder) EmbedQuery(ctx context.Context, text string, opts ...Option) (Embedding, error) {
+	embeddings, err := fallback(f, ctx, func(e Embedder, opts []Option) ([]Embedding, error) {
+		embedding, err := e.EmbedQuery(ctx, text, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return []Embedding{embedding}, nil
+	}, opts)
+	if err != nil {
+		return Embedding{}, err
+	}
+	return embeddings[0], nil
+}
+
+// fallback runs call against each embedder until one succeeds or fails with a
+// non-retryable error
+func fallback(f *FallbackEmbedder, ctx context.Context, call func(Embedder, []Option) ([]Embedding, error), opts []Option) ([]Embedding, error) {
+	var failures []string
+	var lastErr error
+
+	for i, embedder := range f.embedders {
+		embeddings, err := call(embedder, append(append([]Option{}, opts...), f.options[i]...))
+		if err == nil {
+			if err := f.checkDimensions(i, embeddings); err != nil {
+				return nil, err
+			}
+			for j := range embeddings {
+				if embeddings[j].Provider == "" {
+					embeddings[j].Provider = embedderName(embedder)
+				}
+			}
+			return embeddings, nil
+		}
+
+		if ctx.Err() != nil || !f.retryable(err) {
+			return nil, err
+		}
+		lastErr = err
+		failures = append(failures, fmt.Sprintf("%s: %v", embedderName(embedder), err))
+	}
+
+	return nil, Registry.New(ErrAllEmbeddersFailed).
+		WithCause(lastErr).
+		WithDetail("failures", failures)
+}
+
+// checkDimensions verifies every vector has the expected size, fixing it from
+// the first successful response when it isn't configured
+func (f *FallbackEmbedder) checkDimensions(index int, embeddings []Embedding) error {
+	f.dimensionsMutex.Lock()
+	defer f.dimensionsMutex.Unlock()
+
+	for _, embedding := range embeddings {
+		if f.dimensions == 0 {
+			f.dimensions = len(embedding.Vector)
+		}
+		if len(embedding.Vector) != f.dimensions {
+			return Registry.New(ErrDimensionMismatch).
+				WithDetail("embedder", embedderName(f.embedders[index])).
+				WithDetail("expected", f.dimensions).
+				WithDetail("actual", len(embedding.Vector))
+		}
+	}
+	return nil
+}
+
+// embedderName identifies an embedder by its Name method or its type
+func embedderName(e Embedder) string {
+	if named, ok := e.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", e)
+}
+
+// IsRetryable reports whether another embedder may succeed where err failed.
+// Cancellation and errx validation or bad-request errors are not retryable,
+// since every provider would reject the same input; anything else is.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if IsDimensionMismatch(err) {
+		return false
+	}
+	return !errx.IsType(err, errx.TypeValidation) && !errx.IsType(err, errx.TypeBadRequest)
+}
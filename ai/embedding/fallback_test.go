@@ -0,0 +1,167 @@
+package embedding_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/embedding"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// fakeEmbedder answers every call with err, or with vector
+type fakeEmbedder struct {
+	name   string
+	vector []float32
+	err    error
+
+	calls   int
+	options embedding.EmbeddingOptions
+}
+
+func (f *fakeEmbedder) Name() string { return f.name }
+
+func (f *fakeEmbedder) EmbedDocuments(ctx context.Context, documents []string, opts ...embedding.Option) ([]embedding.Embedding, error) {
+	f.calls++
+	f.options = embedding.EmbeddingOptions{}
+	for _, opt := range opts {
+		opt(&f.options)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	embeddings := make([]embedding.Embedding, len(documents))
+	for i := range documents {
+		embeddings[i] = embedding.Embedding{
+			Vector: f.vector,
+			Usage:  embedding.Usage{PromptTokens: 3, TotalTokens: 3},
+		}
+	}
+	return embeddings, nil
+}
+
+func (f *fakeEmbedder) EmbedQuery(ctx context.Context, text string, opts ...embedding.Option) (embedding.Embedding, error) {
+	embeddings, err := f.EmbedDocuments(ctx, []string{text}, opts...)
+	if err != nil {
+		return embedding.Embedding{}, err
+	}
+	return embeddings[0], nil
+}
+
+func TestFallbackEmbedder(t *testing.T) {
+	unavailable := errx.New("provider unavailable", errx.TypeUnavailable)
+	invalid := errx.New("input too long", errx.TypeValidation)
+
+	tests := []struct {
+		name         string
+		primary      *fakeEmbedder
+		secondary    *fakeEmbedder
+		wantVector   []float32
+		wantProvider string
+		wantErr      func(error) bool
+		wantCalls    [2]int
+	}{
+		{
+			name:         "primary succeeds",
+			primary:      &fakeEmbedder{name: "primary", vector: []float32{1, 2, 3}},
+			secondary:    &fakeEmbedder{name: "secondary", vector: []float32{4, 5, 6}},
+			wantVector:   []float32{1, 2, 3},
+			wantProvider: "primary",
+			wantCalls:    [2]int{1, 0},
+		},
+		{
+			name:         "failing primary falls back to secondary",
+			primary:      &fakeEmbedder{name: "primary", err: unavailable},
+			secondary:    &fakeEmbedder{name: "secondary", vector: []float32{4, 5, 6}},
+			wantVector:   []float32{4, 5, 6},
+			wantProvider: "secondary",
+			wantCalls:    [2]int{1, 1},
+		},
+		{
+			name:      "non-retryable error stops the fallback",
+			primary:   &fakeEmbedder{name: "primary", err: invalid},
+			secondary: &fakeEmbedder{name: "secondary", vector: []float32{4, 5, 6}},
+			wantErr:   func(err error) bool { return errors.Is(err, invalid) },
+			wantCalls: [2]int{1, 0},
+		},
+		{
+			name:      "every embedder fails",
+			primary:   &fakeEmbedder{name: "primary", err: unavailable},
+			secondary: &fakeEmbedder{name: "secondary", err: unavailable},
+			wantErr:   embedding.IsAllEmbeddersFailed,
+			wantCalls: [2]int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embedder := embedding.NewFallbackEmbedder(tt.primary, tt.secondary)
+
+			got, err := embedder.EmbedQuery(context.Background(), "hello")
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("err = %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("EmbedQuery: %v", err)
+				}
+				if !reflect.DeepEqual(got.Vector, tt.wantVector) {
+					t.Errorf("vector = %v, want %v", got.Vector, tt.wantVector)
+				}
+				if got.Provider != tt.wantProvider {
+					t.Errorf("provider = %q, want %q", got.Provider, tt.wantProvider)
+				}
+				if got.Usage.TotalTokens != 3 {
+					t.Errorf("usage = %+v, want the answering embedder's usage", got.Usage)
+				}
+			}
+
+			if calls := [2]int{tt.primary.calls, tt.secondary.calls}; calls != tt.wantCalls {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestFallbackEmbedderDimensionMismatch(t *testing.T) {
+	unavailable := errx.New("provider unavailable", errx.TypeUnavailable)
+	primary := &fakeEmbedder{name: "primary", vector: []float32{1, 2, 3}}
+	secondary := &fakeEmbedder{name: "secondary", vector: []float32{4, 5}}
+	embedder := embedding.NewFallbackEmbedder(primary, secondary)
+
+	if _, err := embedder.EmbedDocuments(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatalf("EmbedDocuments: %v", err)
+	}
+
+	primary.err = unavailable
+	_, err := embedder.EmbedDocuments(context.Background(), []string{"a"})
+	if !embedding.IsDimensionMismatch(err) {
+		t.Fatalf("err = %v, want a dimension mismatch", err)
+	}
+
+	fixed := embedding.NewFallbackEmbedder(secondary).WithExpectedDimensions(3)
+	if _, err := fixed.EmbedQuery(context.Background(), "a"); !embedding.IsDimensionMismatch(err) {
+		t.Fatalf("err = %v, want a dimension mismatch against the expected dimensions", err)
+	}
+}
+
+func TestFallbackEmbedderOptions(t *testing.T) {
+	primary := &fakeEmbedder{name: "primary", err: errx.New("rate limited", errx.TypeUnavailable)}
+	secondary := &fakeEmbedder{name: "secondary", vector: []float32{1}}
+	embedder := embedding.NewFallbackEmbedder(primary, secondary).
+		WithEmbedderOptions(0, embedding.WithModel("text-embedding-3-small")).
+		WithEmbedderOptions(1, embedding.WithModel("embed-english-v3.0"))
+
+	if _, err := embedder.EmbedQuery(context.Background(), "a", embedding.WithUser("u1")); err != nil {
+		t.Fatalf("EmbedQuery: %v", err)
+	}
+
+	if want := (embedding.EmbeddingOptions{Model: "text-embedding-3-small", User: "u1"}); primary.options != want {
+		t.Errorf("primary options = %+v, want %+v", primary.options, want)
+	}
+	if want := (embedding.EmbeddingOptions{Model: "embed-english-v3.0", User: "u1"}); secondary.options != want {
+		t.Errorf("secondary options = %+v, want %+v", secondary.options, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/Abraxas-365/craftable/ai/llm"
 	"github.com/Abraxas-365/craftable/ai/llm/memoryx"
@@ -19,6 +20,9 @@ type Agent struct {
 	options            []llm.Option
 	maxAutoIterations  int // Max iterations with "auto" tool choice
 	maxTotalIterations int // Hard limit to prevent infinite loops
+	toolOutputLimit    int // Max characters of a tool result kept in the conversation (0 = unlimited)
+	summarizer         ToolOutputSummarizer
+	toolTimeout        time.Duration // Max duration of a single tool call (0 = unlimited)
 }
 
 // AgentOption configures an Agent
@@ -52,6 +56,34 @@ func WithMaxTotalIterations(max int) AgentOption {
 	}
 }
 
+// WithToolOutputLimit caps how many characters of each tool result are added
+// to the conversation, so one verbose tool can't exhaust the context window.
+// Longer results are truncated with a "[truncated]" marker, or summarized when
+// WithToolOutputSummarizer is set. As a rule of thumb, 4 characters ≈ 1 token.
+func WithToolOutputLimit(maxChars int) AgentOption {
+	return func(a *Agent) {
+		a.toolOutputLimit = maxChars
+	}
+}
+
+// WithToolOutputSummarizer summarizes tool results over the output limit
+// instead of truncating them. If summarizing fails, or the summary is still too
+// long, the result is truncated.
+func WithToolOutputSummarizer(summarizer ToolOutputSummarizer) AgentOption {
+	return func(a *Agent) {
+		a.summarizer = summarizer
+	}
+}
+
+// WithToolTimeout bounds each tool call. The tool's context is cancelled after
+// the timeout and a timeout error is recorded as its response, so a slow or
+// hanging tool can't stall the agent and the model can retry or move on.
+func WithToolTimeout(timeout time.Duration) AgentOption {
+	return func(a *Agent) {
+		a.toolTimeout = timeout
+	}
+}
+
 // New creates a new agent
 func New(client llm.Client, memory memoryx.Memory, opts ...AgentOption) *Agent {
 	agent := &Agent{
@@ -152,10 +184,11 @@ func (a *Agent) handleToolCallsWithLimit(ctx context.Context, toolCalls []llm.To
 	// Process each tool call
 	for _, tc := range toolCalls {
 		// Call the tool
-		toolResponse, err := a.tools.Call(ctx, tc)
+		toolResponse, err := a.callTool(ctx, tc)
 		if err != nil {
 			return "", fmt.Errorf("tool execution error: %w", err)
 		}
+		toolResponse, _ = a.limitToolOutput(ctx, tc, toolResponse)
 
 		// Add tool response to memory
 		if err := a.memory.Add(toolResponse); err != nil {
@@ -330,6 +363,7 @@ func (a *Agent) RunConversation(ctx context.Context, userInputs []string) ([]str
 
 // EvaluateWithTools runs the agent with tools and returns detailed execution info
 func (a *Agent) EvaluateWithTools(ctx context.Context, userInput string) (*AgentEvaluation, error) {
+	start := time.Now()
 	eval := &AgentEvaluation{
 		UserInput: userInput,
 		Steps:     []AgentStep{},
@@ -362,7 +396,9 @@ func (a *Agent) EvaluateWithTools(ctx context.Context, userInput string) (*Agent
 	}
 
 	// Get response from LLM
+	llmStart := time.Now()
 	response, err := a.client.Chat(ctx, messages, options...)
+	evalStep.Duration = time.Since(llmStart)
 	if err != nil {
 		return nil, fmt.Errorf("LLM error: %w", err)
 	}
@@ -389,6 +425,7 @@ func (a *Agent) EvaluateWithTools(ctx context.Context, userInput string) (*Agent
 		eval.FinalResponse = response.Message.Content
 	}
 
+	eval.summarize(time.Since(start))
 	return eval, nil
 }
 
@@ -415,11 +452,17 @@ func (a *Agent) evaluateToolCallsWithLimit(ctx context.Context, toolCalls []llm.
 	var toolResponses []llm.Message
 	for _, tc := range toolCalls {
 		// Call the tool
-		toolResponse, err := a.tools.Call(ctx, tc)
+		callStart := time.Now()
+		toolResponse, err := a.callTool(ctx, tc)
+		callDuration := time.Since(callStart)
+		toolStep.ToolDurations = append(toolStep.ToolDurations, callDuration)
+		toolStep.Duration += callDuration
 		if err != nil {
 			return "", steps, fmt.Errorf("tool execution error: %w", err)
 		}
 
+		toolResponse, originalLength := a.limitToolOutput(ctx, tc, toolResponse)
+		toolStep.ToolOutputLengths = append(toolStep.ToolOutputLengths, originalLength)
 		toolResponses = append(toolResponses, toolResponse)
 
 		// Add tool response to memory
@@ -459,7 +502,9 @@ func (a *Agent) evaluateToolCallsWithLimit(ctx context.Context, toolCalls []llm.
 		}
 	}
 
+	llmStart := time.Now()
 	response, err := a.client.Chat(ctx, messages, options...)
+	responseStep.Duration = time.Since(llmStart)
 	if err != nil {
 		return "", steps, fmt.Errorf("LLM error: %w", err)
 	}
@@ -493,6 +538,31 @@ type AgentEvaluation struct {
 	UserInput     string      `json:"user_input"`
 	Steps         []AgentStep `json:"steps"`
 	FinalResponse string      `json:"final_response"`
+
+	TotalTokenUsage llm.Usage     `json:"total_token_usage"` // Sum of TokenUsage across all LLM steps
+	TotalDuration   time.Duration `json:"total_duration"`    // Wall-clock time of the whole evaluation
+	LLMDuration     time.Duration `json:"llm_duration"`      // Time spent waiting on LLM calls
+	ToolDuration    time.Duration `json:"tool_duration"`     // Time spent executing tools
+}
+
+// summarize aggregates token usage and timing across all steps
+func (e *AgentEvaluation) summarize(total time.Duration) {
+	e.TotalDuration = total
+	e.TotalTokenUsage = llm.Usage{}
+	e.LLMDuration = 0
+	e.ToolDuration = 0
+
+	for _, step := range e.Steps {
+		e.TotalTokenUsage.PromptTokens += step.TokenUsage.PromptTokens
+		e.TotalTokenUsage.CompletionTokens += step.TokenUsage.CompletionTokens
+		e.TotalTokenUsage.TotalTokens += step.TokenUsage.TotalTokens
+
+		if step.StepType == "tool_execution" {
+			e.ToolDuration += step.Duration
+		} else {
+			e.LLMDuration += step.Duration
+		}
+	}
 }
 
 type AgentStep struct {
@@ -502,4 +572,9 @@ type AgentStep struct {
 	ToolCalls     []llm.ToolCall `json:"tool_calls"`     // Tool calls made
 	ToolResponses []llm.Message  `json:"tool_responses"` // Responses from the tools
 	TokenUsage    llm.Usage      `json:"token_usage"`    // Token usage information
+
+	Duration      time.Duration   `json:"duration"`                 // Wall-clock time of the LLM call or of all tool calls in the step
+	ToolDurations []time.Duration `json:"tool_durations,omitempty"` // Per-call durations, aligned with ToolCalls
+
+	ToolOutputLengths []int `json:"tool_output_lengths,omitempty"` // Characters each tool returned before limiting, aligned with ToolCalls
 }
@@ -0,0 +1,169 @@
+package agentx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/craftable/ai/embedding"
+	"github.com/Abraxas-365/craftable/ai/llm"
+	"github.com/Abraxas-365/craftable/ai/llm/toolx"
+	"github.com/Abraxas-365/craftable/ai/vstorex"
+)
+
+const (
+	defaultRetrievalToolName        = "search_documents"
+	defaultRetrievalToolDescription = "Search the knowledge base for passages relevant to a query. " +
+		"Use it before answering questions about facts that may be documented there."
+	defaultRetrievalK = 4
+)
+
+// retrievalTool is a toolx.Toolx that embeds the model's query and returns
+// the most similar documents from a vector index
+type retrievalTool struct {
+	index          vstorex.Store
+	embedder       embedding.Embedder
+	name           string
+	description    string
+	k              int
+	scoreThreshold float32
+	filter         vstorex.Filter
+}
+
+// RetrievalToolOption configures a RetrievalTool
+type RetrievalToolOption func(*retrievalTool)
+
+// WithRetrievalK sets how many documents a search returns (default 4)
+func WithRetrievalK(k int) RetrievalToolOption {
+	return func(t *retrievalTool) {
+		t.k = k
+	}
+}
+
+// WithRetrievalScoreThreshold drops documents scoring below threshold, so
+// the model isn't handed irrelevant text when nothing matches well
+func WithRetrievalScoreThreshold(threshold float32) RetrievalToolOption {
+	return func(t *retrievalTool) {
+		t.scoreThreshold = threshold
+	}
+}
+
+// WithRetrievalFilter restricts searches to documents matching filter
+func WithRetrievalFilter(filter vstorex.Filter) RetrievalToolOption {
+	return func(t *retrievalTool) {
+		t.filter = filter
+	}
+}
+
+// WithRetrievalDescription renames the tool and describes its contents to the
+// model, e.g. ("search_policies", "Search the HR policy handbook"). Use it to
+// register several retrieval tools over different indexes.
+func WithRetrievalDescription(name, description string) RetrievalToolOption {
+	return func(t *retrievalTool) {
+		t.name = name
+		t.description = description
+	}
+}
+
+// RetrievalTool returns a tool that searches index for the top-k documents
+// relevant to the model's query and responds with their text. Queries are
+// embedded with embedder, which must be the one the index was built with.
+//
+//	tools := toolx.FromToolx(agentx.RetrievalTool(store, embedder, agentx.WithRetrievalK(5)))
+//	agent := agentx.New(client, memory, agentx.WithTools(tools))
+func RetrievalTool(index vstorex.Store, embedder embedding.Embedder, opts ...RetrievalToolOption) toolx.Toolx {
+	tool := &retrievalTool{
+		index:       index,
+		embedder:    embedder,
+		name:        defaultRetrievalToolName,
+		description: defaultRetrievalToolDescription,
+		k:           defaultRetrievalK,
+	}
+	for _, opt := range opts {
+		opt(tool)
+	}
+	return tool
+}
+
+// Name returns the tool name
+func (t *retrievalTool) Name() string {
+	return t.name
+}
+
+// GetTool describes the tool to the model
+func (t *retrievalTool) GetTool() llm.Tool {
+	return llm.Tool{
+		Type: "function",
+		Function: llm.Function{
+			Name:        t.name,
+			Description: t.description,
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "What to look for, phrased as a question or keywords",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+	}
+}
+
+// Call runs the search and formats the matching documents for the model
+func (t *retrievalTool) Call(ctx context.Context, inputs string) (any, error) {
+	var request struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(inputs), &request); err != nil {
+		return nil, fmt.Errorf("failed to parse retrieval request: %w", err)
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	queryEmbedding, err := t.embedder.EmbedQuery(ctx, request.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	result, err := t.index.SimilaritySearch(ctx, queryEmbedding.Vector, &vstorex.SearchOptions{
+		Limit:          t.k,
+		Filter:         t.filter,
+		ScoreThreshold: t.scoreThreshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+
+	return formatRetrievedDocuments(result.Documents, t.k, t.scoreThreshold), nil
+}
+
+// formatRetrievedDocuments numbers the passages and labels each with its
+// source and score, so the model can cite them
+func formatRetrievedDocuments(docs []vstorex.Document, k int, threshold float32) string {
+	var sb strings.Builder
+	n := 0
+	for _, doc := range docs {
+		if n == k {
+			break
+		}
+		if doc.Score < threshold {
+			continue
+		}
+		n++
+
+		fmt.Fprintf(&sb, "[%d]", n)
+		if source, ok := doc.Metadata["source"]; ok {
+			fmt.Fprintf(&sb, " source: %v", source)
+		}
+		fmt.Fprintf(&sb, " (score %.2f)\n%s\n\n", doc.Score, strings.TrimSpace(doc.PageContent))
+	}
+
+	if n == 0 {
+		return "No relevant documents found."
+	}
+	return strings.TrimSpace(sb.String())
+}
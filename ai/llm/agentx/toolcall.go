@@ -0,0 +1,42 @@
+package agentx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+// callTool runs a tool call, bounded by the tool timeout when one is set.
+// A timed-out call becomes an error tool response so the model can recover;
+// the agent stops waiting even if the tool ignores its context.
+func (a *Agent) callTool(ctx context.Context, tc llm.ToolCall) (llm.Message, error) {
+	if a.toolTimeout <= 0 {
+		return a.tools.Call(ctx, tc)
+	}
+
+	toolCtx, cancel := context.WithTimeout(ctx, a.toolTimeout)
+	defer cancel()
+
+	type callResult struct {
+		message llm.Message
+		err     error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		message, err := a.tools.Call(toolCtx, tc)
+		done <- callResult{message, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.message, result.err
+	case <-toolCtx.Done():
+		if ctx.Err() != nil {
+			// The agent itself was cancelled, not just this tool
+			return llm.Message{}, ctx.Err()
+		}
+		return llm.NewToolMessage(tc.ID, fmt.Sprintf(
+			"Error calling tool: %s timed out after %s", tc.Function.Name, a.toolTimeout)), nil
+	}
+}
@@ -0,0 +1,161 @@
+package agentx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+	"github.com/Abraxas-365/craftable/ai/llm/agentx"
+	"github.com/Abraxas-365/craftable/ai/llm/llmtest"
+	"github.com/Abraxas-365/craftable/ai/llm/memoryx"
+	"github.com/Abraxas-365/craftable/ai/llm/toolx"
+)
+
+// slowTool answers after delay. It returns early when its context is done,
+// unless it ignores the context, and reports whether it saw the cancellation.
+type slowTool struct {
+	delay     time.Duration
+	ignoreCtx bool
+	cancelled chan struct{}
+}
+
+func (s *slowTool) Name() string { return "lookup" }
+
+func (s *slowTool) GetTool() llm.Tool {
+	return llm.Tool{Type: "function", Function: llm.Function{Name: "lookup", Description: "Looks something up"}}
+}
+
+func (s *slowTool) Call(ctx context.Context, inputs string) (any, error) {
+	if s.ignoreCtx {
+		time.Sleep(s.delay)
+		return "found it", nil
+	}
+	select {
+	case <-time.After(s.delay):
+		return "found it", nil
+	case <-ctx.Done():
+		close(s.cancelled)
+		return nil, ctx.Err()
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		tool          *slowTool
+		wantResult    string
+		wantCancelled bool
+		maxElapsed    time.Duration
+	}{
+		{
+			name:          "blocking tool is cancelled and the agent continues",
+			timeout:       50 * time.Millisecond,
+			tool:          &slowTool{delay: 10 * time.Second},
+			wantResult:    "Error calling tool: lookup timed out after 50ms",
+			wantCancelled: true,
+			maxElapsed:    2 * time.Second,
+		},
+		{
+			name:       "agent stops waiting for a tool that ignores its context",
+			timeout:    50 * time.Millisecond,
+			tool:       &slowTool{delay: 500 * time.Millisecond, ignoreCtx: true},
+			wantResult: "Error calling tool: lookup timed out after 50ms",
+			maxElapsed: 400 * time.Millisecond,
+		},
+		{
+			name:       "tool finishing within the timeout",
+			timeout:    time.Second,
+			tool:       &slowTool{delay: 10 * time.Millisecond},
+			wantResult: "found it",
+			maxElapsed: time.Second,
+		},
+		{
+			name:       "no timeout",
+			tool:       &slowTool{delay: 10 * time.Millisecond},
+			wantResult: "found it",
+			maxElapsed: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tool.cancelled = make(chan struct{})
+			toolCall := llm.ToolCall{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "lookup", Arguments: "{}"}}
+			mock := llmtest.NewMockLLM(
+				llm.Message{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall}},
+				llm.NewAssistantMessage("done"),
+			)
+			agent := agentx.New(*llm.NewClient(mock), memoryx.NewMemory(),
+				agentx.WithTools(toolx.FromToolx(tt.tool)),
+				agentx.WithToolTimeout(tt.timeout),
+			)
+
+			start := time.Now()
+			answer, err := agent.Run(context.Background(), "look it up")
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if answer != "done" {
+				t.Errorf("answer = %q, want the model's reply after the tool call", answer)
+			}
+			if elapsed > tt.maxElapsed {
+				t.Errorf("Run took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+
+			call, ok := mock.LastCall()
+			if !ok {
+				t.Fatal("no call recorded")
+			}
+			var result *llm.Message
+			for i := range call.Messages {
+				if call.Messages[i].Role == llm.RoleTool {
+					result = &call.Messages[i]
+				}
+			}
+			if result == nil {
+				t.Fatal("the model never saw a tool response")
+			}
+			if result.ToolCallID != "call_1" || !strings.Contains(result.Content, tt.wantResult) {
+				t.Errorf("tool response = %q (%s), want %q for call_1", result.Content, result.ToolCallID, tt.wantResult)
+			}
+
+			select {
+			case <-tt.tool.cancelled:
+				if !tt.wantCancelled {
+					t.Error("tool context was cancelled")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantCancelled {
+					t.Error("tool context was not cancelled")
+				}
+			}
+		})
+	}
+}
+
+func TestToolTimeoutAgentCancelled(t *testing.T) {
+	tool := &slowTool{delay: 10 * time.Second, cancelled: make(chan struct{})}
+	toolCall := llm.ToolCall{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "lookup", Arguments: "{}"}}
+	mock := llmtest.NewMockLLM(
+		llm.Message{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall}},
+		llm.NewAssistantMessage("done"),
+	)
+	agent := agentx.New(*llm.NewClient(mock), memoryx.NewMemory(),
+		agentx.WithTools(toolx.FromToolx(tool)),
+		agentx.WithToolTimeout(time.Minute),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := agent.Run(ctx, "look it up"); err == nil {
+		t.Fatal("Run succeeded, want the agent's cancellation to stop it")
+	}
+	if len(mock.Calls()) != 1 {
+		t.Errorf("model called %d times, want no call after cancellation", len(mock.Calls()))
+	}
+}
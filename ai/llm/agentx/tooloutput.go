@@ -0,0 +1,55 @@
+package agentx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+// ToolOutputSummarizer condenses a tool result to at most maxChars characters
+type ToolOutputSummarizer func(ctx context.Context, call llm.ToolCall, content string, maxChars int) (string, error)
+
+// SummarizeWithLLM returns a ToolOutputSummarizer that asks client to condense
+// tool results, keeping the facts needed to answer the user
+func SummarizeWithLLM(client *llm.Client, opts ...llm.Option) ToolOutputSummarizer {
+	return func(ctx context.Context, call llm.ToolCall, content string, maxChars int) (string, error) {
+		messages := []llm.Message{
+			llm.NewSystemMessage(fmt.Sprintf(
+				"Summarize the output of the %q tool in at most %d characters. "+
+					"Keep names, numbers, identifiers and any facts needed to answer the user. "+
+					"Reply with the summary only.",
+				call.Function.Name, maxChars,
+			)),
+			llm.NewUserMessage(content),
+		}
+
+		response, err := client.Chat(ctx, messages, opts...)
+		if err != nil {
+			return "", err
+		}
+		return response.Message.Content, nil
+	}
+}
+
+// limitToolOutput applies the tool output limit to a tool response and
+// returns it with the original content length in characters
+func (a *Agent) limitToolOutput(ctx context.Context, call llm.ToolCall, response llm.Message) (llm.Message, int) {
+	content := []rune(response.Content)
+	originalLength := len(content)
+	if a.toolOutputLimit <= 0 || originalLength <= a.toolOutputLimit {
+		return response, originalLength
+	}
+
+	if a.summarizer != nil {
+		summary, err := a.summarizer(ctx, call, response.Content, a.toolOutputLimit)
+		if err == nil && len([]rune(summary)) <= a.toolOutputLimit {
+			response.Content = fmt.Sprintf("%s\n[summarized from %d characters]", summary, originalLength)
+			return response, originalLength
+		}
+	}
+
+	response.Content = fmt.Sprintf("%s\n[truncated: showing %d of %d characters]",
+		string(content[:a.toolOutputLimit]), a.toolOutputLimit, originalLength)
+	return response, originalLength
+}
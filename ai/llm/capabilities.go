@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"slices"
+
+	"github.com/Abraxas-365/craftable/ai/embedding"
+	"github.com/Abraxas-365/craftable/ai/ocr"
+	"github.com/Abraxas-365/craftable/ai/speech"
+)
+
+// Capability names a modality a provider may support. Each one is backed by
+// an interface, so a provider supporting it can be used through that
+// interface after a type assertion.
+type Capability string
+
+const (
+	CapabilityChat            Capability = "chat"             // LLM
+	CapabilityEmbeddings      Capability = "embeddings"       // embedding.Embedder
+	CapabilityOCR             Capability = "ocr"              // ocr.OCRProvider
+	CapabilitySpeechSynthesis Capability = "speech_synthesis" // speech.Speaker
+	CapabilitySpeechStreaming Capability = "speech_streaming" // speech.StreamingSpeaker
+	CapabilityTranscription   Capability = "transcription"    // speech.Transcriber
+)
+
+// CapabilityReporter is implemented by providers whose support depends on
+// configuration, e.g. a provider with a method for every modality whose
+// backend only offers some of them
+type CapabilityReporter interface {
+	// Capabilities returns the modalities the provider supports
+	Capabilities() []Capability
+}
+
+// CapabilitiesOf returns the modalities provider supports, in the order of
+// the Capability constants. A modality counts when provider implements its
+// interface and, if provider is a CapabilityReporter, reports it.
+//
+//	if llm.Supports(provider, llm.CapabilityEmbeddings) {
+//		embedder := provider.(embedding.Embedder)
+//		...
+//	}
+func CapabilitiesOf(provider any) []Capability {
+	var capabilities []Capability
+	add := func(capability Capability, implemented bool) {
+		if implemented {
+			capabilities = append(capabilities, capability)
+		}
+	}
+
+	_, chat := provider.(LLM)
+	_, embeddings := provider.(embedding.Embedder)
+	_, extracts := provider.(ocr.OCRProvider)
+	_, speaks := provider.(speech.Speaker)
+	_, streams := provider.(speech.StreamingSpeaker)
+	_, transcribes := provider.(speech.Transcriber)
+
+	add(CapabilityChat, chat)
+	add(CapabilityEmbeddings, embeddings)
+	add(CapabilityOCR, extracts)
+	add(CapabilitySpeechSynthesis, speaks)
+	add(CapabilitySpeechStreaming, streams)
+	add(CapabilityTranscription, transcribes)
+
+	if reporter, ok := provider.(CapabilityReporter); ok {
+		reported := reporter.Capabilities()
+		capabilities = slices.DeleteFunc(capabilities, func(c Capability) bool {
+			return !slices.Contains(reported, c)
+		})
+	}
+	return capabilities
+}
+
+// Supports reports whether provider supports capability (see CapabilitiesOf)
+func Supports(provider any, capability Capability) bool {
+	return slices.Contains(CapabilitiesOf(provider), capability)
+}
+
+// WithCapability returns the providers that support capability, in order,
+// e.g. to pick the members of a fallback or router from a mixed list
+func WithCapability[P any](capability Capability, providers ...P) []P {
+	var supporting []P
+	for _, provider := range providers {
+		if Supports(provider, capability) {
+			supporting = append(supporting, provider)
+		}
+	}
+	return supporting
+}
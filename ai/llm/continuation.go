@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"strings"
+)
+
+// ContinuePrompt is the follow-up WithAutoContinue sends after a response was
+// cut off by the token limit
+const ContinuePrompt = "Continue exactly where you left off. Do not repeat or summarize what you already wrote."
+
+// WithAutoContinue continues responses that stop with FinishReasonLength. The
+// partial reply is sent back with ContinuePrompt up to maxContinuations times,
+// and the parts are stitched into one message until the model stops on its own.
+// Usage and cost cover every request; Response.Continuations counts the
+// follow-ups. Responses with tool calls are never continued.
+func WithAutoContinue(maxContinuations int) Option {
+	return func(o *ChatOptions) {
+		o.AutoContinue = maxContinuations
+	}
+}
+
+// continueResponse requests continuations of a truncated response. On error
+// the parts generated so far are returned along with it.
+func (c *Client) continueResponse(ctx context.Context, messages []Message, response Response, limit int, opts []Option) (Response, error) {
+	conversation := append([]Message(nil), messages...)
+
+	var content strings.Builder
+	content.WriteString(response.Message.Content)
+	usage := response.Usage
+
+	for response.Continuations < limit && response.FinishReason == FinishReasonLength && len(response.Message.ToolCalls) == 0 {
+		conversation = append(conversation, response.Message, NewUserMessage(ContinuePrompt))
+
+		next, err := c.llm.Chat(ctx, conversation, opts...)
+		if err != nil {
+			response.Message.Content = content.String()
+			response.Usage = usage
+			return response, err
+		}
+
+		content.WriteString(next.Message.Content)
+		usage.PromptTokens += next.Usage.PromptTokens
+		usage.CompletionTokens += next.Usage.CompletionTokens
+		usage.TotalTokens += next.Usage.TotalTokens
+
+		next.Continuations = response.Continuations + 1
+		response = next
+	}
+
+	response.Message.Content = content.String()
+	response.Usage = usage
+	return response, nil
+}
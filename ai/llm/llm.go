@@ -13,10 +13,27 @@ type LLM interface {
 	ChatStream(ctx context.Context, messages []Message, opts ...Option) (Stream, error)
 }
 
+// Finish reasons reported in Response.FinishReason
+const (
+	FinishReasonStop          = "stop"           // Natural end or a stop sequence
+	FinishReasonLength        = "length"         // Cut off by the token limit
+	FinishReasonToolCalls     = "tool_calls"     // The model called tools
+	FinishReasonContentFilter = "content_filter" // Omitted by the provider's content filter
+)
+
 // Response contains the model's response and additional metadata
 type Response struct {
 	Message Message
 	Usage   Usage
+
+	// FinishReason tells why generation stopped, when the provider reports it
+	FinishReason string
+
+	// Continuations counts the follow-up requests WithAutoContinue made
+	Continuations int
+
+	// Cost is set by clients configured WithPricing when the model is priced
+	Cost *CostEstimate
 }
 
 // Stream represents a streaming response
@@ -25,13 +42,15 @@ type Stream interface {
 	// Returns io.EOF when the stream is complete
 	Next() (Message, error)
 
-	// Close closes the stream
+	// Close closes the stream and releases its connection. Always call it,
+	// including when abandoning a stream before io.EOF.
 	Close() error
 }
 
 // Client represents a configured LLM client
 type Client struct {
-	llm LLM
+	llm     LLM
+	pricing PricingTable
 }
 
 // NewClient creates a new LLM client
@@ -39,9 +58,37 @@ func NewClient(llm LLM) *Client {
 	return &Client{llm: llm}
 }
 
+// WithPricing attaches a cost estimate to every response whose model (set
+// with WithModel) is found in table. Use DefaultPricing for list prices.
+func (c *Client) WithPricing(table PricingTable) *Client {
+	c.pricing = table
+	return c
+}
+
 // Chat generates a response based on the conversation history
 func (c *Client) Chat(ctx context.Context, messages []Message, opts ...Option) (Response, error) {
-	return c.llm.Chat(ctx, messages, opts...)
+	options := ApplyOptions(opts...)
+
+	response, err := c.llm.Chat(ctx, messages, opts...)
+	if err == nil && options.AutoContinue > 0 {
+		response, err = c.continueResponse(ctx, messages, response, options.AutoContinue, opts)
+	}
+	if err == nil {
+		response.Cost = c.estimateCost(options.Model, response.Usage)
+	}
+	return response, err
+}
+
+// estimateCost prices usage when pricing is configured and the model is known
+func (c *Client) estimateCost(model string, usage Usage) *CostEstimate {
+	if c.pricing == nil || model == "" {
+		return nil
+	}
+	estimate, err := c.pricing.EstimateCost(model, usage)
+	if err != nil {
+		return nil
+	}
+	return &estimate
 }
 
 // ChatStream streams the response tokens
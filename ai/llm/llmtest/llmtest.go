@@ -0,0 +1,170 @@
+// Package llmtest provides an in-memory llm.LLM implementation for tests.
+//
+// The mock records every call together with the resolved options, so tests can
+// assert what would have been sent to a real provider (model, seed, tools, ...):
+//
+//	mock := llmtest.NewMockLLM(llm.NewAssistantMessage("hello"))
+//	client := llm.NewClient(mock)
+//
+//	client.Chat(ctx, msgs, llm.WithSeed(42), llm.WithTemperature(0))
+//
+//	if seed, ok := mock.LastSeed(); !ok || seed != 42 {
+//		t.Fatalf("expected seed 42, got %d", seed)
+//	}
+package llmtest
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+// Call records a single invocation of the mock
+type Call struct {
+	Messages []llm.Message
+	Options  *llm.ChatOptions
+	Stream   bool
+}
+
+// MockLLM is a scripted llm.LLM that records its calls
+type MockLLM struct {
+	mutex     sync.Mutex
+	responses []llm.Message
+	finishes  []string
+	calls     []Call
+	err       error
+}
+
+// NewMockLLM creates a mock that returns the given messages in order.
+// Once exhausted, the last message is repeated.
+func NewMockLLM(responses ...llm.Message) *MockLLM {
+	return &MockLLM{responses: responses}
+}
+
+// WithFinishReasons sets the FinishReason of the Chat responses, in the same
+// order as the scripted messages. Once exhausted, the last reason is repeated.
+func (m *MockLLM) WithFinishReasons(reasons ...string) *MockLLM {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.finishes = reasons
+	return m
+}
+
+// WithError makes every subsequent call fail with err
+func (m *MockLLM) WithError(err error) *MockLLM {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.err = err
+	return m
+}
+
+// Chat implements llm.LLM
+func (m *MockLLM) Chat(ctx context.Context, messages []llm.Message, opts ...llm.Option) (llm.Response, error) {
+	msg, err := m.record(messages, opts, false)
+	if err != nil {
+		return llm.Response{}, err
+	}
+	return llm.Response{Message: msg, FinishReason: m.finishReason()}, nil
+}
+
+// ChatStream implements llm.LLM. The scripted message is delivered as a single chunk.
+func (m *MockLLM) ChatStream(ctx context.Context, messages []llm.Message, opts ...llm.Option) (llm.Stream, error) {
+	msg, err := m.record(messages, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return &mockStream{message: msg}, nil
+}
+
+// Calls returns a copy of all recorded calls
+func (m *MockLLM) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	calls := make([]Call, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// LastCall returns the most recent call
+func (m *MockLLM) LastCall() (Call, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.calls) == 0 {
+		return Call{}, false
+	}
+	return m.calls[len(m.calls)-1], true
+}
+
+// LastSeed returns the seed of the most recent call and whether one was set
+func (m *MockLLM) LastSeed() (int64, bool) {
+	call, ok := m.LastCall()
+	if !ok || call.Options.Seed == 0 {
+		return 0, false
+	}
+	return call.Options.Seed, true
+}
+
+// Reset clears the recorded calls
+func (m *MockLLM) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.calls = nil
+}
+
+func (m *MockLLM) record(messages []llm.Message, opts []llm.Option, stream bool) (llm.Message, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = append(m.calls, Call{
+		Messages: messages,
+		Options:  llm.ApplyOptions(opts...),
+		Stream:   stream,
+	})
+
+	if m.err != nil {
+		return llm.Message{}, m.err
+	}
+	if len(m.responses) == 0 {
+		return llm.NewAssistantMessage(""), nil
+	}
+
+	index := len(m.calls) - 1
+	if index >= len(m.responses) {
+		index = len(m.responses) - 1
+	}
+	return m.responses[index], nil
+}
+
+// finishReason returns the scripted finish reason of the latest call
+func (m *MockLLM) finishReason() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.finishes) == 0 {
+		return ""
+	}
+	return m.finishes[min(len(m.calls), len(m.finishes))-1]
+}
+
+// mockStream delivers a single message then io.EOF
+type mockStream struct {
+	message llm.Message
+	done    bool
+}
+
+func (s *mockStream) Next() (llm.Message, error) {
+	if s.done {
+		return llm.Message{}, io.EOF
+	}
+	s.done = true
+	return s.message, nil
+}
+
+func (s *mockStream) Close() error {
+	s.done = true
+	return nil
+}
@@ -0,0 +1,88 @@
+package llmtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+	"github.com/Abraxas-365/craftable/ai/llm/llmtest"
+)
+
+func TestMockRecordsSeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   bool
+		opts     []llm.Option
+		wantSeed int64
+		wantSet  bool
+	}{
+		{name: "chat with seed", opts: []llm.Option{llm.WithSeed(42)}, wantSeed: 42, wantSet: true},
+		{name: "stream with seed", stream: true, opts: []llm.Option{llm.WithSeed(42)}, wantSeed: 42, wantSet: true},
+		{name: "chat without seed"},
+		{name: "stream without seed", stream: true},
+		{name: "last seed wins", opts: []llm.Option{llm.WithSeed(1), llm.WithSeed(7)}, wantSeed: 7, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := llmtest.NewMockLLM(llm.NewAssistantMessage("hello"))
+			client := llm.NewClient(mock)
+			messages := []llm.Message{llm.NewUserMessage("hi")}
+
+			var err error
+			if tt.stream {
+				var stream llm.Stream
+				stream, err = client.ChatStream(context.Background(), messages, tt.opts...)
+				if err == nil {
+					stream.Close()
+				}
+			} else {
+				_, err = client.Chat(context.Background(), messages, tt.opts...)
+			}
+			if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+
+			call, ok := mock.LastCall()
+			if !ok {
+				t.Fatal("no call recorded")
+			}
+			if call.Stream != tt.stream {
+				t.Errorf("Stream = %v, want %v", call.Stream, tt.stream)
+			}
+			seed, set := mock.LastSeed()
+			if seed != tt.wantSeed || set != tt.wantSet {
+				t.Errorf("LastSeed() = %d, %v, want %d, %v", seed, set, tt.wantSeed, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestMockSeedPerCall(t *testing.T) {
+	mock := llmtest.NewMockLLM(llm.NewAssistantMessage("hello"))
+	messages := []llm.Message{llm.NewUserMessage("hi")}
+
+	for _, seed := range []int64{3, 3, 9} {
+		if _, err := mock.Chat(context.Background(), messages, llm.WithSeed(seed), llm.WithTemperature(0)); err != nil {
+			t.Fatalf("Chat: %v", err)
+		}
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("recorded %d calls, want 3", len(calls))
+	}
+	for i, want := range []int64{3, 3, 9} {
+		if calls[i].Options.Seed != want {
+			t.Errorf("call %d seed = %d, want %d", i, calls[i].Options.Seed, want)
+		}
+		if calls[i].Options.Temperature != 0 {
+			t.Errorf("call %d temperature = %v, want 0", i, calls[i].Options.Temperature)
+		}
+	}
+
+	mock.Reset()
+	if _, ok := mock.LastSeed(); ok {
+		t.Error("LastSeed after Reset reported a seed")
+	}
+}
@@ -23,6 +23,11 @@ type ChatOptions struct {
 
 	ReasoningEffort string // Reasoning effort level: "low", "medium", "high"
 
+	JSONRepair        bool // Repair malformed JSON in ChatJSON (client-side only)
+	JSONRepairRetries int  // Model retries with the parse error fed back (client-side only)
+
+	AutoContinue int // Follow-up requests after a length finish (client-side only)
+
 }
 
 // Option is a function type to modify ChatOptions
@@ -84,7 +89,8 @@ func WithFunctions(functions []Function) Option {
 	}
 }
 
-// WithToolChoice forces a specific tool
+// WithToolChoice controls tool use: "auto", "none", "required", or the name
+// of a function the model must call
 func WithToolChoice(toolChoice any) Option {
 	return func(o *ChatOptions) {
 		o.ToolChoice = toolChoice
@@ -129,7 +135,12 @@ func WithFrequencyPenalty(penalty float32) Option {
 	}
 }
 
-// WithSeed sets the random seed
+// WithSeed sets the random seed for reproducible sampling. A seed of 0 means "not set".
+//
+// Determinism is best-effort and provider dependent: OpenAI chat models (gpt-4o,
+// gpt-4o-mini, gpt-4-turbo, gpt-3.5-turbo) honor the seed when the other parameters
+// are identical, while reasoning models (o1, o3) and Anthropic models ignore it.
+// Combine with WithTemperature(0) for the most stable outputs.
 func WithSeed(seed int64) Option {
 	return func(o *ChatOptions) {
 		o.Seed = seed
@@ -151,6 +162,15 @@ func WithReasoningEffort(effort string) Option {
 	}
 }
 
+// ApplyOptions returns the default options with the given options applied
+func ApplyOptions(opts ...Option) *ChatOptions {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
 // DefaultOptions returns the default options
 func DefaultOptions() *ChatOptions {
 	return &ChatOptions{
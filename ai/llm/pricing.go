@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownPricing is returned when a model has no entry in the pricing table
+var ErrUnknownPricing = errors.New("unknown pricing for model")
+
+// ModelPricing is the price of a model in dollars per 1K tokens
+type ModelPricing struct {
+	InputPer1K  float64 `json:"input_per_1k"`
+	OutputPer1K float64 `json:"output_per_1k"`
+}
+
+// PricingTable maps model names to their pricing. Lookups fall back to the
+// longest matching prefix, so "gpt-4o" also prices "gpt-4o-2024-08-06".
+type PricingTable map[string]ModelPricing
+
+// CostEstimate is the estimated dollar cost of a request
+type CostEstimate struct {
+	Model      string  `json:"model"`
+	InputCost  float64 `json:"input_cost"`
+	OutputCost float64 `json:"output_cost"`
+	TotalCost  float64 `json:"total_cost"`
+}
+
+// DefaultPricing holds list prices for common models. Replace or extend it
+// during initialization to match your contract; it is not safe to modify
+// while requests are being priced.
+var DefaultPricing = PricingTable{
+	"gpt-4o":            {InputPer1K: 0.0025, OutputPer1K: 0.01},
+	"gpt-4o-mini":       {InputPer1K: 0.00015, OutputPer1K: 0.0006},
+	"gpt-4.1":           {InputPer1K: 0.002, OutputPer1K: 0.008},
+	"gpt-4.1-mini":      {InputPer1K: 0.0004, OutputPer1K: 0.0016},
+	"gpt-4.1-nano":      {InputPer1K: 0.0001, OutputPer1K: 0.0004},
+	"gpt-4-turbo":       {InputPer1K: 0.01, OutputPer1K: 0.03},
+	"gpt-3.5-turbo":     {InputPer1K: 0.0005, OutputPer1K: 0.0015},
+	"o1":                {InputPer1K: 0.015, OutputPer1K: 0.06},
+	"o3":                {InputPer1K: 0.002, OutputPer1K: 0.008},
+	"o3-mini":           {InputPer1K: 0.0011, OutputPer1K: 0.0044},
+	"o4-mini":           {InputPer1K: 0.0011, OutputPer1K: 0.0044},
+	"claude-3-5-sonnet": {InputPer1K: 0.003, OutputPer1K: 0.015},
+	"claude-3-5-haiku":  {InputPer1K: 0.0008, OutputPer1K: 0.004},
+	"claude-3-opus":     {InputPer1K: 0.015, OutputPer1K: 0.075},
+}
+
+// Lookup returns the pricing for model, trying an exact match first and then
+// the longest table entry the model name starts with
+func (t PricingTable) Lookup(model string) (ModelPricing, bool) {
+	if pricing, ok := t[model]; ok {
+		return pricing, true
+	}
+
+	best := ""
+	for name := range t {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPricing{}, false
+	}
+	return t[best], true
+}
+
+// EstimateCost prices usage for model. It returns ErrUnknownPricing when the
+// model isn't in the table.
+func (t PricingTable) EstimateCost(model string, usage Usage) (CostEstimate, error) {
+	pricing, ok := t.Lookup(model)
+	if !ok {
+		return CostEstimate{}, fmt.Errorf("%w: %q", ErrUnknownPricing, model)
+	}
+
+	estimate := CostEstimate{
+		Model:      model,
+		InputCost:  float64(usage.PromptTokens) / 1000 * pricing.InputPer1K,
+		OutputCost: float64(usage.CompletionTokens) / 1000 * pricing.OutputPer1K,
+	}
+	estimate.TotalCost = estimate.InputCost + estimate.OutputCost
+	return estimate, nil
+}
+
+// EstimateCost prices usage for model using DefaultPricing
+func EstimateCost(model string, usage Usage) (CostEstimate, error) {
+	return DefaultPricing.EstimateCost(model, usage)
+}
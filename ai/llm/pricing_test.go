@@ -0,0 +1,94 @@
+package llm_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+func TestEstimateCost(t *testing.T) {
+	table := llm.PricingTable{
+		"gpt-4o":      {InputPer1K: 0.0025, OutputPer1K: 0.01},
+		"gpt-4o-mini": {InputPer1K: 0.00015, OutputPer1K: 0.0006},
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		usage      llm.Usage
+		wantInput  float64
+		wantOutput float64
+	}{
+		{
+			name:       "exact model",
+			model:      "gpt-4o",
+			usage:      llm.Usage{PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500},
+			wantInput:  0.005,
+			wantOutput: 0.005,
+		},
+		{
+			name:       "dated snapshot uses the longest prefix",
+			model:      "gpt-4o-mini-2024-07-18",
+			usage:      llm.Usage{PromptTokens: 10000, CompletionTokens: 1000, TotalTokens: 11000},
+			wantInput:  0.0015,
+			wantOutput: 0.0006,
+		},
+		{
+			name:  "no usage is free",
+			model: "gpt-4o",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.EstimateCost(tt.model, tt.usage)
+			if err != nil {
+				t.Fatalf("EstimateCost: %v", err)
+			}
+			if got.Model != tt.model {
+				t.Errorf("Model = %q, want %q", got.Model, tt.model)
+			}
+			assertCost(t, "InputCost", got.InputCost, tt.wantInput)
+			assertCost(t, "OutputCost", got.OutputCost, tt.wantOutput)
+			assertCost(t, "TotalCost", got.TotalCost, tt.wantInput+tt.wantOutput)
+		})
+	}
+}
+
+func TestEstimateCostUnknownModel(t *testing.T) {
+	tests := []struct {
+		name  string
+		table llm.PricingTable
+		model string
+	}{
+		{name: "empty table", table: llm.PricingTable{}, model: "gpt-4o"},
+		{name: "no matching prefix", table: llm.DefaultPricing, model: "my-fine-tune"},
+		{name: "table entry longer than model", table: llm.PricingTable{"gpt-4o-mini": {}}, model: "gpt-4o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.table.EstimateCost(tt.model, llm.Usage{PromptTokens: 100})
+			if !errors.Is(err, llm.ErrUnknownPricing) {
+				t.Fatalf("err = %v, want ErrUnknownPricing", err)
+			}
+		})
+	}
+}
+
+func TestEstimateCostDefaultPricing(t *testing.T) {
+	got, err := llm.EstimateCost("gpt-4o-2024-08-06", llm.Usage{PromptTokens: 1000, CompletionTokens: 1000})
+	if err != nil {
+		t.Fatalf("EstimateCost: %v", err)
+	}
+	assertCost(t, "TotalCost", got.TotalCost, 0.0125)
+}
+
+func assertCost(t *testing.T, field string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("%s = %g, want %g", field, got, want)
+	}
+}
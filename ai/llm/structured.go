@@ -0,0 +1,249 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/craftable/logx"
+)
+
+// WithJSONRepair enables best-effort repair of malformed JSON in ChatJSON.
+// Output is first repaired locally (code fences, surrounding prose, trailing
+// commas, unclosed brackets); if it still doesn't parse, the parse error is fed
+// back to the model up to retries times. Repairs are logged as warnings, since
+// they usually mean the prompt could be tightened.
+func WithJSONRepair(retries int) Option {
+	return func(o *ChatOptions) {
+		o.JSONRepair = true
+		o.JSONRepairRetries = retries
+	}
+}
+
+// ChatJSON sends the conversation and decodes the model's reply into T.
+// JSON output is requested unless a response format is already set. Without
+// WithJSONRepair the reply must be valid JSON as-is.
+func ChatJSON[T any](ctx context.Context, client *Client, messages []Message, opts ...Option) (T, Response, error) {
+	var result T
+
+	options := ApplyOptions(opts...)
+	if options.ResponseFormat == nil && !options.JSONMode {
+		opts = append(opts, WithJSONResponseFormat())
+	}
+
+	retries := 0
+	if options.JSONRepair {
+		retries = max(options.JSONRepairRetries, 0)
+	}
+
+	conversation := append([]Message(nil), messages...)
+	var usage Usage
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.Chat(ctx, conversation, opts...)
+		if err != nil {
+			return result, response, err
+		}
+		usage.PromptTokens += response.Usage.PromptTokens
+		usage.CompletionTokens += response.Usage.CompletionTokens
+		usage.TotalTokens += response.Usage.TotalTokens
+		response.Usage = usage
+		response.Cost = client.estimateCost(options.Model, usage)
+
+		content := response.Message.Content
+		parseErr := json.Unmarshal([]byte(content), &result)
+		if parseErr == nil {
+			return result, response, nil
+		}
+
+		if options.JSONRepair {
+			repaired, fixes := RepairJSON(content)
+			if len(fixes) > 0 {
+				var repairedResult T
+				if err := json.Unmarshal([]byte(repaired), &repairedResult); err == nil {
+					logx.Warn("llm: repaired malformed JSON output (%s); consider tightening the prompt", strings.Join(fixes, ", "))
+					return repairedResult, response, nil
+				}
+			}
+		}
+
+		if attempt >= retries {
+			return result, response, fmt.Errorf("failed to parse JSON response: %w", parseErr)
+		}
+
+		logx.Warn("llm: JSON output did not parse (%v), asking the model to correct it (retry %d/%d)", parseErr, attempt+1, retries)
+		conversation = append(conversation,
+			response.Message,
+			NewUserMessage(fmt.Sprintf(
+				"Your previous response was not valid JSON: %v. Reply again with only the corrected JSON, without markdown or explanations.",
+				parseErr,
+			)),
+		)
+		result = *new(T)
+	}
+}
+
+// RepairJSON applies best-effort fixes to almost-valid JSON produced by a model.
+// It returns the repaired text and a description of each fix applied; no fixes
+// means the input was left unchanged.
+func RepairJSON(s string) (string, []string) {
+	var fixes []string
+	repaired := strings.TrimSpace(s)
+
+	if stripped, ok := stripCodeFence(repaired); ok {
+		repaired = stripped
+		fixes = append(fixes, "removed markdown code fence")
+	}
+
+	if extracted, ok := extractJSONValue(repaired); ok {
+		repaired = extracted
+		fixes = append(fixes, "removed text around JSON")
+	}
+
+	if cleaned, ok := removeTrailingCommas(repaired); ok {
+		repaired = cleaned
+		fixes = append(fixes, "removed trailing commas")
+	}
+
+	if closed, ok := closeBrackets(repaired); ok {
+		repaired = closed
+		fixes = append(fixes, "closed unterminated brackets")
+	}
+
+	return repaired, fixes
+}
+
+// stripCodeFence removes a surrounding ```json ... ``` block
+func stripCodeFence(s string) (string, bool) {
+	start := strings.Index(s, "```")
+	if start < 0 {
+		return s, false
+	}
+
+	body := s[start+3:]
+	// Drop the language tag on the opening fence line
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 {
+		body = body[newline+1:]
+	}
+	if end := strings.LastIndex(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+
+	return strings.TrimSpace(body), true
+}
+
+// extractJSONValue trims prose before the first '{' or '[' and after its last closer
+func extractJSONValue(s string) (string, bool) {
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s, false
+	}
+
+	closer := byte('}')
+	if s[start] == '[' {
+		closer = ']'
+	}
+
+	end := strings.LastIndexByte(s, closer)
+	if end < start {
+		end = len(s) - 1
+	}
+
+	extracted := s[start : end+1]
+	return extracted, extracted != s
+}
+
+// removeTrailingCommas drops commas directly followed by '}' or ']', outside strings
+func removeTrailingCommas(s string) (string, bool) {
+	var b strings.Builder
+	changed := false
+	inString, escaped := false, false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			b.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		}
+
+		if c == ',' {
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				changed = true
+				continue
+			}
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String(), changed
+}
+
+// closeBrackets terminates an open string and appends missing closers, which
+// recovers output truncated by a token limit
+func closeBrackets(s string) (string, bool) {
+	var stack []byte
+	inString, escaped := false, false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) > 0 && stack[len(stack)-1] == c {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if !inString && len(stack) == 0 {
+		return s, false
+	}
+
+	var b strings.Builder
+	b.WriteString(s)
+	if inString {
+		b.WriteByte('"')
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteByte(stack[i])
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,74 @@
+package ocr
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of one image in ExtractTextBatch
+type BatchResult struct {
+	Result
+
+	// Err is set when extraction of this image failed or was rejected
+	Err error
+}
+
+// ExtractTextBatch extracts text from many images concurrently. At most
+// Concurrency images (see WithConcurrency) are in flight at a time, so the
+// provider's rate limits can be respected by lowering it. Results are in the
+// same order as images, and a failing image only sets Err on its own entry.
+// The returned error is non-nil only when ctx ends before every image was
+// started; the unstarted entries then carry ctx.Err(). Use TotalUsage to sum
+// Usage across the batch.
+func (c *Client) ExtractTextBatch(ctx context.Context, images [][]byte, opts ...Option) ([]BatchResult, error) {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(images))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, image := range images {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(images); j++ {
+				results[j].Err = ctx.Err()
+			}
+			wg.Wait()
+			return results, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(index int, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := c.ExtractText(ctx, data, opts...)
+			results[index] = BatchResult{Result: result, Err: err}
+		}(i, image)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// TotalUsage sums the Usage of every result in a batch, including failed and
+// rejected images, since the provider may still have billed them.
+// ProcessingTime is the sum of per-image times, not the batch's wall time.
+func TotalUsage(results []BatchResult) Usage {
+	var total Usage
+	for _, r := range results {
+		total.PromptTokens += r.Usage.PromptTokens
+		total.CompletionTokens += r.Usage.CompletionTokens
+		total.TotalTokens += r.Usage.TotalTokens
+		total.ProcessingTime += r.Usage.ProcessingTime
+	}
+	return total
+}
@@ -2,8 +2,24 @@ package ocr
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/craftable/errx"
 )
 
+// Error registry for OCR-specific errors
+var (
+	ErrRegistry = errx.NewRegistry("OCR")
+
+	// ErrCodeLowConfidence is returned when a result falls below the MinConfidence option
+	ErrCodeLowConfidence = ErrRegistry.Register("LOW_CONFIDENCE", errx.TypeValidation, 422, "OCR confidence below threshold")
+)
+
+// IsLowConfidence reports whether err is a rejection caused by MinConfidence
+func IsLowConfidence(err error) bool {
+	return errx.IsCode(err, ErrCodeLowConfidence)
+}
+
 // OCRProvider represents an interface for OCR operations
 type OCRProvider interface {
 	// ExtractText extracts text from an image
@@ -21,11 +37,18 @@ type Result struct {
 	// Confidence is the overall confidence score (0-1)
 	Confidence float32
 
+	// DetectedLanguage is the main language of the text as identified by the
+	// provider, as an ISO 639-1 code such as "en" (empty if not reported)
+	DetectedLanguage string
+
 	// Blocks contains detailed information about text blocks (if supported by provider)
 	Blocks []TextBlock
 
 	// Usage contains token/resource usage statistics
 	Usage Usage
+
+	// Rejected is set when Confidence is below the MinConfidence option
+	Rejected bool
 }
 
 // TextBlock represents a block of text detected in the image
@@ -66,13 +89,41 @@ func NewClient(provider OCRProvider) *Client {
 	return &Client{provider: provider}
 }
 
-// ExtractText extracts text from an image
+// ExtractText extracts text from an image. With WithMinConfidence, a result
+// below the threshold is returned flagged as Rejected together with an
+// ErrCodeLowConfidence error.
 func (c *Client) ExtractText(ctx context.Context, imageData []byte, opts ...Option) (Result, error) {
-	return c.provider.ExtractText(ctx, imageData, opts...)
+	result, err := c.provider.ExtractText(ctx, imageData, opts...)
+	if err != nil {
+		return result, err
+	}
+	return checkConfidence(result, opts)
 }
 
-// ExtractTextFromURL extracts text from an image at the given URL
+// ExtractTextFromURL extracts text from an image at the given URL, applying
+// the same confidence check as ExtractText
 func (c *Client) ExtractTextFromURL(ctx context.Context, imageURL string, opts ...Option) (Result, error) {
-	return c.provider.ExtractTextFromURL(ctx, imageURL, opts...)
+	result, err := c.provider.ExtractTextFromURL(ctx, imageURL, opts...)
+	if err != nil {
+		return result, err
+	}
+	return checkConfidence(result, opts)
+}
+
+// checkConfidence flags and rejects a result below the MinConfidence option
+func checkConfidence(result Result, opts []Option) (Result, error) {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.MinConfidence <= 0 || result.Confidence >= options.MinConfidence {
+		return result, nil
+	}
+
+	result.Rejected = true
+	return result, ErrRegistry.New(ErrCodeLowConfidence).
+		WithDetail("confidence", fmt.Sprintf("%.2f", result.Confidence)).
+		WithDetail("min_confidence", fmt.Sprintf("%.2f", options.MinConfidence))
 }
 
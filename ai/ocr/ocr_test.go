@@ -0,0 +1,67 @@
+package ocr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/ocr"
+)
+
+// fakeProvider returns a fixed result for every image
+type fakeProvider struct {
+	result ocr.Result
+}
+
+func (p fakeProvider) ExtractText(ctx context.Context, imageData []byte, opts ...ocr.Option) (ocr.Result, error) {
+	return p.result, nil
+}
+
+func (p fakeProvider) ExtractTextFromURL(ctx context.Context, imageURL string, opts ...ocr.Option) (ocr.Result, error) {
+	return p.result, nil
+}
+
+func TestMinConfidence(t *testing.T) {
+	tests := []struct {
+		name         string
+		confidence   float32
+		opts         []ocr.Option
+		wantRejected bool
+	}{
+		{name: "high confidence passes", confidence: 0.95, opts: []ocr.Option{ocr.WithMinConfidence(0.8)}},
+		{name: "exactly at threshold passes", confidence: 0.8, opts: []ocr.Option{ocr.WithMinConfidence(0.8)}},
+		{name: "low confidence rejected", confidence: 0.4, opts: []ocr.Option{ocr.WithMinConfidence(0.8)}, wantRejected: true},
+		{name: "no threshold accepts anything", confidence: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := ocr.NewClient(fakeProvider{result: ocr.Result{Text: "INVOICE 42", Confidence: tt.confidence}})
+
+			extractors := map[string]func() (ocr.Result, error){
+				"ExtractText": func() (ocr.Result, error) {
+					return client.ExtractText(context.Background(), []byte("image"), tt.opts...)
+				},
+				"ExtractTextFromURL": func() (ocr.Result, error) {
+					return client.ExtractTextFromURL(context.Background(), "https://example.com/scan.png", tt.opts...)
+				},
+			}
+
+			for method, extract := range extractors {
+				result, err := extract()
+				if tt.wantRejected {
+					if !ocr.IsLowConfidence(err) {
+						t.Errorf("%s: err = %v, want a low confidence error", method, err)
+					}
+				} else if err != nil {
+					t.Errorf("%s: unexpected error: %v", method, err)
+				}
+				if result.Rejected != tt.wantRejected {
+					t.Errorf("%s: Rejected = %v, want %v", method, result.Rejected, tt.wantRejected)
+				}
+				if result.Text != "INVOICE 42" {
+					t.Errorf("%s: Text = %q, want the extracted text kept", method, result.Text)
+				}
+			}
+		})
+	}
+}
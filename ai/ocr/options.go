@@ -5,9 +5,14 @@ type OCROptions struct {
 	// Model is the OCR model to use
 	Model string
 
-	// Language specifies the expected language(s) in the image
+	// Language specifies the expected language in the image, or "auto" to
+	// have it detected
 	Language string
 
+	// Languages lists the expected languages of a mixed-language document,
+	// e.g. ["en", "es"]. It takes precedence over Language when set.
+	Languages []string
+
 	// DetectOrientation automatically rotates the image if needed
 	DetectOrientation bool
 
@@ -19,6 +24,12 @@ type OCROptions struct {
 
 	// User is an optional user identifier for tracking and rate limiting
 	User string
+
+	// MinConfidence rejects results whose Confidence is below it (0 disables)
+	MinConfidence float32
+
+	// Concurrency limits how many images ExtractTextBatch processes at once
+	Concurrency int
 }
 
 // Option is a function type to modify OCROptions
@@ -31,13 +42,20 @@ func WithModel(model string) Option {
 	}
 }
 
-// WithLanguage sets the expected language(s)
+// WithLanguage sets the expected language
 func WithLanguage(language string) Option {
 	return func(o *OCROptions) {
 		o.Language = language
 	}
 }
 
+// WithLanguages sets the expected languages of a mixed-language document
+func WithLanguages(languages ...string) Option {
+	return func(o *OCROptions) {
+		o.Languages = languages
+	}
+}
+
 // WithDetectOrientation enables automatic image orientation detection
 func WithDetectOrientation(detect bool) Option {
 	return func(o *OCROptions) {
@@ -59,6 +77,33 @@ func WithUser(user string) Option {
 	}
 }
 
+// WithMinConfidence rejects results whose overall confidence is below min (0-1)
+func WithMinConfidence(min float32) Option {
+	return func(o *OCROptions) {
+		o.MinConfidence = min
+	}
+}
+
+// WithConcurrency sets how many images ExtractTextBatch sends to the provider
+// at once. Keep it within the provider's rate limits.
+func WithConcurrency(n int) Option {
+	return func(o *OCROptions) {
+		o.Concurrency = n
+	}
+}
+
+// LanguageHints returns the expected languages, or nil when the language
+// should be auto-detected
+func (o *OCROptions) LanguageHints() []string {
+	if len(o.Languages) > 0 {
+		return o.Languages
+	}
+	if o.Language == "" || o.Language == "auto" {
+		return nil
+	}
+	return []string{o.Language}
+}
+
 // DefaultOptions returns the default OCR options
 func DefaultOptions() *OCROptions {
 	return &OCROptions{
@@ -66,6 +111,7 @@ func DefaultOptions() *OCROptions {
 		Language:          "auto",
 		DetectOrientation: true,
 		DetailsLevel:      "medium",
+		Concurrency:       4,
 	}
 }
 
@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Abraxas-365/craftable/ai/embedding"
@@ -27,6 +30,16 @@ type OpenAIProvider struct {
 	client openai.Client
 }
 
+// OpenAIProvider supports every capability (see llm.CapabilitiesOf)
+var (
+	_ llm.LLM                 = (*OpenAIProvider)(nil)
+	_ embedding.Embedder      = (*OpenAIProvider)(nil)
+	_ ocr.OCRProvider         = (*OpenAIProvider)(nil)
+	_ speech.Speaker          = (*OpenAIProvider)(nil)
+	_ speech.StreamingSpeaker = (*OpenAIProvider)(nil)
+	_ speech.Transcriber      = (*OpenAIProvider)(nil)
+)
+
 // NewOpenAIProvider creates a new OpenAI provider
 func NewOpenAIProvider(apiKey string, opts ...option.RequestOption) *OpenAIProvider {
 	if apiKey == "" {
@@ -195,6 +208,10 @@ func (p *OpenAIProvider) ChatStream(ctx context.Context, messages []llm.Message,
 		}
 	}
 
+	if options.Seed != 0 {
+		params.Seed = openai.Int(options.Seed)
+	}
+
 	// Set reasoning effort for reasoning models
 	if options.ReasoningEffort != "" {
 		params.ReasoningEffort = convertToOpenAIReasoningEffort(options.ReasoningEffort)
@@ -220,40 +237,58 @@ func (p *OpenAIProvider) ChatStream(ctx context.Context, messages []llm.Message,
 		params.ResponseFormat = convertToResponseFormatParam(options.ResponseFormat)
 	}
 
-	// Create the stream
-	sseStream := p.client.Chat.Completions.NewStreaming(ctx, params)
+	// Create the stream on its own context so Close can abort the request
+	streamCtx, cancel := context.WithCancel(ctx)
+	sseStream := p.client.Chat.Completions.NewStreaming(streamCtx, params)
 
 	// Return our stream adapter
 	return &openAIStream{
 		stream:      sseStream,
+		cancel:      cancel,
 		accumulator: openai.ChatCompletionAccumulator{},
 	}, nil
 }
 
-// openAIStream adapts the OpenAI streaming response to our Stream interface
+// openAIStream adapts the OpenAI streaming response to our Stream interface.
+// The response body is released when the stream ends, fails, is closed or its
+// context is cancelled, so abandoned generations don't hold pooled connections.
 type openAIStream struct {
 	stream interface {
 		Next() bool
 		Current() openai.ChatCompletionChunk
 		Err() error
+		Close() error
 	}
+	cancel      context.CancelFunc
 	accumulator openai.ChatCompletionAccumulator
 	lastError   error
 	current     llm.Message
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
 }
 
 func (s *openAIStream) Next() (llm.Message, error) {
 	if s.lastError != nil {
 		return llm.Message{}, s.lastError
 	}
+	if s.closed.Load() {
+		s.lastError = io.EOF
+		return llm.Message{}, io.EOF
+	}
 
 	if !s.stream.Next() {
-		if err := s.stream.Err(); err != nil {
+		if s.closed.Load() {
+			// Closed from another goroutine while waiting for a chunk
+			s.lastError = io.EOF
+		} else if err := s.stream.Err(); err != nil {
 			s.lastError = err
-			return llm.Message{}, err
+		} else {
+			s.lastError = io.EOF
 		}
-		s.lastError = io.EOF
-		return llm.Message{}, io.EOF
+		s.release()
+		return llm.Message{}, s.lastError
 	}
 
 	chunk := s.stream.Current()
@@ -300,8 +335,20 @@ func (s *openAIStream) Next() (llm.Message, error) {
 	return s.current, nil
 }
 
+// Close aborts the request and closes the response body. It is safe to call
+// more than once and concurrently with Next, which then returns io.EOF.
 func (s *openAIStream) Close() error {
-	return nil
+	s.closed.Store(true)
+	return s.release()
+}
+
+// release cancels the stream context and closes the SSE stream exactly once
+func (s *openAIStream) release() error {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.closeErr = s.stream.Close()
+	})
+	return s.closeErr
 }
 
 // Helper functions
@@ -389,31 +436,45 @@ func convertToOpenAITools(tools []llm.Tool, functions []llm.Function) []openai.C
 	return result
 }
 
+// convertToOpenAIToolChoice maps llm.WithToolChoice values: "auto", "none" and
+// "required", or a function to force, given by name or in OpenAI's
+// {"type": "function", "function": {"name": ...}} shape
 func convertToOpenAIToolChoice(toolChoice any) openai.ChatCompletionToolChoiceOptionUnionParam {
 	if strChoice, ok := toolChoice.(string); ok {
 		switch strChoice {
-		case "auto":
-			return openai.ChatCompletionToolChoiceOptionUnionParam{
-				OfAuto: openai.String("auto"),
-			}
-		case "none":
+		case "auto", "none", "required":
 			return openai.ChatCompletionToolChoiceOptionUnionParam{
-				OfAuto: openai.String("none"),
+				OfAuto: openai.String(strChoice),
 			}
-		case "required":
-			return openai.ChatCompletionToolChoiceOptionUnionParam{
-				OfAuto: openai.String("required"),
+		case "":
+		default:
+			return namedToolChoice(strChoice)
+		}
+	}
+
+	if mapChoice, ok := toolChoice.(map[string]any); ok {
+		if fn, ok := mapChoice["function"].(map[string]any); ok {
+			if name, ok := fn["name"].(string); ok && name != "" {
+				return namedToolChoice(name)
 			}
 		}
 	}
 
-	// For specific tool choice - simplified approach
-	// Just default to auto if we can't parse it properly
+	// Default to auto if we can't parse it
 	return openai.ChatCompletionToolChoiceOptionUnionParam{
 		OfAuto: openai.String("auto"),
 	}
 }
 
+// namedToolChoice forces a call to the named function
+func namedToolChoice(name string) openai.ChatCompletionToolChoiceOptionUnionParam {
+	return openai.ChatCompletionToolChoiceOptionUnionParam{
+		OfFunctionToolChoice: &openai.ChatCompletionNamedToolChoiceParam{
+			Function: openai.ChatCompletionNamedToolChoiceFunctionParam{Name: name},
+		},
+	}
+}
+
 func convertToOpenAIReasoningEffort(effort string) shared.ReasoningEffort {
 	switch strings.ToLower(effort) {
 	case "low":
@@ -503,8 +564,9 @@ func convertFromOpenAIResponse(completion *openai.ChatCompletion) (llm.Response,
 	}
 
 	return llm.Response{
-		Message: message,
-		Usage:   usage,
+		Message:      message,
+		Usage:        usage,
+		FinishReason: string(choice.FinishReason),
 	}, nil
 }
 
@@ -583,9 +645,7 @@ func (p *OpenAIProvider) ExtractText(ctx context.Context, imageData []byte, opts
 	base64Image := base64.StdEncoding.EncodeToString(imageData)
 
 	systemContent := "You are an OCR system that extracts text from images. "
-	if options.Language != "auto" {
-		systemContent += fmt.Sprintf("The text is in %s. ", options.Language)
-	}
+	systemContent += ocrLanguageInstruction(options)
 	if options.DetectOrientation {
 		systemContent += "Detect and account for text orientation. "
 	}
@@ -659,10 +719,11 @@ func (p *OpenAIProvider) ExtractText(ctx context.Context, imageData []byte, opts
 		return ocr.Result{}, errors.New("no response from API")
 	}
 
-	textContent := completion.Choices[0].Message.Content
+	textContent, detectedLanguage := splitDetectedLanguage(completion.Choices[0].Message.Content)
 
 	result := ocr.Result{
-		Text: textContent,
+		Text:             textContent,
+		DetectedLanguage: detectedLanguage,
 		Usage: ocr.Usage{
 			PromptTokens:     int(completion.Usage.PromptTokens),
 			CompletionTokens: int(completion.Usage.CompletionTokens),
@@ -689,9 +750,7 @@ func (p *OpenAIProvider) ExtractTextFromURL(ctx context.Context, imageURL string
 	}
 
 	systemContent := "You are an OCR system that extracts text from images. "
-	if options.Language != "auto" {
-		systemContent += fmt.Sprintf("The text is in %s. ", options.Language)
-	}
+	systemContent += ocrLanguageInstruction(options)
 	if options.DetectOrientation {
 		systemContent += "Detect and account for text orientation. "
 	}
@@ -765,10 +824,11 @@ func (p *OpenAIProvider) ExtractTextFromURL(ctx context.Context, imageURL string
 		return ocr.Result{}, errors.New("no response from API")
 	}
 
-	textContent := completion.Choices[0].Message.Content
+	textContent, detectedLanguage := splitDetectedLanguage(completion.Choices[0].Message.Content)
 
 	result := ocr.Result{
-		Text: textContent,
+		Text:             textContent,
+		DetectedLanguage: detectedLanguage,
 		Usage: ocr.Usage{
 			PromptTokens:     int(completion.Usage.PromptTokens),
 			CompletionTokens: int(completion.Usage.CompletionTokens),
@@ -788,6 +848,36 @@ func (p *OpenAIProvider) ExtractTextFromURL(ctx context.Context, imageURL string
 	return result, nil
 }
 
+// ocrLanguageInstruction passes the language hints to the model and asks it to
+// report the main language of the text on a final line
+func ocrLanguageInstruction(options *ocr.OCROptions) string {
+	var instruction string
+	switch hints := options.LanguageHints(); len(hints) {
+	case 0:
+	case 1:
+		instruction = fmt.Sprintf("The text is in %s. ", hints[0])
+	default:
+		instruction = fmt.Sprintf("The text may mix these languages: %s. ", strings.Join(hints, ", "))
+	}
+	return instruction + `End your answer with a final line "Language: <code>" giving the ISO 639-1 code of the main language of the text. `
+}
+
+// ocrLanguageLine matches the "Language: <code>" line, tolerating markdown emphasis
+var ocrLanguageLine = regexp.MustCompile(`(?i)^[\s*_]*(?:detected\s+)?language[\s*_]*:[\s*_]*([a-z]{2,3}(?:-[a-z0-9]+)?)[\s*_.]*$`)
+
+// splitDetectedLanguage removes the final language line from an OCR answer and
+// returns the text and the lower-cased language code ("" when not reported)
+func splitDetectedLanguage(text string) (string, string) {
+	trimmed := strings.TrimRight(text, " \t\r\n")
+	lastLine := trimmed[strings.LastIndex(trimmed, "\n")+1:]
+
+	match := ocrLanguageLine.FindStringSubmatch(lastLine)
+	if match == nil {
+		return text, ""
+	}
+	return strings.TrimRight(strings.TrimSuffix(trimmed, lastLine), " \t\r\n"), strings.ToLower(match[1])
+}
+
 func estimateConfidence(text string) float32 {
 	if strings.Contains(strings.ToLower(text), "low confidence") {
 		return 0.3
@@ -845,20 +935,22 @@ func (p *OpenAIProvider) Synthesize(ctx context.Context, text string, opts ...sp
 		responseFormat = openai.AudioSpeechNewParamsResponseFormatPCM
 	}
 
-	voice := openai.AudioSpeechNewParamsVoiceAlloy
+	voice := openai.AudioSpeechNewParamsVoiceString2Alloy
 	switch strings.ToLower(options.Voice) {
 	case "alloy":
-		voice = openai.AudioSpeechNewParamsVoiceAlloy
+		voice = openai.AudioSpeechNewParamsVoiceString2Alloy
 	case "echo":
-		voice = openai.AudioSpeechNewParamsVoiceEcho
+		voice = openai.AudioSpeechNewParamsVoiceString2Echo
 	default:
-		voice = openai.AudioSpeechNewParamsVoiceAlloy
+		voice = openai.AudioSpeechNewParamsVoiceString2Alloy
 	}
 
 	params := openai.AudioSpeechNewParams{
-		Model:          options.Model,
-		Input:          text,
-		Voice:          voice,
+		Model: options.Model,
+		Input: text,
+		Voice: openai.AudioSpeechNewParamsVoiceUnion{
+			OfAudioSpeechNewsVoiceString2: openai.String(string(voice)),
+		},
 		ResponseFormat: responseFormat,
 	}
 
@@ -886,6 +978,16 @@ func (p *OpenAIProvider) Synthesize(ctx context.Context, text string, opts ...sp
 	}, nil
 }
 
+// SynthesizeStream streams speech audio as the API sends it, so playback can
+// start before synthesis completes
+func (p *OpenAIProvider) SynthesizeStream(ctx context.Context, text string, opts ...speech.SynthesisOption) (*speech.AudioStream, error) {
+	audio, err := p.Synthesize(ctx, text, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return speech.StreamAudio(ctx, audio), nil
+}
+
 func (p *OpenAIProvider) Transcribe(ctx context.Context, audio io.Reader, opts ...speech.TranscriptionOption) (speech.Transcript, error) {
 	options := speech.TranscriptionOptions{
 		Model:      string(openai.AudioModelWhisper1),
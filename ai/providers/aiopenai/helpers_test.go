@@ -0,0 +1,99 @@
+package aiopenai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+	"github.com/openai/openai-go/v3/option"
+)
+
+// chatCompletionResponse is a minimal Chat Completions API response
+const chatCompletionResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1,
+	"model": "gpt-4o",
+	"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "hello"}}]
+}`
+
+// chatCompletionStream is a minimal streamed Chat Completions API response
+const chatCompletionStream = "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"hello\"}}]}\n\n" +
+	"data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+	"data: [DONE]\n\n"
+
+// testServer is a fake OpenAI API that records the JSON request bodies
+type testServer struct {
+	mutex  sync.Mutex
+	bodies []map[string]any
+}
+
+// lastBody returns the body of the most recent request
+func (s *testServer) lastBody(t *testing.T) map[string]any {
+	t.Helper()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.bodies) == 0 {
+		t.Fatal("no request received")
+	}
+	return s.bodies[len(s.bodies)-1]
+}
+
+// newTestProvider returns a provider talking to a fake API that answers chat
+// completions, streamed or not, with "hello"
+func newTestProvider(t *testing.T) (*OpenAIProvider, *testServer) {
+	t.Helper()
+
+	server := &testServer{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+
+		server.mutex.Lock()
+		server.bodies = append(server.bodies, body)
+		server.mutex.Unlock()
+
+		if stream, _ := body["stream"].(bool); stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, chatCompletionStream)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, chatCompletionResponse)
+	}))
+	t.Cleanup(srv.Close)
+
+	provider := NewOpenAIProvider("test-key", option.WithBaseURL(srv.URL), option.WithMaxRetries(0))
+	return provider, server
+}
+
+// chat sends messages through Chat, or through ChatStream draining the stream
+func chat(t *testing.T, provider *OpenAIProvider, stream bool, messages []llm.Message, opts ...llm.Option) {
+	t.Helper()
+
+	if !stream {
+		if _, err := provider.Chat(context.Background(), messages, opts...); err != nil {
+			t.Fatalf("Chat: %v", err)
+		}
+		return
+	}
+
+	s, err := provider.ChatStream(context.Background(), messages, opts...)
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	defer s.Close()
+	for {
+		if _, err := s.Next(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package aiopenai
+
+import (
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+func TestSeedIsSent(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   bool
+		opts     []llm.Option
+		wantSeed any
+	}{
+		{name: "chat", opts: []llm.Option{llm.WithSeed(42)}, wantSeed: float64(42)},
+		{name: "stream", stream: true, opts: []llm.Option{llm.WithSeed(42)}, wantSeed: float64(42)},
+		{name: "chat without seed", wantSeed: nil},
+		{name: "stream without seed", stream: true, wantSeed: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, server := newTestProvider(t)
+			chat(t, provider, tt.stream, []llm.Message{llm.NewUserMessage("hi")}, tt.opts...)
+
+			body := server.lastBody(t)
+			if got := body["seed"]; got != tt.wantSeed {
+				t.Errorf("seed = %v, want %v", got, tt.wantSeed)
+			}
+			if stream, _ := body["stream"].(bool); stream != tt.stream {
+				t.Errorf("stream = %v, want %v", stream, tt.stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package aiopenai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+	"github.com/openai/openai-go/v3/option"
+)
+
+// firstChunk is the only chunk the hanging server sends
+const firstChunk = "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Once\"}}]}\n\n"
+
+// newHangingProvider returns a provider whose streams send one chunk and then
+// hang until the client goes away, which is reported on the returned channel
+func newHangingProvider(t *testing.T) (*OpenAIProvider, <-chan struct{}) {
+	t.Helper()
+
+	disconnected := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, firstChunk)
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+		disconnected <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewOpenAIProvider("test-key", option.WithBaseURL(srv.URL), option.WithMaxRetries(0)), disconnected
+}
+
+// waitForGoroutines waits until at most baseline goroutines are running
+func waitForGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running, started with %d", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamAbort(t *testing.T) {
+	tests := []struct {
+		name    string
+		abort   func(stream llm.Stream, cancel context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name:    "context cancelled",
+			abort:   func(_ llm.Stream, cancel context.CancelFunc) { cancel() },
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "stream closed",
+			abort:   func(stream llm.Stream, _ context.CancelFunc) { stream.Close() },
+			wantErr: io.EOF,
+		},
+		{
+			name: "stream closed while waiting for a chunk",
+			abort: func(stream llm.Stream, _ context.CancelFunc) {
+				time.AfterFunc(20*time.Millisecond, func() { stream.Close() })
+			},
+			wantErr: io.EOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, disconnected := newHangingProvider(t)
+			baseline := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream, err := provider.ChatStream(ctx, []llm.Message{llm.NewUserMessage("tell me a story")})
+			if err != nil {
+				t.Fatalf("ChatStream: %v", err)
+			}
+			msg, err := stream.Next()
+			if err != nil || msg.Content != "Once" {
+				t.Fatalf("first chunk = %q, %v", msg.Content, err)
+			}
+
+			tt.abort(stream, cancel)
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := stream.Next()
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Next after abort: err = %v, want %v", err, tt.wantErr)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Next kept waiting after the stream was aborted")
+			}
+
+			select {
+			case <-disconnected:
+			case <-time.After(2 * time.Second):
+				t.Fatal("the HTTP request was not aborted")
+			}
+
+			if err := stream.Close(); err != nil {
+				t.Errorf("Close after abort: %v", err)
+			}
+			waitForGoroutines(t, baseline)
+		})
+	}
+}
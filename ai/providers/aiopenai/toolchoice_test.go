@@ -0,0 +1,58 @@
+package aiopenai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/ai/llm"
+)
+
+func TestToolChoiceIsSent(t *testing.T) {
+	tools := []llm.Tool{{
+		Type:     "function",
+		Function: llm.Function{Name: "get_weather", Parameters: map[string]any{"type": "object"}},
+	}}
+	named := map[string]any{"type": "function", "function": map[string]any{"name": "get_weather"}}
+
+	tests := []struct {
+		name       string
+		toolChoice any
+		want       any
+	}{
+		{name: "unset", want: nil},
+		{name: "auto", toolChoice: "auto", want: "auto"},
+		{name: "none", toolChoice: "none", want: "none"},
+		{name: "required", toolChoice: "required", want: "required"},
+		{name: "function by name", toolChoice: "get_weather", want: named},
+		{name: "function in OpenAI shape", toolChoice: named, want: named},
+		{name: "empty string defaults to auto", toolChoice: "", want: "auto"},
+		{name: "map without a name defaults to auto", toolChoice: map[string]any{"type": "function"}, want: "auto"},
+		{name: "unsupported type defaults to auto", toolChoice: 42, want: "auto"},
+	}
+
+	for _, tt := range tests {
+		for _, stream := range []bool{false, true} {
+			path := "chat"
+			if stream {
+				path = "stream"
+			}
+			t.Run(tt.name+"/"+path, func(t *testing.T) {
+				provider, server := newTestProvider(t)
+
+				opts := []llm.Option{llm.WithTools(tools)}
+				if tt.toolChoice != nil {
+					opts = append(opts, llm.WithToolChoice(tt.toolChoice))
+				}
+				chat(t, provider, stream, []llm.Message{llm.NewUserMessage("weather in Lima?")}, opts...)
+
+				body := server.lastBody(t)
+				if got := body["tool_choice"]; !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("tool_choice = %v, want %v", got, tt.want)
+				}
+				if got, _ := body["stream"].(bool); got != stream {
+					t.Errorf("stream = %v, want %v", got, stream)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// streamChunkSize is how many bytes of audio each chunk carries at most
+const streamChunkSize = 32 * 1024
+
+// StreamingSpeaker is implemented by speakers that can return audio while it
+// is still being synthesized
+type StreamingSpeaker interface {
+	Speaker
+
+	// SynthesizeStream converts text to speech, emitting audio as it arrives
+	SynthesizeStream(ctx context.Context, text string, opts ...SynthesisOption) (*AudioStream, error)
+}
+
+// AudioChunk is a piece of streamed audio. A chunk with Err set is the last one.
+type AudioChunk struct {
+	// Data is the audio bytes, to be played in order
+	Data []byte
+
+	// Segment is the index of the sentence this chunk belongs to when the text
+	// was synthesized sentence by sentence, otherwise 0
+	Segment int
+
+	// Text is the sentence being spoken, set on the first chunk of each segment
+	Text string
+
+	// Err reports a synthesis failure; no chunks follow it
+	Err error
+}
+
+// AudioStream delivers synthesized audio in chunks so playback can start
+// before synthesis completes. Chunks is closed when the audio is complete.
+type AudioStream struct {
+	// Chunks carries the audio in playback order
+	Chunks <-chan AudioChunk
+
+	// Format indicates the audio format (MP3, WAV, etc.)
+	Format AudioFormat
+
+	// SampleRate of the audio in Hz
+	SampleRate int
+
+	cancel context.CancelFunc
+}
+
+// Close stops synthesis and releases the stream. Always call it, including
+// when abandoning a stream before Chunks is closed.
+func (s *AudioStream) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	// Drain so the producer can exit
+	for range s.Chunks {
+	}
+	return nil
+}
+
+// ReadAll collects the remaining audio, returning the first chunk error
+func (s *AudioStream) ReadAll() ([]byte, error) {
+	var data []byte
+	for chunk := range s.Chunks {
+		if chunk.Err != nil {
+			return data, chunk.Err
+		}
+		data = append(data, chunk.Data...)
+	}
+	return data, nil
+}
+
+// StreamAudio emits an Audio's content in chunks as it is read, for speakers
+// whose HTTP response body is already streamed. The content is closed when the
+// stream ends.
+func StreamAudio(ctx context.Context, audio Audio) *AudioStream {
+	ctx, cancel := context.WithCancel(ctx)
+	chunks := make(chan AudioChunk)
+
+	go func() {
+		defer close(chunks)
+		defer cancel()
+		defer audio.Content.Close()
+		pumpAudio(ctx, audio.Content, 0, "", chunks)
+	}()
+
+	return &AudioStream{
+		Chunks:     chunks,
+		Format:     audio.Format,
+		SampleRate: audio.SampleRate,
+		cancel:     cancel,
+	}
+}
+
+// pumpAudio copies r to chunks, reporting whether it finished without error
+func pumpAudio(ctx context.Context, r io.Reader, segment int, text string, chunks chan<- AudioChunk) bool {
+	send := func(chunk AudioChunk) bool {
+		select {
+		case chunks <- chunk:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			if !send(AudioChunk{Data: data, Segment: segment, Text: text}) {
+				return false
+			}
+			text = ""
+		}
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+		if err != nil {
+			send(AudioChunk{Segment: segment, Err: fmt.Errorf("error reading audio: %w", err)})
+			return false
+		}
+	}
+}
+
+// SynthesizeStream converts text to speech and streams the audio. Speakers
+// implementing StreamingSpeaker stream natively; others synthesize the text
+// sentence by sentence and emit each segment as soon as it is ready. Segments
+// are separate files concatenated in order, which plays back seamlessly for
+// MP3 and raw PCM; prefer those formats for sentence-by-sentence streaming.
+func (c *TTSClient) SynthesizeStream(ctx context.Context, text string, opts ...SynthesisOption) (*AudioStream, error) {
+	if streamer, ok := c.speaker.(StreamingSpeaker); ok {
+		return streamer.SynthesizeStream(ctx, text, opts...)
+	}
+
+	sentences := SplitSentences(text)
+	if len(sentences) == 0 {
+		return nil, fmt.Errorf("no text to synthesize")
+	}
+
+	// Synthesize the first sentence up front so errors and the audio
+	// metadata are known before returning
+	first, err := c.speaker.Synthesize(ctx, sentences[0], opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	chunks := make(chan AudioChunk)
+
+	go func() {
+		defer close(chunks)
+		defer cancel()
+
+		audio := first
+		for i, sentence := range sentences {
+			if i > 0 {
+				var err error
+				audio, err = c.speaker.Synthesize(ctx, sentence, opts...)
+				if err != nil {
+					select {
+					case chunks <- AudioChunk{Segment: i, Err: err}:
+					case <-ctx.Done():
+					}
+					return
+				}
+			}
+
+			ok := pumpAudio(ctx, audio.Content, i, sentence, chunks)
+			audio.Content.Close()
+			if !ok {
+				return
+			}
+		}
+	}()
+
+	return &AudioStream{
+		Chunks:     chunks,
+		Format:     first.Format,
+		SampleRate: first.SampleRate,
+		cancel:     cancel,
+	}, nil
+}
+
+// SplitSentences splits text into sentences at ., ! and ? followed by
+// whitespace, and at line breaks. Empty sentences are dropped.
+func SplitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+
+	flush := func() {
+		if sentence := strings.TrimSpace(current.String()); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == '\n' {
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			flush()
+		}
+	}
+	flush()
+
+	return sentences
+}
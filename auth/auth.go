@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,6 +13,7 @@ type OAuthToken struct {
 	AccessToken  string    `json:"access_token"`
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	IDToken      string    `json:"id_token,omitempty"` // OpenID Connect ID token, for providers that carry identity in it
 }
 
 // OAuthAccount represents the stored OAuth credentials
@@ -38,6 +40,11 @@ type AuthUserInfo interface {
 	GetProfilePicture() *string
 	GetToken() *OAuthToken
 	GetRawData() map[string]any // Access to the raw data from provider
+
+	// Normalized profile accessors, consistent across providers
+	GetDisplayName() string // Best human-readable name, never empty when an email is known
+	GetAvatarURL() string   // Profile picture URL, or "" when the provider has none
+	IsEmailVerified() bool  // Whether the provider asserts the email is verified
 }
 
 // BasicAuthUserInfo is a standard implementation of AuthUserInfo
@@ -49,6 +56,7 @@ type BasicAuthUserInfo struct {
 	ProfilePicture *string        `json:"profile_picture,omitempty"`
 	Token          *OAuthToken    `json:"token"`
 	RawData        map[string]any `json:"raw_data,omitempty"`
+	EmailVerified  bool           `json:"email_verified"`
 }
 
 // Implement AuthUserInfo interface
@@ -59,6 +67,26 @@ func (u *BasicAuthUserInfo) GetProvider() string        { return u.Provider }
 func (u *BasicAuthUserInfo) GetProfilePicture() *string { return u.ProfilePicture }
 func (u *BasicAuthUserInfo) GetToken() *OAuthToken      { return u.Token }
 func (u *BasicAuthUserInfo) GetRawData() map[string]any { return u.RawData }
+func (u *BasicAuthUserInfo) IsEmailVerified() bool      { return u.EmailVerified }
+
+// GetDisplayName returns Name, falling back to the local part of the email
+func (u *BasicAuthUserInfo) GetDisplayName() string {
+	if name := strings.TrimSpace(u.Name); name != "" {
+		return name
+	}
+	if local, _, found := strings.Cut(u.Email, "@"); found {
+		return local
+	}
+	return u.Email
+}
+
+// GetAvatarURL returns the profile picture URL, or "" when unset
+func (u *BasicAuthUserInfo) GetAvatarURL() string {
+	if u.ProfilePicture == nil {
+		return ""
+	}
+	return *u.ProfilePicture
+}
 
 // User represents the basic requirements for a user model
 type User interface {
@@ -73,6 +101,15 @@ type AuthResponse struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int    `json:"expires_in"`
+
+	// RememberMeToken is only set when a remember-me token was issued or rotated
+	RememberMeToken     string     `json:"remember_me_token,omitempty"`
+	RememberMeExpiresAt *time.Time `json:"remember_me_expires_at,omitempty"`
+
+	// ReturnURL and FlowData are only set by CompleteOAuth, from BeginOAuth's
+	// WithReturnURL and WithFlowData
+	ReturnURL string            `json:"return_url,omitempty"`
+	FlowData  map[string]string `json:"flow_data,omitempty"`
 }
 
 // JWTClaims for token generation
@@ -81,6 +118,20 @@ type JWTClaims struct {
 	Email     string    `json:"email"`
 	ExpiresAt time.Time `json:"exp"`
 	IssuedAt  time.Time `json:"iat"`
+
+	// Fingerprint is the ClientFingerprint hash of a bound token
+	Fingerprint string `json:"cfp,omitempty"`
+
+	// ID identifies the token for RevokeToken
+	ID string `json:"jti,omitempty"`
+
+	// SessionID is the Session the token belongs to, when a SessionStore is configured
+	SessionID string `json:"sid,omitempty"`
+
+	// Version and UserVersion are the global and per-user claims versions the
+	// token was issued under; see BumpTokenVersion and BumpUserTokenVersion
+	Version     int64 `json:"ver,omitempty"`
+	UserVersion int64 `json:"uver,omitempty"`
 }
 
 // Implement jwt.Claims interface methods
@@ -129,11 +180,129 @@ type OAuthAccountStore interface {
 	UpdateOAuthToken(ctx context.Context, provider, providerID string, token *OAuthToken) error
 }
 
+// RememberMeToken is the stored record of a remember-me token series.
+// Only the hash of the current token value is stored, never the raw token.
+type RememberMeToken struct {
+	Series     string    `json:"series"`
+	TokenHash  string    `json:"token_hash"`
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// RememberMeStore persists remember-me token series.
+// GetRememberMeToken must return (nil, nil) when the series does not exist.
+// RotateRememberMeToken must atomically replace the series only while its stored
+// TokenHash still equals oldHash (a compare-and-swap), reporting whether it did.
+type RememberMeStore interface {
+	SaveRememberMeToken(ctx context.Context, token *RememberMeToken) error
+	RotateRememberMeToken(ctx context.Context, oldHash string, token *RememberMeToken) (bool, error)
+	GetRememberMeToken(ctx context.Context, series string) (*RememberMeToken, error)
+	DeleteRememberMeToken(ctx context.Context, series string) error
+	DeleteUserRememberMeTokens(ctx context.Context, userID string) error
+}
+
+// TransientStore keeps short-lived values, such as OAuth state, PKCE code
+// verifiers and nonces, between the auth URL and callback steps.
+// Get must return ("", false, nil) when the key does not exist or has expired.
+// Take is Get and Delete in one atomic operation (e.g. Redis GETDEL), so a value
+// is returned to at most one caller.
+// Multi-instance deployments need a shared implementation (e.g. Redis).
+type TransientStore interface {
+	Put(ctx context.Context, key, value string, ttl time.Duration) error
+	Get(ctx context.Context, key string) (string, bool, error)
+	Take(ctx context.Context, key string) (string, bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// RevocationStore keeps the IDs of access tokens revoked before they expire.
+// Tokens stay self-contained, so this is the stateless mode: only revoked
+// tokens are stored, each until it would have expired anyway.
+type RevocationStore interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// Session is the stored record of an issued access token
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SessionStore keeps a record of every issued access token. This is the
+// stateful mode: a token is only valid while its session exists, so users can
+// list their sessions and log out everywhere.
+// GetSession must return (nil, nil) when the session does not exist or has expired.
+type SessionStore interface {
+	SaveSession(ctx context.Context, session *Session) error
+	GetSession(ctx context.Context, sessionID string) (*Session, error)
+	ListUserSessions(ctx context.Context, userID string) ([]*Session, error)
+	DeleteSession(ctx context.Context, sessionID string) error
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
+
+// UserByIDStore is an optional extension of UserStore. When the configured
+// UserStore implements it, remember-me logins and ValidateToken reload the
+// user and re-check IsActive, so disabled users are rejected immediately.
+type UserByIDStore interface {
+	GetUserByID(ctx context.Context, userID string) (User, error)
+}
+
+// TokenValidityStore is an optional extension of UserStore that keeps, per
+// user, the time before which issued access tokens are no longer accepted.
+// When the configured UserStore implements it, ValidateToken rejects tokens
+// issued before that time and InvalidateUserSessions is available.
+// GetTokensValidAfter must return the zero time when none was set.
+type TokenValidityStore interface {
+	GetTokensValidAfter(ctx context.Context, userID string) (time.Time, error)
+	SetTokensValidAfter(ctx context.Context, userID string, validAfter time.Time) error
+}
+
 // Service interface
 type Service interface {
 	GetAuthURL(provider, state string) (string, error)
 	HandleOAuthCallback(ctx context.Context, provider, code string) (*AuthResponse, error)
+
+	// Stateful OAuth flows with state, PKCE and nonce kept in the TransientStore
+	BeginOAuth(ctx context.Context, provider string, opts ...FlowOption) (*AuthRequest, error)
+	CompleteOAuth(ctx context.Context, provider, state, code string) (*AuthResponse, error)
 	RegisterProvider(name string, provider OAuthProvider)
 	GenerateToken(user User) (string, error)
 	ValidateToken(tokenString string) (*JWTClaims, error)
+
+	// Client-bound tokens, using the ClientFingerprint attached to ctx
+	GenerateTokenContext(ctx context.Context, user User) (string, error)
+	ValidateTokenContext(ctx context.Context, tokenString string) (*JWTClaims, error)
+
+	// Remember-me sessions
+	IssueRememberMeToken(ctx context.Context, user User) (string, time.Time, error)
+	LoginWithRememberMe(ctx context.Context, rememberMeToken string) (*AuthResponse, error)
+	RevokeRememberMeToken(ctx context.Context, rememberMeToken string) error
+	RevokeUserRememberMeTokens(ctx context.Context, userID string) error
+
+	// Access token revocation, with a RevocationStore or SessionStore
+	RevokeToken(ctx context.Context, tokenString string) error
+
+	// Sessions, with a SessionStore
+	ListSessions(ctx context.Context, userID string) ([]*Session, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+
+	// Invalidation of every token of a user (e.g. on password change), with a TokenValidityStore
+	InvalidateUserSessions(ctx context.Context, userID string) error
+
+	// Claims versioning: bumping a version forces re-authentication of everyone,
+	// or of one user with a TokenVersionStore
+	TokenVersion() int64
+	BumpTokenVersion() int64
+	BumpUserTokenVersion(ctx context.Context, userID string) error
+
+	// Password hashing with transparent upgrades of outdated hashes
+	HashPassword(password string) (string, error)
+	VerifyPassword(password, encoded string) (rehashed string, err error)
 }
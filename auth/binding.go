@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// Token binding ties an access token to the client it was issued to. The
+// fingerprint combines the client's user agent with a secret the client keeps
+// (e.g. in an HttpOnly cookie), so a stolen bearer token alone is not enough
+// to use it from another client.
+
+// ClientFingerprint identifies the client presenting a token
+type ClientFingerprint struct {
+	UserAgent string
+	Secret    string // Client-held random value, never put in the token
+}
+
+// Hash returns the value recorded in the token's fingerprint claim
+func (f ClientFingerprint) Hash() string {
+	sum := sha256.Sum256([]byte(f.UserAgent + "\x00" + f.Secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type fingerprintContextKey struct{}
+
+// ContextWithClientFingerprint attaches the requesting client's fingerprint to
+// ctx for GenerateTokenContext and ValidateTokenContext
+func ContextWithClientFingerprint(ctx context.Context, fingerprint ClientFingerprint) context.Context {
+	return context.WithValue(ctx, fingerprintContextKey{}, fingerprint)
+}
+
+// ClientFingerprintFromContext returns the fingerprint attached to ctx. It
+// reports false when there is none or its Secret is empty.
+func ClientFingerprintFromContext(ctx context.Context) (ClientFingerprint, bool) {
+	fingerprint, ok := ctx.Value(fingerprintContextKey{}).(ClientFingerprint)
+	return fingerprint, ok && fingerprint.Secret != ""
+}
+
+// IsTokenBindingMismatch reports whether a token was presented by a client
+// other than the one it was bound to
+func IsTokenBindingMismatch(err error) bool {
+	return errx.IsCode(err, ErrTokenBindingMismatch)
+}
+
+// GenerateTokenContext generates an access token bound to the client
+// fingerprint in ctx, if any. With WithTokenBinding a fingerprint is required.
+func (s *service) GenerateTokenContext(ctx context.Context, user User) (string, error) {
+	fingerprint, ok := ClientFingerprintFromContext(ctx)
+	if !ok {
+		if s.requireTokenBinding {
+			return "", authErrors.New(ErrTokenGeneration).
+				WithDetail("error", "client fingerprint required for token binding")
+		}
+		return s.generateToken(ctx, user, ClientFingerprint{UserAgent: fingerprint.UserAgent})
+	}
+
+	return s.generateToken(ctx, user, fingerprint)
+}
+
+// ValidateTokenContext validates a token and, when it is bound, checks that
+// the fingerprint in ctx matches. With WithTokenBinding unbound tokens are
+// rejected too.
+func (s *service) ValidateTokenContext(ctx context.Context, tokenString string) (*JWTClaims, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTokenState(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Fingerprint == "" {
+		if s.requireTokenBinding {
+			return nil, authErrors.New(ErrTokenBindingMismatch).
+				WithDetail("error", "token is not bound to a client")
+		}
+		return claims, nil
+	}
+
+	fingerprint, ok := ClientFingerprintFromContext(ctx)
+	if !ok {
+		return nil, authErrors.New(ErrTokenBindingMismatch).
+			WithDetail("error", "client fingerprint missing")
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Fingerprint), []byte(fingerprint.Hash())) != 1 {
+		return nil, authErrors.New(ErrTokenBindingMismatch).
+			WithDetail("user_id", claims.UserID)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+func TestTokenBinding(t *testing.T) {
+	browser := auth.ClientFingerprint{UserAgent: "Mozilla/5.0", Secret: "cookie-secret"}
+
+	withFingerprint := func(fingerprint *auth.ClientFingerprint) context.Context {
+		if fingerprint == nil {
+			return context.Background()
+		}
+		return auth.ContextWithClientFingerprint(context.Background(), *fingerprint)
+	}
+
+	tests := []struct {
+		name          string
+		opts          []auth.ServiceOption
+		issuedTo      *auth.ClientFingerprint
+		presentedBy   *auth.ClientFingerprint
+		wantIssueErr  errx.Code
+		wantMismatch  bool
+		wantValidated bool
+	}{
+		{
+			name:          "same client",
+			issuedTo:      &browser,
+			presentedBy:   &browser,
+			wantValidated: true,
+		},
+		{
+			name:         "different secret",
+			issuedTo:     &browser,
+			presentedBy:  &auth.ClientFingerprint{UserAgent: browser.UserAgent, Secret: "stolen"},
+			wantMismatch: true,
+		},
+		{
+			name:         "different user agent",
+			issuedTo:     &browser,
+			presentedBy:  &auth.ClientFingerprint{UserAgent: "curl/8.0", Secret: browser.Secret},
+			wantMismatch: true,
+		},
+		{
+			name:         "fingerprint missing",
+			issuedTo:     &browser,
+			wantMismatch: true,
+		},
+		{
+			name:          "unbound token",
+			presentedBy:   &browser,
+			wantValidated: true,
+		},
+		{
+			name:         "unbound token with binding required",
+			opts:         []auth.ServiceOption{auth.WithTokenBinding()},
+			wantIssueErr: auth.ErrTokenGeneration,
+		},
+		{
+			name:          "bound token with binding required",
+			opts:          []auth.ServiceOption{auth.WithTokenBinding()},
+			issuedTo:      &browser,
+			presentedBy:   &browser,
+			wantValidated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &testUser{id: "user-1", email: "ada@example.com", active: true}
+			service := newTestService(newTestUserStore(user), time.Hour, tt.opts...)
+
+			token, err := service.GenerateTokenContext(withFingerprint(tt.issuedTo), user)
+			if tt.wantIssueErr != "" {
+				if !errx.IsCode(err, tt.wantIssueErr) {
+					t.Fatalf("GenerateTokenContext: err = %v, want %s", err, tt.wantIssueErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateTokenContext: %v", err)
+			}
+
+			claims, err := service.ValidateTokenContext(withFingerprint(tt.presentedBy), token)
+			if got := auth.IsTokenBindingMismatch(err); got != tt.wantMismatch {
+				t.Errorf("IsTokenBindingMismatch = %v, want %v (err: %v)", got, tt.wantMismatch, err)
+			}
+			if tt.wantValidated {
+				if err != nil {
+					t.Fatalf("ValidateTokenContext: %v", err)
+				}
+				if claims.UserID != user.id {
+					t.Errorf("UserID = %q, want %q", claims.UserID, user.id)
+				}
+			} else if err == nil {
+				t.Error("token was accepted")
+			}
+		})
+	}
+}
+
+func TestValidateTokenRejectsBoundTokens(t *testing.T) {
+	user := &testUser{id: "user-1", email: "ada@example.com", active: true}
+	service := newTestService(newTestUserStore(user), time.Hour)
+
+	ctx := auth.ContextWithClientFingerprint(context.Background(), auth.ClientFingerprint{UserAgent: "Mozilla/5.0", Secret: "s"})
+	token, err := service.GenerateTokenContext(ctx, user)
+	if err != nil {
+		t.Fatalf("GenerateTokenContext: %v", err)
+	}
+
+	// ValidateToken has no fingerprint to compare against
+	if _, err := service.ValidateToken(token); !auth.IsTokenBindingMismatch(err) {
+		t.Errorf("ValidateToken: err = %v, want a binding mismatch", err)
+	}
+}
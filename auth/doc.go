@@ -67,6 +67,235 @@ The typical OAuth flow with this package:
 
 	// Token valid, claims.UserID contains the authenticated user ID
 
+# Signup Policies
+
+By default every OAuth-authenticated person gets an account on first login. Restrict
+this with a SignupPolicy, consulted before UserStore.CreateUser:
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, time.Hour,
+		auth.WithSignupPolicy(auth.AnyOf(
+			auth.AllowEmailDomains("example.com"),
+			authgoogle.AllowHostedDomains("example.com"),
+		)),
+	)
+
+	_, err := authService.HandleOAuthCallback(ctx, "google", code)
+	if auth.IsSignupNotAllowed(err) {
+		// Show an "invite only" page
+	}
+
+Existing users are never affected by the policy.
+
+# Remember-Me Sessions
+
+Access tokens stay short-lived. For "remember me" logins, enable a separate long-lived,
+rotating token backed by a RememberMeStore:
+
+	authService := auth.NewAuthService(
+		userStore,
+		oauthStore,
+		[]byte("your-jwt-secret"),
+		15*time.Minute,
+		auth.WithRememberMe(rememberMeStore, 30*24*time.Hour),
+	)
+
+	rememberMe, expiresAt, err := authService.IssueRememberMeToken(ctx, user)
+	// Store rememberMe in an HttpOnly cookie that expires at expiresAt
+
+Remember-me logins reload the user and reject disabled accounts, so the UserStore must
+implement UserByIDStore; otherwise both calls fail with ErrRememberMeDisabled.
+
+Remember-me tokens cannot be used as access tokens. They are only exchanged for a fresh
+access token, and every exchange rotates the remember-me token:
+
+	resp, err := authService.LoginWithRememberMe(ctx, cookieValue)
+	if auth.IsRememberMeReused(err) {
+		// An old token was replayed: all remember-me sessions of the user were revoked
+	}
+	// Replace the cookie with resp.RememberMeToken
+
+Rotation is a compare-and-swap on the store, so when two requests present the same
+token at once only one wins; the other fails with ErrInvalidRememberMe without
+revoking anything.
+
+# Stateful OAuth Flows
+
+BeginOAuth generates the state, a PKCE code verifier and a nonce and keeps them in a
+TransientStore until the callback arrives. CompleteOAuth consumes the state, so each
+callback can only be used once:
+
+	req, err := authService.BeginOAuth(ctx, "google")
+	// Redirect user to req.URL
+
+	resp, err := authService.CompleteOAuth(ctx, "google", r.URL.Query().Get("state"), code)
+	if auth.IsInvalidState(err) {
+		// Unknown, expired or replayed callback
+	}
+
+The default store is in memory, so behind a load balancer configure a shared one:
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, time.Hour,
+		auth.WithTransientStore(redisTransientStore, 10*time.Minute),
+	)
+
+Providers implementing FlowProvider send the PKCE challenge and nonce; others still get
+single-use state checking.
+
+To send users back where they started, attach a return URL and a small payload to the
+flow. Both are kept server-side with the state, so callers can't tamper with them, and
+CompleteOAuth returns them in the AuthResponse:
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, time.Hour,
+		auth.WithAllowedReturnHosts("app.example.com"),
+	)
+
+	req, err := authService.BeginOAuth(ctx, "google",
+		auth.WithReturnURL(r.URL.Query().Get("next")),
+		auth.WithFlowData(map[string]string{"invite": inviteCode}),
+	)
+	if auth.IsInvalidReturnURL(err) {
+		// Off-site redirect target
+	}
+
+	resp, err := authService.CompleteOAuth(ctx, "google", state, code)
+	http.Redirect(w, r, resp.ReturnURL, http.StatusFound)
+
+Relative paths such as "/settings" are always accepted; absolute URLs need an allowed host.
+
+# Client-Bound Tokens
+
+Access tokens can be bound to a client fingerprint: the user agent plus a random secret
+the client keeps, for example in an HttpOnly cookie. Attach it to the request context;
+OAuth and remember-me logins then record it in the token, and ValidateTokenContext
+rejects the token when another client presents it:
+
+	ctx = auth.ContextWithClientFingerprint(r.Context(), auth.ClientFingerprint{
+		UserAgent: r.UserAgent(),
+		Secret:    fingerprintCookie.Value,
+	})
+
+	claims, err := authService.ValidateTokenContext(ctx, tokenFromRequest)
+	if auth.IsTokenBindingMismatch(err) {
+		// Token presented by a different client
+	}
+
+ValidateToken has no fingerprint to compare, so it rejects bound tokens; validate them
+with ValidateTokenContext. Use WithTokenBinding to require bound tokens everywhere.
+
+# Token Stores
+
+Access tokens are stateless by default. Each stateful feature has its own small store
+interface, so they can be backed by different systems:
+
+  - TransientStore: OAuth state, PKCE verifiers and nonces
+  - RememberMeStore: rotating remember-me (refresh) tokens
+  - RevocationStore: IDs of access tokens revoked before they expire
+  - SessionStore: a record of every issued access token
+
+MemoryStores bundles in-memory implementations of all of them for development and tests.
+Swap individual entries for shared stores later:
+
+	stores := auth.MemoryStores()
+	stores.Sessions = redisSessionStore
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, 15*time.Minute,
+		auth.WithStores(stores),
+	)
+
+With a RevocationStore, RevokeToken denies a single token until it expires. With a
+SessionStore tokens are only valid while their session exists, so users can list their
+sessions and log out everywhere:
+
+	sessions, err := authService.ListSessions(ctx, userID)
+	err = authService.RevokeUserSessions(ctx, userID)
+
+	_, err = authService.ValidateToken(oldToken)
+	if auth.IsTokenRevoked(err) {
+		// Logged out
+	}
+
+# Invalidating User Tokens
+
+Stateless tokens can also be invalidated per user. When the UserStore implements
+TokenValidityStore, ValidateToken rejects tokens issued before the user's
+tokens-valid-after time, which InvalidateUserSessions moves to now. It also ends the
+user's sessions and remember-me tokens when those stores are configured:
+
+	func (s *MyUserStore) GetTokensValidAfter(ctx context.Context, userID string) (time.Time, error) {
+		// SELECT tokens_valid_after FROM users WHERE id = $1 (zero time when NULL)
+	}
+
+	func (s *MyUserStore) SetTokensValidAfter(ctx context.Context, userID string, t time.Time) error {
+		// UPDATE users SET tokens_valid_after = $2 WHERE id = $1
+	}
+
+	// After a password change or when disabling an account
+	err := authService.InvalidateUserSessions(ctx, userID)
+
+When the UserStore also implements UserByIDStore, ValidateToken reloads the user and
+rejects disabled users with ErrUserDisabled right away. Both checks cost a store
+lookup per validation.
+
+# Claims Versioning
+
+Every access token carries the global claims version it was issued under, and
+ValidateToken rejects tokens with an older one as ErrTokenOutdated. Bump it when the
+claims change shape or to log everyone out, e.g. after a breach:
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, 15*time.Minute,
+		auth.WithTokenVersion(cfg.TokenVersion),
+	)
+
+	authService.BumpTokenVersion() // this instance only
+
+	_, err := authService.ValidateToken(oldToken)
+	if auth.IsTokenOutdated(err) {
+		// Authenticate again
+	}
+
+BumpTokenVersion only affects the running process, so multi-instance deployments
+raise the configured version instead. When the UserStore implements TokenVersionStore,
+tokens also carry the user's version and BumpUserTokenVersion forces a single user to
+log in again.
+
+# Password Hashing
+
+For credential logins, hash passwords with HashPassword and check them with
+VerifyPassword. Bcrypt is the default; argon2id is selected with an option:
+
+	authService := auth.NewAuthService(userStore, oauthStore, secret, time.Hour,
+		auth.WithPasswordHasher(auth.NewArgon2idHasher(auth.DefaultArgon2Params())),
+	)
+
+	rehashed, err := authService.VerifyPassword(password, storedHash)
+	if auth.IsInvalidCredentials(err) {
+		// Wrong password
+	}
+	if rehashed != "" {
+		// Older algorithm or parameters: persist rehashed in place of storedHash
+	}
+
+Hashes from the previous algorithm keep verifying, so raising the argon2id
+parameters or switching from bcrypt upgrades users as they log in.
+
+# Sign in with Apple
+
+Apple authenticates the client with a short-lived ES256 JWT instead of a static secret,
+and carries the identity in the ID token. The provider signs the secret with your .p8
+key and verifies ID tokens against Apple's published keys:
+
+	appleProvider, err := authapple.NewAppleProvider(
+		"com.example.web", // Services ID
+		"TEAMID1234",
+		"KEYID12345",
+		p8KeyPEM,
+		"https://your-app.com/auth/callback/apple",
+	)
+	authService.RegisterProvider("apple", appleProvider)
+
+Apple posts the callback as a form and sends the user's name only on the first
+authorization, so read it from the form's "user" field if you need it.
+
 # Implementing the Interfaces
 
 To use this package, you need to implement several interfaces:
@@ -113,17 +342,30 @@ Add more OAuth providers by implementing the OAuthProvider interface:
 
 # Error Handling
 
-The package uses the errx package for standardized error handling:
-
-	if err != nil {
-		if auth.IsUserNotFound(err) {
-			// Handle user not found case
-		}
-
-		// Check for other specific error types
-		if errx.IsType(err, errx.TypeAuthorization) {
-			// Handle authorization errors
-		}
+The package uses the errx package for standardized error handling. Every failure
+mode has its own code in the AUTH registry, and the common ones a predicate, so
+callers can branch on why authentication failed:
+
+  - IsTokenExpired: the access token expired; refresh it or log in again
+  - IsInvalidToken: the token is malformed or badly signed
+  - IsTokenRevoked, IsTokenOutdated, IsTokenBindingMismatch: the token was
+    revoked, issued under an older claims version, or presented by another client
+  - IsUserNotFound, IsUserDisabled: the user is unknown or their account is disabled
+  - IsProviderNotFound, IsCodeExchangeFailed, IsInvalidState, IsInvalidNonce:
+    the OAuth flow failed
+  - IsInvalidCredentials, IsSignupNotAllowed, IsRememberMeReused,
+    IsInvalidReturnURL
+
+For example:
+
+	claims, err := authService.ValidateToken(token)
+	switch {
+	case auth.IsTokenExpired(err):
+		// Silently refresh with the remember-me token
+	case auth.IsInvalidToken(err):
+		// Clear the stored token and show the login page
+	case errx.IsType(err, errx.TypeAuthorization):
+		// Any other rejection
 	}
 
 # Provider-Specific Extensions
@@ -135,5 +377,12 @@ Providers can return extended user information through the AuthUserInfo interfac
 		// Access Google-specific fields
 		domain := googleUser.Hd  // G Suite domain
 	}
+
+For common profile data, prefer the normalized accessors, which every provider
+maps from its native fields:
+
+	name := userInfo.GetDisplayName()     // Google name / Microsoft displayName
+	avatar := userInfo.GetAvatarURL()     // "" when the provider has no picture
+	verified := userInfo.IsEmailVerified() // Google email_verified; false for Microsoft
 */
 package auth
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultFlowTTL is how long a user has to complete an OAuth flow
+const DefaultFlowTTL = 10 * time.Minute
+
+// flowKeyPrefix namespaces OAuth flow entries in the TransientStore
+const flowKeyPrefix = "auth:oauth_flow:"
+
+// AuthFlow holds the per-login secrets generated by BeginOAuth. It is stored
+// in the TransientStore under its State until the callback arrives.
+type AuthFlow struct {
+	Provider      string    `json:"provider"`
+	State         string    `json:"state"`
+	CodeVerifier  string    `json:"code_verifier"`
+	CodeChallenge string    `json:"code_challenge"` // S256 challenge of CodeVerifier
+	Nonce         string    `json:"nonce"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Set with WithReturnURL and WithFlowData; kept server-side, never in the state itself
+	ReturnURL string            `json:"return_url,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+// AuthRequest is returned by BeginOAuth
+type AuthRequest struct {
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FlowProvider is an optional extension of OAuthProvider. Providers that
+// implement it receive the flow so they can send the PKCE challenge and nonce
+// with the auth URL, and the code verifier and expected nonce with the code
+// exchange. Other providers still get single-use state checking.
+type FlowProvider interface {
+	GetFlowAuthURL(flow *AuthFlow) string
+	ExchangeFlowCode(ctx context.Context, code string, flow *AuthFlow) (*OAuthToken, error)
+}
+
+// BeginOAuth starts a stateful OAuth flow: it generates the state, a PKCE code
+// verifier and a nonce, stores them in the TransientStore and returns the
+// provider's auth URL. Pass the callback's state and code to CompleteOAuth.
+func (s *service) BeginOAuth(ctx context.Context, provider string, opts ...FlowOption) (*AuthRequest, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, authErrors.New(ErrProviderNotFound).WithDetail("provider", provider)
+	}
+
+	flow, err := newAuthFlow(provider)
+	if err != nil {
+		return nil, authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+	for _, opt := range opts {
+		opt(flow)
+	}
+	if err := s.validateReturnURL(flow.ReturnURL); err != nil {
+		return nil, err
+	}
+	if flowDataSize(flow.Data) > maxFlowDataSize {
+		return nil, authErrors.New(ErrInvalidState).
+			WithDetail("error", "flow data too large").
+			WithDetail("max_bytes", maxFlowDataSize)
+	}
+
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return nil, authErrors.New(ErrTransientStore).WithCause(err)
+	}
+	if err := s.transientStore.Put(ctx, flowKeyPrefix+flow.State, string(data), s.flowTTL); err != nil {
+		return nil, authErrors.New(ErrTransientStore).
+			WithDetail("provider", provider).
+			WithCause(err)
+	}
+
+	authURL := p.GetAuthURL(flow.State)
+	if fp, ok := p.(FlowProvider); ok {
+		authURL = fp.GetFlowAuthURL(flow)
+	}
+
+	return &AuthRequest{
+		URL:       authURL,
+		State:     flow.State,
+		ExpiresAt: flow.CreatedAt.Add(s.flowTTL),
+	}, nil
+}
+
+// CompleteOAuth finishes a flow started with BeginOAuth. The state is consumed
+// before the code is exchanged, so a replayed callback fails with ErrInvalidState.
+func (s *service) CompleteOAuth(ctx context.Context, provider, state, code string) (*AuthResponse, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, authErrors.New(ErrProviderNotFound).WithDetail("provider", provider)
+	}
+
+	flow, err := s.takeAuthFlow(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if flow.Provider != provider {
+		return nil, authErrors.New(ErrInvalidState).
+			WithDetail("provider", provider).
+			WithDetail("error", "state was issued for a different provider")
+	}
+
+	var token *OAuthToken
+	if fp, ok := p.(FlowProvider); ok {
+		token, err = fp.ExchangeFlowCode(ctx, code, flow)
+	} else {
+		token, err = p.ExchangeCode(ctx, code)
+	}
+	if err != nil {
+		if IsInvalidNonce(err) {
+			return nil, err
+		}
+		return nil, authErrors.New(ErrCodeExchange).
+			WithDetail("provider", provider).
+			WithCause(err)
+	}
+
+	resp, err := s.loginWithToken(ctx, provider, p, token)
+	if err != nil {
+		return nil, err
+	}
+	resp.ReturnURL = flow.ReturnURL
+	resp.FlowData = flow.Data
+	return resp, nil
+}
+
+// takeAuthFlow loads and deletes the flow stored for a state
+func (s *service) takeAuthFlow(ctx context.Context, state string) (*AuthFlow, error) {
+	if state == "" {
+		return nil, authErrors.New(ErrInvalidState).WithDetail("error", "state missing")
+	}
+
+	// Take is atomic, so concurrent callbacks can't both use the same state
+	data, found, err := s.transientStore.Take(ctx, flowKeyPrefix+state)
+	if err != nil {
+		return nil, authErrors.New(ErrTransientStore).WithCause(err)
+	}
+	if !found {
+		return nil, authErrors.New(ErrInvalidState).WithDetail("error", "state unknown, expired or already used")
+	}
+
+	var flow AuthFlow
+	if err := json.Unmarshal([]byte(data), &flow); err != nil {
+		return nil, authErrors.New(ErrInvalidState).WithCause(err)
+	}
+	return &flow, nil
+}
+
+func newAuthFlow(provider string) (*AuthFlow, error) {
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthFlow{
+		Provider:      provider,
+		State:         state,
+		CodeVerifier:  verifier,
+		CodeChallenge: PKCEChallenge(verifier),
+		Nonce:         nonce,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// PKCEChallenge returns the S256 code challenge for a code verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyIDTokenNonce checks the nonce claim of an ID token. The signature is
+// not verified, so only use it on ID tokens received directly from the
+// provider's token endpoint over TLS.
+func VerifyIDTokenNonce(idToken, nonce string) error {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, claims); err != nil {
+		return authErrors.New(ErrInvalidNonce).
+			WithDetail("error", "id token malformed").
+			WithCause(err)
+	}
+
+	got, _ := claims["nonce"].(string)
+	if got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return authErrors.New(ErrInvalidNonce)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// testUser is a minimal auth.User
+type testUser struct {
+	id     string
+	email  string
+	active bool
+}
+
+func (u *testUser) GetID() string    { return u.id }
+func (u *testUser) GetEmail() string { return u.email }
+func (u *testUser) IsActive() bool   { return u.active }
+
+// newTestService returns a service over store with a fixed secret
+func newTestService(store *testUserStore, tokenExpiration time.Duration, opts ...auth.ServiceOption) auth.Service {
+	return auth.NewAuthService(store, store, []byte("test-secret"), tokenExpiration, opts...)
+}
+
+// testUserStore is an in-memory UserStore, OAuthAccountStore and UserByIDStore
+type testUserStore struct {
+	mutex sync.Mutex
+	users map[string]*testUser // by ID
+	byKey map[string]*testUser // by provider + provider ID
+	infos map[string]auth.AuthUserInfo
+}
+
+func newTestUserStore(users ...*testUser) *testUserStore {
+	store := &testUserStore{
+		users: make(map[string]*testUser),
+		byKey: make(map[string]*testUser),
+		infos: make(map[string]auth.AuthUserInfo),
+	}
+	for _, user := range users {
+		store.users[user.id] = user
+	}
+	return store
+}
+
+func (s *testUserStore) CreateUser(ctx context.Context, info auth.AuthUserInfo) (auth.User, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	user := &testUser{id: fmt.Sprintf("user-%d", len(s.users)+1), email: info.GetEmail(), active: true}
+	s.users[user.id] = user
+	s.byKey[info.GetProvider()+"/"+info.GetProviderID()] = user
+	s.infos[user.id] = info
+	return user, nil
+}
+
+func (s *testUserStore) GetUserByProviderID(ctx context.Context, provider, providerID string) (auth.User, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if user, ok := s.byKey[provider+"/"+providerID]; ok {
+		return user, nil
+	}
+	return nil, &errx.Error{Code: auth.ErrUserNotFound, Message: "user not found"}
+}
+
+func (s *testUserStore) GetUserByID(ctx context.Context, userID string) (auth.User, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if user, ok := s.users[userID]; ok {
+		return user, nil
+	}
+	return nil, &errx.Error{Code: auth.ErrUserNotFound, Message: "user not found"}
+}
+
+func (s *testUserStore) CreateOAuthAccount(ctx context.Context, userID string, info auth.AuthUserInfo) error {
+	return nil
+}
+
+func (s *testUserStore) GetOAuthAccount(ctx context.Context, provider, providerID string) (*auth.OAuthAccount, error) {
+	return nil, nil
+}
+
+func (s *testUserStore) UpdateOAuthToken(ctx context.Context, provider, providerID string, token *auth.OAuthToken) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"strings"
+	"time"
+)
+
+// ServiceOption configures optional behaviour of the auth service
+type ServiceOption func(*service)
+
+// WithRememberMe enables long-lived remember-me tokens backed by the given store.
+// The expiration is independent of (and normally much longer than) the access token expiration.
+func WithRememberMe(store RememberMeStore, expiration time.Duration) ServiceOption {
+	return func(s *service) {
+		s.rememberMeStore = store
+		s.rememberMeExpiration = expiration
+	}
+}
+
+// WithSignupPolicy restricts which first-time OAuth users get an account.
+// Without a policy every authenticated user is signed up.
+func WithSignupPolicy(policy SignupPolicy) ServiceOption {
+	return func(s *service) {
+		s.signupPolicy = policy
+	}
+}
+
+// WithTransientStore sets where OAuth state, PKCE verifiers and nonces are kept
+// between BeginOAuth and CompleteOAuth, and how long a flow may take. The
+// default is an in-memory store, which only works when the callback reaches
+// the same instance; use a shared store such as Redis behind a load balancer.
+func WithTransientStore(store TransientStore, ttl time.Duration) ServiceOption {
+	return func(s *service) {
+		s.transientStore = store
+		if ttl > 0 {
+			s.flowTTL = ttl
+		}
+	}
+}
+
+// WithAllowedReturnHosts lists the hosts absolute return URLs may point to.
+// Relative return paths are always allowed; without this option absolute
+// return URLs are rejected.
+func WithAllowedReturnHosts(hosts ...string) ServiceOption {
+	return func(s *service) {
+		if s.allowedReturnHosts == nil {
+			s.allowedReturnHosts = make(map[string]bool, len(hosts))
+		}
+		for _, host := range hosts {
+			s.allowedReturnHosts[strings.ToLower(host)] = true
+		}
+	}
+}
+
+// WithPasswordHasher sets the algorithm new passwords are hashed with. The
+// default is bcrypt at its default cost. Hashes from bcrypt or argon2id still
+// verify after switching, and VerifyPassword returns an upgraded hash for them.
+func WithPasswordHasher(hasher PasswordHasher) ServiceOption {
+	return func(s *service) {
+		s.passwordHasher = hasher
+	}
+}
+
+// WithTokenBinding requires every access token to be bound to a client
+// fingerprint: tokens are only issued when ctx carries a ClientFingerprint and
+// ValidateTokenContext rejects unbound tokens.
+func WithTokenBinding() ServiceOption {
+	return func(s *service) {
+		s.requireTokenBinding = true
+	}
+}
+
+// WithTokenVersion sets the global claims version tokens are issued with.
+// Tokens carrying an older version are rejected, so raising it in the
+// configuration of every instance forces everyone to log in again.
+func WithTokenVersion(version int64) ServiceOption {
+	return func(s *service) {
+		s.tokenVersion.Store(version)
+	}
+}
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes passwords into self-describing strings that carry the
+// algorithm and its parameters
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password
+	Hash(password string) (string, error)
+
+	// Verify reports whether password matches the encoded hash
+	Verify(password, encoded string) (bool, error)
+
+	// CanVerify reports whether encoded was produced by this algorithm
+	CanVerify(encoded string) bool
+
+	// NeedsRehash reports whether encoded uses another algorithm or weaker
+	// parameters than the hasher is configured with
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher hashes passwords with bcrypt
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher. A cost of 0 uses bcrypt.DefaultCost.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+// Hash returns the bcrypt hash of password
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether password matches the bcrypt hash
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch err {
+	case nil:
+		return true, nil
+	case bcrypt.ErrMismatchedHashAndPassword:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// CanVerify reports whether encoded is a bcrypt hash
+func (h *BcryptHasher) CanVerify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+// NeedsRehash reports whether encoded isn't bcrypt or has another cost
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	if !h.CanVerify(encoded) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2Params tunes argon2id. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32 `json:"memory"`
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+	SaltLength  uint32 `json:"salt_length"`
+	KeyLength   uint32 `json:"key_length"`
+}
+
+// DefaultArgon2Params returns 64 MiB, 3 iterations and 2 lanes, with a
+// 16-byte salt and a 32-byte key
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2idHasher hashes passwords with argon2id in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type Argon2idHasher struct {
+	Params Argon2Params
+}
+
+// NewArgon2idHasher creates an argon2id hasher with the given parameters
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	return &Argon2idHasher{Params: params}
+}
+
+// Hash returns the argon2id hash of password with a fresh random salt
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Params.Iterations, h.Params.Memory, h.Params.Parallelism, h.Params.KeyLength)
+	return encodeArgon2id(h.Params, salt, key), nil
+}
+
+// Verify reports whether password matches the argon2id hash, using the
+// parameters stored in the hash
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+}
+
+// CanVerify reports whether encoded is an argon2id hash
+func (h *Argon2idHasher) CanVerify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+// NeedsRehash reports whether encoded isn't argon2id or was hashed with other
+// parameters
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+	return err != nil || params != h.Params
+}
+
+func encodeArgon2id(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("not an argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// knownPasswordHashers verify hashes left over from a previously configured
+// algorithm, so switching hashers doesn't lock existing users out
+var knownPasswordHashers = []PasswordHasher{
+	NewBcryptHasher(0),
+	NewArgon2idHasher(DefaultArgon2Params()),
+}
+
+// HashPassword hashes password with the configured PasswordHasher
+func (s *service) HashPassword(password string) (string, error) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return "", authErrors.New(ErrPasswordHash).WithCause(err)
+	}
+	return hash, nil
+}
+
+// VerifyPassword checks password against a stored hash. When the hash uses
+// another algorithm or outdated parameters, it returns a fresh hash from the
+// configured hasher, which the caller should persist in place of the old one;
+// otherwise rehashed is empty.
+func (s *service) VerifyPassword(password, encoded string) (string, error) {
+	hasher := s.passwordHasher
+	if !hasher.CanVerify(encoded) {
+		hasher = nil
+		for _, known := range knownPasswordHashers {
+			if known.CanVerify(encoded) {
+				hasher = known
+				break
+			}
+		}
+		if hasher == nil {
+			return "", authErrors.New(ErrPasswordHash).WithDetail("reason", "unrecognized hash format")
+		}
+	}
+
+	ok, err := hasher.Verify(password, encoded)
+	if err != nil {
+		return "", authErrors.New(ErrPasswordHash).WithCause(err)
+	}
+	if !ok {
+		return "", authErrors.New(ErrInvalidCredentials)
+	}
+
+	if !s.passwordHasher.NeedsRehash(encoded) {
+		return "", nil
+	}
+	return s.HashPassword(password)
+}
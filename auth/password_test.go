@@ -0,0 +1,184 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2Params keeps argon2id cheap enough for tests
+func fastArgon2Params(iterations uint32) auth.Argon2Params {
+	return auth.Argon2Params{Memory: 1024, Iterations: iterations, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestPasswordHashers(t *testing.T) {
+	tests := []struct {
+		name       string
+		hasher     auth.PasswordHasher
+		other      auth.PasswordHasher
+		wantPrefix string
+	}{
+		{
+			name:       "bcrypt",
+			hasher:     auth.NewBcryptHasher(bcrypt.MinCost),
+			other:      auth.NewArgon2idHasher(fastArgon2Params(1)),
+			wantPrefix: "$2a$04$",
+		},
+		{
+			name:       "argon2id",
+			hasher:     auth.NewArgon2idHasher(fastArgon2Params(1)),
+			other:      auth.NewBcryptHasher(bcrypt.MinCost),
+			wantPrefix: "$argon2id$v=19$m=1024,t=1,p=1$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !strings.HasPrefix(hash, tt.wantPrefix) {
+				t.Errorf("hash = %q, want prefix %q", hash, tt.wantPrefix)
+			}
+
+			again, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if again == hash {
+				t.Error("hashing twice gave the same hash, want a fresh salt")
+			}
+
+			if ok, err := tt.hasher.Verify("correct horse", hash); err != nil || !ok {
+				t.Errorf("Verify(correct) = %v, %v; want true", ok, err)
+			}
+			if ok, err := tt.hasher.Verify("wrong horse", hash); err != nil || ok {
+				t.Errorf("Verify(wrong) = %v, %v; want false", ok, err)
+			}
+
+			if !tt.hasher.CanVerify(hash) {
+				t.Error("CanVerify rejected its own hash")
+			}
+			if tt.other.CanVerify(hash) {
+				t.Error("another algorithm claims the hash")
+			}
+			if tt.hasher.NeedsRehash(hash) {
+				t.Error("NeedsRehash = true for a hash with the current parameters")
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordRehash(t *testing.T) {
+	tests := []struct {
+		name        string
+		stored      auth.PasswordHasher // hashes the stored password
+		configured  auth.PasswordHasher // the service's hasher
+		password    string
+		wantRehash  bool
+		wantErrCode errx.Code
+	}{
+		{
+			name:       "current argon2id parameters",
+			stored:     auth.NewArgon2idHasher(fastArgon2Params(1)),
+			configured: auth.NewArgon2idHasher(fastArgon2Params(1)),
+			password:   "correct horse",
+		},
+		{
+			name:       "upgraded argon2id parameters",
+			stored:     auth.NewArgon2idHasher(fastArgon2Params(1)),
+			configured: auth.NewArgon2idHasher(fastArgon2Params(2)),
+			password:   "correct horse",
+			wantRehash: true,
+		},
+		{
+			name:       "current bcrypt cost",
+			stored:     auth.NewBcryptHasher(bcrypt.MinCost),
+			configured: auth.NewBcryptHasher(bcrypt.MinCost),
+			password:   "correct horse",
+		},
+		{
+			name:       "upgraded bcrypt cost",
+			stored:     auth.NewBcryptHasher(bcrypt.MinCost),
+			configured: auth.NewBcryptHasher(bcrypt.MinCost + 1),
+			password:   "correct horse",
+			wantRehash: true,
+		},
+		{
+			name:       "bcrypt migrated to argon2id",
+			stored:     auth.NewBcryptHasher(bcrypt.MinCost),
+			configured: auth.NewArgon2idHasher(fastArgon2Params(1)),
+			password:   "correct horse",
+			wantRehash: true,
+		},
+		{
+			name:       "argon2id migrated to bcrypt",
+			stored:     auth.NewArgon2idHasher(fastArgon2Params(1)),
+			configured: auth.NewBcryptHasher(bcrypt.MinCost),
+			password:   "correct horse",
+			wantRehash: true,
+		},
+		{
+			name:        "wrong password is not rehashed",
+			stored:      auth.NewArgon2idHasher(fastArgon2Params(1)),
+			configured:  auth.NewArgon2idHasher(fastArgon2Params(2)),
+			password:    "wrong horse",
+			wantErrCode: auth.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(newTestUserStore(), time.Hour, auth.WithPasswordHasher(tt.configured))
+
+			stored, err := tt.stored.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+
+			rehashed, err := svc.VerifyPassword(tt.password, stored)
+			if tt.wantErrCode != "" {
+				if !errx.IsCode(err, tt.wantErrCode) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErrCode)
+				}
+				if rehashed != "" {
+					t.Errorf("rehashed = %q, want none on failure", rehashed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyPassword: %v", err)
+			}
+
+			if !tt.wantRehash {
+				if rehashed != "" {
+					t.Errorf("rehashed = %q, want none", rehashed)
+				}
+				return
+			}
+			if rehashed == "" {
+				t.Fatal("no rehash, want one with the configured parameters")
+			}
+			if tt.configured.NeedsRehash(rehashed) {
+				t.Errorf("rehash %q doesn't use the configured parameters", rehashed)
+			}
+			if again, err := svc.VerifyPassword(tt.password, rehashed); err != nil || again != "" {
+				t.Errorf("VerifyPassword(rehashed) = %q, %v; want it to verify without another rehash", again, err)
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordUnknownFormat(t *testing.T) {
+	svc := newTestService(newTestUserStore(), time.Hour)
+
+	_, err := svc.VerifyPassword("correct horse", "md5$5f4dcc3b5aa765d61d8327deb882cf99")
+	if !errx.IsCode(err, auth.ErrPasswordHash) {
+		t.Fatalf("err = %v, want ErrPasswordHash", err)
+	}
+}
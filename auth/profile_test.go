@@ -0,0 +1,139 @@
+package auth_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/auth/providers/authgoogle"
+	"github.com/Abraxas-365/craftable/auth/providers/authmicrosoft"
+)
+
+// fakeAPI answers provider API requests with canned JSON by URL, and 404 for
+// anything else
+type fakeAPI map[string]string
+
+func (f fakeAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := f[req.URL.String()]
+	status := http.StatusOK
+	if !ok {
+		status, body = http.StatusNotFound, `{}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type userInfoFetcher interface {
+	GetUserInfo(ctx context.Context, token *auth.OAuthToken) (auth.AuthUserInfo, error)
+}
+
+func googleProvider(response string) userInfoFetcher {
+	client := &http.Client{Transport: fakeAPI{"https://www.googleapis.com/oauth2/v3/userinfo": response}}
+	return authgoogle.NewGoogleProvider("id", "secret", "https://app/callback").WithHTTPClient(client)
+}
+
+func microsoftProvider(response string) userInfoFetcher {
+	client := &http.Client{Transport: fakeAPI{"https://graph.microsoft.com/v1.0/me": response}}
+	return authmicrosoft.NewMicrosoftProvider("id", "secret", "https://app/callback").
+		WithHTTPClient(client).
+		WithTenantExtractionStrategy(authmicrosoft.TenantDisabled)
+}
+
+func TestNormalizedProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     userInfoFetcher
+		wantName     string
+		wantAvatar   string
+		wantVerified bool
+	}{
+		{
+			name: "google full profile",
+			provider: googleProvider(`{"sub": "g1", "email": "ada@example.com", "name": "Ada Lovelace",
+				"picture": "https://lh3.example/ada.png", "email_verified": true}`),
+			wantName:     "Ada Lovelace",
+			wantAvatar:   "https://lh3.example/ada.png",
+			wantVerified: true,
+		},
+		{
+			name: "google given and family name",
+			provider: googleProvider(`{"sub": "g1", "email": "ada@example.com",
+				"given_name": "Ada", "family_name": "Lovelace"}`),
+			wantName: "Ada Lovelace",
+		},
+		{
+			name:     "google email only",
+			provider: googleProvider(`{"sub": "g1", "email": "ada@example.com"}`),
+			wantName: "ada",
+		},
+		{
+			name: "microsoft display name",
+			provider: microsoftProvider(`{"id": "m1", "mail": "ada@example.com", "displayName": "Ada Lovelace",
+				"givenName": "Ada", "surname": "L."}`),
+			wantName: "Ada Lovelace",
+		},
+		{
+			name:     "microsoft given name and surname",
+			provider: microsoftProvider(`{"id": "m1", "mail": "ada@example.com", "givenName": "Ada", "surname": "Lovelace"}`),
+			wantName: "Ada Lovelace",
+		},
+		{
+			name:     "microsoft principal name only",
+			provider: microsoftProvider(`{"id": "m1", "userPrincipalName": "ada@example.onmicrosoft.com"}`),
+			wantName: "ada",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := tt.provider.GetUserInfo(context.Background(), &auth.OAuthToken{AccessToken: "token"})
+			if err != nil {
+				t.Fatalf("GetUserInfo: %v", err)
+			}
+
+			if got := info.GetDisplayName(); got != tt.wantName {
+				t.Errorf("GetDisplayName() = %q, want %q", got, tt.wantName)
+			}
+			if got := info.GetAvatarURL(); got != tt.wantAvatar {
+				t.Errorf("GetAvatarURL() = %q, want %q", got, tt.wantAvatar)
+			}
+			if got := info.IsEmailVerified(); got != tt.wantVerified {
+				t.Errorf("IsEmailVerified() = %v, want %v", got, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestBasicProfileFallbacks(t *testing.T) {
+	picture := "https://example.com/p.png"
+
+	tests := []struct {
+		name       string
+		info       auth.BasicAuthUserInfo
+		wantName   string
+		wantAvatar string
+	}{
+		{name: "name", info: auth.BasicAuthUserInfo{Name: " Ada ", Email: "ada@example.com"}, wantName: "Ada"},
+		{name: "email local part", info: auth.BasicAuthUserInfo{Email: "ada@example.com"}, wantName: "ada"},
+		{name: "email without domain", info: auth.BasicAuthUserInfo{Email: "ada"}, wantName: "ada"},
+		{name: "avatar", info: auth.BasicAuthUserInfo{Name: "Ada", ProfilePicture: &picture}, wantName: "Ada", wantAvatar: picture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.GetDisplayName(); got != tt.wantName {
+				t.Errorf("GetDisplayName() = %q, want %q", got, tt.wantName)
+			}
+			if got := tt.info.GetAvatarURL(); got != tt.wantAvatar {
+				t.Errorf("GetAvatarURL() = %q, want %q", got, tt.wantAvatar)
+			}
+		})
+	}
+}
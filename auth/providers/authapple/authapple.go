@@ -0,0 +1,442 @@
+package authapple
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Apple Sign In endpoints, relative to the issuer
+	appleIssuer    = "https://appleid.apple.com"
+	appleAuthPath  = "/auth/authorize"
+	appleTokenPath = "/auth/token"
+	appleKeysPath  = "/auth/keys"
+
+	// clientSecretTTL is how long generated client secrets are valid. Apple
+	// accepts up to six months.
+	clientSecretTTL = 24 * time.Hour
+
+	// keyRefetchInterval is the minimum time between two JWKS downloads
+	// triggered by unknown key IDs, so forged kids can't hammer Apple
+	keyRefetchInterval = time.Minute
+)
+
+// Define Apple-specific error codes
+var (
+	providerErrors = errx.NewRegistry("APPLE_PROVIDER")
+
+	ErrInvalidGrant    = providerErrors.Register("INVALID_GRANT", errx.TypeBadRequest, 400, "Invalid grant")
+	ErrInvalidToken    = providerErrors.Register("INVALID_TOKEN", errx.TypeBadRequest, 400, "Invalid token")
+	ErrInvalidKey      = providerErrors.Register("INVALID_KEY", errx.TypeValidation, 400, "Invalid Apple private key")
+	ErrAPIRequest      = providerErrors.Register("API_REQUEST_FAILED", errx.TypeExternal, 500, "Apple API request failed")
+	ErrResponseParsing = providerErrors.Register("RESPONSE_PARSING_FAILED", errx.TypeInternal, 500, "Failed to parse Apple API response")
+)
+
+// AppleUserInfo extends BasicAuthUserInfo with Apple-specific fields.
+// Apple only sends the user's name in the form post of the first
+// authorization, never in the ID token, so Name is usually empty.
+type AppleUserInfo struct {
+	auth.BasicAuthUserInfo
+	IsPrivateEmail bool `json:"is_private_email"` // Email is an Apple private relay address
+	RealUserStatus int  `json:"real_user_status"` // 0 unsupported, 1 unknown, 2 likely real
+}
+
+// AppleProvider implements the OAuthProvider interface for Sign in with Apple
+type AppleProvider struct {
+	clientID    string // Services ID
+	teamID      string
+	keyID       string
+	privateKey  *ecdsa.PrivateKey
+	redirectURI string
+	scopes      []string
+	baseURL     string
+	httpClient  *http.Client
+
+	mutex        sync.Mutex
+	clientSecret string
+	secretExpiry time.Time
+
+	keysMutex   sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewAppleProvider creates a new Apple OAuth provider. clientID is the
+// Services ID, and privateKeyPEM the contents of the .p8 key identified by keyID.
+func NewAppleProvider(clientID, teamID, keyID string, privateKeyPEM []byte, redirectURI string) (*AppleProvider, error) {
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		return nil, providerErrors.New(ErrInvalidKey).
+			WithDetail("key_id", keyID).
+			WithCause(err)
+	}
+
+	return &AppleProvider{
+		clientID:    clientID,
+		teamID:      teamID,
+		keyID:       keyID,
+		privateKey:  privateKey,
+		redirectURI: redirectURI,
+		scopes:      []string{"name", "email"},
+		baseURL:     appleIssuer,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// WithScopes replaces the requested scopes ("name" and "email" by default)
+func (p *AppleProvider) WithScopes(scopes ...string) *AppleProvider {
+	p.scopes = scopes
+	return p
+}
+
+// WithHTTPClient sets a custom HTTP client
+func (p *AppleProvider) WithHTTPClient(client *http.Client) *AppleProvider {
+	p.httpClient = client
+	return p
+}
+
+// WithBaseURL points the provider at another host, such as a test server.
+// ID tokens are still required to be issued by Apple.
+func (p *AppleProvider) WithBaseURL(baseURL string) *AppleProvider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// GetAuthURL returns the Apple authorization URL. Apple posts the callback as
+// a form (response_mode=form_post) whenever scopes are requested.
+func (p *AppleProvider) GetAuthURL(state string) string {
+	return p.authURL(state, nil)
+}
+
+// GetFlowAuthURL returns the authorization URL with the flow's nonce. Apple
+// does not support PKCE, so the code challenge is not sent.
+func (p *AppleProvider) GetFlowAuthURL(flow *auth.AuthFlow) string {
+	return p.authURL(flow.State, url.Values{"nonce": {flow.Nonce}})
+}
+
+func (p *AppleProvider) authURL(state string, extra url.Values) string {
+	params := url.Values{}
+	params.Add("client_id", p.clientID)
+	params.Add("redirect_uri", p.redirectURI)
+	params.Add("response_type", "code")
+	params.Add("state", state)
+	if len(p.scopes) > 0 {
+		params.Add("scope", strings.Join(p.scopes, " "))
+		params.Add("response_mode", "form_post")
+	}
+	for key, values := range extra {
+		params[key] = values
+	}
+
+	return fmt.Sprintf("%s%s?%s", p.baseURL, appleAuthPath, params.Encode())
+}
+
+// ExchangeCode exchanges an authorization code for tokens. The returned token
+// carries the ID token, which GetUserInfo verifies.
+func (p *AppleProvider) ExchangeCode(ctx context.Context, code string) (*auth.OAuthToken, error) {
+	data := url.Values{}
+	data.Set("code", code)
+	data.Set("redirect_uri", p.redirectURI)
+	data.Set("grant_type", "authorization_code")
+
+	return p.requestToken(ctx, data, "")
+}
+
+// ExchangeFlowCode exchanges an authorization code and checks the ID token nonce
+func (p *AppleProvider) ExchangeFlowCode(ctx context.Context, code string, flow *auth.AuthFlow) (*auth.OAuthToken, error) {
+	token, err := p.ExchangeCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.VerifyIDTokenNonce(token.IDToken, flow.Nonce); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetUserInfo verifies the token's ID token against Apple's keys and returns
+// the identity it asserts. Apple has no user info endpoint.
+func (p *AppleProvider) GetUserInfo(ctx context.Context, token *auth.OAuthToken) (auth.AuthUserInfo, error) {
+	if token == nil || token.IDToken == "" {
+		return nil, providerErrors.New(ErrInvalidToken).
+			WithDetail("error", "token has no id_token")
+	}
+
+	claims, err := p.VerifyIDToken(ctx, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	realUserStatus, _ := claims["real_user_status"].(float64)
+
+	return &AppleUserInfo{
+		BasicAuthUserInfo: auth.BasicAuthUserInfo{
+			ProviderID:    sub,
+			Email:         email,
+			Provider:      "apple",
+			Token:         token,
+			RawData:       claims,
+			EmailVerified: claimBool(claims["email_verified"]),
+		},
+		IsPrivateEmail: claimBool(claims["is_private_email"]),
+		RealUserStatus: int(realUserStatus),
+	}, nil
+}
+
+// RefreshToken refreshes an access token using a refresh token
+func (p *AppleProvider) RefreshToken(ctx context.Context, refreshToken string) (*auth.OAuthToken, error) {
+	data := url.Values{}
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	return p.requestToken(ctx, data, refreshToken)
+}
+
+// VerifyIDToken checks an ID token's RS256 signature against Apple's published
+// keys, its issuer, audience and expiry, and returns its claims
+func (p *AppleProvider) VerifyIDToken(ctx context.Context, idToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(appleIssuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, providerErrors.New(ErrInvalidToken).
+			WithDetail("error", "id token verification failed").
+			WithCause(err)
+	}
+	return claims, nil
+}
+
+// ClientSecret returns the ES256-signed JWT Apple requires as client_secret,
+// reusing it until shortly before it expires
+func (p *AppleProvider) ClientSecret() (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if p.clientSecret != "" && now.Add(time.Minute).Before(p.secretExpiry) {
+		return p.clientSecret, nil
+	}
+
+	expiry := now.Add(clientSecretTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    p.teamID,
+		Subject:   p.clientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiry),
+	})
+	token.Header["kid"] = p.keyID
+
+	secret, err := token.SignedString(p.privateKey)
+	if err != nil {
+		return "", providerErrors.New(ErrInvalidKey).
+			WithDetail("error", "failed to sign client secret").
+			WithCause(err)
+	}
+
+	p.clientSecret = secret
+	p.secretExpiry = expiry
+	return secret, nil
+}
+
+// requestToken calls the token endpoint. Refresh responses carry no refresh
+// token, so the one used is kept.
+func (p *AppleProvider) requestToken(ctx context.Context, data url.Values, refreshToken string) (*auth.OAuthToken, error) {
+	secret, err := p.ClientSecret()
+	if err != nil {
+		return nil, err
+	}
+	data.Set("client_id", p.clientID)
+	data.Set("client_secret", secret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+appleTokenPath, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, providerErrors.New(ErrAPIRequest).WithCause(err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	body, status, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		var errorResp struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if err := json.Unmarshal(body, &errorResp); err != nil {
+			return nil, providerErrors.New(ErrAPIRequest).
+				WithDetail("status_code", status).
+				WithDetail("body", string(body))
+		}
+
+		if errorResp.Error == "invalid_grant" {
+			return nil, providerErrors.New(ErrInvalidGrant).
+				WithDetail("error", errorResp.Description)
+		}
+
+		return nil, providerErrors.New(ErrAPIRequest).
+			WithDetail("error", errorResp.Error).
+			WithDetail("description", errorResp.Description)
+	}
+
+	var tokenResp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		TokenType    string `json:"token_type"`
+		IDToken      string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, providerErrors.New(ErrResponseParsing).
+			WithDetail("error", "failed to parse token response").
+			WithCause(err)
+	}
+
+	if tokenResp.RefreshToken != "" {
+		refreshToken = tokenResp.RefreshToken
+	}
+
+	return &auth.OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+		IDToken:      tokenResp.IDToken,
+	}, nil
+}
+
+// publicKey returns Apple's signing key with the given ID, refetching the key
+// set when the ID is unknown since Apple rotates keys. Refetches happen at
+// most once per keyRefetchInterval: until then the fetched set is the answer,
+// so unknown IDs are rejected without another download. The lock is held
+// across the download so concurrent misses share one request.
+func (p *AppleProvider) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.keysMutex.Lock()
+	defer p.keysMutex.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if !p.keysFetched.IsZero() && time.Since(p.keysFetched) < keyRefetchInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetchKeys downloads and decodes Apple's JWKS
+func (p *AppleProvider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+appleKeysPath, nil)
+	if err != nil {
+		return nil, providerErrors.New(ErrAPIRequest).WithCause(err)
+	}
+
+	body, status, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, providerErrors.New(ErrAPIRequest).
+			WithDetail("status_code", status).
+			WithDetail("body", string(body))
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, providerErrors.New(ErrResponseParsing).
+			WithDetail("error", "failed to parse key set").
+			WithCause(err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			return nil, providerErrors.New(ErrResponseParsing).
+				WithDetail("error", "malformed key").
+				WithDetail("kid", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// do sends req and returns the response body and status code
+func (p *AppleProvider) do(req *http.Request) ([]byte, int, error) {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, providerErrors.New(ErrAPIRequest).
+			WithDetail("error", err.Error()).
+			WithCause(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, providerErrors.New(ErrAPIRequest).
+			WithDetail("error", "failed to read response body").
+			WithCause(err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// claimBool reads boolean claims, which Apple sends as either true or "true"
+func claimBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,386 @@
+package authapple_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/auth/providers/authapple"
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "com.example.web"
+	testTeamID      = "TEAM123456"
+	testKeyID       = "KEY1234567"
+	testRedirectURI = "https://example.com/auth/apple/callback"
+	testSigningKID  = "apple-key-1"
+)
+
+// fakeApple serves Apple's token and keys endpoints
+type fakeApple struct {
+	signingKey *rsa.PrivateKey
+	clientKey  *ecdsa.PublicKey
+
+	mutex         sync.Mutex
+	forms         []url.Values
+	keyFetchCount int
+	idToken       string
+	tokenErr      string
+}
+
+func (f *fakeApple) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/auth/keys":
+		f.keyFetchCount++
+		pub := f.signingKey.PublicKey
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testSigningKID,
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}}})
+	case "/auth/token":
+		r.ParseForm()
+		f.forms = append(f.forms, r.PostForm)
+		if f.tokenErr != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": f.tokenErr, "error_description": "code expired"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+			"expires_in":    3600,
+			"token_type":    "Bearer",
+			"id_token":      f.idToken,
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeApple) lastForm(t *testing.T) url.Values {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(f.forms) == 0 {
+		t.Fatal("token endpoint was not called")
+	}
+	return f.forms[len(f.forms)-1]
+}
+
+func (f *fakeApple) keyFetches() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.keyFetchCount
+}
+
+// newTestProvider returns a provider pointed at a fake Apple server
+func newTestProvider(t *testing.T) (*authapple.AppleProvider, *fakeApple) {
+	t.Helper()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeApple{signingKey: signingKey, clientKey: &clientKey.PublicKey}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	provider, err := authapple.NewAppleProvider(testClientID, testTeamID, testKeyID, keyPEM, testRedirectURI)
+	if err != nil {
+		t.Fatalf("NewAppleProvider: %v", err)
+	}
+	return provider.WithBaseURL(srv.URL), fake
+}
+
+// idToken returns an ID token with the given claims signed by key under kid
+func idToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// appleClaims returns valid ID token claims for the test client
+func appleClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":              "https://appleid.apple.com",
+		"aud":              testClientID,
+		"sub":              "001234.abcdef",
+		"email":            "relay@privaterelay.appleid.com",
+		"email_verified":   "true",
+		"is_private_email": true,
+		"real_user_status": 2,
+		"iat":              time.Now().Unix(),
+		"exp":              time.Now().Add(10 * time.Minute).Unix(),
+	}
+}
+
+func TestClientSecret(t *testing.T) {
+	provider, fake := newTestProvider(t)
+
+	secret, err := provider.ClientSecret()
+	if err != nil {
+		t.Fatalf("ClientSecret: %v", err)
+	}
+
+	claims := jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(secret, &claims, func(*jwt.Token) (any, error) {
+		return fake.clientKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("client secret does not verify with the client key: %v", err)
+	}
+
+	if kid := token.Header["kid"]; kid != testKeyID {
+		t.Errorf("kid = %v, want %s", kid, testKeyID)
+	}
+	if claims.Issuer != testTeamID {
+		t.Errorf("iss = %q, want the team ID %q", claims.Issuer, testTeamID)
+	}
+	if claims.Subject != testClientID {
+		t.Errorf("sub = %q, want the client ID %q", claims.Subject, testClientID)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "https://appleid.apple.com" {
+		t.Errorf("aud = %v, want Apple", claims.Audience)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl <= 0 || ttl > 180*24*time.Hour {
+		t.Errorf("secret lifetime = %v, want within Apple's six months", ttl)
+	}
+
+	again, err := provider.ClientSecret()
+	if err != nil {
+		t.Fatalf("ClientSecret: %v", err)
+	}
+	if again != secret {
+		t.Error("ClientSecret signed a new secret while the cached one is valid")
+	}
+}
+
+func TestExchangeCodeAndUserInfo(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	fake.idToken = idToken(t, fake.signingKey, testSigningKID, appleClaims())
+
+	token, err := provider.ExchangeCode(context.Background(), "auth-code")
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+
+	form := fake.lastForm(t)
+	wantForm := map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         "auth-code",
+		"client_id":    testClientID,
+		"redirect_uri": testRedirectURI,
+	}
+	for key, want := range wantForm {
+		if got := form.Get(key); got != want {
+			t.Errorf("form %s = %q, want %q", key, got, want)
+		}
+	}
+	_, err = jwt.Parse(form.Get("client_secret"), func(*jwt.Token) (any, error) {
+		return fake.clientKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Errorf("client_secret does not verify with the client key: %v", err)
+	}
+
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.IDToken != fake.idToken {
+		t.Errorf("token = %+v, want the token endpoint's response", token)
+	}
+
+	info, err := provider.GetUserInfo(context.Background(), token)
+	if err != nil {
+		t.Fatalf("GetUserInfo: %v", err)
+	}
+	apple, ok := info.(*authapple.AppleUserInfo)
+	if !ok {
+		t.Fatalf("GetUserInfo returned %T, want *AppleUserInfo", info)
+	}
+	if apple.ProviderID != "001234.abcdef" || apple.Email != "relay@privaterelay.appleid.com" {
+		t.Errorf("identity = %q <%s>, want the ID token's subject and email", apple.ProviderID, apple.Email)
+	}
+	if !apple.EmailVerified || !apple.IsPrivateEmail || apple.RealUserStatus != 2 {
+		t.Errorf("flags = verified %v, private %v, status %d; want true, true, 2",
+			apple.EmailVerified, apple.IsPrivateEmail, apple.RealUserStatus)
+	}
+}
+
+func TestExchangeCodeInvalidGrant(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	fake.tokenErr = "invalid_grant"
+
+	_, err := provider.ExchangeCode(context.Background(), "expired-code")
+	if !errx.IsCode(err, authapple.ErrInvalidGrant) {
+		t.Fatalf("err = %v, want ErrInvalidGrant", err)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	with := func(key string, value any) jwt.MapClaims {
+		claims := appleClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   func(fake *fakeApple) string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, testSigningKID, appleClaims())
+			},
+		},
+		{
+			name: "other audience",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, testSigningKID, with("aud", "com.example.other"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "other issuer",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, testSigningKID, with("iss", "https://evil.example.com"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, testSigningKID, with("exp", time.Now().Add(-time.Minute).Unix()))
+			},
+			wantErr: true,
+		},
+		{
+			name: "no expiry",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, testSigningKID, with("exp", nil))
+			},
+			wantErr: true,
+		},
+		{
+			name: "signed by another key",
+			token: func(*fakeApple) string {
+				return idToken(t, otherKey, testSigningKID, appleClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown key ID",
+			token: func(f *fakeApple) string {
+				return idToken(t, f.signingKey, "unknown-kid", appleClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "HMAC signed",
+			token: func(*fakeApple) string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, appleClaims()).SignedString([]byte("secret"))
+				return signed
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, fake := newTestProvider(t)
+
+			claims, err := provider.VerifyIDToken(context.Background(), tt.token(fake))
+			if tt.wantErr {
+				if !errx.IsCode(err, authapple.ErrInvalidToken) {
+					t.Fatalf("err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken: %v", err)
+			}
+			if claims["sub"] != "001234.abcdef" {
+				t.Errorf("sub = %v, want 001234.abcdef", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenCachesKeys(t *testing.T) {
+	provider, fake := newTestProvider(t)
+	valid := idToken(t, fake.signingKey, testSigningKID, appleClaims())
+	unknown := idToken(t, fake.signingKey, "rotated-kid", appleClaims())
+
+	for range 3 {
+		if _, err := provider.VerifyIDToken(context.Background(), valid); err != nil {
+			t.Fatalf("VerifyIDToken: %v", err)
+		}
+		provider.VerifyIDToken(context.Background(), unknown)
+	}
+
+	if got := fake.keyFetches(); got != 1 {
+		t.Errorf("keys fetched %d times, want 1", got)
+	}
+}
+
+func TestGetUserInfoWithoutIDToken(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	_, err := provider.GetUserInfo(context.Background(), &auth.OAuthToken{AccessToken: "access-1"})
+	if !errx.IsCode(err, authapple.ErrInvalidToken) {
+		t.Fatalf("err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestNewAppleProviderInvalidKey(t *testing.T) {
+	_, err := authapple.NewAppleProvider(testClientID, testTeamID, testKeyID, []byte("not a key"), testRedirectURI)
+	if !errx.IsCode(err, authapple.ErrInvalidKey) {
+		t.Fatalf("err = %v, want ErrInvalidKey", err)
+	}
+}
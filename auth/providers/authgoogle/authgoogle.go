@@ -41,6 +41,36 @@ type GoogleUserInfo struct {
 	Hd            string `json:"hd"` // G Suite domain
 }
 
+// GetDisplayName prefers the full name, then given and family name
+func (u *GoogleUserInfo) GetDisplayName() string {
+	if strings.TrimSpace(u.Name) == "" {
+		if name := strings.TrimSpace(u.GivenName + " " + u.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.BasicAuthUserInfo.GetDisplayName()
+}
+
+// IsEmailVerified reports Google's email_verified claim
+func (u *GoogleUserInfo) IsEmailVerified() bool { return u.VerifiedEmail }
+
+// AllowHostedDomains is a signup policy that only admits Google Workspace users
+// whose hosted domain (hd claim) is one of the given domains
+func AllowHostedDomains(domains ...string) auth.SignupPolicy {
+	allowed := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		allowed[strings.ToLower(d)] = true
+	}
+
+	return auth.SignupPolicyFunc(func(ctx context.Context, info auth.AuthUserInfo) (bool, error) {
+		googleUser, ok := info.(*GoogleUserInfo)
+		if !ok || googleUser.Hd == "" {
+			return false, nil
+		}
+		return allowed[strings.ToLower(googleUser.Hd)], nil
+	})
+}
+
 // GoogleProvider implements the OAuthProvider interface for Google
 type GoogleProvider struct {
 	clientID     string
@@ -75,6 +105,19 @@ func (p *GoogleProvider) WithHTTPClient(client *http.Client) *GoogleProvider {
 
 // GetAuthURL returns the Google authorization URL
 func (p *GoogleProvider) GetAuthURL(state string) string {
+	return p.authURL(state, nil)
+}
+
+// GetFlowAuthURL returns the authorization URL with the flow's PKCE challenge and nonce
+func (p *GoogleProvider) GetFlowAuthURL(flow *auth.AuthFlow) string {
+	return p.authURL(flow.State, url.Values{
+		"code_challenge":        {flow.CodeChallenge},
+		"code_challenge_method": {"S256"},
+		"nonce":                 {flow.Nonce},
+	})
+}
+
+func (p *GoogleProvider) authURL(state string, extra url.Values) string {
 	params := url.Values{}
 	params.Add("client_id", p.clientID)
 	params.Add("redirect_uri", p.redirectURI)
@@ -83,29 +126,56 @@ func (p *GoogleProvider) GetAuthURL(state string) string {
 	params.Add("access_type", "offline")
 	params.Add("prompt", "consent") // Force consent to get refresh token
 	params.Add("state", state)
+	for key, values := range extra {
+		params[key] = values
+	}
 
 	return fmt.Sprintf("%s?%s", googleAuthURL, params.Encode())
 }
 
 // ExchangeCode exchanges an authorization code for tokens
 func (p *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*auth.OAuthToken, error) {
+	token, _, err := p.exchangeCode(ctx, code, nil)
+	return token, err
+}
+
+// ExchangeFlowCode exchanges an authorization code using the flow's PKCE
+// verifier and checks the ID token nonce
+func (p *GoogleProvider) ExchangeFlowCode(ctx context.Context, code string, flow *auth.AuthFlow) (*auth.OAuthToken, error) {
+	token, idToken, err := p.exchangeCode(ctx, code, url.Values{"code_verifier": {flow.CodeVerifier}})
+	if err != nil {
+		return nil, err
+	}
+	if idToken != "" {
+		if err := auth.VerifyIDTokenNonce(idToken, flow.Nonce); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// exchangeCode performs the code exchange and also returns the raw ID token
+func (p *GoogleProvider) exchangeCode(ctx context.Context, code string, extra url.Values) (*auth.OAuthToken, string, error) {
 	data := url.Values{}
 	data.Set("code", code)
 	data.Set("client_id", p.clientID)
 	data.Set("client_secret", p.clientSecret)
 	data.Set("redirect_uri", p.redirectURI)
 	data.Set("grant_type", "authorization_code")
+	for key, values := range extra {
+		data[key] = values
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", googleTokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return nil, providerErrors.New(ErrAPIRequest).WithCause(err)
+		return nil, "", providerErrors.New(ErrAPIRequest).WithCause(err)
 	}
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, providerErrors.New(ErrAPIRequest).
+		return nil, "", providerErrors.New(ErrAPIRequest).
 			WithDetail("error", err.Error()).
 			WithCause(err)
 	}
@@ -113,7 +183,7 @@ func (p *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*auth.O
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, providerErrors.New(ErrAPIRequest).
+		return nil, "", providerErrors.New(ErrAPIRequest).
 			WithDetail("error", "failed to read response body").
 			WithCause(err)
 	}
@@ -124,18 +194,18 @@ func (p *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*auth.O
 			Description string `json:"error_description"`
 		}
 		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, providerErrors.New(ErrAPIRequest).
+			return nil, "", providerErrors.New(ErrAPIRequest).
 				WithDetail("status_code", resp.StatusCode).
 				WithDetail("body", string(body))
 		}
 
 		// Handle specific error types
 		if errorResp.Error == "invalid_grant" {
-			return nil, providerErrors.New(ErrInvalidGrant).
+			return nil, "", providerErrors.New(ErrInvalidGrant).
 				WithDetail("error", errorResp.Description)
 		}
 
-		return nil, providerErrors.New(ErrAPIRequest).
+		return nil, "", providerErrors.New(ErrAPIRequest).
 			WithDetail("error", errorResp.Error).
 			WithDetail("description", errorResp.Description)
 	}
@@ -146,10 +216,11 @@ func (p *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*auth.O
 		RefreshToken string `json:"refresh_token"`
 		ExpiresIn    int    `json:"expires_in"`
 		TokenType    string `json:"token_type"`
+		IDToken      string `json:"id_token"`
 	}
 
 	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return nil, providerErrors.New(ErrResponseParsing).
+		return nil, "", providerErrors.New(ErrResponseParsing).
 			WithDetail("error", "failed to parse token response").
 			WithCause(err)
 	}
@@ -161,7 +232,7 @@ func (p *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*auth.O
 		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
 	}
 
-	return token, nil
+	return token, tokenResp.IDToken, nil
 }
 
 // GetUserInfo retrieves the user information using the access token
@@ -228,12 +299,13 @@ func (p *GoogleProvider) GetUserInfo(ctx context.Context, token *auth.OAuthToken
 	// Create user info object
 	userInfo := &GoogleUserInfo{
 		BasicAuthUserInfo: auth.BasicAuthUserInfo{
-			ProviderID: userData.Sub,
-			Email:      userData.Email,
-			Name:       userData.Name,
-			Provider:   "google",
-			Token:      token,
-			RawData:    rawData,
+			ProviderID:    userData.Sub,
+			Email:         userData.Email,
+			Name:          userData.Name,
+			Provider:      "google",
+			Token:         token,
+			RawData:       rawData,
+			EmailVerified: userData.VerifiedEmail,
 		},
 		VerifiedEmail: userData.VerifiedEmail,
 		Locale:        userData.Locale,
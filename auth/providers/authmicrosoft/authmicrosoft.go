@@ -72,6 +72,19 @@ type MicrosoftUserInfo struct {
 	ObjectID          string   `json:"id"` // Microsoft's unique identifier
 }
 
+// GetDisplayName prefers Graph's displayName, then given name and surname.
+// Email verification is left unset: the mail attribute is tenant-managed
+// and Microsoft makes no verification claim about it.
+func (u *MicrosoftUserInfo) GetDisplayName() string {
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(u.GivenName + " " + u.Surname); name != "" {
+		return name
+	}
+	return u.BasicAuthUserInfo.GetDisplayName()
+}
+
 // MicrosoftProvider implements the OAuthProvider interface for Microsoft
 type MicrosoftProvider struct {
 	clientID                 string
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Remember-me tokens are opaque "<series>.<token>" strings. The series identifies
+// the login chain and never changes; the token part is rotated on every use.
+// Presenting a series with a stale token means an older copy of the token was
+// replayed, so the whole set of the user's remember-me tokens is revoked.
+// Remember-me logins reload the user to re-check IsActive, so they need a
+// UserStore implementing UserByIDStore.
+
+// IssueRememberMeToken creates a new remember-me token series for the user
+func (s *service) IssueRememberMeToken(ctx context.Context, user User) (string, time.Time, error) {
+	if err := s.checkRememberMeEnabled(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	series, err := randomToken()
+	if err != nil {
+		return "", time.Time{}, authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+	value, err := randomToken()
+	if err != nil {
+		return "", time.Time{}, authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+
+	now := time.Now()
+	record := &RememberMeToken{
+		Series:     series,
+		TokenHash:  hashRememberMeValue(value),
+		UserID:     user.GetID(),
+		Email:      user.GetEmail(),
+		ExpiresAt:  now.Add(s.rememberMeExpiration),
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+
+	if err := s.rememberMeStore.SaveRememberMeToken(ctx, record); err != nil {
+		return "", time.Time{}, authErrors.New(ErrRememberMeStore).
+			WithDetail("user_id", user.GetID()).
+			WithCause(err)
+	}
+
+	return series + "." + value, record.ExpiresAt, nil
+}
+
+// LoginWithRememberMe validates a remember-me token, rotates it and mints a fresh access token.
+// The rotated remember-me token is returned in the response and replaces the presented one.
+func (s *service) LoginWithRememberMe(ctx context.Context, rememberMeToken string) (*AuthResponse, error) {
+	if err := s.checkRememberMeEnabled(); err != nil {
+		return nil, err
+	}
+
+	series, value, ok := strings.Cut(rememberMeToken, ".")
+	if !ok || series == "" || value == "" {
+		return nil, authErrors.New(ErrInvalidRememberMe).
+			WithDetail("error", "token malformed")
+	}
+
+	record, err := s.rememberMeStore.GetRememberMeToken(ctx, series)
+	if err != nil {
+		return nil, authErrors.New(ErrRememberMeStore).WithCause(err)
+	}
+	if record == nil {
+		return nil, authErrors.New(ErrInvalidRememberMe).
+			WithDetail("error", "unknown token")
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		_ = s.rememberMeStore.DeleteRememberMeToken(ctx, series)
+		return nil, authErrors.New(ErrInvalidRememberMe).
+			WithDetail("error", "token expired")
+	}
+
+	presentedHash := hashRememberMeValue(value)
+	if subtle.ConstantTimeCompare([]byte(record.TokenHash), []byte(presentedHash)) != 1 {
+		// The series is valid but the token was already rotated: assume theft
+		if err := s.rememberMeStore.DeleteUserRememberMeTokens(ctx, record.UserID); err != nil {
+			return nil, authErrors.New(ErrRememberMeStore).
+				WithDetail("user_id", record.UserID).
+				WithCause(err)
+		}
+		return nil, authErrors.New(ErrRememberMeReused).
+			WithDetail("user_id", record.UserID)
+	}
+
+	user, err := s.rememberMeUser(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rotate the token value, keeping the series and its absolute expiry
+	newValue, err := randomToken()
+	if err != nil {
+		return nil, authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+	record.TokenHash = hashRememberMeValue(newValue)
+	record.LastUsedAt = time.Now()
+	rotated, err := s.rememberMeStore.RotateRememberMeToken(ctx, presentedHash, record)
+	if err != nil {
+		return nil, authErrors.New(ErrRememberMeStore).
+			WithDetail("user_id", record.UserID).
+			WithCause(err)
+	}
+	if !rotated {
+		// A concurrent request with the same token rotated it first. That is
+		// a race, not a replay, so the user's tokens are left alone.
+		return nil, authErrors.New(ErrInvalidRememberMe).
+			WithDetail("user_id", record.UserID).
+			WithDetail("error", "token was rotated concurrently")
+	}
+
+	tokenString, err := s.GenerateTokenContext(ctx, user)
+	if err != nil {
+		return nil, authErrors.New(ErrTokenGeneration).
+			WithDetail("user_id", user.GetID()).
+			WithCause(err)
+	}
+
+	expiresAt := record.ExpiresAt
+	return &AuthResponse{
+		User:                user,
+		AccessToken:         tokenString,
+		TokenType:           "Bearer",
+		ExpiresIn:           int(s.tokenExpiration.Seconds()),
+		RememberMeToken:     series + "." + newValue,
+		RememberMeExpiresAt: &expiresAt,
+	}, nil
+}
+
+// RevokeRememberMeToken deletes the series of the given remember-me token (e.g. on logout)
+func (s *service) RevokeRememberMeToken(ctx context.Context, rememberMeToken string) error {
+	if s.rememberMeStore == nil {
+		return authErrors.New(ErrRememberMeDisabled)
+	}
+
+	series, _, ok := strings.Cut(rememberMeToken, ".")
+	if !ok || series == "" {
+		return authErrors.New(ErrInvalidRememberMe).
+			WithDetail("error", "token malformed")
+	}
+
+	if err := s.rememberMeStore.DeleteRememberMeToken(ctx, series); err != nil {
+		return authErrors.New(ErrRememberMeStore).WithCause(err)
+	}
+	return nil
+}
+
+// RevokeUserRememberMeTokens deletes every remember-me series of a user
+func (s *service) RevokeUserRememberMeTokens(ctx context.Context, userID string) error {
+	if s.rememberMeStore == nil {
+		return authErrors.New(ErrRememberMeDisabled)
+	}
+
+	if err := s.rememberMeStore.DeleteUserRememberMeTokens(ctx, userID); err != nil {
+		return authErrors.New(ErrRememberMeStore).
+			WithDetail("user_id", userID).
+			WithCause(err)
+	}
+	return nil
+}
+
+// checkRememberMeEnabled fails unless a RememberMeStore is configured and the
+// UserStore can reload users, so disabled users can't log in with remember-me
+func (s *service) checkRememberMeEnabled() error {
+	if s.rememberMeStore == nil {
+		return authErrors.New(ErrRememberMeDisabled)
+	}
+	if _, ok := s.userStore.(UserByIDStore); !ok {
+		return authErrors.New(ErrRememberMeDisabled).
+			WithDetail("error", "user store must implement UserByIDStore")
+	}
+	return nil
+}
+
+// rememberMeUser reloads the user a remember-me token belongs to
+func (s *service) rememberMeUser(ctx context.Context, record *RememberMeToken) (User, error) {
+	user, err := s.userStore.(UserByIDStore).GetUserByID(ctx, record.UserID)
+	if err != nil {
+		if IsUserNotFound(err) {
+			_ = s.rememberMeStore.DeleteUserRememberMeTokens(ctx, record.UserID)
+			return nil, authErrors.New(ErrInvalidRememberMe).
+				WithDetail("user_id", record.UserID).
+				WithCause(err)
+		}
+		return nil, authErrors.New(ErrUserInfo).WithCause(err)
+	}
+
+	if !user.IsActive() {
+		return nil, authErrors.New(ErrUserDisabled).
+			WithDetail("user_id", user.GetID())
+	}
+
+	return user, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashRememberMeValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+)
+
+func TestRememberMeSurvivesAccessTokenExpiry(t *testing.T) {
+	ctx := context.Background()
+	user := &testUser{id: "u1", email: "ada@example.com", active: true}
+	svc := newTestService(newTestUserStore(user), -time.Minute,
+		auth.WithRememberMe(auth.NewMemoryRememberMeStore(), time.Hour))
+
+	// Access tokens are already expired when issued
+	accessToken, err := svc.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := svc.ValidateToken(accessToken); !auth.IsTokenExpired(err) {
+		t.Fatalf("ValidateToken error = %v, want token expired", err)
+	}
+
+	rememberMe, expiresAt, err := svc.IssueRememberMeToken(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueRememberMeToken: %v", err)
+	}
+	if time.Until(expiresAt) <= 0 {
+		t.Fatalf("remember-me token expires at %v, want in the future", expiresAt)
+	}
+
+	resp, err := svc.LoginWithRememberMe(ctx, rememberMe)
+	if err != nil {
+		t.Fatalf("LoginWithRememberMe: %v", err)
+	}
+	if resp.AccessToken == "" || resp.User.GetID() != user.id {
+		t.Errorf("response = %+v, want a fresh access token for %s", resp, user.id)
+	}
+	if resp.RememberMeToken == "" || resp.RememberMeToken == rememberMe {
+		t.Errorf("remember-me token was not rotated")
+	}
+}
+
+func TestRememberMe(t *testing.T) {
+	tests := []struct {
+		name    string
+		active  bool
+		steps   func(t *testing.T, svc auth.Service, first string) error
+		wantErr func(error) bool
+	}{
+		{
+			name:   "rotated token logs in",
+			active: true,
+			steps: func(t *testing.T, svc auth.Service, first string) error {
+				resp, err := svc.LoginWithRememberMe(context.Background(), first)
+				if err != nil {
+					t.Fatalf("first login: %v", err)
+				}
+				_, err = svc.LoginWithRememberMe(context.Background(), resp.RememberMeToken)
+				return err
+			},
+		},
+		{
+			name:   "reuse after rotation is detected",
+			active: true,
+			steps: func(t *testing.T, svc auth.Service, first string) error {
+				if _, err := svc.LoginWithRememberMe(context.Background(), first); err != nil {
+					t.Fatalf("first login: %v", err)
+				}
+				_, err := svc.LoginWithRememberMe(context.Background(), first)
+				return err
+			},
+			wantErr: auth.IsRememberMeReused,
+		},
+		{
+			name:   "reuse revokes the rotated token too",
+			active: true,
+			steps: func(t *testing.T, svc auth.Service, first string) error {
+				resp, err := svc.LoginWithRememberMe(context.Background(), first)
+				if err != nil {
+					t.Fatalf("first login: %v", err)
+				}
+				if _, err := svc.LoginWithRememberMe(context.Background(), first); !auth.IsRememberMeReused(err) {
+					t.Fatalf("replay error = %v, want reuse detected", err)
+				}
+				_, err = svc.LoginWithRememberMe(context.Background(), resp.RememberMeToken)
+				return err
+			},
+			wantErr: func(err error) bool { return err != nil && !auth.IsRememberMeReused(err) },
+		},
+		{
+			name:   "disabled user is rejected",
+			active: false,
+			steps: func(t *testing.T, svc auth.Service, first string) error {
+				_, err := svc.LoginWithRememberMe(context.Background(), first)
+				return err
+			},
+			wantErr: auth.IsUserDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &testUser{id: "u1", email: "ada@example.com", active: true}
+			svc := newTestService(newTestUserStore(user), time.Minute,
+				auth.WithRememberMe(auth.NewMemoryRememberMeStore(), time.Hour))
+
+			first, _, err := svc.IssueRememberMeToken(context.Background(), user)
+			if err != nil {
+				t.Fatalf("IssueRememberMeToken: %v", err)
+			}
+			user.active = tt.active
+
+			err = tt.steps(t, svc, first)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != nil && !tt.wantErr(err):
+				t.Errorf("error = %v, not the expected kind", err)
+			}
+		})
+	}
+}
+
+func TestRememberMeNeedsUserByIDStore(t *testing.T) {
+	type plainStore struct{ auth.UserStore }
+	store := newTestUserStore()
+	svc := auth.NewAuthService(plainStore{store}, store, []byte("test-secret"), time.Minute,
+		auth.WithRememberMe(auth.NewMemoryRememberMeStore(), time.Hour))
+
+	_, _, err := svc.IssueRememberMeToken(context.Background(), &testUser{id: "u1", active: true})
+	if err == nil {
+		t.Fatal("IssueRememberMeToken succeeded without a UserByIDStore")
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+)
+
+// maxFlowDataSize caps the bytes of FlowData carried through an OAuth flow
+const maxFlowDataSize = 2048
+
+// FlowOption configures a flow started with BeginOAuth
+type FlowOption func(*AuthFlow)
+
+// WithReturnURL records where to send the user once the flow completes.
+// Relative paths are always accepted; absolute URLs must use a host allowed
+// with WithAllowedReturnHosts. CompleteOAuth returns it in AuthResponse.ReturnURL.
+func WithReturnURL(returnURL string) FlowOption {
+	return func(f *AuthFlow) {
+		f.ReturnURL = returnURL
+	}
+}
+
+// WithFlowData carries a small payload, such as an invite code, through the
+// flow. CompleteOAuth returns it in AuthResponse.FlowData.
+func WithFlowData(data map[string]string) FlowOption {
+	return func(f *AuthFlow) {
+		f.Data = data
+	}
+}
+
+// validateReturnURL accepts same-site paths and absolute http(s) URLs whose
+// host is allowed, rejecting everything that could redirect off-site
+func (s *service) validateReturnURL(returnURL string) error {
+	if returnURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(returnURL)
+	if err != nil {
+		return authErrors.New(ErrInvalidReturnURL).WithCause(err)
+	}
+
+	// "/path" is same-site; "//host" and "/\host" are protocol-relative
+	// redirects browsers follow to another host
+	if !u.IsAbs() && u.Host == "" {
+		if strings.HasPrefix(returnURL, "/") &&
+			!strings.HasPrefix(returnURL, "//") &&
+			!strings.HasPrefix(returnURL, "/\\") {
+			return nil
+		}
+		return authErrors.New(ErrInvalidReturnURL).
+			WithDetail("return_url", returnURL).
+			WithDetail("error", "relative return URLs must start with a single /")
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return authErrors.New(ErrInvalidReturnURL).
+			WithDetail("return_url", returnURL).
+			WithDetail("error", "unsupported scheme")
+	}
+	if u.User != nil || !s.allowedReturnHosts[strings.ToLower(u.Hostname())] {
+		return authErrors.New(ErrInvalidReturnURL).
+			WithDetail("return_url", returnURL).
+			WithDetail("error", "host not allowed")
+	}
+	return nil
+}
+
+// flowDataSize returns the bytes a FlowData payload takes
+func flowDataSize(data map[string]string) int {
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	return size
+}
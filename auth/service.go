@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/Abraxas-365/craftable/errx"
@@ -23,7 +24,27 @@ var (
 	ErrOAuthAccountCreation = authErrors.Register("OAUTH_ACCOUNT_CREATION_FAILED", errx.TypeInternal, 500, "Failed to create OAuth account")
 	ErrTokenGeneration      = authErrors.Register("TOKEN_GENERATION_FAILED", errx.TypeInternal, 500, "Failed to generate JWT token")
 	ErrUserNotFound         = authErrors.Register("USER_NOT_FOUND", errx.TypeNotFound, 404, "User not found")
-	ErrInvalidToken         = authErrors.Register("INVALID_TOKEN", errx.TypeAuthorization, 401, "Invalid or expired token")
+	ErrInvalidToken         = authErrors.Register("INVALID_TOKEN", errx.TypeAuthorization, 401, "Invalid token")
+	ErrTokenExpired         = authErrors.Register("TOKEN_EXPIRED", errx.TypeAuthorization, 401, "Token has expired")
+	ErrRememberMeDisabled   = authErrors.Register("REMEMBER_ME_DISABLED", errx.TypeBadRequest, 400, "Remember-me sessions are not enabled")
+	ErrInvalidRememberMe    = authErrors.Register("INVALID_REMEMBER_ME", errx.TypeAuthorization, 401, "Invalid or expired remember-me token")
+	ErrRememberMeReused     = authErrors.Register("REMEMBER_ME_REUSED", errx.TypeAuthorization, 401, "Remember-me token reuse detected")
+	ErrRememberMeStore      = authErrors.Register("REMEMBER_ME_STORE_FAILED", errx.TypeInternal, 500, "Remember-me token store operation failed")
+	ErrSignupNotAllowed     = authErrors.Register("SIGNUP_NOT_ALLOWED", errx.TypeAuthorization, 403, "Signup is not allowed for this user")
+	ErrSignupPolicy         = authErrors.Register("SIGNUP_POLICY_FAILED", errx.TypeInternal, 500, "Failed to evaluate signup policy")
+	ErrInvalidState         = authErrors.Register("INVALID_STATE", errx.TypeAuthorization, 401, "Invalid or expired OAuth state")
+	ErrInvalidNonce         = authErrors.Register("INVALID_NONCE", errx.TypeAuthorization, 401, "ID token nonce does not match")
+	ErrTransientStore       = authErrors.Register("TRANSIENT_STORE_FAILED", errx.TypeInternal, 500, "Transient store operation failed")
+	ErrTokenBindingMismatch = authErrors.Register("TOKEN_BINDING_MISMATCH", errx.TypeAuthorization, 401, "Token was issued to a different client")
+	ErrInvalidReturnURL     = authErrors.Register("INVALID_RETURN_URL", errx.TypeValidation, 400, "Return URL is not allowed")
+	ErrInvalidCredentials   = authErrors.Register("INVALID_CREDENTIALS", errx.TypeAuthorization, 401, "Invalid credentials")
+	ErrPasswordHash         = authErrors.Register("PASSWORD_HASH_FAILED", errx.TypeInternal, 500, "Failed to hash or verify password")
+	ErrTokenRevoked         = authErrors.Register("TOKEN_REVOKED", errx.TypeAuthorization, 401, "Token has been revoked")
+	ErrRevocationDisabled   = authErrors.Register("REVOCATION_DISABLED", errx.TypeBadRequest, 400, "Token revocation is not enabled")
+	ErrSessionsDisabled     = authErrors.Register("SESSIONS_DISABLED", errx.TypeBadRequest, 400, "Sessions are not enabled")
+	ErrTokenStore           = authErrors.Register("TOKEN_STORE_FAILED", errx.TypeInternal, 500, "Token store operation failed")
+	ErrInvalidationDisabled = authErrors.Register("INVALIDATION_DISABLED", errx.TypeBadRequest, 400, "User store does not support token invalidation")
+	ErrTokenOutdated        = authErrors.Register("TOKEN_OUTDATED", errx.TypeAuthorization, 401, "Token was issued under an outdated claims version")
 )
 
 // IsUserNotFound helper function
@@ -31,6 +52,66 @@ func IsUserNotFound(err error) bool {
 	return errx.IsCode(err, ErrUserNotFound)
 }
 
+// IsProviderNotFound reports whether no OAuth provider is registered under the requested name
+func IsProviderNotFound(err error) bool {
+	return errx.IsCode(err, ErrProviderNotFound)
+}
+
+// IsCodeExchangeFailed reports whether the provider rejected the authorization code
+func IsCodeExchangeFailed(err error) bool {
+	return errx.IsCode(err, ErrCodeExchange)
+}
+
+// IsUserDisabled reports whether the user's account is disabled
+func IsUserDisabled(err error) bool {
+	return errx.IsCode(err, ErrUserDisabled)
+}
+
+// IsTokenExpired reports whether an access token has expired, so the client
+// should refresh it or log in again
+func IsTokenExpired(err error) bool {
+	return errx.IsCode(err, ErrTokenExpired)
+}
+
+// IsInvalidToken reports whether an access token is malformed, badly signed
+// or otherwise unusable. Expired, revoked, outdated and client-bound tokens
+// have their own codes.
+func IsInvalidToken(err error) bool {
+	return errx.IsCode(err, ErrInvalidToken)
+}
+
+// IsRememberMeReused reports whether a remember-me token was presented after it had been rotated,
+// which indicates the token was stolen
+func IsRememberMeReused(err error) bool {
+	return errx.IsCode(err, ErrRememberMeReused)
+}
+
+// IsSignupNotAllowed reports whether a login was rejected by the signup policy
+func IsSignupNotAllowed(err error) bool {
+	return errx.IsCode(err, ErrSignupNotAllowed)
+}
+
+// IsInvalidState reports whether an OAuth callback carried an unknown, expired
+// or already used state
+func IsInvalidState(err error) bool {
+	return errx.IsCode(err, ErrInvalidState)
+}
+
+// IsInvalidReturnURL reports whether a return URL was rejected as a possible open redirect
+func IsInvalidReturnURL(err error) bool {
+	return errx.IsCode(err, ErrInvalidReturnURL)
+}
+
+// IsInvalidCredentials reports whether a password didn't match its stored hash
+func IsInvalidCredentials(err error) bool {
+	return errx.IsCode(err, ErrInvalidCredentials)
+}
+
+// IsInvalidNonce reports whether an ID token carried an unexpected nonce
+func IsInvalidNonce(err error) bool {
+	return errx.IsCode(err, ErrInvalidNonce)
+}
+
 // Service implementation
 type service struct {
 	providers       map[string]OAuthProvider
@@ -38,6 +119,25 @@ type service struct {
 	oauthStore      OAuthAccountStore
 	jwtSecret       []byte
 	tokenExpiration time.Duration
+
+	rememberMeStore      RememberMeStore
+	rememberMeExpiration time.Duration
+
+	signupPolicy SignupPolicy
+
+	transientStore TransientStore
+	flowTTL        time.Duration
+
+	requireTokenBinding bool
+
+	allowedReturnHosts map[string]bool
+
+	passwordHasher PasswordHasher
+
+	revocationStore RevocationStore
+	sessionStore    SessionStore
+
+	tokenVersion atomic.Int64
 }
 
 // NewAuthService creates a new auth service
@@ -46,14 +146,22 @@ func NewAuthService(
 	oauthStore OAuthAccountStore,
 	jwtSecret []byte,
 	tokenExpiration time.Duration,
+	opts ...ServiceOption,
 ) Service {
-	return &service{
+	s := &service{
 		providers:       make(map[string]OAuthProvider),
 		userStore:       userStore,
 		oauthStore:      oauthStore,
 		jwtSecret:       jwtSecret,
 		tokenExpiration: tokenExpiration,
+		transientStore:  NewMemoryTransientStore(),
+		flowTTL:         DefaultFlowTTL,
+		passwordHasher:  NewBcryptHasher(0),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // GetAuthURL returns the authorization URL for the specified provider
@@ -80,6 +188,12 @@ func (s *service) HandleOAuthCallback(ctx context.Context, provider, code string
 			WithCause(err)
 	}
 
+	return s.loginWithToken(ctx, provider, p, token)
+}
+
+// loginWithToken resolves the provider user for an exchanged token, signing it
+// up when needed, and issues an access token
+func (s *service) loginWithToken(ctx context.Context, provider string, p OAuthProvider, token *OAuthToken) (*AuthResponse, error) {
 	// Get user info from provider
 	userInfo, err := p.GetUserInfo(ctx, token)
 	if err != nil {
@@ -92,7 +206,11 @@ func (s *service) HandleOAuthCallback(ctx context.Context, provider, code string
 	user, err := s.userStore.GetUserByProviderID(ctx, provider, userInfo.GetProviderID())
 	if err != nil {
 		if IsUserNotFound(err) {
-			// User doesn't exist, create new user
+			// User doesn't exist, check the signup policy before creating it
+			if err := s.checkSignupPolicy(ctx, userInfo); err != nil {
+				return nil, err
+			}
+
 			user, err = s.userStore.CreateUser(ctx, userInfo)
 			if err != nil {
 				return nil, authErrors.New(ErrUserCreation).
@@ -126,7 +244,7 @@ func (s *service) HandleOAuthCallback(ctx context.Context, provider, code string
 	}
 
 	// Generate JWT token
-	tokenString, err := s.GenerateToken(user)
+	tokenString, err := s.GenerateTokenContext(ctx, user)
 	if err != nil {
 		return nil, authErrors.New(ErrTokenGeneration).
 			WithDetail("user_id", user.GetID()).
@@ -141,13 +259,60 @@ func (s *service) HandleOAuthCallback(ctx context.Context, provider, code string
 	}, nil
 }
 
+// checkSignupPolicy returns an error when the configured policy rejects a new user
+func (s *service) checkSignupPolicy(ctx context.Context, userInfo AuthUserInfo) error {
+	if s.signupPolicy == nil {
+		return nil
+	}
+
+	allowed, err := s.signupPolicy.AllowSignup(ctx, userInfo)
+	if err != nil {
+		return authErrors.New(ErrSignupPolicy).
+			WithDetail("provider", userInfo.GetProvider()).
+			WithCause(err)
+	}
+	if !allowed {
+		return authErrors.New(ErrSignupNotAllowed).
+			WithDetail("provider", userInfo.GetProvider()).
+			WithDetail("email", userInfo.GetEmail())
+	}
+
+	return nil
+}
+
 func (s *service) GenerateToken(user User) (string, error) {
+	return s.generateToken(context.Background(), user, ClientFingerprint{})
+}
+
+// generateToken signs an access token, bound to a client when fingerprint has
+// a secret, and records its session when a SessionStore is configured
+func (s *service) generateToken(ctx context.Context, user User, fingerprint ClientFingerprint) (string, error) {
+	tokenID, err := randomToken()
+	if err != nil {
+		return "", authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+
 	now := time.Now()
 	claims := &JWTClaims{ // Note the & to create a pointer
 		UserID:    user.GetID(),
 		Email:     user.GetEmail(),
 		IssuedAt:  now,
 		ExpiresAt: now.Add(s.tokenExpiration),
+		ID:        tokenID,
+	}
+	if fingerprint.Secret != "" {
+		claims.Fingerprint = fingerprint.Hash()
+	}
+	if err := s.stampTokenVersion(ctx, claims); err != nil {
+		return "", err
+	}
+
+	if s.sessionStore != nil {
+		session, err := s.createSession(ctx, claims, fingerprint.UserAgent)
+		if err != nil {
+			return "", err
+		}
+		claims.SessionID = session.ID
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -161,8 +326,19 @@ func (s *service) GenerateToken(user User) (string, error) {
 	return tokenString, nil
 }
 
-// ValidateToken verifies a JWT token and returns the claims
+// ValidateToken verifies a JWT token and returns the claims. Revoked tokens,
+// tokens of ended sessions and tokens issued before InvalidateUserSessions are
+// rejected with ErrTokenRevoked, tokens issued under an older claims version
+// with ErrTokenOutdated, expired tokens with ErrTokenExpired, other unusable
+// tokens with ErrInvalidToken, and tokens of disabled users with ErrUserDisabled.
+// It has no client fingerprint, so bound tokens, and with WithTokenBinding every
+// token, fail with ErrTokenBindingMismatch; validate those with ValidateTokenContext.
 func (s *service) ValidateToken(tokenString string) (*JWTClaims, error) {
+	return s.ValidateTokenContext(context.Background(), tokenString)
+}
+
+// parseToken verifies a token's signature and expiry and returns its claims
+func (s *service) parseToken(tokenString string) (*JWTClaims, error) {
 	claims := &JWTClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -177,8 +353,7 @@ func (s *service) ValidateToken(tokenString string) (*JWTClaims, error) {
 	if err != nil {
 		// Handle JWT errors - jwt/v5 uses wrapped errors
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, authErrors.New(ErrInvalidToken).
-				WithDetail("error", "token expired").
+			return nil, authErrors.New(ErrTokenExpired).
 				WithCause(err)
 		} else if errors.Is(err, jwt.ErrTokenNotValidYet) {
 			return nil, authErrors.New(ErrInvalidToken).
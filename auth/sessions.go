@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// IsTokenRevoked reports whether an access token was revoked or its session ended
+func IsTokenRevoked(err error) bool {
+	return errx.IsCode(err, ErrTokenRevoked)
+}
+
+// createSession records the session of a token about to be issued
+func (s *service) createSession(ctx context.Context, claims *JWTClaims, userAgent string) (*Session, error) {
+	id, err := randomToken()
+	if err != nil {
+		return nil, authErrors.New(ErrTokenGeneration).WithCause(err)
+	}
+
+	session := &Session{
+		ID:        id,
+		UserID:    claims.UserID,
+		UserAgent: userAgent,
+		CreatedAt: claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	if err := s.sessionStore.SaveSession(ctx, session); err != nil {
+		return nil, authErrors.New(ErrTokenStore).
+			WithDetail("user_id", claims.UserID).
+			WithCause(err)
+	}
+	return session, nil
+}
+
+// checkTokenState rejects outdated and revoked tokens, tokens whose session
+// ended and tokens of invalidated or disabled users. Without stores tokens are
+// purely stateless and only the global claims version is checked.
+func (s *service) checkTokenState(ctx context.Context, claims *JWTClaims) error {
+	if err := s.checkTokenVersion(ctx, claims); err != nil {
+		return err
+	}
+
+	if s.revocationStore != nil && claims.ID != "" {
+		revoked, err := s.revocationStore.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return authErrors.New(ErrTokenStore).WithCause(err)
+		}
+		if revoked {
+			return authErrors.New(ErrTokenRevoked).
+				WithDetail("user_id", claims.UserID)
+		}
+	}
+
+	if s.sessionStore != nil {
+		if claims.SessionID == "" {
+			return authErrors.New(ErrTokenRevoked).
+				WithDetail("user_id", claims.UserID).
+				WithDetail("error", "token has no session")
+		}
+		session, err := s.sessionStore.GetSession(ctx, claims.SessionID)
+		if err != nil {
+			return authErrors.New(ErrTokenStore).WithCause(err)
+		}
+		if session == nil || session.UserID != claims.UserID {
+			return authErrors.New(ErrTokenRevoked).
+				WithDetail("user_id", claims.UserID).
+				WithDetail("error", "session ended")
+		}
+	}
+
+	return s.checkTokenUser(ctx, claims)
+}
+
+// checkTokenUser rejects tokens issued before the user's tokens were
+// invalidated and tokens of users that were disabled or deleted since
+func (s *service) checkTokenUser(ctx context.Context, claims *JWTClaims) error {
+	if validity, ok := s.userStore.(TokenValidityStore); ok {
+		validAfter, err := validity.GetTokensValidAfter(ctx, claims.UserID)
+		if err != nil {
+			return authErrors.New(ErrTokenStore).
+				WithDetail("user_id", claims.UserID).
+				WithCause(err)
+		}
+		if claims.IssuedAt.Before(validAfter) {
+			return authErrors.New(ErrTokenRevoked).
+				WithDetail("user_id", claims.UserID).
+				WithDetail("error", "token issued before the user's sessions were invalidated")
+		}
+	}
+
+	if byID, ok := s.userStore.(UserByIDStore); ok {
+		user, err := byID.GetUserByID(ctx, claims.UserID)
+		if err != nil {
+			if IsUserNotFound(err) {
+				return authErrors.New(ErrInvalidToken).
+					WithDetail("user_id", claims.UserID).
+					WithDetail("error", "user not found").
+					WithCause(err)
+			}
+			return authErrors.New(ErrUserInfo).WithCause(err)
+		}
+		if !user.IsActive() {
+			return authErrors.New(ErrUserDisabled).
+				WithDetail("user_id", claims.UserID)
+		}
+	}
+
+	return nil
+}
+
+// InvalidateUserSessions makes every token issued to a user so far unusable,
+// e.g. after a password change or when an account is disabled. It moves the
+// user's tokens-valid-after time to now and also ends their sessions and
+// remember-me tokens when those stores are configured. The UserStore must
+// implement TokenValidityStore.
+func (s *service) InvalidateUserSessions(ctx context.Context, userID string) error {
+	validity, ok := s.userStore.(TokenValidityStore)
+	if !ok {
+		return authErrors.New(ErrInvalidationDisabled).
+			WithDetail("user_id", userID)
+	}
+
+	if err := validity.SetTokensValidAfter(ctx, userID, time.Now()); err != nil {
+		return authErrors.New(ErrTokenStore).
+			WithDetail("user_id", userID).
+			WithCause(err)
+	}
+
+	if s.sessionStore != nil {
+		if err := s.RevokeUserSessions(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if s.rememberMeStore != nil {
+		if err := s.RevokeUserRememberMeTokens(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeToken makes a still valid access token unusable (e.g. on logout). Its
+// ID is added to the RevocationStore and its session, if any, is deleted.
+func (s *service) RevokeToken(ctx context.Context, tokenString string) error {
+	if s.revocationStore == nil && s.sessionStore == nil {
+		return authErrors.New(ErrRevocationDisabled)
+	}
+
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return err
+	}
+
+	if s.revocationStore != nil && claims.ID != "" {
+		if err := s.revocationStore.Revoke(ctx, claims.ID, claims.ExpiresAt); err != nil {
+			return authErrors.New(ErrTokenStore).
+				WithDetail("user_id", claims.UserID).
+				WithCause(err)
+		}
+	}
+	if s.sessionStore != nil && claims.SessionID != "" {
+		if err := s.sessionStore.DeleteSession(ctx, claims.SessionID); err != nil {
+			return authErrors.New(ErrTokenStore).
+				WithDetail("user_id", claims.UserID).
+				WithCause(err)
+		}
+	}
+	return nil
+}
+
+// ListSessions returns the unexpired sessions of a user
+func (s *service) ListSessions(ctx context.Context, userID string) ([]*Session, error) {
+	if s.sessionStore == nil {
+		return nil, authErrors.New(ErrSessionsDisabled)
+	}
+
+	sessions, err := s.sessionStore.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, authErrors.New(ErrTokenStore).
+			WithDetail("user_id", userID).
+			WithCause(err)
+	}
+
+	now := time.Now()
+	active := sessions[:0]
+	for _, session := range sessions {
+		if now.Before(session.ExpiresAt) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// RevokeSession ends one session, invalidating its access token
+func (s *service) RevokeSession(ctx context.Context, sessionID string) error {
+	if s.sessionStore == nil {
+		return authErrors.New(ErrSessionsDisabled)
+	}
+
+	if err := s.sessionStore.DeleteSession(ctx, sessionID); err != nil {
+		return authErrors.New(ErrTokenStore).WithCause(err)
+	}
+	return nil
+}
+
+// RevokeUserSessions ends every session of a user, logging them out everywhere.
+// Remember-me tokens are separate; revoke them with RevokeUserRememberMeTokens.
+func (s *service) RevokeUserSessions(ctx context.Context, userID string) error {
+	if s.sessionStore == nil {
+		return authErrors.New(ErrSessionsDisabled)
+	}
+
+	if err := s.sessionStore.DeleteUserSessions(ctx, userID); err != nil {
+		return authErrors.New(ErrTokenStore).
+			WithDetail("user_id", userID).
+			WithCause(err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// SignupPolicy decides whether a first-time OAuth user may get an account.
+// It is consulted in HandleOAuthCallback before UserStore.CreateUser.
+type SignupPolicy interface {
+	AllowSignup(ctx context.Context, info AuthUserInfo) (bool, error)
+}
+
+// SignupPolicyFunc is a function adapter for SignupPolicy
+type SignupPolicyFunc func(ctx context.Context, info AuthUserInfo) (bool, error)
+
+// AllowSignup implements SignupPolicy
+func (f SignupPolicyFunc) AllowSignup(ctx context.Context, info AuthUserInfo) (bool, error) {
+	return f(ctx, info)
+}
+
+// AllowEmailDomains only allows signups whose email belongs to one of the given domains
+func AllowEmailDomains(domains ...string) SignupPolicy {
+	allowed := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		allowed[strings.ToLower(strings.TrimPrefix(d, "@"))] = true
+	}
+
+	return SignupPolicyFunc(func(ctx context.Context, info AuthUserInfo) (bool, error) {
+		_, domain, ok := strings.Cut(info.GetEmail(), "@")
+		if !ok {
+			return false, nil
+		}
+		return allowed[strings.ToLower(domain)], nil
+	})
+}
+
+// DenySignup rejects every new user, e.g. for invite-only apps where users are pre-created
+func DenySignup() SignupPolicy {
+	return SignupPolicyFunc(func(ctx context.Context, info AuthUserInfo) (bool, error) {
+		return false, nil
+	})
+}
+
+// AllOf allows a signup only when every policy allows it
+func AllOf(policies ...SignupPolicy) SignupPolicy {
+	return SignupPolicyFunc(func(ctx context.Context, info AuthUserInfo) (bool, error) {
+		for _, p := range policies {
+			ok, err := p.AllowSignup(ctx, info)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// AnyOf allows a signup when at least one policy allows it
+func AnyOf(policies ...SignupPolicy) SignupPolicy {
+	return SignupPolicyFunc(func(ctx context.Context, info AuthUserInfo) (bool, error) {
+		for _, p := range policies {
+			ok, err := p.AllowSignup(ctx, info)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRememberMeExpiration is the remember-me lifetime used by WithStores
+const DefaultRememberMeExpiration = 30 * 24 * time.Hour
+
+// Stores bundles the stores behind the stateful features. Each is optional:
+// a nil store leaves its feature at the default (in-memory transient store,
+// remember-me disabled, stateless access tokens).
+type Stores struct {
+	Transient  TransientStore
+	RememberMe RememberMeStore
+	Revocation RevocationStore
+	Sessions   SessionStore
+}
+
+// MemoryStores returns in-process implementations of every store, for
+// development and tests. Replace them one by one with shared stores (e.g.
+// Redis) for multi-instance deployments.
+func MemoryStores() Stores {
+	return Stores{
+		Transient:  NewMemoryTransientStore(),
+		RememberMe: NewMemoryRememberMeStore(),
+		Revocation: NewMemoryRevocationStore(),
+		Sessions:   NewMemorySessionStore(),
+	}
+}
+
+// WithStores configures every non-nil store of the bundle. Remember-me tokens
+// last DefaultRememberMeExpiration unless WithRememberMe sets the expiration.
+func WithStores(stores Stores) ServiceOption {
+	return func(s *service) {
+		if stores.Transient != nil {
+			s.transientStore = stores.Transient
+		}
+		if stores.RememberMe != nil {
+			s.rememberMeStore = stores.RememberMe
+			if s.rememberMeExpiration == 0 {
+				s.rememberMeExpiration = DefaultRememberMeExpiration
+			}
+		}
+		if stores.Revocation != nil {
+			s.revocationStore = stores.Revocation
+		}
+		if stores.Sessions != nil {
+			s.sessionStore = stores.Sessions
+		}
+	}
+}
+
+// WithRevocationStore enables RevokeToken for stateless access tokens
+func WithRevocationStore(store RevocationStore) ServiceOption {
+	return func(s *service) {
+		s.revocationStore = store
+	}
+}
+
+// WithSessionStore records a session for every access token, which is then
+// only valid while its session exists
+func WithSessionStore(store SessionStore) ServiceOption {
+	return func(s *service) {
+		s.sessionStore = store
+	}
+}
+
+// MemoryRememberMeStore is an in-process RememberMeStore
+type MemoryRememberMeStore struct {
+	mutex  sync.Mutex
+	tokens map[string]RememberMeToken
+}
+
+// NewMemoryRememberMeStore creates an empty in-memory remember-me store
+func NewMemoryRememberMeStore() *MemoryRememberMeStore {
+	return &MemoryRememberMeStore{tokens: make(map[string]RememberMeToken)}
+}
+
+// SaveRememberMeToken creates or replaces a token series
+func (m *MemoryRememberMeStore) SaveRememberMeToken(ctx context.Context, token *RememberMeToken) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.tokens[token.Series] = *token
+	return nil
+}
+
+// RotateRememberMeToken replaces a series if its token hash is still oldHash
+func (m *MemoryRememberMeStore) RotateRememberMeToken(ctx context.Context, oldHash string, token *RememberMeToken) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current, exists := m.tokens[token.Series]
+	if !exists || current.TokenHash != oldHash {
+		return false, nil
+	}
+	m.tokens[token.Series] = *token
+	return true, nil
+}
+
+// GetRememberMeToken returns a copy of a series, or nil when it doesn't exist
+func (m *MemoryRememberMeStore) GetRememberMeToken(ctx context.Context, series string) (*RememberMeToken, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	token, exists := m.tokens[series]
+	if !exists {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// DeleteRememberMeToken removes a series
+func (m *MemoryRememberMeStore) DeleteRememberMeToken(ctx context.Context, series string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.tokens, series)
+	return nil
+}
+
+// DeleteUserRememberMeTokens removes every series of a user
+func (m *MemoryRememberMeStore) DeleteUserRememberMeTokens(ctx context.Context, userID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for series, token := range m.tokens {
+		if token.UserID == userID {
+			delete(m.tokens, series)
+		}
+	}
+	return nil
+}
+
+// MemoryRevocationStore is an in-process RevocationStore
+type MemoryRevocationStore struct {
+	mutex   sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationStore creates an empty in-memory revocation store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{revoked: make(map[string]time.Time)}
+}
+
+// Revoke denies tokenID until expiresAt
+func (m *MemoryRevocationStore) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	for id, expiry := range m.revoked {
+		if now.After(expiry) {
+			delete(m.revoked, id)
+		}
+	}
+	m.revoked[tokenID] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether tokenID was revoked
+func (m *MemoryRevocationStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, revoked := m.revoked[tokenID]
+	return revoked, nil
+}
+
+// MemorySessionStore is an in-process SessionStore
+type MemorySessionStore struct {
+	mutex    sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session)}
+}
+
+// SaveSession creates or replaces a session
+func (m *MemorySessionStore) SaveSession(ctx context.Context, session *Session) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	for id, existing := range m.sessions {
+		if now.After(existing.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+	m.sessions[session.ID] = *session
+	return nil
+}
+
+// GetSession returns a copy of a session, or nil when it is missing or expired
+func (m *MemorySessionStore) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	session, exists := m.sessions[sessionID]
+	if !exists || time.Now().After(session.ExpiresAt) {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// ListUserSessions returns the unexpired sessions of a user, oldest first
+func (m *MemorySessionStore) ListUserSessions(ctx context.Context, userID string) ([]*Session, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	var sessions []*Session
+	for _, session := range m.sessions {
+		if session.UserID == userID && now.Before(session.ExpiresAt) {
+			session := session
+			sessions = append(sessions, &session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// DeleteSession removes a session
+func (m *MemorySessionStore) DeleteSession(ctx context.Context, sessionID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+// DeleteUserSessions removes every session of a user
+func (m *MemorySessionStore) DeleteUserSessions(ctx context.Context, userID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, session := range m.sessions {
+		if session.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
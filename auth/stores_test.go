@@ -0,0 +1,183 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+func TestMemoryStores(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// run returns the access tokens that must still validate and those
+		// that must be revoked
+		run func(t *testing.T, svc auth.Service, user *testUser) (valid, revoked []string)
+	}{
+		{
+			name: "refresh with a remember-me token opens a new session",
+			run: func(t *testing.T, svc auth.Service, user *testUser) ([]string, []string) {
+				access := generateToken(t, svc, user)
+				rememberMe, _, err := svc.IssueRememberMeToken(ctx, user)
+				if err != nil {
+					t.Fatalf("IssueRememberMeToken: %v", err)
+				}
+				resp, err := svc.LoginWithRememberMe(ctx, rememberMe)
+				if err != nil {
+					t.Fatalf("LoginWithRememberMe: %v", err)
+				}
+				assertSessionCount(t, svc, user.id, 2)
+				return []string{access, resp.AccessToken}, nil
+			},
+		},
+		{
+			name: "revoking a token leaves the others valid",
+			run: func(t *testing.T, svc auth.Service, user *testUser) ([]string, []string) {
+				first, second := generateToken(t, svc, user), generateToken(t, svc, user)
+				if err := svc.RevokeToken(ctx, first); err != nil {
+					t.Fatalf("RevokeToken: %v", err)
+				}
+				assertSessionCount(t, svc, user.id, 1)
+				return []string{second}, []string{first}
+			},
+		},
+		{
+			name: "revoking a session revokes its token",
+			run: func(t *testing.T, svc auth.Service, user *testUser) ([]string, []string) {
+				first := generateToken(t, svc, user)
+				time.Sleep(time.Millisecond) // order sessions by creation time
+				second := generateToken(t, svc, user)
+
+				sessions, err := svc.ListSessions(ctx, user.id)
+				if err != nil {
+					t.Fatalf("ListSessions: %v", err)
+				}
+				if len(sessions) != 2 {
+					t.Fatalf("sessions = %d, want 2", len(sessions))
+				}
+				if err := svc.RevokeSession(ctx, sessions[0].ID); err != nil {
+					t.Fatalf("RevokeSession: %v", err)
+				}
+				return []string{second}, []string{first}
+			},
+		},
+		{
+			name: "revoking all sessions logs out everywhere",
+			run: func(t *testing.T, svc auth.Service, user *testUser) ([]string, []string) {
+				first, second := generateToken(t, svc, user), generateToken(t, svc, user)
+				if err := svc.RevokeUserSessions(ctx, user.id); err != nil {
+					t.Fatalf("RevokeUserSessions: %v", err)
+				}
+				assertSessionCount(t, svc, user.id, 0)
+				return nil, []string{first, second}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &testUser{id: "u1", email: "ada@example.com", active: true}
+			svc := newTestService(newTestUserStore(user), time.Hour, auth.WithStores(auth.MemoryStores()))
+
+			valid, revoked := tt.run(t, svc, user)
+
+			for _, token := range valid {
+				if _, err := svc.ValidateToken(token); err != nil {
+					t.Errorf("ValidateToken: %v, want valid", err)
+				}
+			}
+			for _, token := range revoked {
+				if _, err := svc.ValidateToken(token); !auth.IsTokenRevoked(err) {
+					t.Errorf("ValidateToken error = %v, want token revoked", err)
+				}
+			}
+		})
+	}
+}
+
+func TestStatelessRevocation(t *testing.T) {
+	ctx := context.Background()
+	user := &testUser{id: "u1", email: "ada@example.com", active: true}
+	svc := newTestService(newTestUserStore(user), time.Hour,
+		auth.WithRevocationStore(auth.NewMemoryRevocationStore()))
+
+	token := generateToken(t, svc, user)
+	if err := svc.RevokeToken(ctx, token); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := svc.ValidateToken(token); !auth.IsTokenRevoked(err) {
+		t.Errorf("ValidateToken error = %v, want token revoked", err)
+	}
+	if _, err := svc.ListSessions(ctx, user.id); !errx.IsCode(err, auth.ErrSessionsDisabled) {
+		t.Errorf("ListSessions error = %v, want sessions disabled", err)
+	}
+}
+
+func TestStoresDisabled(t *testing.T) {
+	ctx := context.Background()
+	user := &testUser{id: "u1", email: "ada@example.com", active: true}
+	svc := newTestService(newTestUserStore(user), time.Hour)
+
+	token := generateToken(t, svc, user)
+	if err := svc.RevokeToken(ctx, token); !errx.IsCode(err, auth.ErrRevocationDisabled) {
+		t.Errorf("RevokeToken error = %v, want revocation disabled", err)
+	}
+	if err := svc.RevokeUserSessions(ctx, user.id); !errx.IsCode(err, auth.ErrSessionsDisabled) {
+		t.Errorf("RevokeUserSessions error = %v, want sessions disabled", err)
+	}
+	if _, err := svc.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken: %v, want stateless tokens to stay valid", err)
+	}
+}
+
+func TestMemorySessionStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := auth.NewMemorySessionStore()
+	now := time.Now()
+
+	sessions := []*auth.Session{
+		{ID: "expired", UserID: "u1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		{ID: "active", UserID: "u1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "other", UserID: "u2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+	for _, session := range sessions {
+		if err := store.SaveSession(ctx, session); err != nil {
+			t.Fatalf("SaveSession: %v", err)
+		}
+	}
+
+	if session, err := store.GetSession(ctx, "expired"); err != nil || session != nil {
+		t.Errorf("GetSession(expired) = %v, %v, want nil, nil", session, err)
+	}
+	listed, err := store.ListUserSessions(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListUserSessions: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != "active" {
+		t.Errorf("sessions = %+v, want only the active one", listed)
+	}
+}
+
+func generateToken(t *testing.T, svc auth.Service, user auth.User) string {
+	t.Helper()
+	token, err := svc.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+func assertSessionCount(t *testing.T, svc auth.Service, userID string, want int) {
+	t.Helper()
+	sessions, err := svc.ListSessions(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != want {
+		t.Errorf("sessions = %d, want %d", len(sessions), want)
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryTransientStore is an in-process TransientStore. It is the default
+// store and is suitable for single-instance deployments and tests.
+type MemoryTransientStore struct {
+	mutex   sync.Mutex
+	entries map[string]transientEntry
+}
+
+type transientEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewMemoryTransientStore creates an empty in-memory transient store
+func NewMemoryTransientStore() *MemoryTransientStore {
+	return &MemoryTransientStore{entries: make(map[string]transientEntry)}
+}
+
+// Put stores a value that expires after ttl
+func (m *MemoryTransientStore) Put(ctx context.Context, key, value string, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	m.evictExpired(now)
+	m.entries[key] = transientEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Get returns a value unless it is missing or expired
+func (m *MemoryTransientStore) Get(ctx context.Context, key string) (string, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, exists := m.entries[key]
+	if !exists {
+		return "", false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Take returns and removes a value unless it is missing or expired
+func (m *MemoryTransientStore) Take(ctx context.Context, key string) (string, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, exists := m.entries[key]
+	if !exists {
+		return "", false, nil
+	}
+	delete(m.entries, key)
+	if time.Now().After(entry.expiresAt) {
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Delete removes a value; deleting a missing key is not an error
+func (m *MemoryTransientStore) Delete(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// evictExpired drops expired entries so abandoned flows don't accumulate
+func (m *MemoryTransientStore) evictExpired(now time.Time) {
+	for key, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
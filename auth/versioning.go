@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// TokenVersionStore is an optional extension of UserStore that keeps a claims
+// version per user. When the configured UserStore implements it, access tokens
+// embed the user's version and ValidateToken rejects tokens carrying an older
+// one, so BumpUserTokenVersion forces a single user to log in again.
+// GetTokenVersion must return 0 when none was set.
+type TokenVersionStore interface {
+	GetTokenVersion(ctx context.Context, userID string) (int64, error)
+	IncrementTokenVersion(ctx context.Context, userID string) (int64, error)
+}
+
+// IsTokenOutdated reports whether a token was issued under an older global or
+// per-user claims version, so the user has to authenticate again
+func IsTokenOutdated(err error) bool {
+	return errx.IsCode(err, ErrTokenOutdated)
+}
+
+// TokenVersion returns the global claims version new tokens are issued with
+func (s *service) TokenVersion() int64 {
+	return s.tokenVersion.Load()
+}
+
+// BumpTokenVersion increments the global claims version, invalidating every
+// token issued so far (e.g. after a breach or a change of the claims), and
+// returns the new version. The version lives in this process only: in
+// multi-instance deployments set it on every instance with WithTokenVersion.
+func (s *service) BumpTokenVersion() int64 {
+	return s.tokenVersion.Add(1)
+}
+
+// BumpUserTokenVersion increments a user's claims version, invalidating every
+// token issued to them so far. The UserStore must implement TokenVersionStore.
+func (s *service) BumpUserTokenVersion(ctx context.Context, userID string) error {
+	versions, ok := s.userStore.(TokenVersionStore)
+	if !ok {
+		return authErrors.New(ErrInvalidationDisabled).
+			WithDetail("user_id", userID)
+	}
+
+	if _, err := versions.IncrementTokenVersion(ctx, userID); err != nil {
+		return authErrors.New(ErrTokenStore).
+			WithDetail("user_id", userID).
+			WithCause(err)
+	}
+	return nil
+}
+
+// stampTokenVersion records the current global and per-user claims versions
+// in the claims of a token about to be issued
+func (s *service) stampTokenVersion(ctx context.Context, claims *JWTClaims) error {
+	claims.Version = s.tokenVersion.Load()
+
+	if versions, ok := s.userStore.(TokenVersionStore); ok {
+		version, err := versions.GetTokenVersion(ctx, claims.UserID)
+		if err != nil {
+			return authErrors.New(ErrTokenStore).
+				WithDetail("user_id", claims.UserID).
+				WithCause(err)
+		}
+		claims.UserVersion = version
+	}
+	return nil
+}
+
+// checkTokenVersion rejects tokens issued under an older global or per-user
+// claims version. Tokens from before versioning carry version 0.
+func (s *service) checkTokenVersion(ctx context.Context, claims *JWTClaims) error {
+	if current := s.tokenVersion.Load(); claims.Version < current {
+		return authErrors.New(ErrTokenOutdated).
+			WithDetail("user_id", claims.UserID).
+			WithDetail("token_version", claims.Version).
+			WithDetail("current_version", current)
+	}
+
+	if versions, ok := s.userStore.(TokenVersionStore); ok {
+		current, err := versions.GetTokenVersion(ctx, claims.UserID)
+		if err != nil {
+			return authErrors.New(ErrTokenStore).
+				WithDetail("user_id", claims.UserID).
+				WithCause(err)
+		}
+		if claims.UserVersion < current {
+			return authErrors.New(ErrTokenOutdated).
+				WithDetail("user_id", claims.UserID).
+				WithDetail("token_version", claims.UserVersion).
+				WithDetail("current_version", current).
+				WithDetail("error", "user token version was bumped")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/auth"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// versionedUserStore adds a TokenVersionStore to testUserStore
+type versionedUserStore struct {
+	*testUserStore
+
+	versionsMutex sync.Mutex
+	versions      map[string]int64
+}
+
+func (s *versionedUserStore) GetTokenVersion(ctx context.Context, userID string) (int64, error) {
+	s.versionsMutex.Lock()
+	defer s.versionsMutex.Unlock()
+	return s.versions[userID], nil
+}
+
+func (s *versionedUserStore) IncrementTokenVersion(ctx context.Context, userID string) (int64, error) {
+	s.versionsMutex.Lock()
+	defer s.versionsMutex.Unlock()
+	s.versions[userID]++
+	return s.versions[userID], nil
+}
+
+func TestTokenVersionBump(t *testing.T) {
+	ada := &testUser{id: "u1", email: "ada@example.com", active: true}
+	bob := &testUser{id: "u2", email: "bob@example.com", active: true}
+
+	tests := []struct {
+		name string
+		// bump returns the users whose earlier tokens must now be rejected
+		// and those whose earlier tokens must still pass
+		bump func(t *testing.T, svc auth.Service) (outdated []auth.User, current []auth.User)
+	}{
+		{
+			name: "global bump invalidates every user's tokens",
+			bump: func(t *testing.T, svc auth.Service) ([]auth.User, []auth.User) {
+				if got := svc.BumpTokenVersion(); got != 1 {
+					t.Errorf("BumpTokenVersion() = %d, want 1", got)
+				}
+				return []auth.User{ada, bob}, nil
+			},
+		},
+		{
+			name: "user bump invalidates only that user's tokens",
+			bump: func(t *testing.T, svc auth.Service) ([]auth.User, []auth.User) {
+				if err := svc.BumpUserTokenVersion(context.Background(), ada.id); err != nil {
+					t.Fatalf("BumpUserTokenVersion: %v", err)
+				}
+				return []auth.User{ada}, []auth.User{bob}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &versionedUserStore{testUserStore: newTestUserStore(ada, bob), versions: map[string]int64{}}
+			svc := auth.NewAuthService(store, store, []byte("test-secret"), time.Hour)
+
+			before := map[string]string{
+				ada.id: generateToken(t, svc, ada),
+				bob.id: generateToken(t, svc, bob),
+			}
+
+			outdated, current := tt.bump(t, svc)
+
+			for _, user := range outdated {
+				if _, err := svc.ValidateToken(before[user.GetID()]); !auth.IsTokenOutdated(err) {
+					t.Errorf("%s: old token error = %v, want token outdated", user.GetID(), err)
+				}
+			}
+			for _, user := range current {
+				if _, err := svc.ValidateToken(before[user.GetID()]); err != nil {
+					t.Errorf("%s: old token: %v, want still valid", user.GetID(), err)
+				}
+			}
+			for _, user := range []auth.User{ada, bob} {
+				if _, err := svc.ValidateToken(generateToken(t, svc, user)); err != nil {
+					t.Errorf("%s: new token: %v, want valid", user.GetID(), err)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTokenVersion(t *testing.T) {
+	user := &testUser{id: "u1", email: "ada@example.com", active: true}
+	store := newTestUserStore(user)
+
+	v5 := newTestService(store, time.Hour, auth.WithTokenVersion(5))
+	v6 := newTestService(store, time.Hour, auth.WithTokenVersion(6))
+	token := generateToken(t, v5, user)
+
+	claims, err := v5.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.Version != 5 || v5.TokenVersion() != 5 {
+		t.Errorf("token version = %d, service version = %d, want 5", claims.Version, v5.TokenVersion())
+	}
+
+	// Raising the configured version on another instance rejects older tokens
+	if _, err := v6.ValidateToken(token); !auth.IsTokenOutdated(err) {
+		t.Errorf("ValidateToken error = %v, want token outdated", err)
+	}
+}
+
+func TestBumpUserTokenVersionUnsupported(t *testing.T) {
+	svc := newTestService(newTestUserStore(), time.Hour)
+
+	if err := svc.BumpUserTokenVersion(context.Background(), "u1"); !errx.IsCode(err, auth.ErrInvalidationDisabled) {
+		t.Errorf("err = %v, want ErrInvalidationDisabled", err)
+	}
+}
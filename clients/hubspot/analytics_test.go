@@ -0,0 +1,152 @@
+package hubspot_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+const analyticsEndpoint = "/analytics/v2/reports/sources/total"
+
+func TestRunAnalytics(t *testing.T) {
+	query := &hubspot.AnalyticsQuery{
+		StartDate:  "2024-01-01",
+		EndDate:    "2024-01-31",
+		Frequency:  hubspot.AnalyticsFrequencyWeekly,
+		Breakdowns: []string{"source"},
+		Filters:    []hubspot.Filter{{PropertyName: "source", Operator: "EQ", Value: "ORGANIC_SEARCH"}},
+	}
+
+	tests := []struct {
+		name        string
+		pages       []map[string]any
+		wantResults []map[string]any
+		wantTotal   int
+	}{
+		{
+			name: "single page",
+			pages: []map[string]any{
+				{"total": 2, "results": []map[string]any{
+					{"breakdown": "ORGANIC_SEARCH", "visits": 120},
+					{"breakdown": "DIRECT_TRAFFIC", "visits": 80},
+				}},
+			},
+			wantResults: []map[string]any{
+				{"breakdown": "ORGANIC_SEARCH", "visits": float64(120)},
+				{"breakdown": "DIRECT_TRAFFIC", "visits": float64(80)},
+			},
+			wantTotal: 2,
+		},
+		{
+			name: "paged results are collected",
+			pages: []map[string]any{
+				{"total": 3, "results": []map[string]any{{"breakdown": "ORGANIC_SEARCH"}}, "paging": map[string]any{"next": map[string]any{"after": "p2"}}},
+				{"total": 3, "results": []map[string]any{{"breakdown": "DIRECT_TRAFFIC"}}, "paging": map[string]any{"next": map[string]any{"after": "p3"}}},
+				{"total": 3, "results": []map[string]any{{"breakdown": "REFERRALS"}}},
+			},
+			wantResults: []map[string]any{
+				{"breakdown": "ORGANIC_SEARCH"},
+				{"breakdown": "DIRECT_TRAFFIC"},
+				{"breakdown": "REFERRALS"},
+			},
+			wantTotal: 3,
+		},
+		{
+			name:        "no results",
+			pages:       []map[string]any{{"total": 0, "results": []map[string]any{}}},
+			wantResults: []map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := 0
+			client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+				writeJSON(w, http.StatusOK, tt.pages[page])
+				page++
+			})
+
+			report, err := client.RunAnalytics(context.Background(), analyticsEndpoint, query)
+			if err != nil {
+				t.Fatalf("RunAnalytics: %v", err)
+			}
+			if !reflect.DeepEqual(report.Results, tt.wantResults) {
+				t.Errorf("results = %v, want %v", report.Results, tt.wantResults)
+			}
+			if report.Total != tt.wantTotal || report.Paging != nil {
+				t.Errorf("total = %d, paging = %v, want %d and no paging", report.Total, report.Paging, tt.wantTotal)
+			}
+
+			requests := api.all()
+			if len(requests) != len(tt.pages) {
+				t.Fatalf("requests = %d, want one per page (%d)", len(requests), len(tt.pages))
+			}
+			for i, req := range requests {
+				want := map[string]any{
+					"startDate":  "2024-01-01",
+					"endDate":    "2024-01-31",
+					"frequency":  "weekly",
+					"breakdowns": []any{"source"},
+					"filters": []any{
+						map[string]any{"propertyName": "source", "operator": "EQ", "value": "ORGANIC_SEARCH"},
+					},
+				}
+				if i > 0 {
+					want["after"] = tt.pages[i-1]["paging"].(map[string]any)["next"].(map[string]any)["after"]
+				}
+				if req.Method != http.MethodPost || req.Path != analyticsEndpoint {
+					t.Errorf("request %d = %s %s, want POST %s", i, req.Method, req.Path, analyticsEndpoint)
+				}
+				if !reflect.DeepEqual(req.Body, want) {
+					t.Errorf("request %d body = %v, want %v", i, req.Body, want)
+				}
+			}
+		})
+	}
+
+	if query.After != "" {
+		t.Errorf("query.After = %q, want the caller's query left unchanged", query.After)
+	}
+}
+
+func TestRunAnalyticsInvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *hubspot.AnalyticsQuery
+	}{
+		{name: "nil query"},
+		{name: "missing end date", query: &hubspot.AnalyticsQuery{StartDate: "2024-01-01"}},
+		{name: "missing start date", query: &hubspot.AnalyticsQuery{EndDate: "2024-01-31"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+				writeJSON(w, http.StatusOK, map[string]any{"results": []any{}})
+			})
+
+			_, err := client.RunAnalytics(context.Background(), analyticsEndpoint, tt.query)
+			if !errx.IsCode(err, hubspot.ErrHubSpotInvalidData) {
+				t.Errorf("err = %v, want ErrHubSpotInvalidData", err)
+			}
+			if n := len(api.all()); n != 0 {
+				t.Errorf("%d requests reached the API, want none", n)
+			}
+		})
+	}
+}
+
+func TestRunAnalyticsError(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "message": "invalid frequency"})
+	})
+
+	query := &hubspot.AnalyticsQuery{StartDate: "2024-01-01", EndDate: "2024-01-31", Frequency: "hourly"}
+	if _, err := client.RunAnalytics(context.Background(), analyticsEndpoint, query); err == nil {
+		t.Fatal("RunAnalytics succeeded, want the API error")
+	}
+}
@@ -0,0 +1,133 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Association type categories returned by the associations schema endpoint
+const (
+	AssociationCategoryHubSpotDefined    = "HUBSPOT_DEFINED"
+	AssociationCategoryUserDefined       = "USER_DEFINED"
+	AssociationCategoryIntegratorDefined = "INTEGRATOR_DEFINED"
+)
+
+// AssociationType is one association type between two object types. Label is
+// empty for the default, unlabeled type.
+type AssociationType struct {
+	Category string `json:"category"`
+	TypeID   int    `json:"typeId"`
+	Label    string `json:"label"`
+}
+
+// AssociationTypeListResponse represents the association types between two object types
+type AssociationTypeListResponse struct {
+	Results []AssociationType `json:"results"`
+}
+
+// associationTypeCache keeps association types per object pair. They rarely
+// change, so they are fetched once per client.
+type associationTypeCache struct {
+	mutex sync.Mutex
+	types map[string][]AssociationType // "fromType/toType" -> types
+}
+
+// GetAssociationTypes fetches the association types defined from fromType to
+// toType in the portal, e.g. ("contacts", "companies")
+func (c *Client) GetAssociationTypes(ctx context.Context, fromType, toType string) ([]AssociationType, error) {
+	var response AssociationTypeListResponse
+	endpoint := fmt.Sprintf("/crm/v4/associations/%s/%s/labels", fromType, toType)
+	if err := c.Get(ctx, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// ResolveAssociationType returns the type ID of the association from fromType
+// to toType with the given label, matched case-insensitively. An empty label
+// resolves the default HubSpot-defined type. Types are cached per object pair;
+// call ClearAssociationTypeCache after editing labels in HubSpot.
+func (c *Client) ResolveAssociationType(ctx context.Context, fromType, toType, label string) (int, error) {
+	types, err := c.cachedAssociationTypes(ctx, fromType, toType)
+	if err != nil {
+		return 0, err
+	}
+
+	if assocType, ok := findAssociationType(types, label); ok {
+		return assocType.TypeID, nil
+	}
+
+	labels := make([]string, 0, len(types))
+	for _, t := range types {
+		if t.Label != "" {
+			labels = append(labels, t.Label)
+		}
+	}
+	return 0, NewResourceNotFoundError("association type", fmt.Sprintf("%s_to_%s", fromType, toType)).
+		WithDetail("label", label).
+		WithDetail("availableLabels", labels)
+}
+
+// ClearAssociationTypeCache forgets all cached association types
+func (c *Client) ClearAssociationTypeCache() {
+	c.associationTypes.mutex.Lock()
+	defer c.associationTypes.mutex.Unlock()
+	c.associationTypes.types = nil
+}
+
+func (c *Client) cachedAssociationTypes(ctx context.Context, fromType, toType string) ([]AssociationType, error) {
+	key := fromType + "/" + toType
+
+	c.associationTypes.mutex.Lock()
+	types, ok := c.associationTypes.types[key]
+	c.associationTypes.mutex.Unlock()
+	if ok {
+		return types, nil
+	}
+
+	types, err := c.GetAssociationTypes(ctx, fromType, toType)
+	if err != nil {
+		return nil, err
+	}
+
+	c.associationTypes.mutex.Lock()
+	if c.associationTypes.types == nil {
+		c.associationTypes.types = make(map[string][]AssociationType)
+	}
+	c.associationTypes.types[key] = types
+	c.associationTypes.mutex.Unlock()
+
+	return types, nil
+}
+
+// findAssociationType picks the type matching label. For an empty label the
+// unlabeled HubSpot-defined type wins over other unlabeled ones.
+func findAssociationType(types []AssociationType, label string) (AssociationType, bool) {
+	if label != "" {
+		for _, t := range types {
+			if strings.EqualFold(t.Label, label) {
+				return t, true
+			}
+		}
+		return AssociationType{}, false
+	}
+
+	var fallback *AssociationType
+	for i, t := range types {
+		if t.Label != "" {
+			continue
+		}
+		if t.Category == AssociationCategoryHubSpotDefined {
+			return t, true
+		}
+		if fallback == nil {
+			fallback = &types[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return AssociationType{}, false
+}
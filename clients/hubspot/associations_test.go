@@ -0,0 +1,112 @@
+package hubspot_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+// contactToCompanyLabels is the associations schema of contacts to companies
+var contactToCompanyLabels = map[string]any{"results": []map[string]any{
+	{"category": "HUBSPOT_DEFINED", "typeId": 1, "label": "Primary"},
+	{"category": "USER_DEFINED", "typeId": 42, "label": "Billing contact"},
+	{"category": "HUBSPOT_DEFINED", "typeId": 279, "label": nil},
+}}
+
+func TestResolveAssociationType(t *testing.T) {
+	client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+		if req.Path != "/crm/v4/associations/contacts/companies/labels" {
+			writeJSON(w, http.StatusNotFound, map[string]any{"status": "error", "message": "unknown object type"})
+			return
+		}
+		writeJSON(w, http.StatusOK, contactToCompanyLabels)
+	})
+
+	tests := []struct {
+		name    string
+		label   string
+		want    int
+		wantErr errx.Code
+	}{
+		{name: "default contact_to_company type", label: "", want: 279},
+		{name: "HubSpot-defined label", label: "Primary", want: 1},
+		{name: "label matched case-insensitively", label: "billing CONTACT", want: 42},
+		{name: "unknown label", label: "Reseller", wantErr: hubspot.ErrResourceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.ResolveAssociationType(context.Background(), "contacts", "companies", tt.label)
+			if tt.wantErr != "" {
+				if !errx.IsCode(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveAssociationType: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("type ID = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if n := len(api.all()); n != 1 {
+		t.Errorf("schema fetched %d times, want once for all lookups", n)
+	}
+
+	client.ClearAssociationTypeCache()
+	if _, err := client.ResolveAssociationType(context.Background(), "contacts", "companies", ""); err != nil {
+		t.Fatalf("ResolveAssociationType: %v", err)
+	}
+	if n := len(api.all()); n != 2 {
+		t.Errorf("schema fetched %d times, want a refetch after clearing the cache", n)
+	}
+}
+
+func TestResolveAssociationTypeErrorNotCached(t *testing.T) {
+	client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"status": "error", "message": "unknown object type"})
+	})
+
+	for range 2 {
+		if _, err := client.ResolveAssociationType(context.Background(), "contacts", "widgets", ""); err == nil {
+			t.Fatal("ResolveAssociationType succeeded for an unknown object type")
+		}
+	}
+	if n := len(api.all()); n != 2 {
+		t.Errorf("schema fetched %d times, want failures to be retried", n)
+	}
+}
+
+func TestGetAssociationTypes(t *testing.T) {
+	client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+		writeJSON(w, http.StatusOK, contactToCompanyLabels)
+	})
+
+	types, err := client.GetAssociationTypes(context.Background(), "contacts", "companies")
+	if err != nil {
+		t.Fatalf("GetAssociationTypes: %v", err)
+	}
+
+	want := []hubspot.AssociationType{
+		{Category: hubspot.AssociationCategoryHubSpotDefined, TypeID: 1, Label: "Primary"},
+		{Category: hubspot.AssociationCategoryUserDefined, TypeID: 42, Label: "Billing contact"},
+		{Category: hubspot.AssociationCategoryHubSpotDefined, TypeID: 279},
+	}
+	if len(types) != len(want) {
+		t.Fatalf("types = %+v, want %+v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("types[%d] = %+v, want %+v", i, types[i], want[i])
+		}
+	}
+	if req := api.all()[0]; req.Method != http.MethodGet || req.Path != "/crm/v4/associations/contacts/companies/labels" {
+		t.Errorf("request = %s %s", req.Method, req.Path)
+	}
+}
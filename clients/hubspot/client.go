@@ -22,6 +22,8 @@ type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+
+	associationTypes associationTypeCache
 }
 
 // Config holds configuration for the HubSpot client
@@ -267,24 +269,12 @@ func (c *Client) SearchWorkflows(ctx context.Context, query string) ([]*Workflow
 
 // GetAllContacts fetches all contacts
 func (c *Client) GetAllContacts(ctx context.Context, properties []string, limit int, after string) (*ContactListResponse, error) {
-	params := make(map[string]string)
-	if len(properties) > 0 {
-		params["properties"] = strings.Join(properties, ",")
-	}
-	if limit > 0 {
-		params["limit"] = strconv.Itoa(limit)
-	}
-	if after != "" {
-		params["after"] = after
-	}
-
-	var response ContactListResponse
-	err := c.Get(ctx, "/crm/v3/objects/contacts", params, &response)
+	page, err := ListPage[Contact](ctx, c, "contacts", properties, limit, after)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response, nil
+	return &ContactListResponse{Results: page.Results, Paging: page.Paging}, nil
 }
 
 // GetContactByID fetches a contact by ID
@@ -333,6 +323,53 @@ func (c *Client) UpdateContact(ctx context.Context, contactID string, contact *C
 	return &result, nil
 }
 
+// PatchContactProperties sets only the given properties on a contact, leaving
+// all others untouched, and returns the contact with the merged values.
+// Set a property to "" to clear it.
+func (c *Client) PatchContactProperties(ctx context.Context, contactID string, props map[string]any) (*Contact, error) {
+	if len(props) == 0 {
+		return nil, Registry.New(ErrHubSpotInvalidData).
+			WithDetail("reason", "no properties to update").
+			WithDetail("contact_id", contactID)
+	}
+
+	return c.UpdateContact(ctx, contactID, &ContactInput{Properties: Properties(props)})
+}
+
+// UpsertContactByEmail creates a contact, or updates the contact that already
+// has the same email. The returned flag reports whether a new contact was
+// created. Safe to retry: a conflicting create falls back to an update by email.
+func (c *Client) UpsertContactByEmail(ctx context.Context, contact *ContactInput) (*Contact, bool, error) {
+	email, _ := contact.Properties["email"].(string)
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, false, Registry.New(ErrHubSpotInvalidData).
+			WithDetail("reason", "contact email is required for upsert")
+	}
+
+	created, err := c.CreateContact(ctx, contact)
+	if err == nil {
+		return created, true, nil
+	}
+	if !errx.IsCode(err, ErrHubSpotConflict) {
+		return nil, false, err
+	}
+
+	// Update by the unique email property instead of searching, since the
+	// search index can lag behind a contact that was just created
+	var result Contact
+	endpoint := fmt.Sprintf("/crm/v3/objects/contacts/%s", url.PathEscape(email))
+	params := map[string]string{"idProperty": "email"}
+	if err := c.PatchWithParams(ctx, endpoint, params, contact, &result); err != nil {
+		if errx.IsCode(err, ErrHubSpotNotFound) {
+			return nil, false, NewResourceNotFoundError("contact", email)
+		}
+		return nil, false, err
+	}
+
+	return &result, false, nil
+}
+
 // DeleteContact deletes a contact
 func (c *Client) DeleteContact(ctx context.Context, contactID string) error {
 	endpoint := fmt.Sprintf("/crm/v3/objects/contacts/%s", contactID)
@@ -364,24 +401,12 @@ func (c *Client) SearchContacts(ctx context.Context, searchReq *SearchRequest) (
 
 // GetAllCompanies fetches all companies
 func (c *Client) GetAllCompanies(ctx context.Context, properties []string, limit int, after string) (*CompanyListResponse, error) {
-	params := make(map[string]string)
-	if len(properties) > 0 {
-		params["properties"] = strings.Join(properties, ",")
-	}
-	if limit > 0 {
-		params["limit"] = strconv.Itoa(limit)
-	}
-	if after != "" {
-		params["after"] = after
-	}
-
-	var response CompanyListResponse
-	err := c.Get(ctx, "/crm/v3/objects/companies", params, &response)
+	page, err := ListPage[Company](ctx, c, "companies", properties, limit, after)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response, nil
+	return &CompanyListResponse{Results: page.Results, Paging: page.Paging}, nil
 }
 
 // GetCompanyByID fetches a company by ID
@@ -461,24 +486,12 @@ func (c *Client) SearchCompanies(ctx context.Context, searchReq *SearchRequest)
 
 // GetAllDeals fetches all deals
 func (c *Client) GetAllDeals(ctx context.Context, properties []string, limit int, after string) (*DealListResponse, error) {
-	params := make(map[string]string)
-	if len(properties) > 0 {
-		params["properties"] = strings.Join(properties, ",")
-	}
-	if limit > 0 {
-		params["limit"] = strconv.Itoa(limit)
-	}
-	if after != "" {
-		params["after"] = after
-	}
-
-	var response DealListResponse
-	err := c.Get(ctx, "/crm/v3/objects/deals", params, &response)
+	page, err := ListPage[Deal](ctx, c, "deals", properties, limit, after)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response, nil
+	return &DealListResponse{Results: page.Results, Paging: page.Paging}, nil
 }
 
 // GetDealByID fetches a deal by ID
@@ -558,24 +571,12 @@ func (c *Client) SearchDeals(ctx context.Context, searchReq *SearchRequest) (*De
 
 // GetAllTickets fetches all tickets
 func (c *Client) GetAllTickets(ctx context.Context, properties []string, limit int, after string) (*TicketListResponse, error) {
-	params := make(map[string]string)
-	if len(properties) > 0 {
-		params["properties"] = strings.Join(properties, ",")
-	}
-	if limit > 0 {
-		params["limit"] = strconv.Itoa(limit)
-	}
-	if after != "" {
-		params["after"] = after
-	}
-
-	var response TicketListResponse
-	err := c.Get(ctx, "/crm/v3/objects/tickets", params, &response)
+	page, err := ListPage[Ticket](ctx, c, "tickets", properties, limit, after)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response, nil
+	return &TicketListResponse{Results: page.Results, Paging: page.Paging}, nil
 }
 
 // GetTicketByID fetches a ticket by ID
@@ -876,6 +877,51 @@ func (c *Client) SearchRequest(ctx context.Context, endpoint string, searchReq *
 	return c.Post(ctx, endpoint, searchReq, result)
 }
 
+// ============================================================================
+// ANALYTICS OPERATIONS
+// ============================================================================
+
+// RunAnalytics POSTs an analytics query to endpoint and returns the results of
+// every page, following paging.next.after until the last page. StartDate and
+// EndDate are required; Frequency, Breakdowns and Filters are sent as given.
+// The returned response has Total from the first page and no Paging.
+//
+//	report, err := client.RunAnalytics(ctx, "/analytics/v2/reports/sources/total", &hubspot.AnalyticsQuery{
+//		StartDate:  "2024-01-01",
+//		EndDate:    "2024-01-31",
+//		Frequency:  hubspot.AnalyticsFrequencyWeekly,
+//		Breakdowns: []string{"source"},
+//	})
+func (c *Client) RunAnalytics(ctx context.Context, endpoint string, query *AnalyticsQuery) (*AnalyticsResponse, error) {
+	if query == nil || query.StartDate == "" || query.EndDate == "" {
+		return nil, Registry.New(ErrHubSpotInvalidData).
+			WithDetail("reason", "analytics query requires startDate and endDate").
+			WithDetail("endpoint", endpoint)
+	}
+
+	logx.Debug("Running analytics query on %s from %s to %s", endpoint, query.StartDate, query.EndDate)
+
+	pageQuery := *query
+	result := &AnalyticsResponse{Results: []map[string]any{}}
+
+	for first := true; ; first = false {
+		var page AnalyticsResponse
+		if err := c.Post(ctx, endpoint, &pageQuery, &page); err != nil {
+			return nil, err
+		}
+
+		if first {
+			result.Total = page.Total
+		}
+		result.Results = append(result.Results, page.Results...)
+
+		if page.Paging == nil || page.Paging.Next == nil || page.Paging.Next.After == "" {
+			return result, nil
+		}
+		pageQuery.After = page.Paging.Next.After
+	}
+}
+
 // ============================================================================
 // UTILITY METHODS
 // ============================================================================
@@ -0,0 +1,209 @@
+package hubspot_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+func TestUpsertContactByEmail(t *testing.T) {
+	tests := []struct {
+		name         string
+		createStatus int
+		patchStatus  int
+		wantCreated  bool
+		wantCalls    []string
+		wantErr      errx.Code
+	}{
+		{
+			name:         "creates a new contact",
+			createStatus: http.StatusCreated,
+			wantCreated:  true,
+			wantCalls:    []string{"POST /crm/v3/objects/contacts"},
+		},
+		{
+			name:         "updates by email on conflict",
+			createStatus: http.StatusConflict,
+			patchStatus:  http.StatusOK,
+			wantCalls:    []string{"POST /crm/v3/objects/contacts", "PATCH /crm/v3/objects/contacts/ada@example.com"},
+		},
+		{
+			name:         "contact vanished before the update",
+			createStatus: http.StatusConflict,
+			patchStatus:  http.StatusNotFound,
+			wantCalls:    []string{"POST /crm/v3/objects/contacts", "PATCH /crm/v3/objects/contacts/ada@example.com"},
+			wantErr:      hubspot.ErrResourceNotFound,
+		},
+		{
+			name:         "other create errors are returned",
+			createStatus: http.StatusBadRequest,
+			wantCalls:    []string{"POST /crm/v3/objects/contacts"},
+			wantErr:      hubspot.ErrHubSpotBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+				status := tt.createStatus
+				if req.Method == http.MethodPatch {
+					status = tt.patchStatus
+				}
+				if status >= 400 {
+					writeJSON(w, status, map[string]any{"status": "error", "message": "rejected"})
+					return
+				}
+				writeJSON(w, status, map[string]any{"id": "101", "properties": req.Body["properties"]})
+			})
+
+			contact, created, err := client.UpsertContactByEmail(context.Background(), &hubspot.ContactInput{
+				Properties: hubspot.Properties{"email": "ada@example.com", "firstname": "Ada"},
+			})
+
+			if tt.wantErr != "" {
+				if !errx.IsCode(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("UpsertContactByEmail: %v", err)
+				}
+				if contact.ID != "101" || contact.Properties["firstname"] != "Ada" {
+					t.Errorf("contact = %+v", contact)
+				}
+				if created != tt.wantCreated {
+					t.Errorf("created = %v, want %v", created, tt.wantCreated)
+				}
+			}
+
+			requests := api.all()
+			if len(requests) != len(tt.wantCalls) {
+				t.Fatalf("made %d requests, want %d", len(requests), len(tt.wantCalls))
+			}
+			for i, req := range requests {
+				if got := req.Method + " " + req.Path; got != tt.wantCalls[i] {
+					t.Errorf("request %d = %s, want %s", i, got, tt.wantCalls[i])
+				}
+				if req.Method == http.MethodPatch && req.Query.Get("idProperty") != "email" {
+					t.Errorf("update idProperty = %q, want email", req.Query.Get("idProperty"))
+				}
+			}
+		})
+	}
+}
+
+func TestUpsertContactByEmailRequiresEmail(t *testing.T) {
+	client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+		writeJSON(w, http.StatusCreated, map[string]any{"id": "101"})
+	})
+
+	_, _, err := client.UpsertContactByEmail(context.Background(), &hubspot.ContactInput{
+		Properties: hubspot.Properties{"firstname": "Ada"},
+	})
+	if !errx.IsCode(err, hubspot.ErrHubSpotInvalidData) {
+		t.Fatalf("err = %v, want ErrHubSpotInvalidData", err)
+	}
+	if len(api.all()) != 0 {
+		t.Error("a contact without email reached the API")
+	}
+}
+
+func TestPatchContactProperties(t *testing.T) {
+	stored := map[string]any{"email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace", "phone": "555-0100"}
+
+	tests := []struct {
+		name      string
+		props     map[string]any
+		status    int
+		wantSent  map[string]any
+		wantProps hubspot.Properties
+		wantErr   errx.Code
+	}{
+		{
+			name:      "sends only the given properties",
+			props:     map[string]any{"firstname": "Augusta", "jobtitle": "Analyst"},
+			status:    http.StatusOK,
+			wantSent:  map[string]any{"firstname": "Augusta", "jobtitle": "Analyst"},
+			wantProps: hubspot.Properties{"email": "ada@example.com", "firstname": "Augusta", "lastname": "Lovelace", "phone": "555-0100", "jobtitle": "Analyst"},
+		},
+		{
+			name:      "empty value clears a property",
+			props:     map[string]any{"phone": ""},
+			status:    http.StatusOK,
+			wantSent:  map[string]any{"phone": ""},
+			wantProps: hubspot.Properties{"email": "ada@example.com", "firstname": "Ada", "lastname": "Lovelace", "phone": ""},
+		},
+		{
+			name:     "unknown contact",
+			props:    map[string]any{"firstname": "Augusta"},
+			status:   http.StatusNotFound,
+			wantSent: map[string]any{"firstname": "Augusta"},
+			wantErr:  hubspot.ErrResourceNotFound,
+		},
+		{
+			name:    "no properties",
+			props:   map[string]any{},
+			wantErr: hubspot.ErrHubSpotInvalidData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, api := newTestClient(t, func(w http.ResponseWriter, req apiRequest) {
+				if tt.status >= 400 {
+					writeJSON(w, tt.status, map[string]any{"status": "error", "message": "not found"})
+					return
+				}
+				merged := map[string]any{}
+				for key, value := range stored {
+					merged[key] = value
+				}
+				for key, value := range req.Body["properties"].(map[string]any) {
+					merged[key] = value
+				}
+				writeJSON(w, tt.status, map[string]any{"id": "101", "properties": merged, "updatedAt": 1700000000000})
+			})
+
+			contact, err := client.PatchContactProperties(context.Background(), "101", tt.props)
+
+			requests := api.all()
+			if tt.wantSent == nil {
+				if len(requests) != 0 {
+					t.Errorf("made %d requests, want none", len(requests))
+				}
+			} else {
+				if len(requests) != 1 {
+					t.Fatalf("made %d requests, want 1", len(requests))
+				}
+				req := requests[0]
+				if req.Method != http.MethodPatch || req.Path != "/crm/v3/objects/contacts/101" {
+					t.Errorf("request = %s %s, want PATCH /crm/v3/objects/contacts/101", req.Method, req.Path)
+				}
+				want := map[string]any{"properties": tt.wantSent}
+				if !reflect.DeepEqual(req.Body, want) {
+					t.Errorf("body = %v, want %v", req.Body, want)
+				}
+			}
+
+			if tt.wantErr != "" {
+				if !errx.IsCode(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PatchContactProperties: %v", err)
+			}
+			if contact.ID != "101" || contact.UpdatedAt == nil {
+				t.Errorf("contact = %+v, want the decoded response", contact)
+			}
+			if !reflect.DeepEqual(contact.Properties, tt.wantProps) {
+				t.Errorf("properties = %v, want %v", contact.Properties, tt.wantProps)
+			}
+		})
+	}
+}
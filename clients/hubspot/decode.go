@@ -0,0 +1,177 @@
+package hubspot
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertyTag is the struct tag naming the HubSpot property a field is decoded from
+const PropertyTag = "hubspot"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DecodeProperties maps HubSpot properties into the struct pointed to by out.
+// Fields are matched by their `hubspot:"name"` tag; untagged fields and
+// fields tagged "-" are left alone.
+//
+// HubSpot returns every property as a string, so values are converted to the
+// field type: numeric strings to ints, uints and floats, "true"/"false" to
+// bools, and epoch milliseconds, RFC 3339 timestamps or YYYY-MM-DD dates to
+// time.Time. Missing or empty properties leave the field at its zero value;
+// pointer fields stay nil.
+//
+//	type Lead struct {
+//		FirstName string     `hubspot:"firstname"`
+//		Employees int        `hubspot:"numberofemployees"`
+//		LastSeen  *time.Time `hubspot:"hs_last_sales_activity_timestamp"`
+//	}
+//
+//	var lead Lead
+//	err := hubspot.DecodeProperties(contact.Properties, &lead)
+func DecodeProperties(props Properties, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return Registry.New(ErrHubSpotInvalidData).
+			WithDetail("reason", "decode target must be a non-nil pointer to a struct").
+			WithDetail("type", fmt.Sprintf("%T", out))
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get(PropertyTag)
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		raw, ok := props[name]
+		if !ok || raw == nil {
+			continue
+		}
+		value := propertyString(raw)
+		if value == "" {
+			continue
+		}
+
+		if err := setPropertyField(rv.Field(i), value); err != nil {
+			return Registry.New(ErrHubSpotParsingError).
+				WithCause(err).
+				WithDetail("property", name).
+				WithDetail("field", field.Name).
+				WithDetail("value", value)
+		}
+	}
+	return nil
+}
+
+// DecodeAs decodes HubSpot properties into a new value of type T
+//
+//	lead, err := hubspot.DecodeAs[Lead](contact.Properties)
+func DecodeAs[T any](props Properties) (*T, error) {
+	out := new(T)
+	if err := DecodeProperties(props, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// propertyString normalizes a property value to the string HubSpot would send
+func propertyString(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// setPropertyField parses value into field according to its type
+func setPropertyField(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := setPropertyField(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	if field.Type() == timeType {
+		t, err := parsePropertyTime(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			// HubSpot number properties may carry a decimal part ("12.0")
+			f, ferr := strconv.ParseFloat(value, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return err
+			}
+			n = int64(f)
+		}
+		if field.OverflowInt(n) {
+			return fmt.Errorf("value %s overflows %s", value, field.Type())
+		}
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	case reflect.Slice:
+		// Multi-select properties are semicolon separated
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		parts := strings.Split(value, ";")
+		field.Set(reflect.ValueOf(parts).Convert(field.Type()))
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// parsePropertyTime accepts epoch milliseconds and HubSpot's datetime and date formats
+func parsePropertyTime(value string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
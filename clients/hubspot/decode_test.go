@@ -0,0 +1,115 @@
+package hubspot_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+	"github.com/Abraxas-365/craftable/errx"
+)
+
+type lead struct {
+	ID        string     `hubspot:"hs_object_id"`
+	FirstName string     `hubspot:"firstname"`
+	Employees int        `hubspot:"numberofemployees"`
+	Revenue   float64    `hubspot:"annualrevenue"`
+	Opted     bool       `hubspot:"hs_email_optout"`
+	Created   time.Time  `hubspot:"createdate"`
+	LastSeen  *time.Time `hubspot:"hs_last_sales_activity_timestamp"`
+	Birthday  time.Time  `hubspot:"date_of_birth"`
+	Interests []string   `hubspot:"interests"`
+	Score     *int       `hubspot:"hubspotscore"`
+	Ignored   string     `hubspot:"-"`
+	Untagged  string
+}
+
+func TestDecodeProperties(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	lastSeen := time.UnixMilli(1700000000000).UTC()
+	score := 42
+
+	tests := []struct {
+		name    string
+		props   hubspot.Properties
+		want    lead
+		wantErr errx.Code
+	}{
+		{
+			name: "mixed field types",
+			props: hubspot.Properties{
+				"hs_object_id":                     "101",
+				"firstname":                        " Ada ",
+				"numberofemployees":                "250",
+				"annualrevenue":                    "1250000.50",
+				"hs_email_optout":                  "true",
+				"createdate":                       "2024-03-01T12:30:00Z",
+				"hs_last_sales_activity_timestamp": "1700000000000",
+				"date_of_birth":                    "1815-12-10",
+				"interests":                        "math;poetry",
+				"hubspotscore":                     "42",
+				"Untagged":                         "ignored",
+			},
+			want: lead{
+				ID:        "101",
+				FirstName: "Ada",
+				Employees: 250,
+				Revenue:   1250000.50,
+				Opted:     true,
+				Created:   created,
+				LastSeen:  &lastSeen,
+				Birthday:  time.Date(1815, 12, 10, 0, 0, 0, 0, time.UTC),
+				Interests: []string{"math", "poetry"},
+				Score:     &score,
+			},
+		},
+		{
+			name:  "decimal integers and JSON numbers",
+			props: hubspot.Properties{"numberofemployees": "12.0", "annualrevenue": float64(99)},
+			want:  lead{Employees: 12, Revenue: 99},
+		},
+		{
+			name:  "missing and empty properties stay zero",
+			props: hubspot.Properties{"firstname": "", "hubspotscore": nil},
+			want:  lead{},
+		},
+		{
+			name:    "invalid number",
+			props:   hubspot.Properties{"numberofemployees": "many"},
+			wantErr: hubspot.ErrHubSpotParsingError,
+		},
+		{
+			name:    "fractional integer",
+			props:   hubspot.Properties{"numberofemployees": "12.5"},
+			wantErr: hubspot.ErrHubSpotParsingError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hubspot.DecodeAs[lead](tt.props)
+			if tt.wantErr != "" {
+				if !errx.IsCode(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeAs: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePropertiesTarget(t *testing.T) {
+	props := hubspot.Properties{"firstname": "Ada"}
+
+	for name, out := range map[string]any{"non-pointer": lead{}, "nil pointer": (*lead)(nil), "pointer to map": &map[string]any{}} {
+		if err := hubspot.DecodeProperties(props, out); !errx.IsCode(err, hubspot.ErrHubSpotInvalidData) {
+			t.Errorf("%s: err = %v, want ErrHubSpotInvalidData", name, err)
+		}
+	}
+}
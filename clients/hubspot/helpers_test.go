@@ -0,0 +1,69 @@
+package hubspot_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+)
+
+// apiRequest is a request received by the fake HubSpot API
+type apiRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   map[string]any
+}
+
+// fakeAPI is a fake HubSpot API that records requests
+type fakeAPI struct {
+	mutex    sync.Mutex
+	requests []apiRequest
+}
+
+func (f *fakeAPI) record(r *http.Request) apiRequest {
+	data, _ := io.ReadAll(r.Body)
+	req := apiRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &req.Body)
+	}
+
+	f.mutex.Lock()
+	f.requests = append(f.requests, req)
+	f.mutex.Unlock()
+	return req
+}
+
+// all returns the recorded requests
+func (f *fakeAPI) all() []apiRequest {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]apiRequest(nil), f.requests...)
+}
+
+// newTestClient returns a client talking to a fake API served by handler,
+// which receives every request after it was recorded
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, req apiRequest)) (*hubspot.Client, *fakeAPI) {
+	t.Helper()
+
+	api := &fakeAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := api.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+
+	return hubspot.NewClient(hubspot.Config{Token: "test-token", BaseURL: srv.URL}), api
+}
+
+// writeJSON encodes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -530,7 +530,18 @@ type FileUploadOptions struct {
 // ANALYTICS TYPES
 // ============================================================================
 
-// AnalyticsQuery represents an analytics query
+// Analytics frequencies, the time buckets results are grouped in
+const (
+	AnalyticsFrequencyDaily     = "daily"
+	AnalyticsFrequencyWeekly    = "weekly"
+	AnalyticsFrequencyMonthly   = "monthly"
+	AnalyticsFrequencyQuarterly = "quarterly"
+	AnalyticsFrequencyTotal     = "total"
+)
+
+// AnalyticsQuery represents an analytics query. StartDate and EndDate bound
+// the date range (YYYY-MM-DD); Frequency is one of the AnalyticsFrequency
+// constants.
 type AnalyticsQuery struct {
 	StartDate  string   `json:"startDate"`
 	EndDate    string   `json:"endDate"`
@@ -0,0 +1,80 @@
+package hubspot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultListPageSize is used by ListAll when pageSize is not positive
+	DefaultListPageSize = 100
+	// MaxListPageSize is the largest page size accepted by the CRM v3 list endpoints
+	MaxListPageSize = 100
+)
+
+// ObjectListResponse represents a single page of CRM objects
+type ObjectListResponse[T any] struct {
+	Results []T     `json:"results"`
+	Paging  *Paging `json:"paging,omitempty"`
+}
+
+// ListPage fetches a single page of CRM objects of the given type
+func ListPage[T any](ctx context.Context, c *Client, objectType string, properties []string, limit int, after string) (*ObjectListResponse[T], error) {
+	params := make(map[string]string)
+	if len(properties) > 0 {
+		params["properties"] = strings.Join(properties, ",")
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if after != "" {
+		params["after"] = after
+	}
+
+	var response ObjectListResponse[T]
+	err := c.Get(ctx, "/crm/v3/objects/"+objectType, params, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ListAll fetches CRM objects of the given type, following paging until there are
+// no more pages or maxTotal objects have been collected. A maxTotal <= 0 disables the cap.
+//
+//	contacts, err := hubspot.ListAll[hubspot.Contact](ctx, client, "contacts", []string{"email"}, 100, 5000)
+func ListAll[T any](ctx context.Context, c *Client, objectType string, properties []string, pageSize, maxTotal int) ([]T, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	if pageSize > MaxListPageSize {
+		pageSize = MaxListPageSize
+	}
+
+	var results []T
+	after := ""
+
+	for {
+		limit := pageSize
+		if maxTotal > 0 && maxTotal-len(results) < limit {
+			limit = maxTotal - len(results)
+		}
+
+		page, err := ListPage[T](ctx, c, objectType, properties, limit, after)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, page.Results...)
+
+		if maxTotal > 0 && len(results) >= maxTotal {
+			return results[:maxTotal], nil
+		}
+		if page.Paging == nil || page.Paging.Next == nil || page.Paging.Next.After == "" {
+			return results, nil
+		}
+		after = page.Paging.Next.After
+	}
+}
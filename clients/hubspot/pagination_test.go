@@ -0,0 +1,88 @@
+package hubspot_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/clients/hubspot"
+)
+
+type listedObject struct {
+	ID string `json:"id"`
+}
+
+// pagedObjects serves total objects numbered from 0, honoring limit and after
+func pagedObjects(total int) func(w http.ResponseWriter, req apiRequest) {
+	return func(w http.ResponseWriter, req apiRequest) {
+		start, _ := strconv.Atoi(req.Query.Get("after"))
+		limit, _ := strconv.Atoi(req.Query.Get("limit"))
+		end := min(start+limit, total)
+
+		page := map[string]any{}
+		results := []listedObject{}
+		for i := start; i < end; i++ {
+			results = append(results, listedObject{ID: strconv.Itoa(i)})
+		}
+		page["results"] = results
+		if end < total {
+			page["paging"] = map[string]any{"next": map[string]any{"after": strconv.Itoa(end)}}
+		}
+		writeJSON(w, http.StatusOK, page)
+	}
+}
+
+func TestListAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		available  int
+		pageSize   int
+		maxTotal   int
+		wantCount  int
+		wantLimits []string
+	}{
+		{name: "follows every page", available: 250, pageSize: 100, wantCount: 250, wantLimits: []string{"100", "100", "100"}},
+		{name: "stops at maxTotal", available: 250, pageSize: 100, maxTotal: 150, wantCount: 150, wantLimits: []string{"100", "50"}},
+		{name: "maxTotal below one page", available: 250, pageSize: 50, maxTotal: 30, wantCount: 30, wantLimits: []string{"30"}},
+		{name: "respects pageSize", available: 70, pageSize: 25, wantCount: 70, wantLimits: []string{"25", "25", "25"}},
+		{name: "default pageSize", available: 10, pageSize: 0, wantCount: 10, wantLimits: []string{"100"}},
+		{name: "pageSize capped", available: 10, pageSize: 500, wantCount: 10, wantLimits: []string{"100"}},
+		{name: "maxTotal above available", available: 40, pageSize: 30, maxTotal: 100, wantCount: 40, wantLimits: []string{"30", "30"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, api := newTestClient(t, pagedObjects(tt.available))
+
+			objects, err := hubspot.ListAll[listedObject](context.Background(), client, "contacts", []string{"email"}, tt.pageSize, tt.maxTotal)
+			if err != nil {
+				t.Fatalf("ListAll: %v", err)
+			}
+			if len(objects) != tt.wantCount {
+				t.Errorf("got %d objects, want %d", len(objects), tt.wantCount)
+			}
+			for i, object := range objects {
+				if object.ID != strconv.Itoa(i) {
+					t.Fatalf("object %d has ID %s, want pages in order", i, object.ID)
+				}
+			}
+
+			requests := api.all()
+			if len(requests) != len(tt.wantLimits) {
+				t.Fatalf("made %d requests, want %d", len(requests), len(tt.wantLimits))
+			}
+			for i, req := range requests {
+				if req.Path != "/crm/v3/objects/contacts" {
+					t.Errorf("request %d path = %s", i, req.Path)
+				}
+				if got := req.Query.Get("limit"); got != tt.wantLimits[i] {
+					t.Errorf("request %d limit = %s, want %s", i, got, tt.wantLimits[i])
+				}
+				if got := req.Query.Get("properties"); got != "email" {
+					t.Errorf("request %d properties = %q, want email", i, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package errx
+
+import (
+	"context"
+	"sync"
+)
+
+// contextKeys maps detail names to the context keys their values are read from
+var (
+	contextKeys      = make(map[string]any)
+	contextKeysMutex sync.RWMutex
+)
+
+// contextDetailsKey stores details attached with ContextWithDetail
+type contextDetailsKey struct{}
+
+// RegisterContextKey makes FromContext harvest the value stored under key and
+// report it as the named detail. Register keys once at startup, typically
+// alongside the middleware that stores them:
+//
+//	errx.RegisterContextKey("request_id", middleware.RequestIDKey)
+func RegisterContextKey(detail string, key any) {
+	contextKeysMutex.Lock()
+	defer contextKeysMutex.Unlock()
+	contextKeys[detail] = key
+}
+
+// UnregisterContextKey stops harvesting the named detail
+func UnregisterContextKey(detail string) {
+	contextKeysMutex.Lock()
+	defer contextKeysMutex.Unlock()
+	delete(contextKeys, detail)
+}
+
+// ContextWithDetail returns a copy of ctx carrying a detail that FromContext
+// harvests without registering a key. Use it where the request id isn't
+// already in the context under a key of its own.
+func ContextWithDetail(ctx context.Context, key string, value any) context.Context {
+	parent, _ := ctx.Value(contextDetailsKey{}).(map[string]any)
+	details := make(map[string]any, len(parent)+1)
+	for k, v := range parent {
+		details[k] = v
+	}
+	details[key] = value
+	return context.WithValue(ctx, contextDetailsKey{}, details)
+}
+
+// FromContext returns the details harvested from ctx: the values of the
+// registered context keys plus those attached with ContextWithDetail. It
+// returns nil when ctx carries none of them.
+func FromContext(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+
+	var details map[string]any
+	set := func(key string, value any) {
+		if details == nil {
+			details = make(map[string]any)
+		}
+		details[key] = value
+	}
+
+	contextKeysMutex.RLock()
+	for detail, key := range contextKeys {
+		if value := ctx.Value(key); value != nil && value != "" {
+			set(detail, value)
+		}
+	}
+	contextKeysMutex.RUnlock()
+
+	if attached, ok := ctx.Value(contextDetailsKey{}).(map[string]any); ok {
+		for key, value := range attached {
+			set(key, value)
+		}
+	}
+	return details
+}
+
+// WithContext adds the details harvested from ctx. Details already set on the
+// error are kept.
+func (e *Error) WithContext(ctx context.Context) *Error {
+	for key, value := range FromContext(ctx) {
+		if _, exists := e.Details[key]; exists {
+			continue
+		}
+		e.WithDetail(key, value)
+	}
+	return e
+}
+
+// NewCtx creates a new Error like New, with the details harvested from ctx
+func NewCtx(ctx context.Context, message string, errType Type) *Error {
+	return New(message, errType).WithContext(ctx)
+}
+
+// NewCtx creates a new instance of a registered error with the details
+// harvested from ctx
+func (r *Registry) NewCtx(ctx context.Context, code Code) *Error {
+	return r.New(code).WithContext(ctx)
+}
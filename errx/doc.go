@@ -47,6 +47,42 @@ Provide additional context with details:
 			"min_length": 8,
 		})
 
+# Request Context
+
+Register the context keys your middleware stores request-scoped values under,
+then create errors from the context instead of repeating WithDetail calls:
+
+	// At startup
+	errx.RegisterContextKey("request_id", requestIDKey{})
+	errx.RegisterContextKey("trace_id", traceIDKey{})
+
+	// In a handler
+	err := userErrors.NewCtx(ctx, ErrUserNotFound).WithDetail("user_id", "123")
+	// details: request_id, trace_id and user_id
+
+	err = errx.NewCtx(ctx, "quota exceeded", errx.TypeRateLimit)
+	err = errx.Wrap(dbErr, "Failed to load user", errx.TypeInternal).WithContext(ctx)
+
+Values without a key of their own can be attached directly with
+ContextWithDetail. Keys missing from the context are skipped, and explicit
+details always win over harvested ones.
+
+# Grouping Errors
+
+Fingerprint returns a stable key for error-tracking systems. It covers the code,
+type and registered message, never detail values, so every "user not found" groups
+together regardless of which user was missing:
+
+	err := userErrors.NewWithMessage(ErrUserNotFound, "User with ID 123 not found").
+		WithDetail("user_id", "123")
+	sentryEvent.Fingerprint = []string{err.Fingerprint()}
+
+	// Override when a different grouping is wanted
+	err = err.WithFingerprint("user-lookup")
+
+	// Works on any error; non-errx errors group by type and message
+	key := errx.Fingerprint(someErr)
+
 # Error Wrapping
 
 Wrap standard errors to add context while preserving the original cause:
@@ -1,6 +1,8 @@
 package errx
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -38,6 +40,9 @@ type Error struct {
 	Details    map[string]any `json:"details,omitempty"`
 	HTTPStatus int            `json:"-"` // Not exposed in JSON
 	Cause      error          `json:"-"` // Underlying cause (not serialized)
+
+	template    string // Registered message, kept when the message is customized
+	fingerprint string // Explicit grouping key set with WithFingerprint
 }
 
 // Error implements the error interface
@@ -80,6 +85,52 @@ func (e *Error) WithCause(cause error) *Error {
 	return e
 }
 
+// WithFingerprint overrides the grouping key returned by Fingerprint
+func (e *Error) WithFingerprint(fingerprint string) *Error {
+	e.fingerprint = fingerprint
+	return e
+}
+
+// Fingerprint returns a stable key that groups occurrences of the same logical
+// error. It is derived from the code, type and message template, so detail
+// values and messages customized with NewWithMessage don't fragment groups.
+func (e *Error) Fingerprint() string {
+	if e.fingerprint != "" {
+		return e.fingerprint
+	}
+
+	message := e.template
+	if message == "" {
+		message = e.Message
+	}
+
+	return fingerprintOf(string(e.Code), string(e.Type), message)
+}
+
+// Fingerprint returns the grouping key of any error. For errors that are not
+// an Error, it is derived from the concrete type and message.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fingerprint()
+	}
+
+	return fingerprintOf(fmt.Sprintf("%T", err), err.Error())
+}
+
+func fingerprintOf(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
 // ToHTTP writes the error to an HTTP response writer (for standard net/http)
 func (e *Error) ToHTTP(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
@@ -158,6 +209,7 @@ func (r *Registry) New(code Code) *Error {
 			Type:       err.Type,
 			Message:    err.Message,
 			HTTPStatus: err.HTTPStatus,
+			template:   err.Message,
 		}
 	}
 	// Return a generic internal error if the code is not found
@@ -239,12 +291,13 @@ func Wrap(err error, message string, errType Type) *Error {
 	var xerr *Error
 	if errors.As(err, &xerr) {
 		return &Error{
-			Code:       xerr.Code,
-			Type:       errType,
-			Message:    message,
-			Details:    xerr.Details,
-			HTTPStatus: xerr.HTTPStatus,
-			Cause:      err,
+			Code:        xerr.Code,
+			Type:        errType,
+			Message:     message,
+			Details:     xerr.Details,
+			HTTPStatus:  xerr.HTTPStatus,
+			Cause:       err,
+			fingerprint: xerr.fingerprint,
 		}
 	}
 
@@ -0,0 +1,196 @@
+package eventx
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// EventTyper lets a payload type name its event type for SubscribeHandler
+type EventTyper interface {
+	EventType() string
+}
+
+// eventContextKey carries the event being handled in a handler's context
+type eventContextKey struct{}
+
+// EventFromContext returns the event a method subscribed with
+// SubscribeHandler is handling, for access to its ID and metadata
+func EventFromContext(ctx context.Context) (Event, bool) {
+	event, ok := ctx.Value(eventContextKey{}).(Event)
+	return event, ok
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// SubscribeHandler subscribes every exported method of handler shaped like
+//
+//	func(ctx context.Context, payload T) error
+//
+// to the event type of T, and returns the subscribed event types. The event
+// type is, in order of precedence: the result of T's EventType method, the
+// eventx tag of a field of T (conventionally a blank `_ struct{}` field tagged
+// eventx:"order.placed"), or T's name split on case changes, so a method
+// taking OrderPlaced handles "order.placed".
+// Methods of other shapes are ignored. Payloads are decoded like SubscribeTyped
+// and opts apply to every method. Handlers receive ctx without its cancellation
+// and with the event attached; see EventFromContext.
+func SubscribeHandler(bus EventBus, ctx context.Context, handler any, opts ...SubscribeOption) ([]string, error) {
+	options := subscribeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	value := reflect.ValueOf(handler)
+	if !value.IsValid() {
+		return nil, ErrorRegistry.New(ErrInvalidConfiguration).
+			WithDetail("reason", "handler is nil")
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	var subscribed []string
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Method(i)
+		payloadType, ok := handlerMethodPayload(method.Type())
+		if !ok {
+			continue
+		}
+
+		eventType := EventTypeOf(payloadType)
+		err := bus.Subscribe(ctx, eventType, methodHandler(handlerCtx, method, payloadType, options))
+		if err != nil {
+			return subscribed, err
+		}
+		subscribed = append(subscribed, eventType)
+	}
+
+	if len(subscribed) == 0 {
+		return nil, ErrorRegistry.New(ErrInvalidConfiguration).
+			WithDetail("handler_type", value.Type().String()).
+			WithDetail("reason", "no methods of the form func(context.Context, T) error")
+	}
+	sort.Strings(subscribed)
+	return subscribed, nil
+}
+
+// handlerMethodPayload returns T for methods of type func(context.Context, T) error
+func handlerMethodPayload(methodType reflect.Type) (reflect.Type, bool) {
+	if methodType.NumIn() != 2 || methodType.NumOut() != 1 || methodType.IsVariadic() {
+		return nil, false
+	}
+	if methodType.In(0) != contextType || methodType.Out(0) != errorType {
+		return nil, false
+	}
+	return methodType.In(1), true
+}
+
+// methodHandler adapts a handler method to an EventHandler
+func methodHandler(ctx context.Context, method reflect.Value, payloadType reflect.Type, options subscribeOptions) EventHandler {
+	return func(e Event) error {
+		payload, err := payloadValue(e, payloadType)
+		if err != nil {
+			return err
+		}
+
+		if options.validator != nil {
+			if err := options.validator.Struct(payload.Interface()); err != nil {
+				return ErrorRegistry.New(ErrPayloadValidation).
+					WithCause(err).
+					WithDetail("event_id", e.ID()).
+					WithDetail("event_type", e.Type()).
+					WithDetail("error", err.Error())
+			}
+		}
+
+		eventCtx := context.WithValue(ctx, eventContextKey{}, e)
+		results := method.Call([]reflect.Value{reflect.ValueOf(eventCtx), payload})
+		if err, _ := results[0].Interface().(error); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// payloadValue converts an event payload to payloadType, decoding raw JSON
+// from durable buses and adjusting between T and *T
+func payloadValue(e Event, payloadType reflect.Type) (reflect.Value, error) {
+	payload := e.Payload()
+
+	if raw, ok := payload.(json.RawMessage); ok {
+		target := reflect.New(payloadType)
+		if err := json.Unmarshal(raw, target.Interface()); err != nil {
+			return reflect.Value{}, ErrorRegistry.New(ErrSerializationFailed).
+				WithCause(err).
+				WithDetail("event_id", e.ID()).
+				WithDetail("event_type", e.Type())
+		}
+		return target.Elem(), nil
+	}
+
+	value := reflect.ValueOf(payload)
+	switch {
+	case !value.IsValid():
+		return reflect.Zero(payloadType), nil
+	case value.Type().AssignableTo(payloadType):
+		return value, nil
+	case value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Type().AssignableTo(payloadType):
+		return value.Elem(), nil
+	case payloadType.Kind() == reflect.Ptr && value.Type().AssignableTo(payloadType.Elem()):
+		ptr := reflect.New(payloadType.Elem())
+		ptr.Elem().Set(value)
+		return ptr, nil
+	}
+
+	return reflect.Value{}, ErrorRegistry.New(ErrInvalidEventType).
+		WithDetail("expected_type", payloadType.String()).
+		WithDetail("actual_type", value.Type().String())
+}
+
+// EventTypeOf returns the event type SubscribeHandler derives for payloads of type t
+func EventTypeOf(t reflect.Type) string {
+	base := t
+	for base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+
+	// *T has both value and pointer receiver methods
+	if typer, ok := reflect.New(base).Interface().(EventTyper); ok {
+		if name := typer.EventType(); name != "" {
+			return name
+		}
+	}
+
+	if base.Kind() == reflect.Struct {
+		for i := 0; i < base.NumField(); i++ {
+			if name := base.Field(i).Tag.Get("eventx"); name != "" {
+				return name
+			}
+		}
+	}
+
+	return dottedName(base.Name())
+}
+
+// dottedName converts a Go type name to a dotted event type, e.g.
+// OrderPlaced -> order.placed and HTTPRequestFailed -> http.request.failed
+func dottedName(name string) string {
+	runes := []rune(name)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(runes[i]) && (prevLower || (unicode.IsUpper(runes[i-1]) && nextLower)) {
+			parts = append(parts, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	parts = append(parts, strings.ToLower(string(runes[start:])))
+	return strings.Join(parts, ".")
+}
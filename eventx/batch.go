@@ -0,0 +1,143 @@
+package eventx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+)
+
+// BatchHandler processes a batch of decoded payloads in arrival order
+type BatchHandler[T any] func(ctx context.Context, batch []T) error
+
+// BatchSubscription buffers the events of a SubscribeTypedBatch subscription
+type BatchSubscription[T any] struct {
+	ctx       context.Context
+	eventType string
+	maxBatch  int
+	maxWait   time.Duration
+	handler   BatchHandler[T]
+	errors    *ErrorChannel
+
+	mutex  sync.Mutex
+	events []Event
+	data   []T
+	timer  *time.Timer
+	batch  uint64 // Incremented on every flush, so a stale timer can't flush a newer batch
+}
+
+// SubscribeTypedBatch registers a handler that receives payloads in batches.
+// Events are buffered and the batch is flushed when it reaches maxBatch
+// events, or maxWait after its first event, whichever comes first.
+//
+// Buffering acknowledges events to the bus before they are processed. A flush
+// triggered by a full batch runs on the event that completed it and its error
+// takes the bus's normal failure path; a flush triggered by maxWait reports one
+// HandlerError per event of the failed batch on the subscription's Errors
+// channel. Call Flush on shutdown so the last partial batch isn't lost.
+func SubscribeTypedBatch[T any](bus EventBus, ctx context.Context, eventType string, maxBatch int, maxWait time.Duration, handler BatchHandler[T], opts ...SubscribeOption) (*BatchSubscription[T], error) {
+	if maxBatch <= 0 || maxWait <= 0 {
+		return nil, ErrorRegistry.New(ErrInvalidConfiguration).
+			WithDetail("event_type", eventType).
+			WithDetail("reason", "maxBatch and maxWait must be positive")
+	}
+
+	subscription := &BatchSubscription[T]{
+		ctx:       ctx,
+		eventType: eventType,
+		maxBatch:  maxBatch,
+		maxWait:   maxWait,
+		handler:   handler,
+		errors:    NewErrorChannel(DefaultErrorBufferSize),
+	}
+
+	err := SubscribeTyped(bus, ctx, eventType, func(e TypedEvent[T]) error {
+		return subscription.add(e)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// Flush hands the buffered events to the handler now, returning its error
+func (s *BatchSubscription[T]) Flush(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := s.flush(ctx)
+	return err
+}
+
+// Pending returns the number of buffered events
+func (s *BatchSubscription[T]) Pending() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.events)
+}
+
+// Errors returns the failures of batches flushed by maxWait
+func (s *BatchSubscription[T]) Errors() <-chan HandlerError {
+	return s.errors.C()
+}
+
+// add buffers an event, flushing when the batch is full
+func (s *BatchSubscription[T]) add(e TypedEvent[T]) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events = append(s.events, e)
+	s.data = append(s.data, e.Data())
+
+	if len(s.events) >= s.maxBatch {
+		_, err := s.flush(s.ctx)
+		return err
+	}
+
+	if len(s.events) == 1 {
+		batch := s.batch
+		s.timer = time.AfterFunc(s.maxWait, func() {
+			s.flushAfterWait(batch)
+		})
+	}
+	return nil
+}
+
+// flushAfterWait flushes the batch a timer was started for, if still pending
+func (s *BatchSubscription[T]) flushAfterWait(batch uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.batch != batch {
+		return
+	}
+
+	events, err := s.flush(s.ctx)
+	if err == nil {
+		return
+	}
+
+	logx.Error("Batch of %d %s events failed: %v", len(events), s.eventType, err)
+	for _, event := range events {
+		s.errors.Report(NewHandlerError(event, err))
+	}
+}
+
+// flush runs the handler on the buffered batch and resets the buffer. The
+// caller must hold the mutex.
+func (s *BatchSubscription[T]) flush(ctx context.Context) ([]Event, error) {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.batch++
+
+	if len(s.events) == 0 {
+		return nil, nil
+	}
+
+	events, data := s.events, s.data
+	s.events, s.data = nil, nil
+
+	return events, s.handler(ctx, data)
+}
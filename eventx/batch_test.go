@@ -0,0 +1,203 @@
+package eventx_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// batchRecorder records the batches its handler receives and signals each
+type batchRecorder struct {
+	mutex   sync.Mutex
+	batches [][]int
+	flushed chan struct{}
+	err     error
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{flushed: make(chan struct{}, 100)}
+}
+
+func (r *batchRecorder) handle(ctx context.Context, batch []int) error {
+	r.mutex.Lock()
+	r.batches = append(r.batches, append([]int(nil), batch...))
+	r.mutex.Unlock()
+	r.flushed <- struct{}{}
+	return r.err
+}
+
+func (r *batchRecorder) snapshot() [][]int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([][]int(nil), r.batches...)
+}
+
+func (r *batchRecorder) waitFlush(t *testing.T, timeout time.Duration) {
+	t.Helper()
+	select {
+	case <-r.flushed:
+	case <-time.After(timeout):
+		t.Fatalf("no batch flushed within %v", timeout)
+	}
+}
+
+func newBatchBus(t *testing.T) eventx.EventBus {
+	t.Helper()
+	cfg := eventx.DefaultBusConfig()
+	cfg.EnableLogging = false
+	return eventxmemory.New(cfg)
+}
+
+func publishNumbers(t *testing.T, bus eventx.EventBus, from, to int) {
+	t.Helper()
+	for n := from; n <= to; n++ {
+		if err := bus.Publish(context.Background(), eventx.NewEvent("row.written", n)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+}
+
+func TestSubscribeTypedBatchSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBatch    int
+		published   int
+		wantBatches [][]int
+		wantPending int
+	}{
+		{
+			name:        "full batches",
+			maxBatch:    3,
+			published:   6,
+			wantBatches: [][]int{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			name:        "remainder stays buffered",
+			maxBatch:    4,
+			published:   6,
+			wantBatches: [][]int{{1, 2, 3, 4}},
+			wantPending: 2,
+		},
+		{
+			name:        "batch of one",
+			maxBatch:    1,
+			published:   2,
+			wantBatches: [][]int{{1}, {2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newBatchBus(t)
+			recorder := newBatchRecorder()
+			subscription, err := eventx.SubscribeTypedBatch(bus, context.Background(), "row.written", tt.maxBatch, time.Hour, recorder.handle)
+			if err != nil {
+				t.Fatalf("SubscribeTypedBatch: %v", err)
+			}
+
+			publishNumbers(t, bus, 1, tt.published)
+
+			if got := recorder.snapshot(); !reflect.DeepEqual(got, tt.wantBatches) {
+				t.Errorf("batches = %v, want %v", got, tt.wantBatches)
+			}
+			if got := subscription.Pending(); got != tt.wantPending {
+				t.Errorf("pending = %d, want %d", got, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestSubscribeTypedBatchMaxWait(t *testing.T) {
+	bus := newBatchBus(t)
+	recorder := newBatchRecorder()
+	subscription, err := eventx.SubscribeTypedBatch(bus, context.Background(), "row.written", 10, 20*time.Millisecond, recorder.handle)
+	if err != nil {
+		t.Fatalf("SubscribeTypedBatch: %v", err)
+	}
+
+	publishNumbers(t, bus, 1, 3)
+	if got := recorder.snapshot(); len(got) != 0 {
+		t.Fatalf("batches = %v before maxWait, want none", got)
+	}
+
+	recorder.waitFlush(t, 5*time.Second)
+	if got, want := recorder.snapshot(), [][]int{{1, 2, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+	if got := subscription.Pending(); got != 0 {
+		t.Errorf("pending = %d after the flush, want 0", got)
+	}
+
+	// The next partial batch gets its own maxWait
+	publishNumbers(t, bus, 4, 4)
+	recorder.waitFlush(t, 5*time.Second)
+	if got, want := recorder.snapshot(), [][]int{{1, 2, 3}, {4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+}
+
+func TestSubscribeTypedBatchMaxWaitError(t *testing.T) {
+	bus := newBatchBus(t)
+	recorder := newBatchRecorder()
+	recorder.err = errors.New("insert failed")
+	subscription, err := eventx.SubscribeTypedBatch(bus, context.Background(), "row.written", 10, 10*time.Millisecond, recorder.handle)
+	if err != nil {
+		t.Fatalf("SubscribeTypedBatch: %v", err)
+	}
+
+	publishNumbers(t, bus, 1, 2)
+
+	for range 2 {
+		select {
+		case failure := <-subscription.Errors():
+			if !errors.Is(failure.Err, recorder.err) {
+				t.Errorf("failure = %v, want %v", failure.Err, recorder.err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no error reported for the failed batch")
+		}
+	}
+}
+
+func TestSubscribeTypedBatchFlush(t *testing.T) {
+	bus := newBatchBus(t)
+	recorder := newBatchRecorder()
+	subscription, err := eventx.SubscribeTypedBatch(bus, context.Background(), "row.written", 10, time.Hour, recorder.handle)
+	if err != nil {
+		t.Fatalf("SubscribeTypedBatch: %v", err)
+	}
+
+	publishNumbers(t, bus, 1, 2)
+	if err := subscription.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := subscription.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush of an empty buffer: %v", err)
+	}
+
+	if got, want := recorder.snapshot(), [][]int{{1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+}
+
+func TestSubscribeTypedBatchInvalidConfig(t *testing.T) {
+	bus := newBatchBus(t)
+	recorder := newBatchRecorder()
+
+	for _, tt := range []struct {
+		maxBatch int
+		maxWait  time.Duration
+	}{{0, time.Second}, {10, 0}} {
+		_, err := eventx.SubscribeTypedBatch(bus, context.Background(), "row.written", tt.maxBatch, tt.maxWait, recorder.handle)
+		if !errx.IsCode(err, eventx.ErrInvalidConfiguration) {
+			t.Errorf("maxBatch=%d maxWait=%v: err = %v, want ErrInvalidConfiguration", tt.maxBatch, tt.maxWait, err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package eventx
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
+	"time"
 )
 
 // EventHandler is a function that processes events
@@ -34,7 +36,8 @@ type EventBus interface {
 	// Publish publishes an event to all registered handlers
 	Publish(ctx context.Context, event Event) error
 
-	// PublishBatch publishes multiple events in a single operation
+	// PublishBatch publishes multiple events in a single operation. A failure
+	// doesn't stop the batch; the failed events are reported in a BatchError.
 	PublishBatch(ctx context.Context, events []Event) error
 
 	// AddFilter adds a filter for a specific event type
@@ -74,11 +77,13 @@ type MetricsEventBus interface {
 
 // BusMetrics represents event bus metrics
 type BusMetrics struct {
-	EventsPublished   int64 `json:"events_published"`
-	EventsProcessed   int64 `json:"events_processed"`
-	EventsFailed      int64 `json:"events_failed"`
-	ActiveSubscribers int   `json:"active_subscribers"`
-	ConnectionStatus  bool  `json:"connection_status"`
+	EventsPublished    int64 `json:"events_published"`
+	EventsProcessed    int64 `json:"events_processed"`
+	EventsFailed       int64 `json:"events_failed"`
+	EventsThrottled    int64 `json:"events_throttled"`
+	EventsDeadLettered int64 `json:"events_dead_lettered"`
+	ActiveSubscribers  int   `json:"active_subscribers"`
+	ConnectionStatus   bool  `json:"connection_status"`
 }
 
 // BusConfig represents common configuration for event buses
@@ -110,6 +115,55 @@ type BusConfig struct {
 	// Monitoring
 	EnableMetrics bool `json:"enable_metrics"`
 	EnableLogging bool `json:"enable_logging"`
+
+	// ErrorBufferSize is the capacity of the Errors() channel. When the buffer is
+	// full (e.g. nobody reads it) the oldest error is dropped so handlers never block.
+	ErrorBufferSize int `json:"error_buffer_size"`
+
+	// SequenceScope enables bus-assigned sequence numbers, stored in event
+	// metadata and read with Sequence(). Disabled by default.
+	SequenceScope SequenceScope `json:"sequence_scope"`
+
+	// CompressionThreshold is the payload size in bytes above which payloads
+	// are gzipped when EnableCompression is set
+	CompressionThreshold int `json:"compression_threshold"`
+
+	// ClaimCheckThreshold is the encoded payload size in bytes above which
+	// payloads are stored in BlobStore and messages carry only a reference.
+	// 0 disables claim checks. Publishers and consumers need the same store.
+	ClaimCheckThreshold int       `json:"claim_check_threshold"`
+	BlobStore           BlobStore `json:"-"`
+	BlobPrefix          string    `json:"blob_prefix"`
+
+	// RateLimits throttles dispatch per event type; see WithRateLimit
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+
+	// DispatchMode selects whether in-process buses run handlers before
+	// Publish returns (DispatchSync, the default) or on goroutines (DispatchAsync)
+	DispatchMode DispatchMode `json:"dispatch_mode"`
+
+	// DispatchWorkers bounds how many handlers DispatchAsync runs at once
+	// (0 for no bound). See WithAsyncDispatch.
+	DispatchWorkers int `json:"dispatch_workers"`
+
+	// HandlerRetry retries failing handlers of in-process buses and of the
+	// RabbitMQ and Redis consumers before they count as failed; the zero value
+	// doesn't retry. See WithHandlerRetry.
+	HandlerRetry RetryPolicy `json:"handler_retry"`
+
+	// DeadLetterSink receives the events of in-process buses whose handler
+	// still fails after HandlerRetry, with the last error. See WithDeadLetter.
+	DeadLetterSink    DeadLetterFunc `json:"-"`
+	DeadLetterTimeout time.Duration  `json:"dead_letter_timeout"`
+
+	// Lifecycle observes in-process buses registering, removing and running
+	// handlers. See WithLifecycleHooks.
+	Lifecycle LifecycleHooks `json:"-"`
+
+	// StrictEventTypes rejects subscribing or publishing to event types that
+	// aren't declared in EventTypes, or DeclaredEventTypes when it is nil
+	StrictEventTypes bool        `json:"strict_event_types"`
+	EventTypes       *EventTypes `json:"-"`
 }
 
 // DefaultBusConfig returns default configuration
@@ -132,17 +186,137 @@ func DefaultBusConfig() BusConfig {
 		RetryDelay:        1,
 		EnableMetrics:     true,
 		EnableLogging:     true,
+		ErrorBufferSize:   DefaultErrorBufferSize,
+		DispatchMode:      DispatchSync,
+
+		CompressionThreshold: 1024,
+		BlobPrefix:           "eventx-payloads",
+	}
+}
+
+// StructValidator validates a decoded payload before a typed handler runs.
+// *validator.Validate from github.com/go-playground/validator/v10 satisfies it,
+// so the validate struct tags already used across the module are enforced.
+type StructValidator interface {
+	Struct(s any) error
+}
+
+// ValidatorFunc adapts a function, such as a JSON Schema check, to StructValidator
+type ValidatorFunc func(payload any) error
+
+// Struct calls f
+func (f ValidatorFunc) Struct(payload any) error {
+	return f(payload)
+}
+
+// SubscribeOption configures SubscribeTyped
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	validator   StructValidator
+	deadLetters DeadLetterStore
+}
+
+// WithPayloadValidator validates every decoded payload before the handler runs.
+// A failing payload never reaches the handler; the subscription returns an
+// ErrPayloadValidation error instead, which buses treat like any handler
+// failure (reported on Errors() and, on SQS, redriven to the dead-letter queue).
+func WithPayloadValidator(validator StructValidator) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.validator = validator
+	}
+}
+
+// WithDeadLetterStore records events whose payload can't be decoded or
+// validated, or whose handler fails, in store for inspection and replay with
+// ReplayDeadLetters. The failure is still returned to the bus.
+func WithDeadLetterStore(store DeadLetterStore) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deadLetters = store
+	}
+}
+
+// SubscribeTyped registers a typed event handler. Events whose payload is raw
+// JSON, as delivered by durable buses, are decoded into T first. An eventType
+// containing wildcards, such as "user.*", is subscribed with SubscribePattern.
+// A panicking handler fails with ErrHandlerFailed. Use SubscribeTypedHandle
+// to be able to remove the handler later.
+func SubscribeTyped[T any](bus EventBus, ctx context.Context, eventType string, handler TypedEventHandler[T], opts ...SubscribeOption) error {
+	typedHandler := newTypedHandler(ctx, handler, opts)
+	if IsPattern(eventType) {
+		return SubscribePattern(bus, ctx, eventType, typedHandler)
 	}
+	return bus.Subscribe(ctx, eventType, typedHandler)
+}
+
+// SubscribeTypedHandle registers a typed event handler like SubscribeTyped
+// and returns a Subscription that removes it again
+func SubscribeTypedHandle[T any](bus EventBus, ctx context.Context, eventType string, handler TypedEventHandler[T], opts ...SubscribeOption) (*Subscription, error) {
+	return SubscribeHandle(bus, ctx, eventType, newTypedHandler(ctx, handler, opts))
 }
 
-// SubscribeTyped registers a typed event handler
-func SubscribeTyped[T any](bus EventBus, ctx context.Context, eventType string, handler TypedEventHandler[T]) error {
-	return bus.Subscribe(ctx, eventType, func(e Event) error {
-		if typedEvent, ok := e.(TypedEvent[T]); ok {
+// newTypedHandler adapts a typed handler to an EventHandler, decoding and
+// validating payloads and dead-lettering failures as opts configure
+func newTypedHandler[T any](ctx context.Context, handler TypedEventHandler[T], opts []SubscribeOption) EventHandler {
+	options := subscribeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	dispatch := func(e Event) (DeadLetterReason, error) {
+		typedEvent, err := typedEventOf[T](e)
+		if err != nil {
+			return DeadLetterDecode, err
+		}
+
+		if options.validator != nil {
+			if err := options.validator.Struct(typedEvent.Data()); err != nil {
+				return DeadLetterValidation, ErrorRegistry.New(ErrPayloadValidation).
+					WithCause(err).
+					WithDetail("event_id", e.ID()).
+					WithDetail("event_type", e.Type()).
+					WithDetail("error", err.Error())
+			}
+		}
+
+		return DeadLetterHandler, CallHandler(func(Event) error {
 			return handler(typedEvent)
+		}, e)
+	}
+
+	return func(e Event) error {
+		reason, err := dispatch(e)
+		if err != nil && options.deadLetters != nil {
+			recordDeadLetter(context.WithoutCancel(ctx), options.deadLetters, e, reason, err)
 		}
-		return ErrorRegistry.New(ErrInvalidEventType).
+		return err
+	}
+}
+
+// typedEventOf returns e as a TypedEvent[T], decoding a raw JSON payload when needed
+func typedEventOf[T any](e Event) (TypedEvent[T], error) {
+	if typedEvent, ok := e.(TypedEvent[T]); ok {
+		return typedEvent, nil
+	}
+
+	raw, ok := e.Payload().(json.RawMessage)
+	if !ok {
+		return nil, ErrorRegistry.New(ErrInvalidEventType).
 			WithDetail("expected_type", reflect.TypeOf((*T)(nil)).Elem().String()).
 			WithDetail("actual_type", reflect.TypeOf(e.Payload()).String())
-	})
+	}
+
+	var data T
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, ErrorRegistry.New(ErrSerializationFailed).
+			WithCause(err).
+			WithDetail("event_id", e.ID()).
+			WithDetail("event_type", e.Type())
+	}
+
+	return NewEventWithID(e.ID(), e.Type(), data, e.Timestamp(), EventOptions{
+		Source:   e.Source(),
+		Version:  e.Version(),
+		Metadata: e.Metadata(),
+	}), nil
 }
@@ -0,0 +1,313 @@
+package eventx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+)
+
+// DeadLetterReason classifies why an event was dead-lettered
+type DeadLetterReason string
+
+const (
+	// DeadLetterDecode means the payload couldn't be decoded into the handler's type
+	DeadLetterDecode DeadLetterReason = "decode"
+
+	// DeadLetterValidation means the payload failed WithPayloadValidator
+	DeadLetterValidation DeadLetterReason = "validation"
+
+	// DeadLetterHandler means the handler returned an error or panicked
+	DeadLetterHandler DeadLetterReason = "handler"
+)
+
+// DeadLetter is an event that failed, with its failure history
+type DeadLetter struct {
+	Event     *SerializableEvent `json:"event"`
+	Reason    DeadLetterReason   `json:"reason"`
+	Error     string             `json:"error"` // Most recent failure
+	Attempts  int                `json:"attempts"`
+	FirstSeen time.Time          `json:"first_seen"`
+	LastSeen  time.Time          `json:"last_seen"`
+}
+
+// Merge folds another failure record of the same event into l: attempts are
+// added, the earliest FirstSeen is kept, and Reason, Error and LastSeen come
+// from the more recent record. Stores implement Record with it.
+func (l DeadLetter) Merge(other DeadLetter) DeadLetter {
+	merged := l
+	merged.Attempts += other.Attempts
+	if other.FirstSeen.Before(merged.FirstSeen) {
+		merged.FirstSeen = other.FirstSeen
+	}
+	if other.LastSeen.After(l.LastSeen) {
+		merged.Event = other.Event
+		merged.Reason = other.Reason
+		merged.Error = other.Error
+		merged.LastSeen = other.LastSeen
+	}
+	return merged
+}
+
+// DeadLetterFilter selects dead letters; zero fields match everything
+type DeadLetterFilter struct {
+	// EventType is an exact event type or a pattern such as "order.*"
+	EventType string
+
+	Reason DeadLetterReason
+
+	// Since and Until bound LastSeen (inclusive and exclusive)
+	Since time.Time
+	Until time.Time
+
+	MinAttempts int
+
+	// Limit caps the number of dead letters returned (0 for no limit)
+	Limit int
+}
+
+// Matches reports whether letter passes the filter, ignoring Limit
+func (f DeadLetterFilter) Matches(letter DeadLetter) bool {
+	if f.EventType != "" {
+		if IsPattern(f.EventType) {
+			if !MatchPattern(f.EventType, letter.Event.Type) {
+				return false
+			}
+		} else if letter.Event.Type != f.EventType {
+			return false
+		}
+	}
+	if f.Reason != "" && letter.Reason != f.Reason {
+		return false
+	}
+	if !f.Since.IsZero() && letter.LastSeen.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !letter.LastSeen.Before(f.Until) {
+		return false
+	}
+	return letter.Attempts >= f.MinAttempts
+}
+
+// DeadLetterStore keeps events that failed so they can be inspected and
+// replayed. Dead letters are keyed by event ID.
+type DeadLetterStore interface {
+	// Record stores a failure, merging it (see DeadLetter.Merge) into the
+	// event's existing dead letter if there is one
+	Record(ctx context.Context, letter DeadLetter) error
+
+	// List returns the dead letters matching filter, oldest FirstSeen first
+	List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error)
+
+	// Delete removes the dead letter of an event
+	Delete(ctx context.Context, eventID string) error
+}
+
+// RecordDeadLetter records a failure of event in store
+func RecordDeadLetter(ctx context.Context, store DeadLetterStore, event Event, reason DeadLetterReason, failure error) error {
+	serializable, err := ToSerializable(event)
+	if err != nil {
+		// Keep the envelope even when the payload can't be encoded
+		serializable = &SerializableEvent{
+			ID:        event.ID(),
+			Type:      event.Type(),
+			Timestamp: event.Timestamp(),
+			Source:    event.Source(),
+			Version:   event.Version(),
+			Data:      json.RawMessage("null"),
+			Metadata:  event.Metadata(),
+		}
+	}
+
+	now := time.Now()
+	return store.Record(ctx, DeadLetter{
+		Event:     serializable,
+		Reason:    reason,
+		Error:     failure.Error(),
+		Attempts:  1,
+		FirstSeen: now,
+		LastSeen:  now,
+	})
+}
+
+// DeadLettering wraps handler so that its failures, including panics, are
+// recorded in store. The failure is still returned, so the bus handles it as
+// usual.
+func DeadLettering(store DeadLetterStore, handler EventHandler) EventHandler {
+	return func(event Event) error {
+		err := CallHandler(handler, event)
+		if err != nil {
+			recordDeadLetter(context.Background(), store, event, DeadLetterHandler, err)
+		}
+		return err
+	}
+}
+
+// recordDeadLetter records a failure, logging instead of returning store
+// errors so the original failure reaches the bus unchanged
+func recordDeadLetter(ctx context.Context, store DeadLetterStore, event Event, reason DeadLetterReason, failure error) {
+	if err := RecordDeadLetter(ctx, store, event, reason, failure); err != nil {
+		logx.Error("Failed to dead-letter event %s (%s): %v", event.ID(), event.Type(), err)
+	}
+}
+
+// DefaultDeadLetterTimeout bounds the context of a DeadLetterSink call when
+// BusConfig.DeadLetterTimeout is not set
+const DefaultDeadLetterTimeout = 5 * time.Second
+
+// DeadLetterFunc receives an event whose handler failed for good, with the
+// last error. In-process buses call it on its own goroutine, so a slow sink
+// never holds up publishers, with a context that expires after
+// BusConfig.DeadLetterTimeout.
+type DeadLetterFunc func(ctx context.Context, event Event, err error)
+
+// WithDeadLetter returns a copy of the config that forwards events whose
+// handler still fails after HandlerRetry to sink
+//
+//	bus := eventxmemory.New(eventx.DefaultBusConfig().
+//		WithHandlerRetry(3, 100*time.Millisecond).
+//		WithDeadLetter(func(ctx context.Context, e eventx.Event, err error) {
+//			logx.Error("dead-lettered %s (%s): %v", e.ID(), e.Type(), err)
+//		}))
+func (c BusConfig) WithDeadLetter(sink DeadLetterFunc) BusConfig {
+	c.DeadLetterSink = sink
+	return c
+}
+
+// DeadLetterStoreSink returns a DeadLetterFunc that records events in store
+// as handler failures, for inspection and replay with ReplayDeadLetters
+func DeadLetterStoreSink(store DeadLetterStore) DeadLetterFunc {
+	return func(ctx context.Context, event Event, err error) {
+		recordDeadLetter(ctx, store, event, DeadLetterHandler, err)
+	}
+}
+
+// CallDeadLetter calls sink for event with a context that expires after
+// timeout (DefaultDeadLetterTimeout when 0) and isn't canceled with ctx.
+// A panicking sink is logged instead of crashing the process.
+func CallDeadLetter(ctx context.Context, sink DeadLetterFunc, event Event, failure error, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultDeadLetterTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Error("Dead-letter sink panicked for event %s (%s): %v", event.ID(), event.Type(), r)
+		}
+	}()
+	sink(ctx, event, failure)
+}
+
+// ReplayResult summarizes a ReplayDeadLetters run
+type ReplayResult struct {
+	Replayed int
+	Failed   int
+}
+
+// ReplayDeadLetters re-publishes the dead letters matching filter to bus,
+// oldest first. Each one is removed from the store before it is published
+// and restored if publishing fails, so a failure during replay adds to its
+// attempts. Events come back with raw JSON payloads, as from a durable bus,
+// which SubscribeTyped decodes. Publish errors are returned joined.
+func ReplayDeadLetters(ctx context.Context, store DeadLetterStore, bus EventBus, filter DeadLetterFilter) (ReplayResult, error) {
+	var result ReplayResult
+
+	letters, err := store.List(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+
+	var errs []error
+	for _, letter := range letters {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		event, err := FromSerializable[json.RawMessage](letter.Event)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := store.Delete(ctx, letter.Event.ID); err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := bus.Publish(ctx, event); err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			letter.Attempts++
+			letter.Error = err.Error()
+			letter.LastSeen = time.Now()
+			if restoreErr := store.Record(ctx, letter); restoreErr != nil {
+				errs = append(errs, restoreErr)
+			}
+			continue
+		}
+		result.Replayed++
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// MemoryDeadLetterStore keeps dead letters in memory, for tests and
+// single-process services
+type MemoryDeadLetterStore struct {
+	mutex   sync.RWMutex
+	letters map[string]DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates an empty in-memory dead-letter store
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: make(map[string]DeadLetter)}
+}
+
+// Record stores a failure, merging it into the event's existing dead letter
+func (s *MemoryDeadLetterStore) Record(ctx context.Context, letter DeadLetter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing, ok := s.letters[letter.Event.ID]; ok {
+		letter = existing.Merge(letter)
+	}
+	s.letters[letter.Event.ID] = letter
+	return nil
+}
+
+// List returns the dead letters matching filter, oldest FirstSeen first
+func (s *MemoryDeadLetterStore) List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error) {
+	s.mutex.RLock()
+	letters := make([]DeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		if filter.Matches(letter) {
+			letters = append(letters, letter)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FirstSeen.Before(letters[j].FirstSeen)
+	})
+	if filter.Limit > 0 && len(letters) > filter.Limit {
+		letters = letters[:filter.Limit]
+	}
+	return letters, nil
+}
+
+// Delete removes the dead letter of an event
+func (s *MemoryDeadLetterStore) Delete(ctx context.Context, eventID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.letters, eventID)
+	return nil
+}
@@ -0,0 +1,344 @@
+package eventx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+type refund struct {
+	OrderID string `json:"order_id"`
+	Amount  int    `json:"amount"`
+}
+
+// letter returns a dead letter of a new event of eventType seen at seen
+func letter(eventType string, reason eventx.DeadLetterReason, attempts int, seen time.Time) eventx.DeadLetter {
+	event, _ := eventx.ToSerializable(eventx.NewEvent(eventType, refund{OrderID: "o1"}))
+	return eventx.DeadLetter{
+		Event: event, Reason: reason, Error: "failed", Attempts: attempts,
+		FirstSeen: seen, LastSeen: seen,
+	}
+}
+
+func letterTypes(letters []eventx.DeadLetter) []string {
+	types := make([]string, len(letters))
+	for i, l := range letters {
+		types[i] = l.Event.Type
+	}
+	return types
+}
+
+func TestDeadLetterMerge(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := letter("order.refunded", eventx.DeadLetterDecode, 1, t0)
+	later := first
+	later.Reason, later.Error, later.Attempts = eventx.DeadLetterHandler, "timeout", 2
+	later.FirstSeen, later.LastSeen = t0.Add(time.Minute), t0.Add(time.Hour)
+
+	for name, merged := range map[string]eventx.DeadLetter{
+		"older into newer": later.Merge(first),
+		"newer into older": first.Merge(later),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if merged.Attempts != 3 {
+				t.Errorf("Attempts = %d, want 3", merged.Attempts)
+			}
+			if !merged.FirstSeen.Equal(t0) || !merged.LastSeen.Equal(t0.Add(time.Hour)) {
+				t.Errorf("seen = %v..%v, want %v..%v", merged.FirstSeen, merged.LastSeen, t0, t0.Add(time.Hour))
+			}
+			if merged.Reason != eventx.DeadLetterHandler || merged.Error != "timeout" {
+				t.Errorf("last failure = %s %q, want the newer record's", merged.Reason, merged.Error)
+			}
+		})
+	}
+}
+
+func TestDeadLetterFilterMatches(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := letter("order.refunded", eventx.DeadLetterHandler, 2, t0)
+
+	tests := []struct {
+		name   string
+		filter eventx.DeadLetterFilter
+		want   bool
+	}{
+		{"zero filter", eventx.DeadLetterFilter{}, true},
+		{"exact type", eventx.DeadLetterFilter{EventType: "order.refunded"}, true},
+		{"other type", eventx.DeadLetterFilter{EventType: "order.placed"}, false},
+		{"pattern", eventx.DeadLetterFilter{EventType: "order.*"}, true},
+		{"other pattern", eventx.DeadLetterFilter{EventType: "user.*"}, false},
+		{"reason", eventx.DeadLetterFilter{Reason: eventx.DeadLetterHandler}, true},
+		{"other reason", eventx.DeadLetterFilter{Reason: eventx.DeadLetterDecode}, false},
+		{"since is inclusive", eventx.DeadLetterFilter{Since: t0}, true},
+		{"since later", eventx.DeadLetterFilter{Since: t0.Add(time.Second)}, false},
+		{"until is exclusive", eventx.DeadLetterFilter{Until: t0}, false},
+		{"until later", eventx.DeadLetterFilter{Until: t0.Add(time.Second)}, true},
+		{"enough attempts", eventx.DeadLetterFilter{MinAttempts: 2}, true},
+		{"too few attempts", eventx.DeadLetterFilter{MinAttempts: 3}, false},
+		{"limit is ignored", eventx.DeadLetterFilter{Limit: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(l); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryDeadLetterStore(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := eventx.NewMemoryDeadLetterStore()
+
+	b := letter("b", eventx.DeadLetterHandler, 1, t0.Add(2*time.Minute))
+	a := letter("a", eventx.DeadLetterHandler, 1, t0.Add(time.Minute))
+	c := letter("c", eventx.DeadLetterDecode, 1, t0.Add(3*time.Minute))
+	for _, l := range []eventx.DeadLetter{b, a, c} {
+		if err := store.Record(ctx, l); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	// Recording the same event again merges into one dead letter
+	again := a
+	again.FirstSeen, again.LastSeen = t0.Add(time.Hour), t0.Add(time.Hour)
+	store.Record(ctx, again)
+
+	letters, _ := store.List(ctx, eventx.DeadLetterFilter{})
+	if got := letterTypes(letters); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("List = %v, want oldest first [a b c]", got)
+	}
+	if letters[0].Attempts != 2 {
+		t.Errorf("attempts of a = %d, want 2", letters[0].Attempts)
+	}
+
+	letters, _ = store.List(ctx, eventx.DeadLetterFilter{Reason: eventx.DeadLetterHandler, Limit: 1})
+	if got := letterTypes(letters); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("List(handler, limit 1) = %v, want [a]", got)
+	}
+
+	store.Delete(ctx, a.Event.ID)
+	letters, _ = store.List(ctx, eventx.DeadLetterFilter{})
+	if got := letterTypes(letters); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("List after Delete = %v, want [b c]", got)
+	}
+}
+
+func TestRecordDeadLetterKeepsUnencodableEvents(t *testing.T) {
+	ctx := context.Background()
+	store := eventx.NewMemoryDeadLetterStore()
+	event := eventx.NewEvent("job.run", make(chan int))
+
+	if err := eventx.RecordDeadLetter(ctx, store, event, eventx.DeadLetterHandler, errors.New("boom")); err != nil {
+		t.Fatalf("RecordDeadLetter: %v", err)
+	}
+	letters, _ := store.List(ctx, eventx.DeadLetterFilter{})
+	if len(letters) != 1 || letters[0].Event.ID != event.ID() || string(letters[0].Event.Data) != "null" {
+		t.Fatalf("letters = %+v, want the event's envelope with a null payload", letters)
+	}
+	if letters[0].Error != "boom" || letters[0].Attempts != 1 {
+		t.Errorf("letter = %+v, want error boom and 1 attempt", letters[0])
+	}
+}
+
+func TestDeadLetteringRecordsFailures(t *testing.T) {
+	store := eventx.NewMemoryDeadLetterStore()
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		handler eventx.EventHandler
+		check   func(error) bool
+	}{
+		{"error", func(eventx.Event) error { return errFailed }, func(err error) bool { return errors.Is(err, errFailed) }},
+		{"panic", func(eventx.Event) error { panic("boom") }, func(err error) bool { return errx.IsCode(err, eventx.ErrHandlerFailed) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := eventx.NewEvent("job.run", 1)
+			if err := eventx.DeadLettering(store, tt.handler)(event); !tt.check(err) {
+				t.Errorf("error = %v, not the handler's failure", err)
+			}
+			letters, _ := store.List(context.Background(), eventx.DeadLetterFilter{})
+			found := false
+			for _, l := range letters {
+				found = found || (l.Event.ID == event.ID() && l.Reason == eventx.DeadLetterHandler)
+			}
+			if !found {
+				t.Errorf("event %s wasn't dead-lettered as a handler failure", event.ID())
+			}
+		})
+	}
+
+	// Successful events aren't recorded
+	before, _ := store.List(context.Background(), eventx.DeadLetterFilter{})
+	eventx.DeadLettering(store, func(eventx.Event) error { return nil })(eventx.NewEvent("job.run", 1))
+	if after, _ := store.List(context.Background(), eventx.DeadLetterFilter{}); len(after) != len(before) {
+		t.Errorf("dead letters = %d after a success, want %d", len(after), len(before))
+	}
+}
+
+func TestSubscribeTypedDeadLetterReasons(t *testing.T) {
+	ctx := context.Background()
+	store := eventx.NewMemoryDeadLetterStore()
+	bus := newSubscriptionBus(t)
+
+	validator := eventx.ValidatorFunc(func(payload any) error {
+		if payload.(refund).Amount <= 0 {
+			return errors.New("amount must be positive")
+		}
+		return nil
+	})
+	err := eventx.SubscribeTyped(bus, ctx, "order.refunded", func(e eventx.TypedEvent[refund]) error {
+		if e.Data().OrderID == "bad" {
+			return errors.New("refund rejected")
+		}
+		return nil
+	}, eventx.WithPayloadValidator(validator), eventx.WithDeadLetterStore(store))
+	if err != nil {
+		t.Fatalf("SubscribeTyped: %v", err)
+	}
+
+	events := map[eventx.DeadLetterReason]eventx.Event{
+		eventx.DeadLetterDecode:     eventx.NewEvent("order.refunded", json.RawMessage(`{"amount":"ten"}`)),
+		eventx.DeadLetterValidation: eventx.NewEvent("order.refunded", refund{OrderID: "o1"}),
+		eventx.DeadLetterHandler:    eventx.NewEvent("order.refunded", refund{OrderID: "bad", Amount: 5}),
+	}
+	for reason, event := range events {
+		if err := bus.Publish(ctx, event); err == nil {
+			t.Errorf("Publish(%s case) succeeded, want the failure returned", reason)
+		}
+	}
+	bus.Publish(ctx, eventx.NewEvent("order.refunded", refund{OrderID: "o2", Amount: 5}))
+
+	letters, _ := store.List(ctx, eventx.DeadLetterFilter{})
+	if len(letters) != len(events) {
+		t.Fatalf("dead letters = %d, want %d", len(letters), len(events))
+	}
+	for _, l := range letters {
+		if want := events[l.Reason]; want == nil || want.ID() != l.Event.ID {
+			t.Errorf("event %s dead-lettered as %s", l.Event.ID, l.Reason)
+		}
+	}
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Now()
+	store := eventx.NewMemoryDeadLetterStore()
+	first := letter("order.refunded", eventx.DeadLetterHandler, 1, t0)
+	second := letter("order.refunded", eventx.DeadLetterHandler, 1, t0.Add(time.Second))
+	second.Event.Data = json.RawMessage(`{"order_id":"fails","amount":1}`)
+	other := letter("user.created", eventx.DeadLetterHandler, 1, t0)
+	for _, l := range []eventx.DeadLetter{second, first, other} {
+		store.Record(ctx, l)
+	}
+
+	// The handler is fixed for o1 but still fails for the second event
+	bus := newSubscriptionBus(t)
+	var replayed []string
+	eventx.SubscribeTyped(bus, ctx, "order.refunded", func(e eventx.TypedEvent[refund]) error {
+		replayed = append(replayed, e.Data().OrderID)
+		if e.Data().OrderID == "fails" {
+			return errors.New("still broken")
+		}
+		return nil
+	})
+
+	result, err := eventx.ReplayDeadLetters(ctx, store, bus, eventx.DeadLetterFilter{EventType: "order.*"})
+	if err == nil {
+		t.Error("ReplayDeadLetters error = nil, want the failed publish")
+	}
+	if result.Replayed != 1 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 1 replayed and 1 failed", result)
+	}
+	if !reflect.DeepEqual(replayed, []string{"o1", "fails"}) {
+		t.Errorf("replayed = %v, want oldest first [o1 fails]", replayed)
+	}
+
+	letters, _ := store.List(ctx, eventx.DeadLetterFilter{})
+	if len(letters) != 2 {
+		t.Fatalf("dead letters left = %d, want the failed one and the unmatched one", len(letters))
+	}
+	for _, l := range letters {
+		switch l.Event.ID {
+		case second.Event.ID:
+			if l.Attempts != 2 || !strings.Contains(l.Error, "still broken") {
+				t.Errorf("failed event = %d attempts, error %q, want 2 attempts and the replay's error", l.Attempts, l.Error)
+			}
+		case other.Event.ID:
+		default:
+			t.Errorf("unexpected dead letter %s left", l.Event.ID)
+		}
+	}
+}
+
+func TestReplayDeadLettersCanceledContext(t *testing.T) {
+	store := eventx.NewMemoryDeadLetterStore()
+	l := letter("order.refunded", eventx.DeadLetterHandler, 1, time.Now())
+	store.Record(context.Background(), l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := eventx.ReplayDeadLetters(ctx, store, newSubscriptionBus(t), eventx.DeadLetterFilter{})
+	if !errors.Is(err, context.Canceled) || result.Replayed != 0 {
+		t.Errorf("ReplayDeadLetters = %+v, %v, want nothing replayed and context.Canceled", result, err)
+	}
+	if letters, _ := store.List(context.Background(), eventx.DeadLetterFilter{}); len(letters) != 1 {
+		t.Errorf("dead letters = %d, want the letter kept", len(letters))
+	}
+}
+
+func TestDeadLetterStoreSink(t *testing.T) {
+	ctx := context.Background()
+	store := eventx.NewMemoryDeadLetterStore()
+	cfg := eventx.DefaultBusConfig().WithDeadLetter(eventx.DeadLetterStoreSink(store))
+	cfg.EnableLogging = false
+	bus := eventxmemory.New(cfg)
+
+	bus.Subscribe(ctx, "job.run", func(eventx.Event) error { return errors.New("failed") })
+	event := eventx.NewEvent("job.run", 1)
+	bus.Publish(ctx, event)
+	bus.(interface{ Wait() }).Wait()
+
+	letters, _ := store.List(ctx, eventx.DeadLetterFilter{})
+	if len(letters) != 1 || letters[0].Event.ID != event.ID() || letters[0].Reason != eventx.DeadLetterHandler {
+		t.Errorf("letters = %+v, want the failed event as a handler failure", letters)
+	}
+}
+
+func TestCallDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	event := eventx.NewEvent("job.run", 1)
+
+	// The sink gets a live context with the timeout even when ctx is canceled
+	var sinkErr error
+	var deadline time.Time
+	eventx.CallDeadLetter(ctx, func(ctx context.Context, e eventx.Event, err error) {
+		sinkErr = ctx.Err()
+		deadline, _ = ctx.Deadline()
+	}, event, errors.New("failed"), time.Minute)
+	if sinkErr != nil {
+		t.Errorf("sink context error = %v, want nil", sinkErr)
+	}
+	if until := time.Until(deadline); until <= 0 || until > time.Minute {
+		t.Errorf("sink deadline in %v, want within a minute", until)
+	}
+
+	// A panicking sink is recovered
+	eventx.CallDeadLetter(context.Background(), func(context.Context, eventx.Event, error) {
+		panic("sink down")
+	}, event, errors.New("failed"), 0)
+}
@@ -0,0 +1,58 @@
+package eventx
+
+import "fmt"
+
+// DispatchMode controls how an in-process bus runs handlers on Publish
+type DispatchMode string
+
+const (
+	// DispatchSync runs the handlers of an event one after another on the
+	// publisher's goroutine, exact subscriptions first in subscription order,
+	// and returns once all of them finished. Events from one publisher are
+	// handled in publish order. Publish returns the errors of every failed
+	// handler joined into one.
+	DispatchSync DispatchMode = "sync"
+
+	// DispatchAsync runs every handler of an event on its own goroutine and
+	// returns once the event is dispatched. Handlers of one event run
+	// concurrently and events may be handled in any order. Handler failures,
+	// including panics, are reported on Errors() instead of returned. With
+	// BusConfig.DispatchWorkers set, at most that many handlers run at once
+	// and Publish waits for a free worker.
+	DispatchAsync DispatchMode = "async"
+)
+
+// WithDispatchMode returns a copy of the config that dispatches events in mode
+func (c BusConfig) WithDispatchMode(mode DispatchMode) BusConfig {
+	c.DispatchMode = mode
+	return c
+}
+
+// WithSyncDispatch returns a copy of the config that runs handlers before
+// Publish returns (DispatchSync)
+func (c BusConfig) WithSyncDispatch() BusConfig {
+	return c.WithDispatchMode(DispatchSync)
+}
+
+// WithAsyncDispatch returns a copy of the config that runs handlers on
+// goroutines (DispatchAsync), at most workers of them at once. A burst of
+// events then makes publishers wait for a free worker instead of starting
+// unbounded goroutines. Zero workers doesn't bound them.
+func (c BusConfig) WithAsyncDispatch(workers int) BusConfig {
+	c.DispatchWorkers = workers
+	return c.WithDispatchMode(DispatchAsync)
+}
+
+// CallHandler runs handler for event, turning a panic into an ErrHandlerFailed
+// error so a faulty handler can't crash the process
+func CallHandler(handler EventHandler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrorRegistry.New(ErrHandlerFailed).
+				WithDetail("event_id", event.ID()).
+				WithDetail("event_type", event.Type()).
+				WithDetail("panic", fmt.Sprint(r))
+		}
+	}()
+	return handler(event)
+}
@@ -0,0 +1,60 @@
+package eventx
+
+import "maps"
+
+// Metadata keys of the event envelope. They travel in the metadata map, so
+// every backend that serializes metadata carries them across.
+const (
+	// MetadataCorrelationID identifies the chain of events an event belongs
+	// to. NewEvent sets it to the event's own ID when none is given.
+	MetadataCorrelationID = "eventx_correlation_id"
+	// MetadataCausationID holds the ID of the event that caused this one
+	MetadataCausationID = "eventx_causation_id"
+)
+
+// WithMetadata returns a copy of the options with key set in the metadata
+func (o EventOptions) WithMetadata(key string, value any) EventOptions {
+	metadata := maps.Clone(o.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata[key] = value
+	o.Metadata = metadata
+	return o
+}
+
+// WithCorrelationID returns a copy of the options that puts the event in the
+// chain identified by correlationID
+func (o EventOptions) WithCorrelationID(correlationID string) EventOptions {
+	return o.WithMetadata(MetadataCorrelationID, correlationID)
+}
+
+// CausedBy returns a copy of the options for an event emitted in reaction to
+// parent: it joins parent's chain and records parent as its cause.
+//
+//	func handleOrderPlaced(e eventx.TypedEvent[Order]) error {
+//		opts := eventx.DefaultEventOptions().CausedBy(e)
+//		return bus.Publish(ctx, eventx.NewEvent("invoice.requested", invoiceFor(e.Data()), opts))
+//	}
+func (o EventOptions) CausedBy(parent Event) EventOptions {
+	correlationID := CorrelationID(parent)
+	if correlationID == "" {
+		correlationID = parent.ID()
+	}
+	return o.WithCorrelationID(correlationID).
+		WithMetadata(MetadataCausationID, parent.ID())
+}
+
+// CorrelationID returns the ID of the chain of events an event belongs to, or
+// "" when it has none
+func CorrelationID(event Event) string {
+	correlationID, _ := event.Metadata()[MetadataCorrelationID].(string)
+	return correlationID
+}
+
+// CausationID returns the ID of the event that caused an event, or "" when it
+// started its chain
+func CausationID(event Event) string {
+	causationID, _ := event.Metadata()[MetadataCausationID].(string)
+	return causationID
+}
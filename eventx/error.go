@@ -22,4 +22,35 @@ var (
 	ErrTimeout              = ErrorRegistry.Register("TIMEOUT", errx.TypeTimeout, http.StatusRequestTimeout, "Event operation timed out")
 	ErrRateLimit            = ErrorRegistry.Register("RATE_LIMIT", errx.TypeRateLimit, http.StatusTooManyRequests, "Event rate limit exceeded")
 	ErrInvalidConfiguration = ErrorRegistry.Register("INVALID_CONFIGURATION", errx.TypeValidation, http.StatusBadRequest, "Invalid event bus configuration")
+	ErrPayloadValidation    = ErrorRegistry.Register("PAYLOAD_VALIDATION_FAILED", errx.TypeValidation, http.StatusUnprocessableEntity, "Event payload failed validation")
+	ErrClaimCheckFailed     = ErrorRegistry.Register("CLAIM_CHECK_FAILED", errx.TypeExternal, http.StatusBadGateway, "Failed to store or fetch event payload")
+	ErrUndeclaredEventType  = ErrorRegistry.Register("UNDECLARED_EVENT_TYPE", errx.TypeValidation, http.StatusBadRequest, "Event type is not declared")
+	ErrSagaFailed           = ErrorRegistry.Register("SAGA_FAILED", errx.TypeBusiness, http.StatusConflict, "Saga step failed")
+	ErrInvalidPattern       = ErrorRegistry.Register("INVALID_PATTERN", errx.TypeValidation, http.StatusBadRequest, "Invalid event type pattern")
 )
+
+// IsPayloadValidation reports whether a typed handler rejected an event because
+// its payload failed validation
+func IsPayloadValidation(err error) bool {
+	return errx.IsCode(err, ErrPayloadValidation)
+}
+
+// IsUndeclaredEventType reports whether a strict bus rejected an undeclared event type
+func IsUndeclaredEventType(err error) bool {
+	return errx.IsCode(err, ErrUndeclaredEventType)
+}
+
+// IsSagaFailed reports whether a saga run failed and was compensated
+func IsSagaFailed(err error) bool {
+	return errx.IsCode(err, ErrSagaFailed)
+}
+
+// IsInvalidPattern reports whether a pattern subscription was rejected as malformed
+func IsInvalidPattern(err error) bool {
+	return errx.IsCode(err, ErrInvalidPattern)
+}
+
+// IsRateLimited reports whether an event was shed by a rate limit
+func IsRateLimited(err error) bool {
+	return errx.IsCode(err, ErrRateLimit)
+}
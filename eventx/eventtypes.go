@@ -0,0 +1,119 @@
+package eventx
+
+import (
+	"sort"
+	"sync"
+)
+
+// EventTypes is a set of declared event type names. In strict mode buses reject
+// subscriptions and publishes for undeclared types, catching typos such as
+// subscribing to "user.created" but publishing "user.create".
+type EventTypes struct {
+	types map[string]bool
+	mutex sync.RWMutex
+}
+
+// NewEventTypes creates a set with the given types declared
+func NewEventTypes(names ...string) *EventTypes {
+	r := &EventTypes{types: make(map[string]bool, len(names))}
+	r.Register(names...)
+	return r
+}
+
+// Register declares event types
+func (r *EventTypes) Register(names ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, name := range names {
+		r.types[name] = true
+	}
+}
+
+// IsDeclared reports whether eventType was registered
+func (r *EventTypes) IsDeclared(eventType string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.types[eventType]
+}
+
+// List returns the declared types in sorted order
+func (r *EventTypes) List() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check returns an ErrUndeclaredEventType error for undeclared types, with
+// the closest declared type as a suggestion
+func (r *EventTypes) Check(eventType string) error {
+	if r.IsDeclared(eventType) {
+		return nil
+	}
+
+	err := ErrorRegistry.New(ErrUndeclaredEventType).WithDetail("event_type", eventType)
+	if suggestion := r.closest(eventType); suggestion != "" {
+		err = err.WithDetail("did_you_mean", suggestion)
+	}
+	return err
+}
+
+// closest returns the declared type with the smallest edit distance to
+// eventType, if it is near enough to be a likely typo
+func (r *EventTypes) closest(eventType string) string {
+	best, bestDistance := "", len(eventType)/3+1
+	for _, name := range r.List() {
+		if d := editDistance(eventType, name); d <= bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// DeclaredEventTypes is the set used by strict buses without their own EventTypes
+var DeclaredEventTypes = NewEventTypes()
+
+// RegisterEventType declares event types in DeclaredEventTypes
+func RegisterEventType(names ...string) {
+	DeclaredEventTypes.Register(names...)
+}
+
+// CheckEventType returns an ErrUndeclaredEventType error when the config is
+// strict and eventType isn't declared. Bus implementations call it before
+// subscribing or publishing; non-strict configs accept every type.
+func (c BusConfig) CheckEventType(eventType string) error {
+	if !c.StrictEventTypes {
+		return nil
+	}
+	types := c.EventTypes
+	if types == nil {
+		types = DeclaredEventTypes
+	}
+	return types.Check(eventType)
+}
@@ -1,6 +1,7 @@
 package eventx
 
 import (
+	"maps"
 	"time"
 
 	"github.com/google/uuid"
@@ -50,19 +51,28 @@ type BaseEvent[T any] struct {
 	metadata  map[string]any
 }
 
-// NewEvent creates a new typed event
+// NewEvent creates a new typed event with a generated ID and the current
+// time. Without a correlation ID in the options the event starts a new chain,
+// correlated by its own ID.
 func NewEvent[T any](eventType string, data T, opts ...EventOptions) TypedEvent[T] {
 	options := DefaultEventOptions()
 	if len(opts) > 0 {
 		options = opts[0]
 	}
 
+	// Copy the metadata, so events built from the same options don't share it
+	options.Metadata = maps.Clone(options.Metadata)
 	if options.Metadata == nil {
 		options.Metadata = make(map[string]any)
 	}
 
+	id := generateID()
+	if correlationID, _ := options.Metadata[MetadataCorrelationID].(string); correlationID == "" {
+		options.Metadata[MetadataCorrelationID] = id
+	}
+
 	return &BaseEvent[T]{
-		id:        generateID(),
+		id:        id,
 		eventType: eventType,
 		timestamp: time.Now(),
 		source:    options.Source,
@@ -0,0 +1,41 @@
+package eventx_test
+
+import (
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+func TestNewEventCopiesMetadata(t *testing.T) {
+	opts := eventx.DefaultEventOptions()
+	opts.Metadata = map[string]any{"tenant": "acme"}
+
+	first := eventx.NewEvent("user.created", 1, opts)
+	second := eventx.NewEvent("user.created", 2, opts)
+
+	firstID := first.Metadata()[eventx.MetadataCorrelationID]
+	secondID := second.Metadata()[eventx.MetadataCorrelationID]
+	if firstID != first.ID() || secondID != second.ID() {
+		t.Errorf("correlation IDs = %v, %v, want the event IDs %s, %s", firstID, secondID, first.ID(), second.ID())
+	}
+	if firstID == secondID {
+		t.Errorf("both events have correlation ID %v", firstID)
+	}
+
+	if _, ok := opts.Metadata[eventx.MetadataCorrelationID]; ok {
+		t.Errorf("caller's metadata was modified: %v", opts.Metadata)
+	}
+	if got := second.Metadata()["tenant"]; got != "acme" {
+		t.Errorf("tenant = %v, want acme", got)
+	}
+}
+
+func TestNewEventKeepsCorrelationID(t *testing.T) {
+	opts := eventx.DefaultEventOptions()
+	opts.Metadata = map[string]any{eventx.MetadataCorrelationID: "req-1"}
+
+	event := eventx.NewEvent("user.created", 1, opts)
+	if got := event.Metadata()[eventx.MetadataCorrelationID]; got != "req-1" {
+		t.Errorf("correlation ID = %v, want req-1", got)
+	}
+}
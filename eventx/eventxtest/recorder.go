@@ -0,0 +1,279 @@
+// Package eventxtest provides a recording event bus for tests.
+//
+// The recorder wraps any eventx.EventBus (an in-memory bus by default), keeps
+// every published event in order and records which handlers ran, so tests can
+// verify event emission without a real backend:
+//
+//	bus := eventxtest.NewRecorder()
+//	bus.SubscribeNamed(ctx, "user.created", "send-welcome", sendWelcome)
+//
+//	service := NewUserService(bus)
+//	service.Register(ctx, "jane@example.com")
+//
+//	event := bus.AssertPublished(t, "user.created")
+//	users := eventxtest.Payloads[UserCreated](bus, "user.created")
+//	bus.AssertHandled(t, "send-welcome")
+package eventxtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// HandlerCall records a single handler invocation
+type HandlerCall struct {
+	Handler   string // Name given to SubscribeNamed, or "<event type or pattern>#<n>"
+	EventType string
+	EventID   string
+	Err       error
+}
+
+// Recorder is an eventx.EventBus that records published events and handler calls
+type Recorder struct {
+	eventx.EventBus
+
+	mutex     sync.Mutex
+	published []eventx.Event
+	calls     []HandlerCall
+	handlers  map[string]int // Handlers subscribed per event type, for default names
+}
+
+// NewRecorder wraps bus, or a fresh in-memory bus when none is given
+func NewRecorder(bus ...eventx.EventBus) *Recorder {
+	var inner eventx.EventBus
+	if len(bus) > 0 && bus[0] != nil {
+		inner = bus[0]
+	} else {
+		cfg := eventx.DefaultBusConfig()
+		cfg.EnableLogging = false
+		inner = eventxmemory.New(cfg)
+	}
+
+	return &Recorder{
+		EventBus: inner,
+		handlers: make(map[string]int),
+	}
+}
+
+// Subscribe registers a handler whose invocations are recorded
+func (r *Recorder) Subscribe(ctx context.Context, eventType string, handler eventx.EventHandler) error {
+	return r.SubscribeNamed(ctx, eventType, "", handler)
+}
+
+// SubscribeNamed registers a handler under a name used in HandlerCalls
+func (r *Recorder) SubscribeNamed(ctx context.Context, eventType, name string, handler eventx.EventHandler) error {
+	r.mutex.Lock()
+	r.handlers[eventType]++
+	if name == "" {
+		name = fmt.Sprintf("%s#%d", eventType, r.handlers[eventType])
+	}
+	r.mutex.Unlock()
+
+	return r.EventBus.Subscribe(ctx, eventType, r.recording(name, handler))
+}
+
+// SubscribePattern registers a pattern handler whose invocations are recorded.
+// It fails like eventx.SubscribePattern when the wrapped bus has no pattern support.
+func (r *Recorder) SubscribePattern(ctx context.Context, pattern string, handler eventx.EventHandler) error {
+	r.mutex.Lock()
+	r.handlers[pattern]++
+	name := fmt.Sprintf("%s#%d", pattern, r.handlers[pattern])
+	r.mutex.Unlock()
+
+	return eventx.SubscribePattern(r.EventBus, ctx, pattern, r.recording(name, handler))
+}
+
+// recording wraps handler to record its invocations under name
+func (r *Recorder) recording(name string, handler eventx.EventHandler) eventx.EventHandler {
+	return func(event eventx.Event) error {
+		err := handler(event)
+
+		r.mutex.Lock()
+		r.calls = append(r.calls, HandlerCall{
+			Handler:   name,
+			EventType: event.Type(),
+			EventID:   event.ID(),
+			Err:       err,
+		})
+		r.mutex.Unlock()
+
+		return err
+	}
+}
+
+// Unsubscribe removes handlers for an event type
+func (r *Recorder) Unsubscribe(ctx context.Context, eventType string) error {
+	r.mutex.Lock()
+	delete(r.handlers, eventType)
+	r.mutex.Unlock()
+
+	return r.EventBus.Unsubscribe(ctx, eventType)
+}
+
+// Publish records the event and publishes it on the wrapped bus
+func (r *Recorder) Publish(ctx context.Context, event eventx.Event) error {
+	r.record(event)
+	return r.EventBus.Publish(ctx, event)
+}
+
+// PublishBatch records the events and publishes them on the wrapped bus
+func (r *Recorder) PublishBatch(ctx context.Context, events []eventx.Event) error {
+	r.record(events...)
+	return r.EventBus.PublishBatch(ctx, events)
+}
+
+// PublishAsync records the event and publishes it asynchronously when the
+// wrapped bus supports it, synchronously otherwise
+func (r *Recorder) PublishAsync(ctx context.Context, event eventx.Event) error {
+	r.record(event)
+	if async, ok := r.EventBus.(eventx.AsyncEventBus); ok {
+		return async.PublishAsync(ctx, event)
+	}
+	return r.EventBus.Publish(ctx, event)
+}
+
+// PublishBatchAsync records the events and publishes them asynchronously when
+// the wrapped bus supports it, synchronously otherwise
+func (r *Recorder) PublishBatchAsync(ctx context.Context, events []eventx.Event) error {
+	r.record(events...)
+	if async, ok := r.EventBus.(eventx.AsyncEventBus); ok {
+		return async.PublishBatchAsync(ctx, events)
+	}
+	return r.EventBus.PublishBatch(ctx, events)
+}
+
+func (r *Recorder) record(events ...eventx.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.published = append(r.published, events...)
+}
+
+// PublishedEvents returns every published event in publish order
+func (r *Recorder) PublishedEvents() []eventx.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	events := make([]eventx.Event, len(r.published))
+	copy(events, r.published)
+	return events
+}
+
+// Published returns the published events of one type in publish order
+func (r *Recorder) Published(eventType string) []eventx.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var events []eventx.Event
+	for _, event := range r.published {
+		if event.Type() == eventType {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Count returns how many events of a type were published
+func (r *Recorder) Count(eventType string) int {
+	return len(r.Published(eventType))
+}
+
+// Last returns the most recently published event of a type
+func (r *Recorder) Last(eventType string) (eventx.Event, bool) {
+	events := r.Published(eventType)
+	if len(events) == 0 {
+		return nil, false
+	}
+	return events[len(events)-1], true
+}
+
+// HandlerCalls returns every recorded handler invocation in completion order
+func (r *Recorder) HandlerCalls() []HandlerCall {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	calls := make([]HandlerCall, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallsTo returns the recorded invocations of a named handler
+func (r *Recorder) CallsTo(handler string) []HandlerCall {
+	var calls []HandlerCall
+	for _, call := range r.HandlerCalls() {
+		if call.Handler == handler {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears recorded events and handler calls, keeping subscriptions
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.published = nil
+	r.calls = nil
+}
+
+// AssertPublished fails the test unless an event of the type was published,
+// and returns the most recent one
+func (r *Recorder) AssertPublished(t testing.TB, eventType string) eventx.Event {
+	t.Helper()
+
+	event, ok := r.Last(eventType)
+	if !ok {
+		t.Fatalf("expected event %q to be published, published types: %v", eventType, r.publishedTypes())
+	}
+	return event
+}
+
+// AssertNotPublished fails the test if an event of the type was published
+func (r *Recorder) AssertNotPublished(t testing.TB, eventType string) {
+	t.Helper()
+
+	if n := r.Count(eventType); n > 0 {
+		t.Fatalf("expected event %q not to be published, got %d", eventType, n)
+	}
+}
+
+// AssertCount fails the test unless exactly n events of the type were published
+func (r *Recorder) AssertCount(t testing.TB, eventType string, n int) {
+	t.Helper()
+
+	if got := r.Count(eventType); got != n {
+		t.Fatalf("expected %d %q events, got %d", n, eventType, got)
+	}
+}
+
+// AssertHandled fails the test unless the named handler ran at least once
+func (r *Recorder) AssertHandled(t testing.TB, handler string) {
+	t.Helper()
+
+	if len(r.CallsTo(handler)) == 0 {
+		t.Fatalf("expected handler %q to be invoked", handler)
+	}
+}
+
+func (r *Recorder) publishedTypes() []string {
+	var types []string
+	for _, event := range r.PublishedEvents() {
+		types = append(types, event.Type())
+	}
+	return types
+}
+
+// Payloads returns the payloads of published events of a type that hold a T
+func Payloads[T any](r *Recorder, eventType string) []T {
+	var payloads []T
+	for _, event := range r.Published(eventType) {
+		if payload, ok := event.Payload().(T); ok {
+			payloads = append(payloads, payload)
+		}
+	}
+	return payloads
+}
@@ -0,0 +1,98 @@
+package eventx
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultErrorBufferSize is the capacity of a bus error channel when BusConfig.ErrorBufferSize is not set
+const DefaultErrorBufferSize = 100
+
+// HandlerError describes a failed asynchronous handler invocation
+type HandlerError struct {
+	Event     Event     `json:"-"`
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Err       error     `json:"-"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewHandlerError creates a HandlerError for the given event
+func NewHandlerError(event Event, err error) HandlerError {
+	return HandlerError{
+		Event:     event,
+		EventID:   event.ID(),
+		EventType: event.Type(),
+		Err:       err,
+		Timestamp: time.Now(),
+	}
+}
+
+// Error implements the error interface
+func (e HandlerError) Error() string {
+	return fmt.Sprintf("handler failed for event %s (%s): %v", e.EventID, e.EventType, e.Err)
+}
+
+// Unwrap returns the underlying handler error
+func (e HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorReportingEventBus extends EventBus with a channel of asynchronous handler failures
+type ErrorReportingEventBus interface {
+	EventBus
+
+	// Errors returns a channel that receives asynchronous handler failures.
+	// The channel is bounded; when it is full the oldest error is dropped.
+	Errors() <-chan HandlerError
+}
+
+// ErrorChannel is a bounded, non-blocking error channel with a drop-oldest policy.
+// Reporting never blocks a handler: when no consumer keeps up, the oldest buffered
+// error is discarded to make room and the drop counter is incremented.
+type ErrorChannel struct {
+	ch      chan HandlerError
+	mutex   sync.Mutex
+	dropped atomic.Int64
+}
+
+// NewErrorChannel creates an error channel with the given buffer size
+func NewErrorChannel(size int) *ErrorChannel {
+	if size <= 0 {
+		size = DefaultErrorBufferSize
+	}
+	return &ErrorChannel{ch: make(chan HandlerError, size)}
+}
+
+// Report publishes an error without blocking, dropping the oldest buffered error if full
+func (c *ErrorChannel) Report(err HandlerError) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for {
+		select {
+		case c.ch <- err:
+			return
+		default:
+		}
+
+		// Buffer full: drop the oldest error and retry
+		select {
+		case <-c.ch:
+			c.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// C returns the receive side of the channel
+func (c *ErrorChannel) C() <-chan HandlerError {
+	return c.ch
+}
+
+// Dropped returns how many errors were discarded because the buffer was full
+func (c *ErrorChannel) Dropped() int64 {
+	return c.dropped.Load()
+}
@@ -0,0 +1,74 @@
+package eventx
+
+import "time"
+
+// SubscriptionInfo identifies one registered handler in LifecycleHooks
+type SubscriptionInfo struct {
+	// HandlerID is the bus-assigned handler ID, as used by UnsubscribeHandler
+	HandlerID string `json:"handler_id"`
+	// EventType is the event type or pattern the handler was registered for
+	EventType string `json:"event_type"`
+}
+
+// LifecycleHooks observe how a bus registers and runs handlers, e.g. for
+// diagnostics or to find subscriptions that are never removed. Every hook is
+// optional and unset hooks cost nothing. Hooks run synchronously, outside the
+// bus's locks, on the goroutine doing the work, so they should be quick.
+type LifecycleHooks struct {
+	// OnSubscribe is called after a handler is registered
+	OnSubscribe func(info SubscriptionInfo)
+	// OnUnsubscribe is called after a handler is removed, once per handler
+	// when Unsubscribe removes all handlers of an event type
+	OnUnsubscribe func(info SubscriptionInfo)
+	// OnHandlerStart is called before a handler processes an event
+	OnHandlerStart func(info SubscriptionInfo, event Event)
+	// OnHandlerEnd is called after a handler processed an event, with the
+	// time taken and the final error, retries included
+	OnHandlerEnd func(info SubscriptionInfo, event Event, duration time.Duration, err error)
+}
+
+// WithLifecycleHooks returns a copy of the config that calls hooks as
+// in-process buses register, remove and run handlers
+//
+//	active := map[string]eventx.SubscriptionInfo{} // guard with a mutex
+//	cfg := eventx.DefaultBusConfig().WithLifecycleHooks(eventx.LifecycleHooks{
+//		OnSubscribe:   func(info eventx.SubscriptionInfo) { active[info.HandlerID] = info },
+//		OnUnsubscribe: func(info eventx.SubscriptionInfo) { delete(active, info.HandlerID) },
+//	})
+func (c BusConfig) WithLifecycleHooks(hooks LifecycleHooks) BusConfig {
+	c.Lifecycle = hooks
+	return c
+}
+
+// Subscribed calls OnSubscribe when set
+func (h LifecycleHooks) Subscribed(info SubscriptionInfo) {
+	if h.OnSubscribe != nil {
+		h.OnSubscribe(info)
+	}
+}
+
+// Unsubscribed calls OnUnsubscribe when set
+func (h LifecycleHooks) Unsubscribed(info SubscriptionInfo) {
+	if h.OnUnsubscribe != nil {
+		h.OnUnsubscribe(info)
+	}
+}
+
+// HandlerStarted calls OnHandlerStart when set and returns the start time to
+// pass to HandlerEnded
+func (h LifecycleHooks) HandlerStarted(info SubscriptionInfo, event Event) time.Time {
+	if h.OnHandlerStart != nil {
+		h.OnHandlerStart(info, event)
+	}
+	if h.OnHandlerEnd == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// HandlerEnded calls OnHandlerEnd when set
+func (h LifecycleHooks) HandlerEnded(info SubscriptionInfo, event Event, start time.Time, err error) {
+	if h.OnHandlerEnd != nil {
+		h.OnHandlerEnd(info, event, time.Since(start), err)
+	}
+}
@@ -0,0 +1,302 @@
+package eventx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+)
+
+// MetadataTraceID is the event metadata key PropagateTraceID stores the
+// publisher's trace ID under
+const MetadataTraceID = "eventx_trace_id"
+
+// PublishFunc publishes an event
+type PublishFunc func(ctx context.Context, event Event) error
+
+// PublishMiddleware wraps a publish. It may run code before and after calling
+// next, or return an error without calling next to stop the publish.
+type PublishMiddleware func(next PublishFunc) PublishFunc
+
+// HandlerFunc handles an event. The context is the one given to Subscribe,
+// carrying the event for EventFromContext.
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// HandlerMiddleware wraps a handler invocation. It may run code before and
+// after calling next, or return an error without calling next to skip the
+// handler; the bus then treats the event as failed.
+type HandlerMiddleware func(next HandlerFunc) HandlerFunc
+
+// MiddlewareBus wraps an EventBus with publish and handler middleware for
+// cross-cutting concerns such as logging, metrics or authorization checks.
+// Middleware runs in registration order, the first registered outermost, and
+// applies to every publish and handler call after it is registered, including
+// handlers subscribed earlier. The optional capabilities of the wrapped bus,
+// such as HandlerEventBus, Errors, GetMetrics and the in-memory bus's Wait and
+// Flush, are forwarded.
+//
+//	bus := eventx.NewMiddlewareBus(eventxmemory.New())
+//	bus.UseHandler(func(next eventx.HandlerFunc) eventx.HandlerFunc {
+//		return func(ctx context.Context, e eventx.Event) error {
+//			start := time.Now()
+//			err := next(ctx, e)
+//			logx.Info("handled %s in %s", e.Type(), time.Since(start))
+//			return err
+//		}
+//	})
+type MiddlewareBus struct {
+	EventBus
+
+	mutex   sync.RWMutex
+	publish []PublishMiddleware
+	handler []HandlerMiddleware
+}
+
+// NewMiddlewareBus wraps bus so middleware can be added with Use and UseHandler
+func NewMiddlewareBus(bus EventBus) *MiddlewareBus {
+	return &MiddlewareBus{EventBus: bus}
+}
+
+// Use adds middleware around every Publish and PublishBatch
+func (b *MiddlewareBus) Use(middleware ...PublishMiddleware) *MiddlewareBus {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.publish = append(b.publish, middleware...)
+	return b
+}
+
+// UseHandler adds middleware around every handler invocation
+func (b *MiddlewareBus) UseHandler(middleware ...HandlerMiddleware) *MiddlewareBus {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.handler = append(b.handler, middleware...)
+	return b
+}
+
+// Unwrap returns the wrapped bus
+func (b *MiddlewareBus) Unwrap() EventBus {
+	return b.EventBus
+}
+
+// Subscribe registers handler on the wrapped bus, running it through the
+// handler middleware
+func (b *MiddlewareBus) Subscribe(ctx context.Context, eventType string, handler EventHandler) error {
+	return b.EventBus.Subscribe(ctx, eventType, b.wrapHandler(ctx, handler))
+}
+
+// SubscribePattern registers a pattern handler on the wrapped bus, running it
+// through the handler middleware (implements PatternEventBus)
+func (b *MiddlewareBus) SubscribePattern(ctx context.Context, pattern string, handler EventHandler) error {
+	return SubscribePattern(b.EventBus, ctx, pattern, b.wrapHandler(ctx, handler))
+}
+
+// SubscribeHandler registers handler on the wrapped bus, running it through
+// the handler middleware, and returns its ID for UnsubscribeHandler
+// (implements HandlerEventBus). It fails when the wrapped bus isn't a
+// HandlerEventBus.
+func (b *MiddlewareBus) SubscribeHandler(ctx context.Context, eventType string, handler EventHandler) (string, error) {
+	hb, ok := b.EventBus.(HandlerEventBus)
+	if !ok {
+		return "", ErrorRegistry.New(ErrSubscriptionFailed).
+			WithDetail("event_type", eventType).
+			WithDetail("reason", "bus does not support removing single handlers")
+	}
+	return hb.SubscribeHandler(ctx, eventType, b.wrapHandler(ctx, handler))
+}
+
+// UnsubscribeHandler removes a handler registered with SubscribeHandler from
+// the wrapped bus (implements HandlerEventBus)
+func (b *MiddlewareBus) UnsubscribeHandler(ctx context.Context, id string) error {
+	if hb, ok := b.EventBus.(HandlerEventBus); ok {
+		return hb.UnsubscribeHandler(ctx, id)
+	}
+	return nil
+}
+
+// Errors returns the wrapped bus's channel of handler failures (implements
+// ErrorReportingEventBus), or nil when it doesn't report them
+func (b *MiddlewareBus) Errors() <-chan HandlerError {
+	if eb, ok := b.EventBus.(ErrorReportingEventBus); ok {
+		return eb.Errors()
+	}
+	return nil
+}
+
+// GetMetrics returns the wrapped bus's metrics (implements MetricsEventBus),
+// or zero metrics when it doesn't keep any
+func (b *MiddlewareBus) GetMetrics() BusMetrics {
+	if mb, ok := b.EventBus.(MetricsEventBus); ok {
+		return mb.GetMetrics()
+	}
+	return BusMetrics{}
+}
+
+// Wait blocks until the wrapped bus's asynchronously dispatched handlers have
+// returned, when it has a Wait method
+func (b *MiddlewareBus) Wait() {
+	if wb, ok := b.EventBus.(interface{ Wait() }); ok {
+		wb.Wait()
+	}
+}
+
+// Flush waits like Wait but gives up when ctx is done, when the wrapped bus
+// has a Flush method
+func (b *MiddlewareBus) Flush(ctx context.Context) error {
+	if fb, ok := b.EventBus.(interface{ Flush(context.Context) error }); ok {
+		return fb.Flush(ctx)
+	}
+	return nil
+}
+
+// Publish runs the publish middleware and then publishes on the wrapped bus
+func (b *MiddlewareBus) Publish(ctx context.Context, event Event) error {
+	return b.publishChain(b.EventBus.Publish)(ctx, event)
+}
+
+// PublishBatch runs the publish middleware for each event, and publishes the
+// events that passed it on the wrapped bus in one batch. The last middleware's
+// next only collects the event, so middleware sees no publish errors and
+// measures no publish time for batches. Events rejected by middleware or
+// failed by the wrapped bus are reported together in a BatchError, indexed
+// into events.
+func (b *MiddlewareBus) PublishBatch(ctx context.Context, events []Event) error {
+	var passed []Event
+	var passedIndex []int
+	var failures []BatchFailure
+	current := 0
+	chain := b.publishChain(func(ctx context.Context, event Event) error {
+		passed = append(passed, event)
+		passedIndex = append(passedIndex, current)
+		return nil
+	})
+
+	for i, event := range events {
+		current = i
+		if err := chain(ctx, event); err != nil {
+			failures = append(failures, BatchFailure{Index: i, Event: event, Err: err})
+		}
+	}
+
+	if len(passed) > 0 {
+		if err := b.EventBus.PublishBatch(ctx, passed); err != nil {
+			if batchFailures := BatchFailures(err); batchFailures != nil {
+				for _, failure := range batchFailures {
+					failure.Index = passedIndex[failure.Index]
+					failures = append(failures, failure)
+				}
+			} else {
+				// The wrapped bus didn't say which events failed
+				for j, event := range passed {
+					failures = append(failures, BatchFailure{Index: passedIndex[j], Event: event, Err: err})
+				}
+			}
+		}
+	}
+	return NewBatchError(len(events), failures)
+}
+
+// publishChain wraps publish in the publish middleware
+func (b *MiddlewareBus) publishChain(publish PublishFunc) PublishFunc {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for i := len(b.publish) - 1; i >= 0; i-- {
+		publish = b.publish[i](publish)
+	}
+	return publish
+}
+
+// wrapHandler adapts handler to a HandlerFunc and runs it through the handler
+// middleware registered at the time of each call
+func (b *MiddlewareBus) wrapHandler(ctx context.Context, handler EventHandler) EventHandler {
+	return func(event Event) error {
+		next := HandlerFunc(func(ctx context.Context, event Event) error {
+			return handler(event)
+		})
+
+		b.mutex.RLock()
+		for i := len(b.handler) - 1; i >= 0; i-- {
+			next = b.handler[i](next)
+		}
+		b.mutex.RUnlock()
+
+		return next(context.WithValue(ctx, eventContextKey{}, event), event)
+	}
+}
+
+// traceIDContextKey carries a trace ID in a context
+type traceIDContextKey struct{}
+
+// ContextWithTraceID returns a copy of ctx carrying traceID, for
+// PropagateTraceID to attach to published events
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored with ContextWithTraceID or,
+// in a handler, by InjectTraceID
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDContextKey{}).(string)
+	return traceID, ok && traceID != ""
+}
+
+// PropagateTraceID returns publish middleware that copies the publisher's
+// trace ID from the context into the event's metadata, unless the event
+// already carries one
+func PropagateTraceID() PublishMiddleware {
+	return func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, event Event) error {
+			if traceID, ok := TraceIDFromContext(ctx); ok {
+				if metadata := event.Metadata(); metadata != nil {
+					if _, exists := metadata[MetadataTraceID]; !exists {
+						metadata[MetadataTraceID] = traceID
+					}
+				}
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// InjectTraceID returns handler middleware that puts the trace ID found in
+// the event's metadata into the handler's context, for TraceIDFromContext
+func InjectTraceID() HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event Event) error {
+			if traceID, ok := event.Metadata()[MetadataTraceID].(string); ok && traceID != "" {
+				ctx = ContextWithTraceID(ctx, traceID)
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// RecoverHandler returns handler middleware that turns a panic in the rest of
+// the chain into an ErrHandlerFailed error, as CallHandler does
+func RecoverHandler() HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event Event) error {
+			return CallHandler(func(event Event) error {
+				return next(ctx, event)
+			}, event)
+		}
+	}
+}
+
+// LogHandlerTiming returns handler middleware that logs how long each handler
+// took with logx, at debug level on success and error level on failure
+func LogHandlerTiming() HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next(ctx, event)
+			if err != nil {
+				logx.Error("Handler for event %s (%s) failed after %s: %v", event.ID(), event.Type(), time.Since(start), err)
+			} else {
+				logx.Debug("Handled event %s (%s) in %s", event.ID(), event.Type(), time.Since(start))
+			}
+			return err
+		}
+	}
+}
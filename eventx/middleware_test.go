@@ -0,0 +1,457 @@
+package eventx_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// callLog records the order middleware and handlers run in
+type callLog struct {
+	mutex sync.Mutex
+	calls []string
+}
+
+func (l *callLog) add(call string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.calls = append(l.calls, call)
+}
+
+func (l *callLog) snapshot() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]string(nil), l.calls...)
+}
+
+func (l *callLog) publish(name string) eventx.PublishMiddleware {
+	return func(next eventx.PublishFunc) eventx.PublishFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			l.add(name + " before")
+			err := next(ctx, event)
+			l.add(name + " after")
+			return err
+		}
+	}
+}
+
+func (l *callLog) handler(name string) eventx.HandlerMiddleware {
+	return func(next eventx.HandlerFunc) eventx.HandlerFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			l.add(name + " before")
+			err := next(ctx, event)
+			l.add(name + " after")
+			return err
+		}
+	}
+}
+
+func newMiddlewareBus(t *testing.T, cfg eventx.BusConfig) *eventx.MiddlewareBus {
+	t.Helper()
+	cfg.EnableLogging = false
+	return eventx.NewMiddlewareBus(eventxmemory.New(cfg))
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	ctx := context.Background()
+	log := &callLog{}
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+
+	// Handlers subscribed before the middleware is added still get it
+	if err := bus.Subscribe(ctx, "order.placed", func(eventx.Event) error {
+		log.add("handler")
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	bus.Use(log.publish("p1"))
+	bus.Use(log.publish("p2"))
+	bus.UseHandler(log.handler("h1"))
+	bus.UseHandler(log.handler("h2"))
+
+	if err := bus.Publish(ctx, eventx.NewEvent("order.placed", 1)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	want := []string{
+		"p1 before", "p2 before",
+		"h1 before", "h2 before", "handler", "h2 after", "h1 after",
+		"p2 after", "p1 after",
+	}
+	if got := log.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestPublishMiddlewareCanRejectEvents(t *testing.T) {
+	ctx := context.Background()
+	errForbidden := errors.New("forbidden")
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+	bus.Use(func(next eventx.PublishFunc) eventx.PublishFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			if event.Type() == "admin.reset" {
+				return errForbidden
+			}
+			return next(ctx, event)
+		}
+	})
+
+	var handled []string
+	for _, eventType := range []string{"admin.reset", "user.login"} {
+		if err := bus.Subscribe(ctx, eventType, func(e eventx.Event) error {
+			handled = append(handled, e.Type())
+			return nil
+		}); err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+	}
+
+	if err := bus.Publish(ctx, eventx.NewEvent("admin.reset", 1)); !errors.Is(err, errForbidden) {
+		t.Errorf("Publish(admin.reset) error = %v, want %v", err, errForbidden)
+	}
+	if err := bus.Publish(ctx, eventx.NewEvent("user.login", 1)); err != nil {
+		t.Errorf("Publish(user.login): %v", err)
+	}
+	if !reflect.DeepEqual(handled, []string{"user.login"}) {
+		t.Errorf("handled = %v, want [user.login]", handled)
+	}
+}
+
+func TestHandlerMiddlewareErrors(t *testing.T) {
+	ctx := context.Background()
+	errDenied := errors.New("denied")
+	errHandler := errors.New("handler failed")
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+
+	// The middleware sees the handler's error and can replace it
+	var seen error
+	bus.UseHandler(func(next eventx.HandlerFunc) eventx.HandlerFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			if event.Type() == "denied" {
+				return errDenied
+			}
+			seen = next(ctx, event)
+			return seen
+		}
+	})
+
+	called := false
+	bus.Subscribe(ctx, "denied", func(eventx.Event) error {
+		called = true
+		return nil
+	})
+	bus.Subscribe(ctx, "failing", func(eventx.Event) error { return errHandler })
+
+	if err := bus.Publish(ctx, eventx.NewEvent("denied", 1)); !errors.Is(err, errDenied) {
+		t.Errorf("Publish(denied) error = %v, want %v", err, errDenied)
+	}
+	if called {
+		t.Error("handler ran although the middleware skipped it")
+	}
+
+	if err := bus.Publish(ctx, eventx.NewEvent("failing", 1)); !errors.Is(err, errHandler) {
+		t.Errorf("Publish(failing) error = %v, want %v", err, errHandler)
+	}
+	if !errors.Is(seen, errHandler) {
+		t.Errorf("middleware saw %v, want %v", seen, errHandler)
+	}
+}
+
+func TestHandlerMiddlewareContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "subscriber")
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+
+	var value any
+	var fromContext eventx.Event
+	bus.UseHandler(func(next eventx.HandlerFunc) eventx.HandlerFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			value = ctx.Value(key{})
+			fromContext, _ = eventx.EventFromContext(ctx)
+			return next(ctx, event)
+		}
+	})
+	if err := eventx.SubscribePattern(bus, ctx, "order.*", func(eventx.Event) error { return nil }); err != nil {
+		t.Fatalf("SubscribePattern: %v", err)
+	}
+
+	event := eventx.NewEvent("order.placed", 1)
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if value != "subscriber" {
+		t.Errorf("context value = %v, want the Subscribe context's", value)
+	}
+	if fromContext == nil || fromContext.ID() != event.ID() {
+		t.Errorf("EventFromContext = %v, want the published event", fromContext)
+	}
+}
+
+func TestPublishMiddlewareCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	log := &callLog{}
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+	bus.Use(log.publish("p1"))
+	bus.Subscribe(context.Background(), "order.placed", func(eventx.Event) error {
+		log.add("handler")
+		return nil
+	})
+
+	// The middleware runs; the bus then refuses to dispatch
+	if err := bus.Publish(ctx, eventx.NewEvent("order.placed", 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Publish error = %v, want context.Canceled", err)
+	}
+	if got, want := log.snapshot(), []string{"p1 before", "p1 after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestMiddlewarePublishBatch(t *testing.T) {
+	ctx := context.Background()
+	errOdd := errors.New("odd")
+	errFour := eventx.ErrorRegistry.New(eventx.ErrHandlerFailed)
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig())
+
+	// Middleware rejects odd numbers; the handler fails on 4. Failures must be
+	// indexed into the original slice.
+	bus.Use(func(next eventx.PublishFunc) eventx.PublishFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			if event.Payload().(int)%2 == 1 {
+				return errOdd
+			}
+			return next(ctx, event)
+		}
+	})
+	var handled []int
+	bus.Subscribe(ctx, "n", func(e eventx.Event) error {
+		n := e.Payload().(int)
+		if n == 4 {
+			return errFour
+		}
+		handled = append(handled, n)
+		return nil
+	})
+
+	events := make([]eventx.Event, 6)
+	for i := range events {
+		events[i] = eventx.NewEvent("n", i)
+	}
+	err := bus.PublishBatch(ctx, events)
+
+	if !reflect.DeepEqual(handled, []int{0, 2}) {
+		t.Errorf("handled = %v, want [0 2]", handled)
+	}
+
+	var got []int
+	for _, failure := range eventx.BatchFailures(err) {
+		got = append(got, failure.Index)
+		switch {
+		case failure.Index == 4 && !errx.IsCode(failure.Err, eventx.ErrHandlerFailed):
+			t.Errorf("event 4 error = %v, want ErrHandlerFailed", failure.Err)
+		case failure.Index != 4 && !errors.Is(failure.Err, errOdd):
+			t.Errorf("event %d error = %v, want %v", failure.Index, failure.Err, errOdd)
+		}
+	}
+	if !reflect.DeepEqual(got, []int{1, 3, 4, 5}) {
+		t.Errorf("failed indexes = %v, want [1 3 4 5]", got)
+	}
+}
+
+func TestMiddlewareBusForwardsCapabilities(t *testing.T) {
+	ctx := context.Background()
+	log := &callLog{}
+	inner := eventxmemory.New(eventx.DefaultBusConfig().WithAsyncDispatch(1))
+	bus := eventx.NewMiddlewareBus(inner).UseHandler(log.handler("h"))
+
+	if bus.Unwrap() != eventx.EventBus(inner) {
+		t.Error("Unwrap didn't return the wrapped bus")
+	}
+
+	id, err := bus.SubscribeHandler(ctx, "job.run", func(eventx.Event) error {
+		log.add("handler")
+		return errors.New("failed")
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandler: %v", err)
+	}
+	if err := bus.Publish(ctx, eventx.NewEvent("job.run", 1)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := bus.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got, want := log.snapshot(), []string{"h before", "handler", "h after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	select {
+	case report := <-bus.Errors():
+		if report.EventType != "job.run" {
+			t.Errorf("reported event type = %s, want job.run", report.EventType)
+		}
+	default:
+		t.Error("no handler error on the forwarded Errors channel")
+	}
+	if metrics := bus.GetMetrics(); metrics.EventsPublished != 1 || metrics.EventsFailed != 1 {
+		t.Errorf("metrics = %+v, want 1 published and 1 failed", metrics)
+	}
+
+	if err := bus.UnsubscribeHandler(ctx, id); err != nil {
+		t.Fatalf("UnsubscribeHandler: %v", err)
+	}
+	bus.Publish(ctx, eventx.NewEvent("job.run", 2))
+	bus.Wait()
+	if got := len(log.snapshot()); got != 3 {
+		t.Errorf("%d calls after UnsubscribeHandler, want none", got-3)
+	}
+}
+
+func TestMiddlewareBusWithoutHandlerSupport(t *testing.T) {
+	bus := eventx.NewMiddlewareBus(plainEventBus{newSubscriptionBus(t)})
+
+	_, err := bus.SubscribeHandler(context.Background(), "job.run", func(eventx.Event) error { return nil })
+	if !errx.IsCode(err, eventx.ErrSubscriptionFailed) {
+		t.Errorf("SubscribeHandler error = %v, want ErrSubscriptionFailed", err)
+	}
+	if ch := bus.Errors(); ch != nil {
+		t.Error("Errors() isn't nil for a bus without error reporting")
+	}
+	if err := bus.Flush(context.Background()); err != nil {
+		t.Errorf("Flush: %v", err)
+	}
+}
+
+// plainEventBus hides every optional capability of the bus it wraps
+type plainEventBus struct {
+	eventx.EventBus
+}
+
+func TestUseAcceptsSeveralMiddleware(t *testing.T) {
+	ctx := context.Background()
+	log := &callLog{}
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig()).
+		Use(log.publish("p1"), log.publish("p2")).
+		UseHandler(log.handler("h1"), log.handler("h2"))
+	bus.Subscribe(ctx, "order.placed", func(eventx.Event) error {
+		log.add("handler")
+		return nil
+	})
+
+	if err := bus.Publish(ctx, eventx.NewEvent("order.placed", 1)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	want := []string{
+		"p1 before", "p2 before",
+		"h1 before", "h2 before", "handler", "h2 after", "h1 after",
+		"p2 after", "p1 after",
+	}
+	if got := log.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestTraceIDPropagation(t *testing.T) {
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig()).
+		Use(eventx.PropagateTraceID()).
+		UseHandler(eventx.InjectTraceID())
+
+	var received []string
+	bus.Subscribe(context.Background(), "order.placed", func(e eventx.Event) error {
+		return nil
+	})
+	bus.UseHandler(func(next eventx.HandlerFunc) eventx.HandlerFunc {
+		return func(ctx context.Context, event eventx.Event) error {
+			traceID, _ := eventx.TraceIDFromContext(ctx)
+			received = append(received, traceID)
+			return next(ctx, event)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		event eventx.Event
+		want  string
+	}{
+		{"from the publisher's context", eventx.ContextWithTraceID(context.Background(), "trace-1"),
+			eventx.NewEvent("order.placed", 1), "trace-1"},
+		{"event's own trace ID wins", eventx.ContextWithTraceID(context.Background(), "trace-2"),
+			withMetadata(eventx.NewEvent("order.placed", 2), eventx.MetadataTraceID, "original"), "original"},
+		{"no trace ID", context.Background(), eventx.NewEvent("order.placed", 3), ""},
+		{"empty trace ID is ignored", eventx.ContextWithTraceID(context.Background(), ""),
+			eventx.NewEvent("order.placed", 4), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			if err := bus.Publish(tt.ctx, tt.event); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+			if len(received) != 1 || received[0] != tt.want {
+				t.Errorf("handler trace IDs = %q, want [%q]", received, tt.want)
+			}
+			if got, _ := tt.event.Metadata()[eventx.MetadataTraceID].(string); got != tt.want {
+				t.Errorf("metadata trace ID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func withMetadata(event eventx.Event, key string, value any) eventx.Event {
+	event.Metadata()[key] = value
+	return event
+}
+
+func TestRecoverHandler(t *testing.T) {
+	ctx := context.Background()
+	log := &callLog{}
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig()).
+		UseHandler(log.handler("outer"), eventx.RecoverHandler())
+	bus.Subscribe(ctx, "job.run", func(eventx.Event) error {
+		panic("boom")
+	})
+
+	err := bus.Publish(ctx, eventx.NewEvent("job.run", 1))
+	if !errx.IsCode(err, eventx.ErrHandlerFailed) {
+		t.Errorf("Publish error = %v, want ErrHandlerFailed", err)
+	}
+	// Middleware outside RecoverHandler sees the error, not the panic
+	if got, want := log.snapshot(), []string{"outer before", "outer after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestLogHandlerTimingPassesResultsThrough(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	bus := newMiddlewareBus(t, eventx.DefaultBusConfig()).UseHandler(eventx.LogHandlerTiming())
+
+	calls := 0
+	bus.Subscribe(ctx, "ok", func(eventx.Event) error {
+		calls++
+		return nil
+	})
+	bus.Subscribe(ctx, "failing", func(eventx.Event) error {
+		calls++
+		return errFailed
+	})
+
+	if err := bus.Publish(ctx, eventx.NewEvent("ok", 1)); err != nil {
+		t.Errorf("Publish(ok): %v", err)
+	}
+	if err := bus.Publish(ctx, eventx.NewEvent("failing", 1)); !errors.Is(err, errFailed) {
+		t.Errorf("Publish(failing) error = %v, want %v", err, errFailed)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
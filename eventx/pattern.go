@@ -0,0 +1,132 @@
+package eventx
+
+import (
+	"context"
+	"strings"
+)
+
+// Pattern wildcards. Event types are dot-separated segments, and a wildcard
+// always stands for whole segments:
+//
+//	user.*        matches user.created, not user or user.email.changed
+//	order.#       matches order, order.placed and order.item.added
+//	*.failed      matches payment.failed, not payment.card.failed
+//	#.failed      matches failed, payment.failed and payment.card.failed
+const (
+	// WildcardSegment matches exactly one segment
+	WildcardSegment = "*"
+
+	// WildcardSegments matches zero or more segments
+	WildcardSegments = "#"
+)
+
+// PatternEventBus extends EventBus with wildcard subscriptions
+type PatternEventBus interface {
+	EventBus
+
+	// SubscribePattern registers a handler for every event type matching
+	// pattern. Handlers of exact subscriptions to the type run first.
+	SubscribePattern(ctx context.Context, pattern string, handler EventHandler) error
+}
+
+// SubscribePattern registers a handler for all event types matching pattern,
+// for example "user.*" or "order.#". It fails with ErrInvalidPattern for
+// malformed patterns and ErrSubscriptionFailed when bus doesn't implement
+// PatternEventBus.
+func SubscribePattern(bus EventBus, ctx context.Context, pattern string, handler EventHandler) error {
+	if err := ValidatePattern(pattern); err != nil {
+		return err
+	}
+
+	pb, ok := bus.(PatternEventBus)
+	if !ok {
+		return ErrorRegistry.New(ErrSubscriptionFailed).
+			WithDetail("pattern", pattern).
+			WithDetail("reason", "bus does not support pattern subscriptions")
+	}
+	return pb.SubscribePattern(ctx, pattern, handler)
+}
+
+// Pattern is a compiled event type pattern. Buses compile patterns once on
+// subscribe and match every published event type against them.
+type Pattern struct {
+	source   string
+	segments []string
+	variable bool // Contains "#", so the number of segments may differ
+}
+
+// CompilePattern checks that pattern has no empty segments and uses wildcards
+// only as whole segments, and compiles it
+func CompilePattern(pattern string) (*Pattern, error) {
+	p := &Pattern{source: pattern, segments: strings.Split(pattern, ".")}
+	for _, segment := range p.segments {
+		switch {
+		case segment == WildcardSegments:
+			p.variable = true
+		case segment == WildcardSegment:
+		case segment == "" || IsPattern(segment):
+			return nil, ErrorRegistry.New(ErrInvalidPattern).
+				WithDetail("pattern", pattern).
+				WithDetail("segment", segment)
+		}
+	}
+	return p, nil
+}
+
+// String returns the pattern as given to CompilePattern
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Match reports whether eventType matches the pattern
+func (p *Pattern) Match(eventType string) bool {
+	segments := strings.Split(eventType, ".")
+	if !p.variable && len(segments) != len(p.segments) {
+		return false
+	}
+	return matchSegments(p.segments, segments)
+}
+
+// ValidatePattern checks that pattern has no empty segments and uses
+// wildcards only as whole segments
+func ValidatePattern(pattern string) error {
+	_, err := CompilePattern(pattern)
+	return err
+}
+
+// IsPattern reports whether s contains a wildcard segment
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, WildcardSegment+WildcardSegments)
+}
+
+// MatchPattern reports whether eventType matches pattern. Compile patterns
+// that are matched repeatedly with CompilePattern instead.
+func MatchPattern(pattern, eventType string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(eventType, "."))
+}
+
+// matchSegments matches event type segments against pattern segments,
+// backtracking over the segments a "#" consumes
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case WildcardSegments:
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		case WildcardSegment:
+			if len(segments) == 0 {
+				return false
+			}
+		default:
+			if len(segments) == 0 || segments[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
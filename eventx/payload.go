@@ -0,0 +1,170 @@
+package eventx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"path"
+)
+
+// Payload encodings recorded in SerializableEvent.Encoding
+const (
+	EncodingGzip = "gzip"
+)
+
+// BlobStore keeps oversized payloads for claim-check mode. fsx.FileSystem
+// implementations, such as the S3 provider, satisfy it.
+type BlobStore interface {
+	WriteFile(ctx context.Context, path string, data []byte) error
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+}
+
+// MarshalEvent serializes an event for a broker, compressing its payload when
+// config.EnableCompression is set and it exceeds CompressionThreshold, and
+// moving it to config.BlobStore when it still exceeds ClaimCheckThreshold.
+// The message then carries only a reference in DataRef.
+func MarshalEvent(ctx context.Context, event Event, config BusConfig) ([]byte, error) {
+	se, err := ToSerializable(event)
+	if err != nil {
+		return nil, err
+	}
+	if err := encodePayload(ctx, se, config); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(se)
+	if err != nil {
+		return nil, ErrorRegistry.New(ErrSerializationFailed).
+			WithCause(err).
+			WithDetail("event_id", event.ID()).
+			WithDetail("event_type", event.Type())
+	}
+	return data, nil
+}
+
+// UnmarshalEvent parses a message produced by MarshalEvent, fetching claim-checked
+// payloads from config.BlobStore and decompressing them, so Data holds plain JSON.
+// Consumers must use the same blob store as the publisher.
+func UnmarshalEvent(ctx context.Context, data []byte, config BusConfig) (*SerializableEvent, error) {
+	var se SerializableEvent
+	if err := json.Unmarshal(data, &se); err != nil {
+		return nil, ErrorRegistry.New(ErrSerializationFailed).
+			WithCause(err).
+			WithDetail("operation", "unmarshal_serializable_event")
+	}
+	if err := decodePayload(ctx, &se, config); err != nil {
+		return nil, err
+	}
+	return &se, nil
+}
+
+// encodePayload compresses and claim-checks se.Data in place
+func encodePayload(ctx context.Context, se *SerializableEvent, config BusConfig) error {
+	data := []byte(se.Data)
+
+	if config.EnableCompression && len(data) > config.CompressionThreshold {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write(data)
+		if err == nil {
+			err = zw.Close()
+		}
+		if err != nil {
+			return ErrorRegistry.New(ErrSerializationFailed).
+				WithCause(err).
+				WithDetail("event_id", se.ID).
+				WithDetail("reason", "failed to compress payload")
+		}
+		data = buf.Bytes()
+		se.Encoding = EncodingGzip
+	}
+
+	if config.ClaimCheckThreshold > 0 && len(data) > config.ClaimCheckThreshold {
+		if config.BlobStore == nil {
+			return ErrorRegistry.New(ErrInvalidConfiguration).
+				WithDetail("event_id", se.ID).
+				WithDetail("reason", "ClaimCheckThreshold is set but BlobStore is nil")
+		}
+
+		ref := path.Join(config.BlobPrefix, se.Type, se.ID)
+		if err := config.BlobStore.WriteFile(ctx, ref, data); err != nil {
+			return ErrorRegistry.New(ErrClaimCheckFailed).
+				WithCause(err).
+				WithDetail("event_id", se.ID).
+				WithDetail("ref", ref)
+		}
+		se.Data = nil
+		se.DataRef = ref
+		return nil
+	}
+
+	if se.Encoding == EncodingGzip {
+		// Binary data travels as a base64 JSON string
+		encoded, _ := json.Marshal(base64.StdEncoding.EncodeToString(data))
+		se.Data = encoded
+	}
+	return nil
+}
+
+// decodePayload reverses encodePayload, leaving plain JSON in se.Data
+func decodePayload(ctx context.Context, se *SerializableEvent, config BusConfig) error {
+	var data []byte
+
+	switch {
+	case se.DataRef != "":
+		if config.BlobStore == nil {
+			return ErrorRegistry.New(ErrInvalidConfiguration).
+				WithDetail("event_id", se.ID).
+				WithDetail("reason", "event payload is claim-checked but BlobStore is nil")
+		}
+		stored, err := config.BlobStore.ReadFile(ctx, se.DataRef)
+		if err != nil {
+			return ErrorRegistry.New(ErrClaimCheckFailed).
+				WithCause(err).
+				WithDetail("event_id", se.ID).
+				WithDetail("ref", se.DataRef)
+		}
+		data = stored
+
+	case se.Encoding == EncodingGzip:
+		var encoded string
+		if err := json.Unmarshal(se.Data, &encoded); err != nil {
+			return payloadDecodeError(se, err)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return payloadDecodeError(se, err)
+		}
+		data = decoded
+
+	default:
+		return nil
+	}
+
+	if se.Encoding == EncodingGzip {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return payloadDecodeError(se, err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(zr); err != nil {
+			return payloadDecodeError(se, err)
+		}
+	}
+
+	se.Data = json.RawMessage(data)
+	se.Encoding = ""
+	se.DataRef = ""
+	return nil
+}
+
+func payloadDecodeError(se *SerializableEvent, err error) error {
+	return ErrorRegistry.New(ErrSerializationFailed).
+		WithCause(err).
+		WithDetail("event_id", se.ID).
+		WithDetail("encoding", se.Encoding).
+		WithDetail("reason", "failed to decode payload")
+}
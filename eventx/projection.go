@@ -0,0 +1,280 @@
+package eventx
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Projection builds a read model from events. Events are applied in sequence
+// order and each one at most once per position.
+type Projection interface {
+	// Apply updates the read model with an event
+	Apply(ctx context.Context, event Event) error
+
+	// Position returns the sequence number of the last event the read model
+	// contains, or 0 when it is empty. Projections that persist their position
+	// atomically with the read model let the runner resume exactly.
+	Position() uint64
+}
+
+// ResettableProjection is a Projection that can be cleared for a rebuild
+type ResettableProjection interface {
+	Projection
+
+	// Reset empties the read model and sets Position back to 0
+	Reset(ctx context.Context) error
+}
+
+// CheckpointStore persists how far a named projection has progressed
+type CheckpointStore interface {
+	// Load returns the saved position, or 0 when none was saved
+	Load(ctx context.Context, name string) (uint64, error)
+
+	// Save stores the position of the last applied event
+	Save(ctx context.Context, name string, position uint64) error
+}
+
+// EventReplayer delivers stored events with a sequence number greater than
+// after, in sequence order
+type EventReplayer interface {
+	Replay(ctx context.Context, after uint64, handler EventHandler) error
+}
+
+// ProjectionRunner feeds a Projection from a bus and checkpoints its position.
+// Events must carry sequence numbers (see BusConfig.SequenceScope); use
+// SequenceGlobal when the projection consumes more than one event type.
+// Events at or below the current position are skipped, so redeliveries and
+// replays never apply an event twice.
+type ProjectionRunner struct {
+	name        string
+	projection  Projection
+	bus         EventBus
+	eventTypes  []string
+	checkpoints CheckpointStore
+	replayer    EventReplayer
+
+	mutex    sync.Mutex
+	position uint64
+}
+
+// NewProjectionRunner creates a runner for projection, consuming eventTypes
+// from bus and storing its checkpoint in checkpoints under name
+func NewProjectionRunner(name string, projection Projection, bus EventBus, checkpoints CheckpointStore, eventTypes ...string) *ProjectionRunner {
+	return &ProjectionRunner{
+		name:        name,
+		projection:  projection,
+		bus:         bus,
+		eventTypes:  eventTypes,
+		checkpoints: checkpoints,
+	}
+}
+
+// WithReplayer sets the event history used to catch up on Start and to Rebuild
+func (r *ProjectionRunner) WithReplayer(replayer EventReplayer) *ProjectionRunner {
+	r.replayer = replayer
+	return r
+}
+
+// Start resumes from the saved checkpoint, catches up from the replayer when
+// one is set, and subscribes to the bus for new events
+func (r *ProjectionRunner) Start(ctx context.Context) error {
+	position, err := r.checkpoints.Load(ctx, r.name)
+	if err != nil {
+		return ErrorRegistry.New(ErrHandlerFailed).
+			WithCause(err).
+			WithDetail("projection", r.name).
+			WithDetail("reason", "failed to load checkpoint")
+	}
+	if p := r.projection.Position(); p > position {
+		position = p
+	}
+
+	r.mutex.Lock()
+	r.position = position
+	r.mutex.Unlock()
+
+	if err := r.catchUp(ctx); err != nil {
+		return err
+	}
+
+	for _, eventType := range r.eventTypes {
+		err := r.bus.Subscribe(ctx, eventType, func(event Event) error {
+			return r.apply(ctx, event)
+		})
+		if err != nil {
+			return ErrorRegistry.New(ErrSubscriptionFailed).
+				WithCause(err).
+				WithDetail("projection", r.name).
+				WithDetail("event_type", eventType)
+		}
+	}
+
+	// Pick up events recorded while subscribing
+	return r.catchUp(ctx)
+}
+
+// Rebuild resets the projection and its checkpoint, then replays every event
+// from the replayer. The projection must implement ResettableProjection.
+func (r *ProjectionRunner) Rebuild(ctx context.Context) error {
+	resettable, ok := r.projection.(ResettableProjection)
+	if !ok || r.replayer == nil {
+		return ErrorRegistry.New(ErrInvalidConfiguration).
+			WithDetail("projection", r.name).
+			WithDetail("reason", "rebuild needs a ResettableProjection and a replayer")
+	}
+
+	r.mutex.Lock()
+	err := resettable.Reset(ctx)
+	if err == nil {
+		err = r.checkpoints.Save(ctx, r.name, 0)
+	}
+	if err == nil {
+		r.position = 0
+	}
+	r.mutex.Unlock()
+	if err != nil {
+		return ErrorRegistry.New(ErrHandlerFailed).
+			WithCause(err).
+			WithDetail("projection", r.name).
+			WithDetail("reason", "failed to reset projection")
+	}
+
+	return r.catchUp(ctx)
+}
+
+// Position returns the sequence number of the last applied event
+func (r *ProjectionRunner) Position() uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.position
+}
+
+// catchUp applies the replayer's events after the current position
+func (r *ProjectionRunner) catchUp(ctx context.Context) error {
+	if r.replayer == nil {
+		return nil
+	}
+	return r.replayer.Replay(ctx, r.Position(), func(event Event) error {
+		return r.apply(ctx, event)
+	})
+}
+
+// apply hands an event to the projection unless it was already applied, then
+// checkpoints its sequence number
+func (r *ProjectionRunner) apply(ctx context.Context, event Event) error {
+	seq, ok := Sequence(event)
+	if !ok {
+		return ErrorRegistry.New(ErrInvalidEventType).
+			WithDetail("projection", r.name).
+			WithDetail("event_id", event.ID()).
+			WithDetail("reason", "event has no sequence number")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if seq <= r.position {
+		return nil
+	}
+
+	if err := r.projection.Apply(ctx, event); err != nil {
+		return ErrorRegistry.New(ErrHandlerFailed).
+			WithCause(err).
+			WithDetail("projection", r.name).
+			WithDetail("event_id", event.ID()).
+			WithDetail("sequence", seq)
+	}
+	if err := r.checkpoints.Save(ctx, r.name, seq); err != nil {
+		return ErrorRegistry.New(ErrHandlerFailed).
+			WithCause(err).
+			WithDetail("projection", r.name).
+			WithDetail("reason", "failed to save checkpoint")
+	}
+
+	r.position = seq
+	return nil
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory, for tests and
+// projections that are rebuilt on every start
+type MemoryCheckpointStore struct {
+	mutex     sync.RWMutex
+	positions map[string]uint64
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{positions: make(map[string]uint64)}
+}
+
+// Load returns the saved position for name
+func (s *MemoryCheckpointStore) Load(ctx context.Context, name string) (uint64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.positions[name], nil
+}
+
+// Save stores the position for name
+func (s *MemoryCheckpointStore) Save(ctx context.Context, name string, position uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.positions[name] = position
+	return nil
+}
+
+// MemoryEventLog is an in-memory EventReplayer. Subscribe Append to a bus
+// to record its sequenced events.
+type MemoryEventLog struct {
+	mutex  sync.RWMutex
+	events []Event
+}
+
+// NewMemoryEventLog creates an empty in-memory event log
+func NewMemoryEventLog() *MemoryEventLog {
+	return &MemoryEventLog{}
+}
+
+// Append records an event; events without a sequence number are rejected
+func (l *MemoryEventLog) Append(event Event) error {
+	seq, ok := Sequence(event)
+	if !ok {
+		return ErrorRegistry.New(ErrInvalidEventType).
+			WithDetail("event_id", event.ID()).
+			WithDetail("reason", "event has no sequence number")
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Keep the log sorted even if events arrive out of order
+	i := sort.Search(len(l.events), func(i int) bool {
+		s, _ := Sequence(l.events[i])
+		return s > seq
+	})
+	l.events = append(l.events, nil)
+	copy(l.events[i+1:], l.events[i:])
+	l.events[i] = event
+	return nil
+}
+
+// Replay calls handler for every event after the given sequence number
+func (l *MemoryEventLog) Replay(ctx context.Context, after uint64, handler EventHandler) error {
+	l.mutex.RLock()
+	events := make([]Event, len(l.events))
+	copy(events, l.events)
+	l.mutex.RUnlock()
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if seq, _ := Sequence(event); seq <= after {
+			continue
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
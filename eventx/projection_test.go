@@ -0,0 +1,216 @@
+package eventx_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// counterProjection counts events per type and records the sequence numbers
+// it applied
+type counterProjection struct {
+	mutex    sync.Mutex
+	counts   map[string]int
+	applied  []uint64
+	position uint64
+}
+
+func newCounterProjection() *counterProjection {
+	return &counterProjection{counts: map[string]int{}}
+}
+
+func (p *counterProjection) Apply(ctx context.Context, event eventx.Event) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	seq, _ := eventx.Sequence(event)
+	p.counts[event.Type()]++
+	p.applied = append(p.applied, seq)
+	p.position = seq
+	return nil
+}
+
+func (p *counterProjection) Position() uint64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.position
+}
+
+func (p *counterProjection) Reset(ctx context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.counts = map[string]int{}
+	p.applied = nil
+	p.position = 0
+	return nil
+}
+
+func (p *counterProjection) snapshot() (map[string]int, []uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	counts := map[string]int{}
+	for eventType, n := range p.counts {
+		counts[eventType] = n
+	}
+	return counts, append([]uint64(nil), p.applied...)
+}
+
+// eventStream is a globally sequenced in-memory bus whose events are also
+// recorded in a log
+type eventStream struct {
+	bus eventx.EventBus
+	log *eventx.MemoryEventLog
+}
+
+func newEventStream(t *testing.T, eventTypes ...string) *eventStream {
+	t.Helper()
+
+	cfg := eventx.DefaultBusConfig()
+	cfg.EnableLogging = false
+	cfg.SequenceScope = eventx.SequenceGlobal
+	stream := &eventStream{bus: eventxmemory.New(cfg), log: eventx.NewMemoryEventLog()}
+
+	for _, eventType := range eventTypes {
+		if err := stream.bus.Subscribe(context.Background(), eventType, stream.log.Append); err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+	}
+	return stream
+}
+
+func (s *eventStream) publish(t *testing.T, eventTypes ...string) {
+	t.Helper()
+	for _, eventType := range eventTypes {
+		if err := s.bus.Publish(context.Background(), eventx.NewEvent(eventType, 1)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+}
+
+func sequence(from, to uint64) []uint64 {
+	var seqs []uint64
+	for seq := from; seq <= to; seq++ {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+func TestProjectionRunner(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      []string // published while the runner is running
+		stopped     []string // published while the runner is stopped
+		after       []string // published after the runner restarts
+		wantCounts  map[string]int
+		wantApplied []uint64
+	}{
+		{
+			name:        "builds from the stream",
+			before:      []string{"item.added", "item.added", "item.removed"},
+			wantCounts:  map[string]int{"item.added": 2, "item.removed": 1},
+			wantApplied: sequence(1, 3),
+		},
+		{
+			name:        "resumes from the checkpoint",
+			before:      []string{"item.added", "item.added"},
+			stopped:     []string{"item.removed", "item.added"},
+			after:       []string{"item.added"},
+			wantCounts:  map[string]int{"item.added": 4, "item.removed": 1},
+			wantApplied: sequence(1, 5),
+		},
+		{
+			name:        "restart without new events",
+			before:      []string{"item.added"},
+			wantCounts:  map[string]int{"item.added": 1},
+			wantApplied: sequence(1, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			eventTypes := []string{"item.added", "item.removed"}
+			projection := newCounterProjection()
+			checkpoints := eventx.NewMemoryCheckpointStore()
+
+			// The first run consumes a live bus
+			stream := newEventStream(t, eventTypes...)
+			runner := eventx.NewProjectionRunner("counter", projection, stream.bus, checkpoints, eventTypes...)
+			if err := runner.Start(ctx); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			stream.publish(t, tt.before...)
+
+			// Stopping drops the runner's subscriptions; events published
+			// meanwhile only reach the log
+			for _, eventType := range eventTypes {
+				stream.bus.Unsubscribe(ctx, eventType)
+				stream.bus.Subscribe(ctx, eventType, stream.log.Append)
+			}
+			stream.publish(t, tt.stopped...)
+
+			// The restarted runner replays the log from its checkpoint
+			restarted := eventx.NewProjectionRunner("counter", projection, stream.bus, checkpoints, eventTypes...).
+				WithReplayer(stream.log)
+			if err := restarted.Start(ctx); err != nil {
+				t.Fatalf("restart: %v", err)
+			}
+			stream.publish(t, tt.after...)
+
+			counts, applied := projection.snapshot()
+			if !reflect.DeepEqual(counts, tt.wantCounts) {
+				t.Errorf("counts = %v, want %v", counts, tt.wantCounts)
+			}
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("applied %v, want %v: every event exactly once, in order", applied, tt.wantApplied)
+			}
+
+			want := uint64(len(tt.wantApplied))
+			if got := restarted.Position(); got != want {
+				t.Errorf("Position() = %d, want %d", got, want)
+			}
+			if saved, _ := checkpoints.Load(ctx, "counter"); saved != want {
+				t.Errorf("checkpoint = %d, want %d", saved, want)
+			}
+		})
+	}
+}
+
+func TestProjectionRunnerRebuild(t *testing.T) {
+	ctx := context.Background()
+	eventTypes := []string{"item.added", "item.removed"}
+	stream := newEventStream(t, eventTypes...)
+	projection := newCounterProjection()
+	checkpoints := eventx.NewMemoryCheckpointStore()
+
+	runner := eventx.NewProjectionRunner("counter", projection, stream.bus, checkpoints, eventTypes...).
+		WithReplayer(stream.log)
+	if err := runner.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	stream.publish(t, "item.added", "item.removed", "item.added")
+
+	if err := runner.Rebuild(ctx); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+
+	counts, applied := projection.snapshot()
+	if want := map[string]int{"item.added": 2, "item.removed": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts after rebuild = %v, want %v", counts, want)
+	}
+	if want := sequence(1, 3); !reflect.DeepEqual(applied, want) {
+		t.Errorf("replayed %v, want %v", applied, want)
+	}
+	if runner.Position() != 3 {
+		t.Errorf("Position() = %d, want 3", runner.Position())
+	}
+
+	withoutReplayer := eventx.NewProjectionRunner("counter", projection, stream.bus, checkpoints, eventTypes...)
+	if err := withoutReplayer.Rebuild(ctx); !errx.IsCode(err, eventx.ErrInvalidConfiguration) {
+		t.Errorf("Rebuild without a replayer: err = %v, want ErrInvalidConfiguration", err)
+	}
+}
@@ -0,0 +1,229 @@
+package eventxmemory_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// newDispatchBus returns a connected in-memory bus using cfg, as its concrete
+// type so tests can Flush it
+func newDispatchBus(t *testing.T, cfg eventx.BusConfig) *eventxmemory.MemoryBus {
+	t.Helper()
+	return newTestBus(t, cfg).(*eventxmemory.MemoryBus)
+}
+
+// gate is a handler that blocks until released, counting how many calls run
+// at once
+type gate struct {
+	release chan struct{}
+	started chan struct{}
+	running atomic.Int64
+	peak    atomic.Int64
+}
+
+func newGate() *gate {
+	return &gate{release: make(chan struct{}), started: make(chan struct{}, 64)}
+}
+
+func (g *gate) handler(eventx.Event) error {
+	running := g.running.Add(1)
+	for {
+		peak := g.peak.Load()
+		if running <= peak || g.peak.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	g.started <- struct{}{}
+	<-g.release
+	g.running.Add(-1)
+	return nil
+}
+
+// waitStarted waits until n calls of the gate's handler have started
+func (g *gate) waitStarted(t *testing.T, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-g.started:
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d handler calls started", i, n)
+		}
+	}
+}
+
+func TestSyncDispatchRunsHandlersBeforePublishReturns(t *testing.T) {
+	bus := newDispatchBus(t, testConfig().WithSyncDispatch())
+
+	var (
+		mutex sync.Mutex
+		calls []string
+	)
+	record := func(name string, err error) eventx.EventHandler {
+		return func(eventx.Event) error {
+			mutex.Lock()
+			calls = append(calls, name)
+			mutex.Unlock()
+			return err
+		}
+	}
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	for _, handler := range []eventx.EventHandler{record("a", errFirst), record("b", nil), record("c", errSecond)} {
+		subscribe(t, bus, "order.created", handler)
+	}
+
+	err := bus.Publish(context.Background(), eventx.NewEvent("order.created", 1))
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Publish error = %v, want both handler errors", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(calls) != 3 || calls[0] != "a" || calls[1] != "b" || calls[2] != "c" {
+		t.Errorf("calls = %v, want [a b c] before Publish returned", calls)
+	}
+}
+
+func TestAsyncDispatchReportsErrorsInsteadOfReturning(t *testing.T) {
+	bus := newDispatchBus(t, testConfig().WithAsyncDispatch(0))
+
+	g := newGate()
+	failure := errors.New("boom")
+	subscribe(t, bus, "order.created", g.handler)
+	subscribe(t, bus, "order.created", func(eventx.Event) error { return failure })
+	subscribe(t, bus, "order.created", func(eventx.Event) error { panic("kaboom") })
+
+	if err := bus.Publish(context.Background(), eventx.NewEvent("order.created", 1)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// Publish returned while a handler is still blocked
+	g.waitStarted(t, 1)
+	close(g.release)
+
+	if err := bus.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var reported []error
+	for len(reported) < 2 {
+		select {
+		case report := <-bus.Errors():
+			reported = append(reported, report.Err)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d handler errors, want 2", len(reported))
+		}
+	}
+	var sawFailure, sawPanic bool
+	for _, err := range reported {
+		sawFailure = sawFailure || errors.Is(err, failure)
+		sawPanic = sawPanic || errx.IsCode(err, eventx.ErrHandlerFailed)
+	}
+	if !sawFailure || !sawPanic {
+		t.Errorf("reported errors = %v, want the returned error and the panic", reported)
+	}
+}
+
+func TestAsyncDispatchBoundsWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		events  int
+		peak    int64
+	}{
+		{name: "bounded", workers: 2, events: 5, peak: 2},
+		{name: "unbounded", workers: 0, events: 5, peak: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newDispatchBus(t, testConfig().WithAsyncDispatch(tt.workers))
+			g := newGate()
+			subscribe(t, bus, "job.run", g.handler)
+
+			published := make(chan error, 1)
+			go func() {
+				for i := 0; i < tt.events; i++ {
+					if err := bus.Publish(context.Background(), eventx.NewEvent("job.run", i)); err != nil {
+						published <- err
+						return
+					}
+				}
+				published <- nil
+			}()
+
+			g.waitStarted(t, int(tt.peak))
+			if tt.workers > 0 {
+				select {
+				case err := <-published:
+					t.Fatalf("Publish returned (%v) with every worker busy", err)
+				case <-time.After(20 * time.Millisecond):
+				}
+			}
+
+			close(g.release)
+			if err := <-published; err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+			if err := bus.Flush(context.Background()); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			if peak := g.peak.Load(); peak != tt.peak {
+				t.Errorf("peak concurrent handlers = %d, want %d", peak, tt.peak)
+			}
+		})
+	}
+}
+
+func TestAsyncDispatchCanceledWhileWaitingForWorker(t *testing.T) {
+	bus := newDispatchBus(t, testConfig().WithAsyncDispatch(1))
+	g := newGate()
+	subscribe(t, bus, "job.run", g.handler)
+	defer close(g.release)
+
+	publish(t, bus, "job.run")
+	g.waitStarted(t, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := bus.Publish(ctx, eventx.NewEvent("job.run", 2))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Publish error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFlushGivesUpWhenContextIsDone(t *testing.T) {
+	bus := newDispatchBus(t, testConfig().WithAsyncDispatch(0))
+	g := newGate()
+	subscribe(t, bus, "job.run", g.handler)
+
+	publish(t, bus, "job.run")
+	g.waitStarted(t, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush error = %v, want %v while a handler runs", err, context.DeadlineExceeded)
+	}
+
+	close(g.release)
+	if err := bus.Flush(context.Background()); err != nil {
+		t.Errorf("Flush after the handler returned: %v", err)
+	}
+}
+
+// subscribe registers handler for eventType
+func subscribe(t *testing.T, bus eventx.EventBus, eventType string, handler eventx.EventHandler) {
+	t.Helper()
+
+	if err := bus.Subscribe(context.Background(), eventType, handler); err != nil {
+		t.Fatalf("Subscribe(%s): %v", eventType, err)
+	}
+}
@@ -0,0 +1,39 @@
+package eventxmemory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+// testConfig returns the default bus config without logging
+func testConfig() eventx.BusConfig {
+	cfg := eventx.DefaultBusConfig()
+	cfg.EnableLogging = false
+	return cfg
+}
+
+// newTestBus returns a connected in-memory bus using cfg
+func newTestBus(t *testing.T, cfg eventx.BusConfig) eventx.EventBus {
+	t.Helper()
+
+	bus := eventxmemory.New(cfg)
+	if err := bus.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { bus.Disconnect(context.Background()) })
+	return bus
+}
+
+// publish publishes an event with data 1 for each event type
+func publish(t *testing.T, bus eventx.EventBus, eventTypes ...string) {
+	t.Helper()
+
+	for _, eventType := range eventTypes {
+		if err := bus.Publish(context.Background(), eventx.NewEvent(eventType, 1)); err != nil {
+			t.Fatalf("Publish(%s): %v", eventType, err)
+		}
+	}
+}
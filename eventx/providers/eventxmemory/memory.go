@@ -2,19 +2,45 @@ package eventxmemory
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strconv"
 	"sync"
 
 	"github.com/Abraxas-365/craftable/eventx"
 	"github.com/Abraxas-365/craftable/logx"
 )
 
-// MemoryBus implements EventBus interface using in-memory storage
+// MemoryBus implements EventBus interface using in-memory storage. Publish
+// runs handlers according to BusConfig.DispatchMode; see eventx.DispatchSync
+// and eventx.DispatchAsync for the ordering each mode guarantees.
 type MemoryBus struct {
-	handlers map[string][]eventx.EventHandler
+	handlers map[string][]registeredHandler
+	patterns []patternHandler
+	nextID   uint64
 	filters  map[string][]eventx.EventFilter
 	metrics  eventx.BusMetrics
 	mutex    sync.RWMutex
 	config   eventx.BusConfig
+	errors   *eventx.ErrorChannel
+	sequence *eventx.Sequencer
+	limiter  *eventx.RateLimiter
+	inflight sync.WaitGroup
+	workers  chan struct{} // Free worker slots of DispatchAsync; nil when unbounded
+}
+
+// registeredHandler is a handler with the subscription it belongs to, as
+// registered for one event type and as dispatched
+type registeredHandler struct {
+	info    eventx.SubscriptionInfo
+	handler eventx.EventHandler
+}
+
+// patternHandler is a handler registered with SubscribePattern
+type patternHandler struct {
+	info    eventx.SubscriptionInfo
+	pattern *eventx.Pattern
+	handler eventx.EventHandler
 }
 
 // New creates a new in-memory event bus
@@ -24,11 +50,20 @@ func New(config ...eventx.BusConfig) eventx.EventBus {
 		cfg = config[0]
 	}
 
+	var workers chan struct{}
+	if cfg.DispatchWorkers > 0 {
+		workers = make(chan struct{}, cfg.DispatchWorkers)
+	}
+
 	return &MemoryBus{
-		handlers: make(map[string][]eventx.EventHandler),
+		handlers: make(map[string][]registeredHandler),
 		filters:  make(map[string][]eventx.EventFilter),
 		metrics:  eventx.BusMetrics{ConnectionStatus: true},
 		config:   cfg,
+		errors:   eventx.NewErrorChannel(cfg.ErrorBufferSize),
+		sequence: eventx.NewSequencer(cfg.SequenceScope),
+		limiter:  eventx.NewRateLimiter(cfg.RateLimits),
+		workers:  workers,
 	}
 }
 
@@ -57,29 +92,162 @@ func (mb *MemoryBus) IsConnected() bool {
 
 // Subscribe registers an event handler
 func (mb *MemoryBus) Subscribe(ctx context.Context, eventType string, handler eventx.EventHandler) error {
-	mb.mutex.Lock()
-	defer mb.mutex.Unlock()
+	_, err := mb.subscribe(eventType, handler)
+	return err
+}
 
+// SubscribePattern registers an event handler for every event type matching
+// pattern (implements PatternEventBus)
+func (mb *MemoryBus) SubscribePattern(ctx context.Context, pattern string, handler eventx.EventHandler) error {
+	_, err := mb.subscribePattern(pattern, handler)
+	return err
+}
+
+// SubscribeHandler registers a handler for an event type or pattern and
+// returns its ID for UnsubscribeHandler (implements HandlerEventBus)
+func (mb *MemoryBus) SubscribeHandler(ctx context.Context, eventType string, handler eventx.EventHandler) (string, error) {
+	if eventx.IsPattern(eventType) {
+		return mb.subscribePattern(eventType, handler)
+	}
+	return mb.subscribe(eventType, handler)
+}
+
+// subscribe registers a handler for one event type and returns its ID
+func (mb *MemoryBus) subscribe(eventType string, handler eventx.EventHandler) (string, error) {
+	if err := mb.config.CheckEventType(eventType); err != nil {
+		return "", err
+	}
+
+	mb.mutex.Lock()
 	if !mb.metrics.ConnectionStatus {
-		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+		mb.mutex.Unlock()
+		return "", eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
 	}
 
-	mb.handlers[eventType] = append(mb.handlers[eventType], handler)
+	info := eventx.SubscriptionInfo{HandlerID: mb.newHandlerID(), EventType: eventType}
+	mb.handlers[eventType] = append(mb.handlers[eventType], registeredHandler{info: info, handler: handler})
 	mb.metrics.ActiveSubscribers++
 
 	if mb.config.EnableLogging {
 		logx.Debug("Subscribed to event type: %s, total handlers: %d", eventType, len(mb.handlers[eventType]))
 	}
+	mb.mutex.Unlock()
+
+	mb.config.Lifecycle.Subscribed(info)
+	return info.HandlerID, nil
+}
+
+// subscribePattern registers a pattern handler and returns its ID
+func (mb *MemoryBus) subscribePattern(pattern string, handler eventx.EventHandler) (string, error) {
+	compiled, err := eventx.CompilePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	mb.mutex.Lock()
+	if !mb.metrics.ConnectionStatus {
+		mb.mutex.Unlock()
+		return "", eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	info := eventx.SubscriptionInfo{HandlerID: mb.newHandlerID(), EventType: pattern}
+	mb.patterns = append(mb.patterns, patternHandler{info: info, pattern: compiled, handler: handler})
+	mb.metrics.ActiveSubscribers++
+
+	if mb.config.EnableLogging {
+		logx.Debug("Subscribed to event pattern: %s", pattern)
+	}
+	mb.mutex.Unlock()
+
+	mb.config.Lifecycle.Subscribed(info)
+	return info.HandlerID, nil
+}
 
+// newHandlerID returns the next handler ID; the caller holds the write lock
+func (mb *MemoryBus) newHandlerID() string {
+	mb.nextID++
+	return strconv.FormatUint(mb.nextID, 10)
+}
+
+// UnsubscribeHandler removes the handler registered under id by
+// SubscribeHandler (implements HandlerEventBus)
+func (mb *MemoryBus) UnsubscribeHandler(ctx context.Context, id string) error {
+	if info, removed := mb.removeHandler(id); removed {
+		mb.config.Lifecycle.Unsubscribed(info)
+	}
 	return nil
 }
 
-// Unsubscribe removes handlers for an event type
+// removeHandler removes the handler registered under id, reporting whether
+// there was one
+func (mb *MemoryBus) removeHandler(id string) (eventx.SubscriptionInfo, bool) {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+
+	for i, ph := range mb.patterns {
+		if ph.info.HandlerID == id {
+			mb.patterns = slices.Delete(mb.patterns, i, i+1)
+			mb.metrics.ActiveSubscribers--
+			return ph.info, true
+		}
+	}
+
+	for eventType, handlers := range mb.handlers {
+		for i, h := range handlers {
+			if h.info.HandlerID != id {
+				continue
+			}
+
+			handlers = slices.Delete(handlers, i, i+1)
+			if len(handlers) == 0 {
+				delete(mb.handlers, eventType)
+			} else {
+				mb.handlers[eventType] = handlers
+			}
+			mb.metrics.ActiveSubscribers--
+
+			if mb.config.EnableLogging {
+				logx.Debug("Unsubscribed handler %s from event type: %s", id, eventType)
+			}
+			return h.info, true
+		}
+	}
+
+	return eventx.SubscriptionInfo{}, false
+}
+
+// Unsubscribe removes handlers for an event type, or for a pattern passed
+// to SubscribePattern
 func (mb *MemoryBus) Unsubscribe(ctx context.Context, eventType string) error {
+	for _, info := range mb.removeHandlers(eventType) {
+		mb.config.Lifecycle.Unsubscribed(info)
+	}
+	return nil
+}
+
+// removeHandlers removes every handler of an event type or pattern and
+// returns what was removed
+func (mb *MemoryBus) removeHandlers(eventType string) []eventx.SubscriptionInfo {
 	mb.mutex.Lock()
 	defer mb.mutex.Unlock()
 
+	var removed []eventx.SubscriptionInfo
+	remaining := mb.patterns[:0]
+	for _, ph := range mb.patterns {
+		if ph.pattern.String() != eventType {
+			remaining = append(remaining, ph)
+		} else {
+			removed = append(removed, ph.info)
+		}
+	}
+	mb.metrics.ActiveSubscribers -= len(mb.patterns) - len(remaining)
+	clear(mb.patterns[len(remaining):])
+	mb.patterns = remaining
+
 	if handlers, exists := mb.handlers[eventType]; exists {
+		for _, h := range handlers {
+			removed = append(removed, h.info)
+		}
 		mb.metrics.ActiveSubscribers -= len(handlers)
 		delete(mb.handlers, eventType)
 		delete(mb.filters, eventType)
@@ -89,14 +257,31 @@ func (mb *MemoryBus) Unsubscribe(ctx context.Context, eventType string) error {
 		}
 	}
 
-	return nil
+	return removed
 }
 
-// Publish publishes an event
+// Publish publishes an event. In DispatchSync mode it returns after every
+// handler ran, with the joined errors of failed handlers; in DispatchAsync mode
+// it returns once handlers are started.
 func (mb *MemoryBus) Publish(ctx context.Context, event eventx.Event) error {
+	return mb.publish(ctx, event, false)
+}
+
+// publish dispatches an event to its handlers. When reportErrors is set every
+// handler failure is also sent to the Errors() channel.
+func (mb *MemoryBus) publish(ctx context.Context, event eventx.Event, reportErrors bool) error {
+	if err := mb.config.CheckEventType(event.Type()); err != nil {
+		return err
+	}
+
+	// Exact subscriptions run before matching pattern subscriptions
 	mb.mutex.RLock()
-	handlers := make([]eventx.EventHandler, len(mb.handlers[event.Type()]))
-	copy(handlers, mb.handlers[event.Type()])
+	handlers := slices.Clone(mb.handlers[event.Type()])
+	for _, ph := range mb.patterns {
+		if ph.pattern.Match(event.Type()) {
+			handlers = append(handlers, registeredHandler{info: ph.info, handler: ph.handler})
+		}
+	}
 
 	filters := make([]eventx.EventFilter, len(mb.filters[event.Type()]))
 	copy(filters, mb.filters[event.Type()])
@@ -116,46 +301,151 @@ func (mb *MemoryBus) Publish(ctx context.Context, event eventx.Event) error {
 		}
 	}
 
+	// Throttle dispatch of rate-limited event types
+	if err := mb.limiter.Wait(ctx, event.Type()); err != nil {
+		if eventx.IsRateLimited(err) {
+			mb.mutex.Lock()
+			mb.metrics.EventsThrottled++
+			mb.mutex.Unlock()
+		}
+		return err
+	}
+
+	// Number the event before any handler sees it
+	mb.sequence.Assign(event)
+
+	mb.mutex.Lock()
+	mb.metrics.EventsPublished++
+	mb.mutex.Unlock()
+
+	if mb.config.DispatchMode == eventx.DispatchAsync {
+		return mb.dispatchAsync(ctx, event, handlers)
+	}
+
 	// Execute handlers
-	var lastErr error
+	var errs []error
 	for _, handler := range handlers {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			if err := handler(event); err != nil {
-				mb.mutex.Lock()
-				mb.metrics.EventsFailed++
-				mb.mutex.Unlock()
-
-				if mb.config.EnableLogging {
-					logx.Error("Error handling event %s: %v", event.ID(), err)
-				}
-				lastErr = err
-			} else {
-				mb.mutex.Lock()
-				mb.metrics.EventsProcessed++
-				mb.mutex.Unlock()
+			if err := mb.runHandler(ctx, handler, event, reportErrors); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
 
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// dispatchAsync runs each handler on its own goroutine, reporting failures
+// on the Errors() channel. With DispatchWorkers set it first waits for a free
+// worker, failing with the context's error if ctx is done before one frees up.
+func (mb *MemoryBus) dispatchAsync(ctx context.Context, event eventx.Event, handlers []registeredHandler) error {
+	for _, handler := range handlers {
+		if mb.workers != nil {
+			select {
+			case mb.workers <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		mb.inflight.Add(1)
+		go func(handler registeredHandler) {
+			defer mb.inflight.Done()
+			if mb.workers != nil {
+				defer func() { <-mb.workers }()
+			}
+			mb.runHandler(ctx, handler, event, true)
+		}(handler)
+	}
+	return nil
+}
+
+// runHandler calls handler, recovering panics and retrying failures per
+// BusConfig.HandlerRetry, between the OnHandlerStart and OnHandlerEnd hooks,
+// and records the outcome in the metrics. A final
+// failure goes to the dead-letter sink, and also to Errors() when
+// reportErrors is set.
+func (mb *MemoryBus) runHandler(ctx context.Context, handler registeredHandler, event eventx.Event, reportErrors bool) error {
+	start := mb.config.Lifecycle.HandlerStarted(handler.info, event)
+	attempts, err := eventx.CallHandlerWithRetry(ctx, handler.handler, event, mb.config.HandlerRetry)
+	mb.config.Lifecycle.HandlerEnded(handler.info, event, start, err)
+
 	mb.mutex.Lock()
-	mb.metrics.EventsPublished++
+	if err != nil {
+		mb.metrics.EventsFailed++
+	} else {
+		mb.metrics.EventsProcessed++
+	}
 	mb.mutex.Unlock()
 
-	return lastErr
+	if err != nil {
+		if mb.config.EnableLogging {
+			logx.Error("Error handling event %s after %d attempt(s): %v", event.ID(), attempts, err)
+		}
+		if reportErrors {
+			mb.errors.Report(eventx.NewHandlerError(event, err))
+		}
+		mb.deadLetter(ctx, event, err)
+	}
+	return err
 }
 
-// PublishBatch publishes multiple events
-func (mb *MemoryBus) PublishBatch(ctx context.Context, events []eventx.Event) error {
-	var lastErr error
-	for _, event := range events {
-		if err := mb.Publish(ctx, event); err != nil {
-			lastErr = err
-		}
+// deadLetter forwards a failed event to the configured sink on its own
+// goroutine, so a slow sink never holds up the publisher
+func (mb *MemoryBus) deadLetter(ctx context.Context, event eventx.Event, err error) {
+	sink := mb.config.DeadLetterSink
+	if sink == nil {
+		return
 	}
-	return lastErr
+
+	mb.mutex.Lock()
+	mb.metrics.EventsDeadLettered++
+	mb.mutex.Unlock()
+
+	mb.inflight.Add(1)
+	go func() {
+		defer mb.inflight.Done()
+		eventx.CallDeadLetter(ctx, sink, event, err, mb.config.DeadLetterTimeout)
+	}()
+}
+
+// Wait blocks until every asynchronously dispatched handler, from DispatchAsync
+// mode or PublishAsync, and every dead-letter sink call has returned, e.g. before shutdown or when asserting
+// on handler effects in tests
+func (mb *MemoryBus) Wait() {
+	mb.inflight.Wait()
+}
+
+// Flush waits like Wait, but gives up when ctx is done, returning its error;
+// use it to bound how long shutdown waits for pending handlers
+func (mb *MemoryBus) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		mb.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PublishBatch publishes events one at a time in slice order, each exactly
+// as Publish would: with DispatchSync an event's handlers finish before the
+// next event is dispatched. Failed events, including those whose handlers
+// failed, don't stop the batch and are reported per event in an
+// eventx.BatchError.
+func (mb *MemoryBus) PublishBatch(ctx context.Context, events []eventx.Event) error {
+	return eventx.PublishEach(ctx, events, mb.Publish)
 }
 
 // AddFilter adds a filter for an event type
@@ -174,23 +464,39 @@ func (mb *MemoryBus) RemoveFilter(eventType string) error {
 	return nil
 }
 
-// ListEventTypes returns all registered event types
+// ListEventTypes returns all registered event types, followed by the
+// patterns subscribed with SubscribePattern
 func (mb *MemoryBus) ListEventTypes() []string {
 	mb.mutex.RLock()
 	defer mb.mutex.RUnlock()
 
-	types := make([]string, 0, len(mb.handlers))
+	types := make([]string, 0, len(mb.handlers)+len(mb.patterns))
 	for eventType := range mb.handlers {
 		types = append(types, eventType)
 	}
+	for _, ph := range mb.patterns {
+		if !slices.Contains(types, ph.pattern.String()) {
+			types = append(types, ph.pattern.String())
+		}
+	}
 	return types
 }
 
-// HandlerCount returns the number of handlers for an event type
+// HandlerCount returns the number of handlers a Publish of eventType runs,
+// pattern handlers matching it included. Given a pattern, it returns the
+// number of handlers subscribed to that pattern.
 func (mb *MemoryBus) HandlerCount(eventType string) int {
 	mb.mutex.RLock()
 	defer mb.mutex.RUnlock()
-	return len(mb.handlers[eventType])
+
+	count := len(mb.handlers[eventType])
+	pattern := eventx.IsPattern(eventType)
+	for _, ph := range mb.patterns {
+		if pattern && ph.pattern.String() == eventType || !pattern && ph.pattern.Match(eventType) {
+			count++
+		}
+	}
+	return count
 }
 
 // Health returns the health status
@@ -208,10 +514,21 @@ func (mb *MemoryBus) GetMetrics() eventx.BusMetrics {
 	return mb.metrics
 }
 
+// Errors returns the channel of asynchronous handler failures (implements ErrorReportingEventBus)
+func (mb *MemoryBus) Errors() <-chan eventx.HandlerError {
+	return mb.errors.C()
+}
+
 // PublishAsync publishes an event asynchronously (implements AsyncEventBus)
 func (mb *MemoryBus) PublishAsync(ctx context.Context, event eventx.Event) error {
+	if err := mb.config.CheckEventType(event.Type()); err != nil {
+		return err
+	}
+
+	mb.inflight.Add(1)
 	go func() {
-		if err := mb.Publish(ctx, event); err != nil && mb.config.EnableLogging {
+		defer mb.inflight.Done()
+		if err := mb.publish(ctx, event, true); err != nil && mb.config.EnableLogging {
 			logx.Error("Async publish error for event %s: %v", event.ID(), err)
 		}
 	}()
@@ -220,9 +537,23 @@ func (mb *MemoryBus) PublishAsync(ctx context.Context, event eventx.Event) error
 
 // PublishBatchAsync publishes multiple events asynchronously (implements AsyncEventBus)
 func (mb *MemoryBus) PublishBatchAsync(ctx context.Context, events []eventx.Event) error {
+	for _, event := range events {
+		if err := mb.config.CheckEventType(event.Type()); err != nil {
+			return err
+		}
+	}
+
+	mb.inflight.Add(1)
 	go func() {
-		if err := mb.PublishBatch(ctx, events); err != nil && mb.config.EnableLogging {
-			logx.Error("Async batch publish error: %v", err)
+		defer mb.inflight.Done()
+		var lastErr error
+		for _, event := range events {
+			if err := mb.publish(ctx, event, true); err != nil {
+				lastErr = err
+			}
+		}
+		if lastErr != nil && mb.config.EnableLogging {
+			logx.Error("Async batch publish error: %v", lastErr)
 		}
 	}()
 	return nil
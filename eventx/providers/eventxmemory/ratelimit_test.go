@@ -0,0 +1,119 @@
+package eventxmemory_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+func TestRateLimit(t *testing.T) {
+	const burst = 15
+
+	tests := []struct {
+		name          string
+		cfg           eventx.BusConfig
+		wantDelivered int64
+		wantThrottled int64
+		minElapsed    time.Duration
+	}{
+		{
+			// 5 events pass at once, the other 10 wait for tokens at 20/s
+			name:          "blocking limit paces the burst",
+			cfg:           testConfig().WithRateLimit("report.requested", 20, 5),
+			wantDelivered: burst,
+			minElapsed:    400 * time.Millisecond,
+		},
+		{
+			name:          "shedding limit rejects the excess",
+			cfg:           testConfig().WithSheddingRateLimit("report.requested", 1, 5),
+			wantDelivered: 5,
+			wantThrottled: burst - 5,
+		},
+		{
+			name:          "limits on other event types don't apply",
+			cfg:           testConfig().WithSheddingRateLimit("page.viewed", 1, 1),
+			wantDelivered: burst,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newTestBus(t, tt.cfg)
+
+			var reports atomic.Int64
+			subscribeCounter(t, bus, "report.requested", &reports)
+
+			start := time.Now()
+			var throttled int64
+			for range burst {
+				err := bus.Publish(context.Background(), eventx.NewEvent("report.requested", 1))
+				switch {
+				case eventx.IsRateLimited(err):
+					throttled++
+				case err != nil:
+					t.Fatalf("Publish: %v", err)
+				}
+			}
+			elapsed := time.Since(start)
+
+			if got := reports.Load(); got != tt.wantDelivered {
+				t.Errorf("delivered %d events, want %d", got, tt.wantDelivered)
+			}
+			if throttled != tt.wantThrottled {
+				t.Errorf("%d events rate limited, want %d", throttled, tt.wantThrottled)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("burst dispatched in %v, want at least %v", elapsed, tt.minElapsed)
+			}
+			if metrics := bus.(eventx.MetricsEventBus).GetMetrics(); metrics.EventsThrottled != tt.wantThrottled {
+				t.Errorf("EventsThrottled = %d, want %d", metrics.EventsThrottled, tt.wantThrottled)
+			}
+
+			// An unlimited event type is dispatched right away
+			start = time.Now()
+			for range 50 {
+				publish(t, bus, "audit.logged")
+			}
+			if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+				t.Errorf("unlimited event type took %v, want it unthrottled", elapsed)
+			}
+		})
+	}
+}
+
+func TestRateLimitCancelledWhileWaiting(t *testing.T) {
+	bus := newTestBus(t, testConfig().WithRateLimit("report.requested", 0.1, 1))
+	publish(t, bus, "report.requested")
+
+	// The next token is ten seconds away
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := bus.Publish(ctx, eventx.NewEvent("report.requested", 1))
+	if err == nil {
+		t.Fatal("Publish succeeded, want it to give up when the context is done")
+	}
+	if eventx.IsRateLimited(err) {
+		t.Errorf("err = %v, want a cancellation rather than a shed event", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Publish returned after %v, want it to stop waiting on cancellation", elapsed)
+	}
+}
+
+// subscribeCounter counts the events of eventType delivered to a handler
+func subscribeCounter(t *testing.T, bus eventx.EventBus, eventType string, count *atomic.Int64) {
+	t.Helper()
+
+	err := bus.Subscribe(context.Background(), eventType, func(eventx.Event) error {
+		count.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe(%s): %v", eventType, err)
+	}
+}
@@ -0,0 +1,108 @@
+package eventxmemory_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+func TestSequenceNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope eventx.SequenceScope
+		want  map[string][]uint64
+	}{
+		{
+			name:  "per type",
+			scope: eventx.SequencePerType,
+			want:  map[string][]uint64{"order.created": {1, 2, 3}, "order.paid": {1, 2}},
+		},
+		{
+			name:  "global",
+			scope: eventx.SequenceGlobal,
+			want:  map[string][]uint64{"order.created": {1, 3, 4}, "order.paid": {2, 5}},
+		},
+		{
+			name:  "disabled",
+			scope: eventx.SequenceDisabled,
+			want:  map[string][]uint64{"order.created": {0, 0, 0}, "order.paid": {0, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.SequenceScope = tt.scope
+			bus := newTestBus(t, cfg)
+
+			got := map[string][]uint64{}
+			for eventType := range tt.want {
+				err := bus.Subscribe(context.Background(), eventType, func(e eventx.Event) error {
+					seq, ok := eventx.Sequence(e)
+					if ok != (tt.scope != eventx.SequenceDisabled) {
+						t.Errorf("Sequence() ok = %v with scope %q", ok, tt.scope)
+					}
+					got[e.Type()] = append(got[e.Type()], seq)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Subscribe: %v", err)
+				}
+			}
+
+			publish(t, bus, "order.created", "order.paid", "order.created", "order.created", "order.paid")
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sequences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceNumbersConcurrentPublish(t *testing.T) {
+	const publishers, perPublisher = 8, 50
+
+	cfg := testConfig()
+	cfg.SequenceScope = eventx.SequencePerType
+	bus := newTestBus(t, cfg)
+
+	var mutex sync.Mutex
+	var seen []uint64
+	err := bus.Subscribe(context.Background(), "tick", func(e eventx.Event) error {
+		seq, _ := eventx.Sequence(e)
+		mutex.Lock()
+		seen = append(seen, seq)
+		mutex.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perPublisher {
+				bus.Publish(context.Background(), eventx.NewEvent("tick", 1))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every number is assigned exactly once, without gaps
+	sort.Slice(seen, func(i, j int) bool { return seen[i] < seen[j] })
+	if len(seen) != publishers*perPublisher {
+		t.Fatalf("handled %d events, want %d", len(seen), publishers*perPublisher)
+	}
+	for i, seq := range seen {
+		if seq != uint64(i+1) {
+			t.Fatalf("sequence %d is %d; numbers must be unique and gapless", i, seq)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package eventxmemory_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+// plainBus hides the memory bus's handler methods, so SubscribeHandle falls
+// back to guarding the handler
+type plainBus struct {
+	eventx.EventBus
+}
+
+func TestSubscribeHandleStopsAfterUnsubscribe(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(eventx.EventBus) eventx.EventBus
+	}{
+		{"handler bus", func(bus eventx.EventBus) eventx.EventBus { return bus }},
+		{"plain bus", func(bus eventx.EventBus) eventx.EventBus { return plainBus{bus} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			bus := tt.wrap(newTestBus(t, testConfig()))
+
+			var kept, removed atomic.Int32
+			if _, err := eventx.SubscribeHandle(bus, ctx, "order.shipped", func(eventx.Event) error {
+				kept.Add(1)
+				return nil
+			}); err != nil {
+				t.Fatalf("SubscribeHandle: %v", err)
+			}
+			sub, err := eventx.SubscribeHandle(bus, ctx, "order.shipped", func(eventx.Event) error {
+				removed.Add(1)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("SubscribeHandle: %v", err)
+			}
+
+			publish(t, bus, "order.shipped")
+			if err := sub.Unsubscribe(); err != nil {
+				t.Fatalf("Unsubscribe: %v", err)
+			}
+			publish(t, bus, "order.shipped", "order.shipped")
+
+			if got := removed.Load(); got != 1 {
+				t.Errorf("unsubscribed handler calls = %d, want 1", got)
+			}
+			if got := kept.Load(); got != 3 {
+				t.Errorf("other handler calls = %d, want 3", got)
+			}
+			if err := sub.Close(); err != nil {
+				t.Errorf("second Close: %v", err)
+			}
+		})
+	}
+}
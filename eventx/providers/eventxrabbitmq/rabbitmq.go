@@ -0,0 +1,799 @@
+package eventxrabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/logx"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeliveryMode controls whether the broker writes published events to disk
+type DeliveryMode uint8
+
+const (
+	// Persistent events survive a broker restart on durable queues
+	Persistent DeliveryMode = DeliveryMode(amqp.Persistent)
+	// Transient events are kept in memory only, trading durability for throughput
+	Transient DeliveryMode = DeliveryMode(amqp.Transient)
+)
+
+// RabbitMQBus implements EventBus interface using a RabbitMQ topic exchange
+type RabbitMQBus struct {
+	options   Options
+	conn      *amqp.Connection
+	ownsConn  bool // Set once the bus redialed, so Disconnect closes its own connection
+	channel   *amqp.Channel
+	ready     chan struct{} // Closed while channel is usable; replaced when it drops
+	done      chan struct{} // Closed by Disconnect to stop reconnecting
+	handlers  map[string][]registeredHandler
+	nextID    uint64
+	filters   map[string][]eventx.EventFilter
+	consumers map[string]string // Consumer tag per event type
+	metrics   eventx.BusMetrics
+	mutex     sync.RWMutex
+	connected bool
+	errors    *eventx.ErrorChannel
+	sequence  *eventx.Sequencer
+	limiter   *eventx.RateLimiter
+}
+
+// registeredHandler is a handler with the ID SubscribeHandler returned for it
+type registeredHandler struct {
+	id      string
+	handler eventx.EventHandler
+}
+
+// Options configures a RabbitMQ event bus
+type Options struct {
+	eventx.BusConfig
+
+	Exchange     string        // Topic exchange events are published to
+	QueuePrefix  string        // Queues are named <prefix>.<event type>
+	DeliveryMode DeliveryMode  // Persistent or Transient
+	RetryDelay   time.Duration // Wait between attempts to reopen a dropped channel
+}
+
+// Option configures a RabbitMQ event bus
+type Option func(*Options)
+
+// DefaultOptions returns default RabbitMQ options
+func DefaultOptions() Options {
+	config := eventx.DefaultBusConfig()
+	return Options{
+		BusConfig:    config,
+		Exchange:     "eventx",
+		QueuePrefix:  "eventx",
+		DeliveryMode: Persistent,
+		RetryDelay:   time.Duration(config.ReconnectDelay) * time.Second,
+	}
+}
+
+// WithBusConfig sets the common bus configuration
+func WithBusConfig(config eventx.BusConfig) Option {
+	return func(o *Options) {
+		o.BusConfig = config
+		o.RetryDelay = time.Duration(config.ReconnectDelay) * time.Second
+	}
+}
+
+// WithExchange sets the topic exchange events are published to
+func WithExchange(name string) Option {
+	return func(o *Options) {
+		o.Exchange = name
+	}
+}
+
+// WithQueuePrefix sets the queue name prefix. Buses sharing a prefix compete
+// for events; give each service its own prefix so every service receives them.
+func WithQueuePrefix(prefix string) Option {
+	return func(o *Options) {
+		o.QueuePrefix = prefix
+	}
+}
+
+// WithDeliveryMode chooses between Persistent and Transient delivery.
+// Transient buses also declare their exchange and queues non-durable.
+func WithDeliveryMode(mode DeliveryMode) Option {
+	return func(o *Options) {
+		o.DeliveryMode = mode
+	}
+}
+
+// WithRetryDelay sets the wait between attempts to reopen a dropped channel
+func WithRetryDelay(delay time.Duration) Option {
+	return func(o *Options) {
+		o.RetryDelay = delay
+	}
+}
+
+// New creates a new RabbitMQ event bus on an open connection. Events are
+// published to a topic exchange with their type as routing key, and each
+// subscribed event type consumes from a queue bound to that key.
+//
+// When the channel drops, the bus reopens it and resumes its consumers. If
+// the connection itself is closed, the bus redials BusConfig.URL when it is
+// set. BusConfig.MaxReconnects bounds the attempts; 0 retries forever.
+func New(conn *amqp.Connection, opts ...Option) eventx.EventBus {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &RabbitMQBus{
+		options:   options,
+		conn:      conn,
+		handlers:  make(map[string][]registeredHandler),
+		filters:   make(map[string][]eventx.EventFilter),
+		consumers: make(map[string]string),
+		errors:    eventx.NewErrorChannel(options.ErrorBufferSize),
+		sequence:  eventx.NewSequencer(options.SequenceScope),
+		limiter:   eventx.NewRateLimiter(options.RateLimits),
+	}
+}
+
+// Connect opens a channel and declares the exchange
+func (rb *RabbitMQBus) Connect(ctx context.Context) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.connected {
+		return nil
+	}
+	if rb.conn == nil {
+		return eventx.ErrorRegistry.New(eventx.ErrInvalidConfiguration).
+			WithDetail("reason", "connection is nil")
+	}
+
+	ch, err := rb.openChannel()
+	if err != nil {
+		return err
+	}
+
+	rb.channel = ch
+	rb.ready = make(chan struct{})
+	close(rb.ready)
+	rb.done = make(chan struct{})
+	rb.connected = true
+	rb.metrics.ConnectionStatus = true
+	go rb.watch(ch, rb.done)
+
+	if rb.options.EnableLogging {
+		logx.Debug("Connected to RabbitMQ exchange: %s", rb.options.Exchange)
+	}
+
+	return nil
+}
+
+// Disconnect stops consumers and closes the channel. The connection passed
+// to New is left open for its owner to close.
+func (rb *RabbitMQBus) Disconnect(ctx context.Context) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if !rb.connected {
+		return nil
+	}
+
+	close(rb.done)
+	if rb.channel != nil {
+		for eventType, tag := range rb.consumers {
+			if err := rb.channel.Cancel(tag, false); err != nil && rb.options.EnableLogging {
+				logx.Error("Error stopping consumer for event type %s: %v", eventType, err)
+			}
+		}
+		rb.channel.Close()
+	}
+	if rb.ownsConn {
+		rb.conn.Close()
+	}
+
+	rb.consumers = make(map[string]string)
+	rb.channel = nil
+	rb.connected = false
+	rb.metrics.ConnectionStatus = false
+
+	if rb.options.EnableLogging {
+		logx.Debug("Disconnecting from RabbitMQ")
+	}
+
+	return nil
+}
+
+// IsConnected returns connection status
+func (rb *RabbitMQBus) IsConnected() bool {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.connected
+}
+
+// openChannel opens a channel in confirm mode and declares the exchange
+func (rb *RabbitMQBus) openChannel() (*amqp.Channel, error) {
+	ch, err := rb.conn.Channel()
+	if err != nil {
+		return nil, eventx.ErrorRegistry.New(eventx.ErrConnectionFailed).
+			WithCause(err).
+			WithDetail("operation", "open_channel")
+	}
+
+	if err := rb.setupChannel(ch); err != nil {
+		ch.Close()
+		return nil, eventx.ErrorRegistry.New(eventx.ErrConnectionFailed).
+			WithCause(err).
+			WithDetail("exchange", rb.options.Exchange)
+	}
+
+	return ch, nil
+}
+
+func (rb *RabbitMQBus) setupChannel(ch *amqp.Channel) error {
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+	if rb.options.PrefetchCount > 0 {
+		if err := ch.Qos(rb.options.PrefetchCount, 0, false); err != nil {
+			return err
+		}
+	}
+	return ch.ExchangeDeclare(rb.options.Exchange, amqp.ExchangeTopic, rb.durable(), false, false, false, nil)
+}
+
+func (rb *RabbitMQBus) durable() bool {
+	return rb.options.DeliveryMode != Transient
+}
+
+// watch waits for ch to close and reopens it unless the bus was disconnected
+func (rb *RabbitMQBus) watch(ch *amqp.Channel, done chan struct{}) {
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	select {
+	case <-done:
+		return
+	case amqpErr := <-closed:
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if rb.options.EnableLogging {
+			logx.Error("RabbitMQ channel closed: %v", amqpErr)
+		}
+		rb.markDown(ch)
+		rb.reconnect(done)
+	}
+}
+
+// markDown makes publishers wait for a new channel if ch is the current one
+func (rb *RabbitMQBus) markDown(ch *amqp.Channel) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.channel != ch || ch == nil {
+		return
+	}
+	rb.channel = nil
+	rb.ready = make(chan struct{})
+	rb.metrics.ConnectionStatus = false
+}
+
+// reconnect reopens the channel, redialing if needed, and resumes consumers
+func (rb *RabbitMQBus) reconnect(done chan struct{}) {
+	for attempt := 1; rb.options.MaxReconnects <= 0 || attempt <= rb.options.MaxReconnects; attempt++ {
+		select {
+		case <-done:
+			return
+		case <-time.After(rb.options.RetryDelay):
+		}
+
+		if rb.conn.IsClosed() {
+			if rb.options.URL == "" {
+				continue
+			}
+			conn, err := amqp.Dial(rb.options.URL)
+			if err != nil {
+				if rb.options.EnableLogging {
+					logx.Error("Reconnect attempt %d to RabbitMQ failed: %v", attempt, err)
+				}
+				continue
+			}
+			rb.mutex.Lock()
+			rb.conn = conn
+			rb.ownsConn = true
+			rb.mutex.Unlock()
+		}
+
+		ch, err := rb.openChannel()
+		if err != nil {
+			if rb.options.EnableLogging {
+				logx.Error("Reconnect attempt %d to RabbitMQ failed: %v", attempt, err)
+			}
+			continue
+		}
+
+		rb.mutex.Lock()
+		select {
+		case <-done:
+			rb.mutex.Unlock()
+			ch.Close()
+			return
+		default:
+		}
+
+		resumed := true
+		for eventType := range rb.handlers {
+			if err := rb.consume(ch, eventType); err != nil {
+				if rb.options.EnableLogging {
+					logx.Error("Failed to resume consumer for event type %s: %v", eventType, err)
+				}
+				resumed = false
+				break
+			}
+		}
+		if !resumed {
+			rb.mutex.Unlock()
+			ch.Close()
+			continue
+		}
+
+		rb.channel = ch
+		close(rb.ready)
+		rb.metrics.ConnectionStatus = true
+		rb.mutex.Unlock()
+
+		go rb.watch(ch, done)
+
+		if rb.options.EnableLogging {
+			logx.Debug("Reconnected to RabbitMQ after %d attempt(s)", attempt)
+		}
+		return
+	}
+
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.options.EnableLogging {
+		logx.Error("Giving up reconnecting to RabbitMQ after %d attempts", rb.options.MaxReconnects)
+	}
+	rb.connected = false
+	select {
+	case <-rb.ready:
+	default:
+		close(rb.ready) // Wake waiting publishers so they see the bus is disconnected
+	}
+}
+
+// currentChannel returns the open channel, waiting while it is being reopened
+func (rb *RabbitMQBus) currentChannel(ctx context.Context) (*amqp.Channel, error) {
+	for {
+		rb.mutex.RLock()
+		connected, ch, ready := rb.connected, rb.channel, rb.ready
+		rb.mutex.RUnlock()
+
+		if !connected {
+			return nil, eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+		}
+		if ch != nil {
+			return ch, nil
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, eventx.ErrorRegistry.New(eventx.ErrTimeout).
+				WithCause(ctx.Err()).
+				WithDetail("operation", "await_channel")
+		}
+	}
+}
+
+// queueName returns the queue name for an event type
+func (rb *RabbitMQBus) queueName(eventType string) string {
+	return rb.options.QueuePrefix + "." + eventType
+}
+
+// consume declares the queue for eventType, binds it and starts consuming.
+// The caller must hold the mutex.
+func (rb *RabbitMQBus) consume(ch *amqp.Channel, eventType string) error {
+	queueName := rb.queueName(eventType)
+
+	// ExclusiveConsumer only applies to the consumer: an exclusive queue would
+	// be deleted with the connection and couldn't be shared or redeclared
+	queue, err := ch.QueueDeclare(queueName, rb.durable(), false, false, false, nil)
+	if err == nil {
+		err = ch.QueueBind(queue.Name, eventType, rb.options.Exchange, false, nil)
+	}
+	if err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("event_type", eventType).
+			WithDetail("queue", queueName)
+	}
+
+	tag := fmt.Sprintf("%s-%s", rb.options.ConnectionName, eventType)
+	deliveries, err := ch.Consume(queue.Name, tag, false, rb.options.ExclusiveConsumer, false, false, nil)
+	if err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("event_type", eventType).
+			WithDetail("queue", queueName)
+	}
+
+	rb.consumers[eventType] = tag
+	go rb.consumeMessages(eventType, deliveries)
+	return nil
+}
+
+// Subscribe registers an event handler
+func (rb *RabbitMQBus) Subscribe(ctx context.Context, eventType string, handler eventx.EventHandler) error {
+	_, err := rb.SubscribeHandler(ctx, eventType, handler)
+	return err
+}
+
+// SubscribeHandler registers an event handler and returns its ID for
+// UnsubscribeHandler (implements HandlerEventBus)
+func (rb *RabbitMQBus) SubscribeHandler(ctx context.Context, eventType string, handler eventx.EventHandler) (string, error) {
+	if err := rb.options.CheckEventType(eventType); err != nil {
+		return "", err
+	}
+
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if !rb.connected {
+		return "", eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	// While the channel is being reopened, reconnect starts the consumer
+	if _, exists := rb.consumers[eventType]; !exists && rb.channel != nil {
+		if err := rb.consume(rb.channel, eventType); err != nil {
+			return "", err
+		}
+	}
+
+	rb.nextID++
+	id := strconv.FormatUint(rb.nextID, 10)
+	rb.handlers[eventType] = append(rb.handlers[eventType], registeredHandler{id: id, handler: handler})
+	rb.metrics.ActiveSubscribers++
+
+	if rb.options.EnableLogging {
+		logx.Debug("Subscribing to event type: %s (queue: %s)", eventType, rb.queueName(eventType))
+	}
+
+	return id, nil
+}
+
+// UnsubscribeHandler removes the handler registered under id by
+// SubscribeHandler (implements HandlerEventBus). Removing the last handler of
+// an event type stops its consumer like Unsubscribe.
+func (rb *RabbitMQBus) UnsubscribeHandler(ctx context.Context, id string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	for eventType, handlers := range rb.handlers {
+		i := slices.IndexFunc(handlers, func(h registeredHandler) bool { return h.id == id })
+		if i < 0 {
+			continue
+		}
+
+		handlers = slices.Delete(handlers, i, i+1)
+		if len(handlers) == 0 {
+			delete(rb.handlers, eventType)
+			rb.cancelConsumer(eventType)
+		} else {
+			rb.handlers[eventType] = handlers
+		}
+		rb.metrics.ActiveSubscribers--
+
+		if rb.options.EnableLogging {
+			logx.Debug("Unsubscribed handler %s from event type: %s", id, eventType)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// cancelConsumer stops consuming an event type's queue. The caller must hold
+// the mutex.
+func (rb *RabbitMQBus) cancelConsumer(eventType string) {
+	if tag, exists := rb.consumers[eventType]; exists {
+		if rb.channel != nil {
+			if err := rb.channel.Cancel(tag, false); err != nil && rb.options.EnableLogging {
+				logx.Error("Error stopping consumer for event type %s: %v", eventType, err)
+			}
+		}
+		delete(rb.consumers, eventType)
+	}
+}
+
+// consumeMessages dispatches deliveries until the consumer is cancelled or
+// its channel closes
+func (rb *RabbitMQBus) consumeMessages(eventType string, deliveries <-chan amqp.Delivery) {
+	for delivery := range deliveries {
+		rb.processDelivery(eventType, delivery)
+	}
+}
+
+// processDelivery runs the handlers for a delivery and acknowledges it.
+// Handlers are retried per BusConfig.HandlerRetry and a panic fails them like
+// an error. A failed event is requeued once and dropped, or dead-lettered when
+// the queue has a dead letter exchange, when it fails again.
+func (rb *RabbitMQBus) processDelivery(eventType string, delivery amqp.Delivery) {
+	ctx := context.Background()
+
+	// Deserialize event, fetching claim-checked payloads
+	serializableEvent, err := eventx.UnmarshalEvent(ctx, delivery.Body, rb.options.BusConfig)
+	if err != nil {
+		if rb.options.EnableLogging {
+			logx.Error("Failed to deserialize message body: %s, error: %v", delivery.Body, err)
+		}
+		delivery.Nack(false, false)
+		return
+	}
+
+	if serializableEvent.Metadata == nil {
+		serializableEvent.Metadata = make(map[string]any)
+	}
+	event, err := eventx.FromSerializable[json.RawMessage](serializableEvent)
+	if err != nil {
+		if rb.options.EnableLogging {
+			logx.Error("Failed to rebuild event %s: %v", serializableEvent.ID, err)
+		}
+		delivery.Nack(false, false)
+		return
+	}
+
+	// Apply filters
+	rb.mutex.RLock()
+	filters := make([]eventx.EventFilter, len(rb.filters[eventType]))
+	copy(filters, rb.filters[eventType])
+	rb.mutex.RUnlock()
+
+	for _, filter := range filters {
+		if !filter(event) {
+			delivery.Ack(false)
+			return
+		}
+	}
+
+	// Throttle dispatch; shed events go back to the queue
+	if err := rb.limiter.Wait(ctx, eventType); err != nil {
+		if eventx.IsRateLimited(err) {
+			rb.mutex.Lock()
+			rb.metrics.EventsThrottled++
+			rb.mutex.Unlock()
+		}
+		delivery.Nack(false, true)
+		return
+	}
+
+	// Execute handlers
+	rb.mutex.RLock()
+	handlers := slices.Clone(rb.handlers[eventType])
+	rb.mutex.RUnlock()
+
+	success := true
+	for _, h := range handlers {
+		if attempts, err := eventx.CallHandlerWithRetry(ctx, h.handler, event, rb.options.HandlerRetry); err != nil {
+			rb.mutex.Lock()
+			rb.metrics.EventsFailed++
+			rb.mutex.Unlock()
+
+			if rb.options.EnableLogging {
+				logx.Error("Error handling event %s after %d attempt(s): %v", serializableEvent.ID, attempts, err)
+			}
+			rb.errors.Report(eventx.NewHandlerError(event, err))
+			success = false
+		} else {
+			rb.mutex.Lock()
+			rb.metrics.EventsProcessed++
+			rb.mutex.Unlock()
+		}
+	}
+
+	if success {
+		delivery.Ack(false)
+	} else {
+		delivery.Nack(false, !delivery.Redelivered)
+	}
+}
+
+// Publish publishes an event to the exchange with its type as routing key and
+// waits for the broker to confirm it. While the channel is being reopened,
+// Publish waits for it; cancelling ctx abandons the wait.
+func (rb *RabbitMQBus) Publish(ctx context.Context, event eventx.Event) error {
+	if err := rb.options.CheckEventType(event.Type()); err != nil {
+		return err
+	}
+	if !rb.IsConnected() {
+		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	if rb.options.PublishTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(rb.options.PublishTimeout)*time.Second)
+		defer cancel()
+	}
+
+	// Number the event so consumers can order it and detect gaps
+	rb.sequence.Assign(event)
+
+	// Serialize event, compressing or claim-checking large payloads
+	data, err := eventx.MarshalEvent(ctx, event, rb.options.BusConfig)
+	if err != nil {
+		return err
+	}
+
+	headers := amqp.Table{}
+	for key, value := range event.Metadata() {
+		if strValue, ok := value.(string); ok {
+			headers[key] = strValue
+		}
+	}
+
+	msg := amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: uint8(rb.options.DeliveryMode),
+		MessageId:    event.ID(),
+		Timestamp:    event.Timestamp(),
+		Type:         event.Type(),
+		AppId:        event.Source(),
+		Body:         data,
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return rb.publishFailed(event, eventx.ErrTimeout, err)
+		}
+
+		ch, err := rb.currentChannel(ctx)
+		if err != nil {
+			return err
+		}
+
+		confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, rb.options.Exchange, event.Type(), false, false, msg)
+		if errors.Is(err, amqp.ErrClosed) {
+			// The channel dropped under us; wait for reconnect and retry
+			rb.markDown(ch)
+			continue
+		}
+		if err != nil {
+			return rb.publishFailed(event, eventx.ErrPublishFailed, err)
+		}
+
+		acked, err := confirmation.WaitContext(ctx)
+		if err != nil {
+			return rb.publishFailed(event, eventx.ErrTimeout, err)
+		}
+		if !acked {
+			return rb.publishFailed(event, eventx.ErrPublishFailed, errors.New("broker rejected the event"))
+		}
+		break
+	}
+
+	rb.mutex.Lock()
+	rb.metrics.EventsPublished++
+	rb.mutex.Unlock()
+
+	return nil
+}
+
+func (rb *RabbitMQBus) publishFailed(event eventx.Event, code errx.Code, cause error) error {
+	rb.mutex.Lock()
+	rb.metrics.EventsFailed++
+	rb.mutex.Unlock()
+
+	return eventx.ErrorRegistry.New(code).
+		WithCause(cause).
+		WithDetail("event_id", event.ID()).
+		WithDetail("event_type", event.Type()).
+		WithDetail("exchange", rb.options.Exchange)
+}
+
+// PublishBatch publishes events one at a time in slice order. Nothing is
+// published when an event type isn't declared; otherwise failed events don't
+// stop the batch and are reported per event in an eventx.BatchError.
+func (rb *RabbitMQBus) PublishBatch(ctx context.Context, events []eventx.Event) error {
+	for _, event := range events {
+		if err := rb.options.CheckEventType(event.Type()); err != nil {
+			return err
+		}
+	}
+
+	return eventx.PublishEach(ctx, events, rb.Publish)
+}
+
+// Unsubscribe removes handlers for an event type. Its queue and binding are
+// kept so events published meanwhile wait for the next subscriber.
+func (rb *RabbitMQBus) Unsubscribe(ctx context.Context, eventType string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.cancelConsumer(eventType)
+
+	// Remove handlers
+	if handlers, exists := rb.handlers[eventType]; exists {
+		rb.metrics.ActiveSubscribers -= len(handlers)
+		delete(rb.handlers, eventType)
+		delete(rb.filters, eventType)
+	}
+
+	if rb.options.EnableLogging {
+		logx.Debug("Unsubscribing from event type: %s", eventType)
+	}
+
+	return nil
+}
+
+// AddFilter adds a filter for an event type
+func (rb *RabbitMQBus) AddFilter(eventType string, filter eventx.EventFilter) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	rb.filters[eventType] = append(rb.filters[eventType], filter)
+	return nil
+}
+
+// RemoveFilter removes filters for an event type
+func (rb *RabbitMQBus) RemoveFilter(eventType string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	delete(rb.filters, eventType)
+	return nil
+}
+
+// ListEventTypes returns all registered event types
+func (rb *RabbitMQBus) ListEventTypes() []string {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	types := make([]string, 0, len(rb.handlers))
+	for eventType := range rb.handlers {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// HandlerCount returns the number of handlers for an event type
+func (rb *RabbitMQBus) HandlerCount(eventType string) int {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return len(rb.handlers[eventType])
+}
+
+// Health returns the health status
+func (rb *RabbitMQBus) Health(ctx context.Context) error {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	if !rb.connected {
+		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+	if rb.channel == nil || rb.channel.IsClosed() {
+		return eventx.ErrorRegistry.New(eventx.ErrConnectionFailed).
+			WithDetail("operation", "health_check").
+			WithDetail("reason", "channel is reconnecting")
+	}
+
+	return nil
+}
+
+// GetMetrics returns bus metrics
+func (rb *RabbitMQBus) GetMetrics() eventx.BusMetrics {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.metrics
+}
+
+// Errors returns the channel of consumer handler failures (implements ErrorReportingEventBus).
+func (rb *RabbitMQBus) Errors() <-chan eventx.HandlerError {
+	return rb.errors.C()
+}
@@ -0,0 +1,105 @@
+package eventxrabbitmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// acknowledger records how a delivery was settled
+type acknowledger struct {
+	acked    bool
+	nacked   bool
+	requeued bool
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// newRetryBus returns an unconnected bus retrying handlers twice, with
+// handler registered for "job.run"
+func newRetryBus(t *testing.T, handler eventx.EventHandler) *RabbitMQBus {
+	t.Helper()
+
+	cfg := eventx.DefaultBusConfig().WithHandlerRetry(2, time.Millisecond)
+	cfg.EnableLogging = false
+	rb := New(nil, WithBusConfig(cfg)).(*RabbitMQBus)
+	rb.handlers["job.run"] = []registeredHandler{{id: "h1", handler: handler}}
+	return rb
+}
+
+// delivery returns a delivery of event as Publish would send it
+func delivery(t *testing.T, rb *RabbitMQBus, event eventx.Event, ack *acknowledger) amqp.Delivery {
+	t.Helper()
+
+	data, err := eventx.MarshalEvent(context.Background(), event, rb.options.BusConfig)
+	if err != nil {
+		t.Fatalf("MarshalEvent: %v", err)
+	}
+	return amqp.Delivery{Acknowledger: ack, Body: data, RoutingKey: event.Type()}
+}
+
+func TestProcessDeliveryRetriesHandler(t *testing.T) {
+	calls := 0
+	rb := newRetryBus(t, func(eventx.Event) error {
+		if calls++; calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	ack := &acknowledger{}
+	rb.processDelivery("job.run", delivery(t, rb, eventx.NewEvent("job.run", 1), ack))
+
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+	if !ack.acked || ack.nacked {
+		t.Errorf("delivery settled as %+v, want acked", *ack)
+	}
+}
+
+func TestProcessDeliveryRequeuesAfterExhaustedRetries(t *testing.T) {
+	calls := 0
+	rb := newRetryBus(t, func(eventx.Event) error {
+		calls++
+		panic("boom")
+	})
+
+	ack := &acknowledger{}
+	rb.processDelivery("job.run", delivery(t, rb, eventx.NewEvent("job.run", 1), ack))
+
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+	if ack.acked || !ack.nacked || !ack.requeued {
+		t.Errorf("delivery settled as %+v, want nacked and requeued", *ack)
+	}
+	if rb.metrics.EventsFailed != 1 {
+		t.Errorf("EventsFailed = %d, want 1", rb.metrics.EventsFailed)
+	}
+
+	// A redelivered event that fails again isn't requeued
+	ack = &acknowledger{}
+	redelivered := delivery(t, rb, eventx.NewEvent("job.run", 1), ack)
+	redelivered.Redelivered = true
+	rb.processDelivery("job.run", redelivered)
+	if !ack.nacked || ack.requeued {
+		t.Errorf("redelivery settled as %+v, want nacked without requeue", *ack)
+	}
+}
@@ -0,0 +1,582 @@
+package eventxredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/logx"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus implements EventBus interface using Redis Pub/Sub
+type RedisBus struct {
+	options       Options
+	client        *redis.Client
+	handlers      map[string][]registeredHandler
+	nextID        uint64
+	filters       map[string][]eventx.EventFilter
+	subscriptions map[string]*subscription
+	metrics       eventx.BusMetrics
+	mutex         sync.RWMutex
+	connected     bool
+	errors        *eventx.ErrorChannel
+	sequence      *eventx.Sequencer
+	limiter       *eventx.RateLimiter
+}
+
+// registeredHandler is a handler with the ID SubscribeHandler returned for it
+type registeredHandler struct {
+	id      string
+	handler eventx.EventHandler
+}
+
+// subscription is the Redis channel subscription of one event type
+type subscription struct {
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+}
+
+// Options configures a Redis event bus
+type Options struct {
+	eventx.BusConfig
+
+	ChannelPrefix       string        // Channels are named <prefix>:<event type>
+	HealthCheckInterval time.Duration // Idle time after which a subscription pings Redis
+	RetryDelay          time.Duration // Wait between attempts to restore a dropped subscription
+}
+
+// Option configures a Redis event bus
+type Option func(*Options)
+
+// DefaultOptions returns default Redis options
+func DefaultOptions() Options {
+	config := eventx.DefaultBusConfig()
+	return Options{
+		BusConfig:           config,
+		ChannelPrefix:       "eventx",
+		HealthCheckInterval: 30 * time.Second,
+		RetryDelay:          time.Duration(config.ReconnectDelay) * time.Second,
+	}
+}
+
+// WithBusConfig sets the common bus configuration
+func WithBusConfig(config eventx.BusConfig) Option {
+	return func(o *Options) {
+		o.BusConfig = config
+		o.RetryDelay = time.Duration(config.ReconnectDelay) * time.Second
+	}
+}
+
+// WithChannelPrefix sets the prefix of the Redis channel names
+func WithChannelPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.ChannelPrefix = prefix
+	}
+}
+
+// WithHealthCheckInterval sets how long a subscription may stay idle before
+// it pings Redis to detect a dropped connection
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.HealthCheckInterval = interval
+	}
+}
+
+// WithRetryDelay sets the wait between attempts to restore a dropped subscription
+func WithRetryDelay(delay time.Duration) Option {
+	return func(o *Options) {
+		o.RetryDelay = delay
+	}
+}
+
+// New creates a new Redis Pub/Sub event bus. Each event type is published to
+// its own channel, and each subscribed event type is received by a background
+// goroutine that dispatches to its handlers.
+//
+// Pub/Sub delivers at most once: events published while no subscriber is
+// connected, including while a dropped subscription is being restored, are
+// lost. Use a durable backend when every event must be processed.
+func New(client *redis.Client, opts ...Option) eventx.EventBus {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &RedisBus{
+		options:       options,
+		client:        client,
+		handlers:      make(map[string][]registeredHandler),
+		filters:       make(map[string][]eventx.EventFilter),
+		subscriptions: make(map[string]*subscription),
+		errors:        eventx.NewErrorChannel(options.ErrorBufferSize),
+		sequence:      eventx.NewSequencer(options.SequenceScope),
+		limiter:       eventx.NewRateLimiter(options.RateLimits),
+	}
+}
+
+// Connect checks that Redis is reachable
+func (rb *RedisBus) Connect(ctx context.Context) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.connected {
+		return nil
+	}
+	if rb.client == nil {
+		return eventx.ErrorRegistry.New(eventx.ErrInvalidConfiguration).
+			WithDetail("reason", "client is nil")
+	}
+
+	if err := rb.client.Ping(ctx).Err(); err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrConnectionFailed).
+			WithCause(err).
+			WithDetail("operation", "ping")
+	}
+
+	rb.connected = true
+	rb.metrics.ConnectionStatus = true
+
+	if rb.options.EnableLogging {
+		logx.Debug("Connected to Redis Pub/Sub (channel prefix: %s)", rb.options.ChannelPrefix)
+	}
+
+	return nil
+}
+
+// Disconnect closes every subscription. The client passed to New is left
+// open for its owner to close.
+func (rb *RedisBus) Disconnect(ctx context.Context) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if !rb.connected {
+		return nil
+	}
+
+	for eventType, sub := range rb.subscriptions {
+		sub.close()
+		if rb.options.EnableLogging {
+			logx.Debug("Stopping subscriber for event type: %s", eventType)
+		}
+	}
+	rb.subscriptions = make(map[string]*subscription)
+
+	rb.connected = false
+	rb.metrics.ConnectionStatus = false
+
+	if rb.options.EnableLogging {
+		logx.Debug("Disconnecting from Redis Pub/Sub")
+	}
+
+	return nil
+}
+
+// IsConnected returns connection status
+func (rb *RedisBus) IsConnected() bool {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.connected
+}
+
+// channelName returns the Redis channel name for an event type
+func (rb *RedisBus) channelName(eventType string) string {
+	return rb.options.ChannelPrefix + ":" + eventType
+}
+
+// Subscribe registers an event handler. The first handler of an event type
+// subscribes to its channel and returns an error if Redis doesn't confirm it.
+func (rb *RedisBus) Subscribe(ctx context.Context, eventType string, handler eventx.EventHandler) error {
+	_, err := rb.SubscribeHandler(ctx, eventType, handler)
+	return err
+}
+
+// SubscribeHandler registers an event handler like Subscribe and returns its
+// ID for UnsubscribeHandler (implements HandlerEventBus)
+func (rb *RedisBus) SubscribeHandler(ctx context.Context, eventType string, handler eventx.EventHandler) (string, error) {
+	if err := rb.options.CheckEventType(eventType); err != nil {
+		return "", err
+	}
+
+	for {
+		rb.mutex.RLock()
+		connected := rb.connected
+		_, exists := rb.subscriptions[eventType]
+		rb.mutex.RUnlock()
+
+		if !connected {
+			return "", eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+		}
+
+		// Wait for Redis to confirm a new subscription without holding the
+		// lock, so publishing and dispatch aren't blocked on the round trip
+		var pubsub *redis.PubSub
+		if !exists {
+			var err error
+			if pubsub, err = rb.subscribeChannel(ctx, eventType); err != nil {
+				return "", err
+			}
+		}
+
+		id, registered, err := rb.addHandler(eventType, handler, pubsub)
+		if err != nil || registered {
+			return id, err
+		}
+		// The subscription went away meanwhile; subscribe again
+	}
+}
+
+// subscribeChannel subscribes to the channel of an event type and waits for
+// Redis to confirm it
+func (rb *RedisBus) subscribeChannel(ctx context.Context, eventType string) (*redis.PubSub, error) {
+	channel := rb.channelName(eventType)
+	pubsub := rb.client.Subscribe(ctx, channel)
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("event_type", eventType).
+			WithDetail("channel", channel)
+	}
+	return pubsub, nil
+}
+
+// addHandler registers handler, starting pubsub as the event type's
+// subscription unless a concurrent call already did; an unused pubsub is
+// closed. It reports false, registering nothing, when pubsub is nil and the
+// event type has no subscription anymore.
+func (rb *RedisBus) addHandler(eventType string, handler eventx.EventHandler, pubsub *redis.PubSub) (string, bool, error) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if !rb.connected {
+		if pubsub != nil {
+			pubsub.Close()
+		}
+		return "", false, eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	_, exists := rb.subscriptions[eventType]
+	switch {
+	case exists && pubsub != nil:
+		pubsub.Close()
+	case !exists && pubsub == nil:
+		return "", false, nil
+	case !exists:
+		subscriberCtx, cancel := context.WithCancel(context.Background())
+		rb.subscriptions[eventType] = &subscription{pubsub: pubsub, cancel: cancel}
+		go rb.receiveMessages(subscriberCtx, eventType, pubsub)
+	}
+
+	rb.nextID++
+	id := strconv.FormatUint(rb.nextID, 10)
+	rb.handlers[eventType] = append(rb.handlers[eventType], registeredHandler{id: id, handler: handler})
+	rb.metrics.ActiveSubscribers++
+
+	if rb.options.EnableLogging {
+		logx.Debug("Subscribing to event type: %s (channel: %s)", eventType, rb.channelName(eventType))
+	}
+
+	return id, true, nil
+}
+
+// UnsubscribeHandler removes the handler registered under id by
+// SubscribeHandler (implements HandlerEventBus). Removing the last handler of
+// an event type closes its subscription like Unsubscribe.
+func (rb *RedisBus) UnsubscribeHandler(ctx context.Context, id string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	for eventType, handlers := range rb.handlers {
+		i := slices.IndexFunc(handlers, func(h registeredHandler) bool { return h.id == id })
+		if i < 0 {
+			continue
+		}
+
+		handlers = slices.Delete(handlers, i, i+1)
+		if len(handlers) == 0 {
+			delete(rb.handlers, eventType)
+			if sub, exists := rb.subscriptions[eventType]; exists {
+				sub.close()
+				delete(rb.subscriptions, eventType)
+			}
+		} else {
+			rb.handlers[eventType] = handlers
+		}
+		rb.metrics.ActiveSubscribers--
+
+		if rb.options.EnableLogging {
+			logx.Debug("Unsubscribed handler %s from event type: %s", id, eventType)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// close stops the subscriber goroutine and its connection
+func (s *subscription) close() {
+	s.cancel()
+	s.pubsub.Close()
+}
+
+// receiveMessages dispatches messages until the subscription is closed. When
+// the connection drops, the next receive reconnects and resubscribes.
+func (rb *RedisBus) receiveMessages(ctx context.Context, eventType string, pubsub *redis.PubSub) {
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, rb.options.HealthCheckInterval)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+				return
+			}
+
+			// An idle subscription is fine as long as Redis still answers
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				if err = pubsub.Ping(ctx); err == nil {
+					continue
+				}
+			}
+
+			rb.setConnectionStatus(false)
+			if rb.options.EnableLogging {
+				logx.Error("Redis subscription for event type %s dropped, retrying: %v", eventType, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rb.options.RetryDelay):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			// Sent again after every reconnect
+			rb.setConnectionStatus(true)
+		case *redis.Message:
+			rb.setConnectionStatus(true)
+			rb.processMessage(ctx, eventType, msg)
+		}
+	}
+}
+
+func (rb *RedisBus) setConnectionStatus(up bool) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	if rb.connected {
+		rb.metrics.ConnectionStatus = up
+	}
+}
+
+// processMessage runs the handlers for a single Redis message. Handlers are
+// retried per BusConfig.HandlerRetry and a panic fails them like an error.
+func (rb *RedisBus) processMessage(ctx context.Context, eventType string, msg *redis.Message) {
+	// Deserialize event, fetching claim-checked payloads
+	serializableEvent, err := eventx.UnmarshalEvent(ctx, []byte(msg.Payload), rb.options.BusConfig)
+	if err != nil {
+		if rb.options.EnableLogging {
+			logx.Error("Failed to deserialize message body: %s, error: %v", msg.Payload, err)
+		}
+		return
+	}
+
+	if serializableEvent.Metadata == nil {
+		serializableEvent.Metadata = make(map[string]any)
+	}
+	event, err := eventx.FromSerializable[json.RawMessage](serializableEvent)
+	if err != nil {
+		if rb.options.EnableLogging {
+			logx.Error("Failed to rebuild event %s: %v", serializableEvent.ID, err)
+		}
+		return
+	}
+
+	// Apply filters
+	rb.mutex.RLock()
+	filters := make([]eventx.EventFilter, len(rb.filters[eventType]))
+	copy(filters, rb.filters[eventType])
+	rb.mutex.RUnlock()
+
+	for _, filter := range filters {
+		if !filter(event) {
+			return
+		}
+	}
+
+	// Throttle dispatch; Pub/Sub can't redeliver, so shed events are dropped
+	if err := rb.limiter.Wait(ctx, eventType); err != nil {
+		if eventx.IsRateLimited(err) {
+			rb.mutex.Lock()
+			rb.metrics.EventsThrottled++
+			rb.mutex.Unlock()
+		}
+		return
+	}
+
+	// Execute handlers
+	rb.mutex.RLock()
+	handlers := slices.Clone(rb.handlers[eventType])
+	rb.mutex.RUnlock()
+
+	for _, h := range handlers {
+		if attempts, err := eventx.CallHandlerWithRetry(ctx, h.handler, event, rb.options.HandlerRetry); err != nil {
+			rb.mutex.Lock()
+			rb.metrics.EventsFailed++
+			rb.mutex.Unlock()
+
+			if rb.options.EnableLogging {
+				logx.Error("Error handling event %s after %d attempt(s): %v", serializableEvent.ID, attempts, err)
+			}
+			rb.errors.Report(eventx.NewHandlerError(event, err))
+		} else {
+			rb.mutex.Lock()
+			rb.metrics.EventsProcessed++
+			rb.mutex.Unlock()
+		}
+	}
+}
+
+// Publish publishes an event to the channel of its type
+func (rb *RedisBus) Publish(ctx context.Context, event eventx.Event) error {
+	if err := rb.options.CheckEventType(event.Type()); err != nil {
+		return err
+	}
+	if !rb.IsConnected() {
+		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	// Number the event so consumers can order it and detect gaps
+	rb.sequence.Assign(event)
+
+	// Serialize event, compressing or claim-checking large payloads
+	data, err := eventx.MarshalEvent(ctx, event, rb.options.BusConfig)
+	if err != nil {
+		return err
+	}
+
+	channel := rb.channelName(event.Type())
+	if err := rb.client.Publish(ctx, channel, data).Err(); err != nil {
+		rb.mutex.Lock()
+		rb.metrics.EventsFailed++
+		rb.mutex.Unlock()
+
+		return eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
+			WithCause(err).
+			WithDetail("event_id", event.ID()).
+			WithDetail("event_type", event.Type()).
+			WithDetail("channel", channel)
+	}
+
+	rb.mutex.Lock()
+	rb.metrics.EventsPublished++
+	rb.mutex.Unlock()
+
+	return nil
+}
+
+// PublishBatch publishes events one at a time in slice order. Nothing is
+// published when an event type isn't declared; otherwise failed events don't
+// stop the batch and are reported per event in an eventx.BatchError.
+func (rb *RedisBus) PublishBatch(ctx context.Context, events []eventx.Event) error {
+	for _, event := range events {
+		if err := rb.options.CheckEventType(event.Type()); err != nil {
+			return err
+		}
+	}
+
+	return eventx.PublishEach(ctx, events, rb.Publish)
+}
+
+// Unsubscribe removes handlers for an event type and closes its subscription
+func (rb *RedisBus) Unsubscribe(ctx context.Context, eventType string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if sub, exists := rb.subscriptions[eventType]; exists {
+		sub.close()
+		delete(rb.subscriptions, eventType)
+	}
+
+	if handlers, exists := rb.handlers[eventType]; exists {
+		rb.metrics.ActiveSubscribers -= len(handlers)
+		delete(rb.handlers, eventType)
+		delete(rb.filters, eventType)
+	}
+
+	if rb.options.EnableLogging {
+		logx.Debug("Unsubscribing from event type: %s", eventType)
+	}
+
+	return nil
+}
+
+// AddFilter adds a filter for an event type
+func (rb *RedisBus) AddFilter(eventType string, filter eventx.EventFilter) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	rb.filters[eventType] = append(rb.filters[eventType], filter)
+	return nil
+}
+
+// RemoveFilter removes filters for an event type
+func (rb *RedisBus) RemoveFilter(eventType string) error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	delete(rb.filters, eventType)
+	return nil
+}
+
+// ListEventTypes returns all registered event types
+func (rb *RedisBus) ListEventTypes() []string {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	types := make([]string, 0, len(rb.handlers))
+	for eventType := range rb.handlers {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// HandlerCount returns the number of handlers for an event type
+func (rb *RedisBus) HandlerCount(eventType string) int {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return len(rb.handlers[eventType])
+}
+
+// Health returns the health status
+func (rb *RedisBus) Health(ctx context.Context) error {
+	if !rb.IsConnected() {
+		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+	}
+
+	if err := rb.client.Ping(ctx).Err(); err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrConnectionFailed).
+			WithCause(err).
+			WithDetail("operation", "health_check")
+	}
+
+	return nil
+}
+
+// GetMetrics returns bus metrics
+func (rb *RedisBus) GetMetrics() eventx.BusMetrics {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.metrics
+}
+
+// Errors returns the channel of subscriber handler failures (implements ErrorReportingEventBus).
+func (rb *RedisBus) Errors() <-chan eventx.HandlerError {
+	return rb.errors.C()
+}
@@ -0,0 +1,78 @@
+package eventxredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/redis/go-redis/v9"
+)
+
+// newRetryBus returns an unconnected bus retrying handlers twice, with
+// handler registered for "job.run"
+func newRetryBus(t *testing.T, handler eventx.EventHandler) *RedisBus {
+	t.Helper()
+
+	cfg := eventx.DefaultBusConfig().WithHandlerRetry(2, time.Millisecond)
+	cfg.EnableLogging = false
+	rb := New(nil, WithBusConfig(cfg)).(*RedisBus)
+	rb.handlers["job.run"] = []registeredHandler{{id: "h1", handler: handler}}
+	return rb
+}
+
+// message returns the Redis message Publish would send for event
+func message(t *testing.T, rb *RedisBus, event eventx.Event) *redis.Message {
+	t.Helper()
+
+	data, err := eventx.MarshalEvent(context.Background(), event, rb.options.BusConfig)
+	if err != nil {
+		t.Fatalf("MarshalEvent: %v", err)
+	}
+	return &redis.Message{Channel: rb.channelName(event.Type()), Payload: string(data)}
+}
+
+func TestProcessMessageRetriesHandler(t *testing.T) {
+	calls := 0
+	rb := newRetryBus(t, func(eventx.Event) error {
+		if calls++; calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	rb.processMessage(context.Background(), "job.run", message(t, rb, eventx.NewEvent("job.run", 1)))
+
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+	if rb.metrics.EventsProcessed != 1 || rb.metrics.EventsFailed != 0 {
+		t.Errorf("metrics = %+v, want one processed event", rb.metrics)
+	}
+}
+
+func TestProcessMessageReportsExhaustedRetries(t *testing.T) {
+	calls := 0
+	rb := newRetryBus(t, func(eventx.Event) error {
+		calls++
+		panic("boom")
+	})
+
+	rb.processMessage(context.Background(), "job.run", message(t, rb, eventx.NewEvent("job.run", 1)))
+
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+	if rb.metrics.EventsFailed != 1 {
+		t.Errorf("EventsFailed = %d, want 1", rb.metrics.EventsFailed)
+	}
+	select {
+	case report := <-rb.Errors():
+		if report.Err == nil {
+			t.Error("reported error is nil")
+		}
+	default:
+		t.Error("no handler error reported")
+	}
+}
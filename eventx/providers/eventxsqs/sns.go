@@ -0,0 +1,225 @@
+package eventxsqs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/logx"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// snsEnvelope is the JSON body SNS wraps messages in when raw message delivery is off
+type snsEnvelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// topicAttributes builds the SNS message attributes of an event. EventType is
+// what subscription filter policies match on.
+func topicAttributes(event eventx.Event) map[string]snstypes.MessageAttributeValue {
+	attributes := map[string]snstypes.MessageAttributeValue{
+		"EventType": {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(event.Type()),
+		},
+		"EventSource": {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(event.Source()),
+		},
+		"EventVersion": {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(event.Version()),
+		},
+	}
+
+	// SNS allows at most 10 attributes per message
+	for key, value := range event.Metadata() {
+		if len(attributes) >= 10 {
+			break
+		}
+		if strValue, ok := value.(string); ok && strValue != "" {
+			attributes[key] = snstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(strValue),
+			}
+		}
+	}
+	return attributes
+}
+
+// publishToTopic publishes a serialized event to the configured SNS topic
+func (sb *SQSBus) publishToTopic(ctx context.Context, event eventx.Event, data []byte) error {
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(sb.config.TopicARN),
+		Message:           aws.String(string(data)),
+		MessageAttributes: topicAttributes(event),
+	}
+
+	// Add FIFO-specific attributes if enabled
+	if sb.config.EnableFIFO {
+		input.MessageGroupId = aws.String(event.Type())
+		if !sb.config.ContentBasedDedup {
+			input.MessageDeduplicationId = aws.String(event.ID())
+		}
+	}
+
+	if _, err := sb.snsClient.Publish(ctx, input); err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
+			WithCause(err).
+			WithDetail("event_id", event.ID()).
+			WithDetail("event_type", event.Type()).
+			WithDetail("topic_arn", sb.config.TopicARN)
+	}
+	return nil
+}
+
+// publishTopicBatch publishes up to 10 events to the SNS topic in one call
+// and returns the events that failed, indexed into events
+func (sb *SQSBus) publishTopicBatch(ctx context.Context, eventType string, events []eventx.Event) []eventx.BatchFailure {
+	var failures []eventx.BatchFailure
+	var entries []snstypes.PublishBatchRequestEntry
+	var entryIndexes []int
+	for i, event := range events {
+		sb.sequence.Assign(event)
+
+		data, err := eventx.MarshalEvent(ctx, event, sb.config.BusConfig)
+		if err != nil {
+			failures = append(failures, eventx.BatchFailure{Index: i, Event: event, Err: err})
+			continue
+		}
+
+		entry := snstypes.PublishBatchRequestEntry{
+			Id:                aws.String(batchEntryID(i)),
+			Message:           aws.String(string(data)),
+			MessageAttributes: topicAttributes(event),
+		}
+		if sb.config.EnableFIFO {
+			entry.MessageGroupId = aws.String(event.Type())
+			if !sb.config.ContentBasedDedup {
+				entry.MessageDeduplicationId = aws.String(event.ID())
+			}
+		}
+		entries = append(entries, entry)
+		entryIndexes = append(entryIndexes, i)
+	}
+
+	if len(entries) == 0 {
+		return failures
+	}
+
+	output, err := sb.snsClient.PublishBatch(ctx, &sns.PublishBatchInput{
+		TopicArn:                   aws.String(sb.config.TopicARN),
+		PublishBatchRequestEntries: entries,
+	})
+	if err != nil {
+		sb.mutex.Lock()
+		sb.metrics.EventsFailed += int64(len(entries))
+		sb.mutex.Unlock()
+
+		return append(failures, failBatch(events, entryIndexes, eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
+			WithCause(err).
+			WithDetail("event_type", eventType).
+			WithDetail("batch_size", len(entries)).
+			WithDetail("topic_arn", sb.config.TopicARN))...)
+	}
+
+	// Update metrics
+	sb.mutex.Lock()
+	sb.metrics.EventsPublished += int64(len(output.Successful))
+	sb.metrics.EventsFailed += int64(len(output.Failed))
+	sb.mutex.Unlock()
+
+	for _, failed := range output.Failed {
+		if sb.config.EnableLogging {
+			logx.Error("Failed to publish message %s: %s", aws.ToString(failed.Id), aws.ToString(failed.Message))
+		}
+		failures = append(failures, entryFailure(events, failed.Id, failed.Code, failed.Message, "topic_arn", sb.config.TopicARN))
+	}
+
+	return failures
+}
+
+// subscribeQueueToTopic subscribes an event type's queue to the SNS topic
+// with raw message delivery and a filter policy on EventType, and replaces
+// the queue policy with one allowing the topic to send to it. SNS returns the
+// existing subscription when it is already in place.
+func (sb *SQSBus) subscribeQueueToTopic(ctx context.Context, eventType string, queueInfo *QueueInfo) error {
+	attrs, err := sb.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueInfo.URL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("operation", "get_queue_arn").
+			WithDetail("queue_name", queueInfo.Name)
+	}
+	queueARN := attrs.Attributes[string(types.QueueAttributeNameQueueArn)]
+
+	policy, _ := json.Marshal(map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{{
+			"Effect":    "Allow",
+			"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+			"Action":    "sqs:SendMessage",
+			"Resource":  queueARN,
+			"Condition": map[string]any{
+				"ArnEquals": map[string]string{"aws:SourceArn": sb.config.TopicARN},
+			},
+		}},
+	})
+	_, err = sb.client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueInfo.URL),
+		Attributes: map[string]string{
+			string(types.QueueAttributeNamePolicy): string(policy),
+		},
+	})
+	if err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("operation", "set_queue_policy").
+			WithDetail("queue_name", queueInfo.Name)
+	}
+
+	filterPolicy, _ := json.Marshal(map[string][]string{"EventType": {eventType}})
+	_, err = sb.snsClient.Subscribe(ctx, &sns.SubscribeInput{
+		TopicArn: aws.String(sb.config.TopicARN),
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String(queueARN),
+		Attributes: map[string]string{
+			"RawMessageDelivery": "true",
+			"FilterPolicy":       string(filterPolicy),
+		},
+		ReturnSubscriptionArn: true,
+	})
+	if err != nil {
+		return eventx.ErrorRegistry.New(eventx.ErrSubscriptionFailed).
+			WithCause(err).
+			WithDetail("operation", "subscribe_topic").
+			WithDetail("topic_arn", sb.config.TopicARN).
+			WithDetail("queue_name", queueInfo.Name)
+	}
+
+	if sb.config.EnableLogging {
+		logx.Debug("Subscribed queue %s to topic %s for event type: %s", queueInfo.Name, sb.config.TopicARN, eventType)
+	}
+	return nil
+}
+
+// unwrapSNSEnvelope returns the event JSON of a message delivered by an SNS
+// subscription without raw message delivery, and body unchanged otherwise
+func unwrapSNSEnvelope(body string) string {
+	var envelope snsEnvelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return body
+	}
+	if envelope.Type == "Notification" && envelope.Message != "" {
+		return envelope.Message
+	}
+	return body
+}
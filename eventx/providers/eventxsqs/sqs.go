@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/Abraxas-365/craftable/logx"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
@@ -20,7 +23,9 @@ import (
 type SQSBus struct {
 	config    SQSConfig
 	client    *sqs.Client
-	handlers  map[string][]eventx.EventHandler
+	snsClient *sns.Client
+	handlers  map[string][]registeredHandler
+	nextID    uint64
 	filters   map[string][]eventx.EventFilter
 	metrics   eventx.BusMetrics
 	mutex     sync.RWMutex
@@ -28,6 +33,15 @@ type SQSBus struct {
 	queues    map[string]*QueueInfo
 	consumers map[string]context.CancelFunc
 	awsConfig aws.Config
+	errors    *eventx.ErrorChannel
+	sequence  *eventx.Sequencer
+	limiter   *eventx.RateLimiter
+}
+
+// registeredHandler is a handler with the ID SubscribeHandler returned for it
+type registeredHandler struct {
+	id      string
+	handler eventx.EventHandler
 }
 
 // QueueInfo stores information about SQS queues
@@ -59,6 +73,11 @@ type SQSConfig struct {
 	EnableFIFO            bool   `json:"enable_fifo"`
 	ContentBasedDedup     bool   `json:"content_based_deduplication"`
 
+	// SNS fan-out: when TopicARN is set, events are published to the topic with
+	// an EventType message attribute, and each subscribed event type's queue is
+	// subscribed to the topic with a filter policy on it
+	TopicARN string `json:"topic_arn"`
+
 	// Dead Letter Queue
 	EnableDLQ bool   `json:"enable_dlq"`
 	DLQSuffix string `json:"dlq_suffix"`
@@ -67,9 +86,13 @@ type SQSConfig struct {
 	MaxBatchSize  int `json:"max_batch_size"`
 	BatchWaitTime int `json:"batch_wait_time_ms"`
 
-	// Consumer settings
-	MaxConcurrentConsumers  int `json:"max_concurrent_consumers"`
-	ConsumerPollingInterval int `json:"consumer_polling_interval_ms"`
+	// Consumer settings. Consumers receive up to MaxBatchSize (at most 10)
+	// messages per poll. With ExtendVisibility, the visibility timeout of a
+	// received batch is extended while its handlers run, so slow handlers
+	// don't cause redelivery to another consumer.
+	MaxConcurrentConsumers  int  `json:"max_concurrent_consumers"`
+	ConsumerPollingInterval int  `json:"consumer_polling_interval_ms"`
+	ExtendVisibility        bool `json:"extend_visibility"`
 }
 
 // DefaultSQSConfig returns default SQS configuration
@@ -92,6 +115,7 @@ func DefaultSQSConfig() SQSConfig {
 		BatchWaitTime:           100,
 		MaxConcurrentConsumers:  5,
 		ConsumerPollingInterval: 1000,
+		ExtendVisibility:        true,
 	}
 }
 
@@ -99,11 +123,14 @@ func DefaultSQSConfig() SQSConfig {
 func New(config SQSConfig) eventx.EventBus {
 	return &SQSBus{
 		config:    config,
-		handlers:  make(map[string][]eventx.EventHandler),
+		handlers:  make(map[string][]registeredHandler),
 		filters:   make(map[string][]eventx.EventFilter),
 		metrics:   eventx.BusMetrics{},
 		queues:    make(map[string]*QueueInfo),
 		consumers: make(map[string]context.CancelFunc),
+		errors:    eventx.NewErrorChannel(config.ErrorBufferSize),
+		sequence:  eventx.NewSequencer(config.SequenceScope),
+		limiter:   eventx.NewRateLimiter(config.RateLimits),
 	}
 }
 
@@ -146,8 +173,11 @@ func (sb *SQSBus) Connect(ctx context.Context) error {
 			WithDetail("region", sb.config.Region)
 	}
 
-	// Create SQS client
+	// Create SQS client, and SNS client when publishing to a topic
 	sb.client = sqs.NewFromConfig(sb.awsConfig)
+	if sb.config.TopicARN != "" {
+		sb.snsClient = sns.NewFromConfig(sb.awsConfig)
+	}
 
 	// Test connection by listing queues
 	_, err = sb.client.ListQueues(ctx, &sqs.ListQueuesInput{
@@ -188,6 +218,7 @@ func (sb *SQSBus) Disconnect(ctx context.Context) error {
 	sb.consumers = make(map[string]context.CancelFunc)
 
 	sb.client = nil
+	sb.snsClient = nil
 	sb.connected = false
 	sb.metrics.ConnectionStatus = false
 
@@ -207,21 +238,41 @@ func (sb *SQSBus) IsConnected() bool {
 
 // Subscribe registers an event handler
 func (sb *SQSBus) Subscribe(ctx context.Context, eventType string, handler eventx.EventHandler) error {
+	_, err := sb.SubscribeHandler(ctx, eventType, handler)
+	return err
+}
+
+// SubscribeHandler registers an event handler and returns its ID for
+// UnsubscribeHandler (implements HandlerEventBus)
+func (sb *SQSBus) SubscribeHandler(ctx context.Context, eventType string, handler eventx.EventHandler) (string, error) {
+	if err := sb.config.CheckEventType(eventType); err != nil {
+		return "", err
+	}
+
 	sb.mutex.Lock()
 	defer sb.mutex.Unlock()
 
 	if !sb.connected {
-		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
+		return "", eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
 	}
 
 	// Create queue for this event type if it doesn't exist
 	queueInfo, err := sb.ensureQueue(ctx, eventType)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	// Route the event type's messages from the topic to its queue
+	if _, exists := sb.consumers[eventType]; !exists && sb.config.TopicARN != "" {
+		if err := sb.subscribeQueueToTopic(ctx, eventType, queueInfo); err != nil {
+			return "", err
+		}
 	}
 
 	// Store handler
-	sb.handlers[eventType] = append(sb.handlers[eventType], handler)
+	sb.nextID++
+	id := strconv.FormatUint(sb.nextID, 10)
+	sb.handlers[eventType] = append(sb.handlers[eventType], registeredHandler{id: id, handler: handler})
 	sb.queues[eventType] = queueInfo
 	sb.metrics.ActiveSubscribers++
 
@@ -240,6 +291,41 @@ func (sb *SQSBus) Subscribe(ctx context.Context, eventType string, handler event
 		logx.Debug("Subscribing to event type: %s (queue: %s)", eventType, queueInfo.Name)
 	}
 
+	return id, nil
+}
+
+// UnsubscribeHandler removes the handler registered under id by
+// SubscribeHandler (implements HandlerEventBus). Removing the last handler of
+// an event type stops its consumers like Unsubscribe.
+func (sb *SQSBus) UnsubscribeHandler(ctx context.Context, id string) error {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	for eventType, handlers := range sb.handlers {
+		i := slices.IndexFunc(handlers, func(h registeredHandler) bool { return h.id == id })
+		if i < 0 {
+			continue
+		}
+
+		handlers = slices.Delete(handlers, i, i+1)
+		if len(handlers) == 0 {
+			delete(sb.handlers, eventType)
+			delete(sb.queues, eventType)
+			if cancel, exists := sb.consumers[eventType]; exists {
+				cancel()
+				delete(sb.consumers, eventType)
+			}
+		} else {
+			sb.handlers[eventType] = handlers
+		}
+		sb.metrics.ActiveSubscribers--
+
+		if sb.config.EnableLogging {
+			logx.Debug("Unsubscribed handler %s from event type: %s", id, eventType)
+		}
+		return nil
+	}
+
 	return nil
 }
 
@@ -339,6 +425,12 @@ func (sb *SQSBus) buildQueueAttributes(eventType string) map[string]string {
 	return attrs
 }
 
+// batchSize returns MaxBatchSize within the 1 to 10 messages SQS and SNS
+// accept per batch call
+func (sb *SQSBus) batchSize() int {
+	return min(max(sb.config.MaxBatchSize, 1), 10)
+}
+
 // getQueueName generates the queue name for an event type
 func (sb *SQSBus) getQueueName(eventType string) string {
 	queueName := fmt.Sprintf("%s-%s", sb.config.QueuePrefix, eventType)
@@ -368,7 +460,7 @@ func (sb *SQSBus) processQueueMessages(ctx context.Context, eventType string, qu
 	// Receive messages
 	receiveOutput, err := sb.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
 		QueueUrl:              aws.String(queueInfo.URL),
-		MaxNumberOfMessages:   int32(sb.config.MaxBatchSize),
+		MaxNumberOfMessages:   int32(sb.batchSize()),
 		WaitTimeSeconds:       int32(sb.config.ReceiveTimeoutSecs),
 		MessageAttributeNames: []string{"All"},
 		AttributeNames:        []types.QueueAttributeName{types.QueueAttributeNameAll},
@@ -384,6 +476,9 @@ func (sb *SQSBus) processQueueMessages(ctx context.Context, eventType string, qu
 		return
 	}
 
+	// Keep the batch invisible while its handlers run
+	stopExtending := sb.extendVisibility(ctx, queueInfo, receiveOutput.Messages)
+
 	// Process each message
 	var messagesToDelete []types.DeleteMessageBatchRequestEntry
 
@@ -397,6 +492,8 @@ func (sb *SQSBus) processQueueMessages(ctx context.Context, eventType string, qu
 		}
 	}
 
+	stopExtending()
+
 	// Delete successfully processed messages
 	if len(messagesToDelete) > 0 {
 		_, err := sb.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
@@ -410,10 +507,10 @@ func (sb *SQSBus) processQueueMessages(ctx context.Context, eventType string, qu
 }
 
 // processMessage processes a single SQS message
-func (sb *SQSBus) processMessage(_ context.Context, eventType string, msg types.Message) bool {
-	// Deserialize event
-	var serializableEvent eventx.SerializableEvent
-	if err := json.Unmarshal([]byte(*msg.Body), &serializableEvent); err != nil {
+func (sb *SQSBus) processMessage(ctx context.Context, eventType string, msg types.Message) bool {
+	// Deserialize event, fetching claim-checked payloads
+	serializableEvent, err := eventx.UnmarshalEvent(ctx, []byte(unwrapSNSEnvelope(*msg.Body)), sb.config.BusConfig)
+	if err != nil {
 		if sb.config.EnableLogging {
 			logx.Error("Failed to deserialize message body: %s, error: %v", *msg.Body, err)
 		}
@@ -421,9 +518,20 @@ func (sb *SQSBus) processMessage(_ context.Context, eventType string, msg types.
 	}
 
 	// Create generic event
-	event := &eventx.BaseEvent[json.RawMessage]{}
-	*event = eventx.BaseEvent[json.RawMessage]{
-		// Initialize fields from serializableEvent
+	if serializableEvent.Metadata == nil {
+		serializableEvent.Metadata = make(map[string]any)
+	}
+	event, err := eventx.FromSerializable[json.RawMessage](serializableEvent)
+	if err != nil {
+		if sb.config.EnableLogging {
+			logx.Error("Failed to rebuild event %s: %v", serializableEvent.ID, err)
+		}
+		return false
+	}
+
+	// FIFO queues expose a native, per-group increasing sequence number
+	if seq, ok := msg.Attributes[string(types.MessageSystemAttributeNameSequenceNumber)]; ok {
+		event.Metadata()[eventx.MetadataOffset] = seq
 	}
 
 	// Apply filters
@@ -438,15 +546,24 @@ func (sb *SQSBus) processMessage(_ context.Context, eventType string, msg types.
 		}
 	}
 
+	// Throttle dispatch; shed messages become visible again after the visibility timeout
+	if err := sb.limiter.Wait(ctx, eventType); err != nil {
+		if eventx.IsRateLimited(err) {
+			sb.mutex.Lock()
+			sb.metrics.EventsThrottled++
+			sb.mutex.Unlock()
+		}
+		return false
+	}
+
 	// Execute handlers
 	sb.mutex.RLock()
-	handlers := make([]eventx.EventHandler, len(sb.handlers[eventType]))
-	copy(handlers, sb.handlers[eventType])
+	handlers := slices.Clone(sb.handlers[eventType])
 	sb.mutex.RUnlock()
 
 	success := true
-	for _, handler := range handlers {
-		if err := handler(event); err != nil {
+	for _, h := range handlers {
+		if err := h.handler(event); err != nil {
 			sb.mutex.Lock()
 			sb.metrics.EventsFailed++
 			sb.mutex.Unlock()
@@ -454,6 +571,7 @@ func (sb *SQSBus) processMessage(_ context.Context, eventType string, msg types.
 			if sb.config.EnableLogging {
 				logx.Error("Error handling event %s: %v", serializableEvent.ID, err)
 			}
+			sb.errors.Report(eventx.NewHandlerError(event, err))
 			success = false
 		} else {
 			sb.mutex.Lock()
@@ -467,22 +585,44 @@ func (sb *SQSBus) processMessage(_ context.Context, eventType string, msg types.
 
 // Publish publishes an event
 func (sb *SQSBus) Publish(ctx context.Context, event eventx.Event) error {
-	sb.mutex.RLock()
-	defer sb.mutex.RUnlock()
+	if err := sb.config.CheckEventType(event.Type()); err != nil {
+		return err
+	}
 
-	if !sb.connected {
+	// Not holding the read lock while publishing: failures take the write lock
+	if !sb.IsConnected() {
 		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
 	}
 
-	// Ensure queue exists
-	queueInfo, err := sb.ensureQueue(ctx, event.Type())
+	// Ensure queue exists, unless the topic routes events to the queues
+	var queueInfo *QueueInfo
+	if sb.config.TopicARN == "" {
+		var err error
+		if queueInfo, err = sb.ensureQueue(ctx, event.Type()); err != nil {
+			return err
+		}
+	}
+
+	// Number the event so consumers can order it and detect gaps
+	sb.sequence.Assign(event)
+
+	// Serialize event, compressing or claim-checking large payloads
+	data, err := eventx.MarshalEvent(ctx, event, sb.config.BusConfig)
 	if err != nil {
 		return err
 	}
 
-	// Serialize event
-	data, err := eventx.ToJSON(event)
-	if err != nil {
+	if sb.config.TopicARN != "" {
+		err := sb.publishToTopic(ctx, event, data)
+
+		sb.mutex.Lock()
+		if err != nil {
+			sb.metrics.EventsFailed++
+		} else {
+			sb.metrics.EventsPublished++
+		}
+		sb.mutex.Unlock()
+
 		return err
 	}
 
@@ -547,56 +687,84 @@ func (sb *SQSBus) Publish(ctx context.Context, event eventx.Event) error {
 	return nil
 }
 
-// PublishBatch publishes multiple events in a batch
+// PublishBatch sends events with SendMessageBatch (or SNS PublishBatch when
+// TopicARN is set), grouped by event type into batches of up to 10. Events of
+// one type are sent in slice order, which FIFO queues preserve since the type
+// is the message group; there is no ordering across types. Nothing is sent
+// when an event type isn't declared; otherwise events SQS rejects, or that
+// can't be serialized, are reported per event in an eventx.BatchError and
+// the rest are still sent.
 func (sb *SQSBus) PublishBatch(ctx context.Context, events []eventx.Event) error {
 	if len(events) == 0 {
 		return nil
 	}
+	for _, event := range events {
+		if err := sb.config.CheckEventType(event.Type()); err != nil {
+			return err
+		}
+	}
 
-	sb.mutex.RLock()
-	defer sb.mutex.RUnlock()
-
-	if !sb.connected {
+	if !sb.IsConnected() {
 		return eventx.ErrorRegistry.New(eventx.ErrBusNotConnected)
 	}
 
-	// Group events by type for batch sending
-	eventsByType := make(map[string][]eventx.Event)
-	for _, event := range events {
-		eventsByType[event.Type()] = append(eventsByType[event.Type()], event)
+	// Group events by type for batch sending, keeping their positions
+	var eventTypes []string
+	indexesByType := make(map[string][]int)
+	for i, event := range events {
+		if _, exists := indexesByType[event.Type()]; !exists {
+			eventTypes = append(eventTypes, event.Type())
+		}
+		indexesByType[event.Type()] = append(indexesByType[event.Type()], i)
 	}
 
-	var lastErr error
-	for eventType, typeEvents := range eventsByType {
+	var failures []eventx.BatchFailure
+	for _, eventType := range eventTypes {
+		indexes := indexesByType[eventType]
+
 		// Process events in batches of MaxBatchSize
-		for i := 0; i < len(typeEvents); i += sb.config.MaxBatchSize {
-			end := min(i+sb.config.MaxBatchSize, len(typeEvents))
+		for i := 0; i < len(indexes); i += sb.batchSize() {
+			batchIndexes := indexes[i:min(i+sb.batchSize(), len(indexes))]
 
-			batch := typeEvents[i:end]
-			if err := sb.sendBatch(ctx, eventType, batch); err != nil {
-				lastErr = err
+			batch := make([]eventx.Event, len(batchIndexes))
+			for j, index := range batchIndexes {
+				batch[j] = events[index]
+			}
+			for _, failure := range sb.sendBatch(ctx, eventType, batch) {
+				failure.Index = batchIndexes[failure.Index]
+				failures = append(failures, failure)
 			}
 		}
 	}
 
-	return lastErr
+	return eventx.NewBatchError(len(events), failures)
 }
 
-// sendBatch sends a batch of events of the same type
-func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []eventx.Event) error {
+// sendBatch sends a batch of events of the same type and returns the events
+// that failed, indexed into events
+func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []eventx.Event) []eventx.BatchFailure {
+	if sb.config.TopicARN != "" {
+		return sb.publishTopicBatch(ctx, eventType, events)
+	}
+
 	// Ensure queue exists
 	queueInfo, err := sb.ensureQueue(ctx, eventType)
 	if err != nil {
-		return err
+		return failBatch(events, nil, err)
 	}
 
 	// Prepare batch entries
+	var failures []eventx.BatchFailure
 	var entries []types.SendMessageBatchRequestEntry
+	var entryIndexes []int
 	for i, event := range events {
+		sb.sequence.Assign(event)
+
 		// Serialize event
-		data, err := eventx.ToJSON(event)
+		data, err := eventx.MarshalEvent(ctx, event, sb.config.BusConfig)
 		if err != nil {
-			continue // Skip invalid events
+			failures = append(failures, eventx.BatchFailure{Index: i, Event: event, Err: err})
+			continue
 		}
 
 		// Prepare message attributes
@@ -611,7 +779,7 @@ func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []even
 		}
 
 		entry := types.SendMessageBatchRequestEntry{
-			Id:                aws.String(fmt.Sprintf("msg-%d", i)),
+			Id:                aws.String(batchEntryID(i)),
 			MessageBody:       aws.String(string(data)),
 			MessageAttributes: messageAttributes,
 		}
@@ -625,10 +793,11 @@ func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []even
 		}
 
 		entries = append(entries, entry)
+		entryIndexes = append(entryIndexes, i)
 	}
 
 	if len(entries) == 0 {
-		return nil
+		return failures
 	}
 
 	// Send batch
@@ -641,11 +810,11 @@ func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []even
 		sb.metrics.EventsFailed += int64(len(entries))
 		sb.mutex.Unlock()
 
-		return eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
+		return append(failures, failBatch(events, entryIndexes, eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
 			WithCause(err).
 			WithDetail("event_type", eventType).
 			WithDetail("batch_size", len(entries)).
-			WithDetail("queue_url", queueInfo.URL)
+			WithDetail("queue_url", queueInfo.URL))...)
 	}
 
 	// Update metrics
@@ -654,14 +823,53 @@ func (sb *SQSBus) sendBatch(ctx context.Context, eventType string, events []even
 	sb.metrics.EventsFailed += int64(len(output.Failed))
 	sb.mutex.Unlock()
 
-	// Log failures
-	if len(output.Failed) > 0 && sb.config.EnableLogging {
-		for _, failed := range output.Failed {
-			logx.Error("Failed to send message %s: %s", *failed.Id, *failed.Message)
+	for _, failed := range output.Failed {
+		if sb.config.EnableLogging {
+			logx.Error("Failed to send message %s: %s", aws.ToString(failed.Id), aws.ToString(failed.Message))
 		}
+		failures = append(failures, entryFailure(events, failed.Id, failed.Code, failed.Message, "queue_url", queueInfo.URL))
 	}
 
-	return nil
+	return failures
+}
+
+// batchEntryID is the batch request entry ID of the event at index i
+func batchEntryID(i int) string {
+	return fmt.Sprintf("msg-%d", i)
+}
+
+// entryFailure reports the event behind a failed batch request entry
+func entryFailure(events []eventx.Event, id, code, message *string, targetKey, target string) eventx.BatchFailure {
+	index, _ := strconv.Atoi(strings.TrimPrefix(aws.ToString(id), "msg-"))
+	event := events[index]
+
+	return eventx.BatchFailure{
+		Index: index,
+		Event: event,
+		Err: eventx.ErrorRegistry.New(eventx.ErrPublishFailed).
+			WithDetail("event_id", event.ID()).
+			WithDetail("event_type", event.Type()).
+			WithDetail("code", aws.ToString(code)).
+			WithDetail("error", aws.ToString(message)).
+			WithDetail(targetKey, target),
+	}
+}
+
+// failBatch reports the events at indexes, or every event when indexes is
+// nil, as failed with err
+func failBatch(events []eventx.Event, indexes []int, err error) []eventx.BatchFailure {
+	if indexes == nil {
+		indexes = make([]int, len(events))
+		for i := range indexes {
+			indexes[i] = i
+		}
+	}
+
+	failures := make([]eventx.BatchFailure, len(indexes))
+	for i, index := range indexes {
+		failures[i] = eventx.BatchFailure{Index: index, Event: events[index], Err: err}
+	}
+	return failures
 }
 
 // Unsubscribe removes handlers for an event type
@@ -751,8 +959,18 @@ func (sb *SQSBus) GetMetrics() eventx.BusMetrics {
 	return sb.metrics
 }
 
+// Errors returns the channel of consumer handler failures (implements ErrorReportingEventBus).
+// SQS handlers always run asynchronously in consumer goroutines.
+func (sb *SQSBus) Errors() <-chan eventx.HandlerError {
+	return sb.errors.C()
+}
+
 // PublishAsync publishes an event asynchronously
 func (sb *SQSBus) PublishAsync(ctx context.Context, event eventx.Event) error {
+	if err := sb.config.CheckEventType(event.Type()); err != nil {
+		return err
+	}
+
 	go func() {
 		if err := sb.Publish(ctx, event); err != nil && sb.config.EnableLogging {
 			logx.Error("Async publish error for event %s: %v", event.ID(), err)
@@ -763,6 +981,12 @@ func (sb *SQSBus) PublishAsync(ctx context.Context, event eventx.Event) error {
 
 // PublishBatchAsync publishes multiple events asynchronously
 func (sb *SQSBus) PublishBatchAsync(ctx context.Context, events []eventx.Event) error {
+	for _, event := range events {
+		if err := sb.config.CheckEventType(event.Type()); err != nil {
+			return err
+		}
+	}
+
 	go func() {
 		if err := sb.PublishBatch(ctx, events); err != nil && sb.config.EnableLogging {
 			logx.Error("Async batch publish error: %v", err)
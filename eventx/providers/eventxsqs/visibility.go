@@ -0,0 +1,82 @@
+package eventxsqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// visibilityTimeout returns the queue's visibility timeout in seconds,
+// preferring the queue's actual attribute over the configured value
+func (sb *SQSBus) visibilityTimeout(queueInfo *QueueInfo) int {
+	if value, ok := queueInfo.Attributes[string(types.QueueAttributeNameVisibilityTimeout)]; ok {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return seconds
+		}
+	}
+	return sb.config.VisibilityTimeoutSecs
+}
+
+// extendVisibility keeps a received batch invisible to other consumers while
+// its handlers run, by resetting the visibility timeout of every message at
+// half the timeout. The returned function stops extending; messages that
+// aren't deleted afterwards become visible once the last extension expires.
+func (sb *SQSBus) extendVisibility(ctx context.Context, queueInfo *QueueInfo, messages []types.Message) (stop func()) {
+	timeout := sb.visibilityTimeout(queueInfo)
+	if !sb.config.ExtendVisibility || timeout <= 1 || len(messages) == 0 {
+		return func() {}
+	}
+
+	entries := make([]types.ChangeMessageVisibilityBatchRequestEntry, len(messages))
+	for i, msg := range messages {
+		entries[i] = types.ChangeMessageVisibilityBatchRequestEntry{
+			Id:                aws.String(fmt.Sprintf("msg-%d", i)),
+			ReceiptHandle:     msg.ReceiptHandle,
+			VisibilityTimeout: int32(timeout),
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(time.Duration(timeout) * time.Second / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			output, err := sb.client.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{
+				QueueUrl: aws.String(queueInfo.URL),
+				Entries:  entries,
+			})
+			if !sb.config.EnableLogging || ctx.Err() != nil {
+				continue
+			}
+			if err != nil {
+				logx.Error("Error extending message visibility on queue %s: %v", queueInfo.Name, err)
+				continue
+			}
+			for _, failed := range output.Failed {
+				logx.Error("Error extending visibility of message %s: %s", aws.ToString(failed.Id), aws.ToString(failed.Message))
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,88 @@
+package eventx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// BatchFailure is an event of a PublishBatch call that failed: it wasn't
+// published or, on in-process buses, one of its handlers failed
+type BatchFailure struct {
+	// Index is the event's position in the slice passed to PublishBatch
+	Index int
+	Event Event
+	Err   error
+}
+
+// BatchError reports the failures of a PublishBatch call per event. Events
+// it doesn't list succeeded. errors.Is checks every failed event's error, so
+// errors.Is(err, target) matches an errx error of any failure. errors.As, and
+// with it errx.IsCode, only finds the first matching error by index; use
+// BatchFailures to inspect each one.
+type BatchError struct {
+	Total    int
+	Failures []BatchFailure
+}
+
+// NewBatchError returns a BatchError for failures out of total events,
+// ordered by index, or nil when nothing failed
+func NewBatchError(total int, failures []BatchFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	failures = slices.Clone(failures)
+	slices.SortStableFunc(failures, func(a, b BatchFailure) int {
+		return a.Index - b.Index
+	})
+	return &BatchError{Total: total, Failures: failures}
+}
+
+func (e *BatchError) Error() string {
+	first := e.Failures[0]
+	return fmt.Sprintf("%d of %d events failed; first: event %s (%s): %v",
+		len(e.Failures), e.Total, first.Event.ID(), first.Event.Type(), first.Err)
+}
+
+// Unwrap returns the error of every failed event
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// BatchFailures returns the per-event failures reported by PublishBatch, or
+// nil when err doesn't carry any
+//
+//	err := bus.PublishBatch(ctx, events)
+//	for _, failure := range eventx.BatchFailures(err) {
+//		retryLater(failure.Event, failure.Err)
+//	}
+func BatchFailures(err error) []BatchFailure {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.Failures
+	}
+	return nil
+}
+
+// PublishEach publishes events one at a time in order and reports the
+// failed ones in a BatchError. A failure doesn't stop the batch; once ctx is
+// done the remaining events fail with its error. Buses without a native
+// batch operation implement PublishBatch with it.
+func PublishEach(ctx context.Context, events []Event, publish func(context.Context, Event) error) error {
+	var failures []BatchFailure
+	for i, event := range events {
+		err := ctx.Err()
+		if err == nil {
+			err = publish(ctx, event)
+		}
+		if err != nil {
+			failures = append(failures, BatchFailure{Index: i, Event: event, Err: err})
+		}
+	}
+	return NewBatchError(len(events), failures)
+}
@@ -0,0 +1,84 @@
+package eventx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+func TestPublishEachReportsEveryFailure(t *testing.T) {
+	events := []eventx.Event{
+		eventx.NewEvent("a", 0),
+		eventx.NewEvent("b", 1),
+		eventx.NewEvent("c", 2),
+		eventx.NewEvent("d", 3),
+	}
+	errNetwork := errors.New("network down")
+
+	var published []string
+	err := eventx.PublishEach(context.Background(), events, func(ctx context.Context, event eventx.Event) error {
+		switch event.Type() {
+		case "b":
+			return eventx.ErrorRegistry.New(eventx.ErrRateLimit)
+		case "d":
+			return eventx.ErrorRegistry.NewWithCause(eventx.ErrTimeout, errNetwork)
+		}
+		published = append(published, event.Type())
+		return nil
+	})
+
+	if len(published) != 2 || published[0] != "a" || published[1] != "c" {
+		t.Errorf("published = %v, want [a c]", published)
+	}
+
+	failures := eventx.BatchFailures(err)
+	if len(failures) != 2 || failures[0].Index != 1 || failures[1].Index != 3 {
+		t.Fatalf("failures = %+v, want events 1 and 3", failures)
+	}
+
+	// errors.Is walks every failure
+	for _, code := range []errx.Code{eventx.ErrRateLimit, eventx.ErrTimeout} {
+		if !errors.Is(err, eventx.ErrorRegistry.New(code)) {
+			t.Errorf("errors.Is(%s) = false, want true", code)
+		}
+	}
+	if !errors.Is(err, errNetwork) {
+		t.Error("errors.Is(cause of the second failure) = false, want true")
+	}
+
+	// errors.As, and so errx.IsCode, stops at the first failure
+	if !errx.IsCode(err, eventx.ErrRateLimit) {
+		t.Error("IsCode(first failure's code) = false, want true")
+	}
+	if errx.IsCode(err, eventx.ErrTimeout) {
+		t.Error("IsCode(second failure's code) = true, want false")
+	}
+}
+
+func TestPublishEachStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := eventx.PublishEach(ctx, []eventx.Event{eventx.NewEvent("a", 0), eventx.NewEvent("b", 1)},
+		func(context.Context, eventx.Event) error {
+			calls++
+			return nil
+		})
+
+	if calls != 0 {
+		t.Errorf("publish calls = %d, want 0", calls)
+	}
+	if failures := eventx.BatchFailures(err); len(failures) != 2 || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want both events failed with context.Canceled", err)
+	}
+}
+
+func TestNewBatchErrorWithoutFailures(t *testing.T) {
+	if err := eventx.NewBatchError(3, nil); err != nil {
+		t.Errorf("NewBatchError(no failures) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,143 @@
+package eventx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit throttles dispatch of one event type with a token bucket
+type RateLimit struct {
+	// PerSecond is the sustained number of events dispatched per second
+	PerSecond float64 `json:"per_second"`
+
+	// Burst is how many events may be dispatched at once after an idle period
+	Burst int `json:"burst"`
+
+	// Shed rejects events over the limit with an ErrRateLimit error instead of
+	// blocking the publisher until a token is available
+	Shed bool `json:"shed"`
+}
+
+// WithRateLimit returns a copy of the config that throttles dispatch of
+// eventType, blocking publishers while the limit is exceeded
+func (c BusConfig) WithRateLimit(eventType string, perSecond float64, burst int) BusConfig {
+	return c.withRateLimit(eventType, RateLimit{PerSecond: perSecond, Burst: burst})
+}
+
+// WithSheddingRateLimit returns a copy of the config that throttles dispatch
+// of eventType, rejecting events over the limit with an ErrRateLimit error
+func (c BusConfig) WithSheddingRateLimit(eventType string, perSecond float64, burst int) BusConfig {
+	return c.withRateLimit(eventType, RateLimit{PerSecond: perSecond, Burst: burst, Shed: true})
+}
+
+func (c BusConfig) withRateLimit(eventType string, limit RateLimit) BusConfig {
+	limits := make(map[string]RateLimit, len(c.RateLimits)+1)
+	for name, l := range c.RateLimits {
+		limits[name] = l
+	}
+	limits[eventType] = limit
+	c.RateLimits = limits
+	return c
+}
+
+// RateLimiter applies BusConfig.RateLimits for bus implementations. Event
+// types without a limit are never throttled. A nil RateLimiter allows everything.
+type RateLimiter struct {
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimiter creates a limiter with one token bucket per limited event type
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	limiter := &RateLimiter{buckets: make(map[string]*tokenBucket, len(limits))}
+	for eventType, limit := range limits {
+		if limit.PerSecond <= 0 {
+			continue
+		}
+		burst := float64(max(limit.Burst, 1))
+		limiter.buckets[eventType] = &tokenBucket{
+			rate:   limit.PerSecond,
+			burst:  burst,
+			tokens: burst,
+			last:   time.Now(),
+			shed:   limit.Shed,
+		}
+	}
+	return limiter
+}
+
+// Wait takes a token for eventType. Blocking limits wait until one is
+// available or ctx is done; shedding limits return an ErrRateLimit error
+// immediately when the bucket is empty.
+func (l *RateLimiter) Wait(ctx context.Context, eventType string) error {
+	if l == nil {
+		return nil
+	}
+	bucket, ok := l.buckets[eventType]
+	if !ok {
+		return nil
+	}
+
+	delay, ok := bucket.take()
+	if !ok {
+		return ErrorRegistry.New(ErrRateLimit).
+			WithDetail("event_type", eventType).
+			WithDetail("per_second", bucket.rate)
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		bucket.refund()
+		return ErrorRegistry.New(ErrTimeout).
+			WithCause(ctx.Err()).
+			WithDetail("event_type", eventType).
+			WithDetail("reason", "cancelled while rate limited")
+	}
+}
+
+// tokenBucket refills at rate tokens per second up to burst
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	shed   bool
+}
+
+// take consumes a token and returns how long the caller must wait for it. A
+// shedding bucket returns false instead of going into debt.
+func (b *tokenBucket) take() (time.Duration, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if b.shed {
+		return 0, false
+	}
+
+	// Reserve the next token; waiters queue up behind each other
+	b.tokens--
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), true
+}
+
+// refund returns a reserved token whose caller gave up waiting
+func (b *tokenBucket) refund() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,70 @@
+package eventx
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy configures how in-process buses retry a failing handler before
+// the event counts as failed and goes to BusConfig.DeadLetterSink
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int `json:"max_retries"`
+
+	// Backoff is the wait before the first retry
+	Backoff time.Duration `json:"backoff"`
+
+	// Multiplier grows the wait after each retry; values below 1 keep it constant
+	Multiplier float64 `json:"multiplier"`
+
+	// MaxBackoff caps the wait between retries (0 for no cap)
+	MaxBackoff time.Duration `json:"max_backoff"`
+}
+
+// WithHandlerRetry returns a copy of the config that retries failing handlers
+// maxRetries times, waiting backoff before the first retry and doubling the
+// wait after each one. It applies to the in-process buses and to the RabbitMQ
+// and Redis consumers.
+func (c BusConfig) WithHandlerRetry(maxRetries int, backoff time.Duration) BusConfig {
+	c.HandlerRetry = RetryPolicy{MaxRetries: maxRetries, Backoff: backoff, Multiplier: 2}
+	return c
+}
+
+// Delay returns the wait before the given retry, counting from 1
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := float64(p.Backoff)
+	for i := 1; i < retry && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// CallHandlerWithRetry runs handler for event with CallHandler, retrying
+// failures as policy allows. It returns the number of attempts and the last
+// error, or nil once an attempt succeeds. Retrying stops early when ctx is
+// done, so the caller is never held up longer than ctx allows.
+func CallHandlerWithRetry(ctx context.Context, handler EventHandler, event Event, policy RetryPolicy) (int, error) {
+	attempts := 1
+	err := CallHandler(handler, event)
+
+	for retry := 1; err != nil && retry <= policy.MaxRetries; retry++ {
+		timer := time.NewTimer(policy.Delay(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, err
+		case <-timer.C:
+		}
+
+		attempts++
+		err = CallHandler(handler, event)
+	}
+	return attempts, err
+}
@@ -0,0 +1,102 @@
+package eventx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Router splits one event stream between handlers by predicate. Routes are
+// evaluated in the order they were added; by default each event goes to the
+// first matching route only, or with FanOut to every matching route. Events
+// no route matches go to the default handler, or are dropped without one.
+//
+//	router := eventx.NewRouter().
+//		AddRoute(isSuspicious, fraudPipeline).
+//		Default(settlePayment)
+//	router.Subscribe(ctx, bus, "payment.received")
+type Router struct {
+	mutex    sync.RWMutex
+	routes   []route
+	fallback EventHandler
+	fanOut   bool
+}
+
+// route is a handler with the predicate selecting its events
+type route struct {
+	predicate EventFilter
+	handler   EventHandler
+}
+
+// NewRouter creates a router without routes
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// AddRoute sends events for which predicate returns true to handler
+func (r *Router) AddRoute(predicate EventFilter, handler EventHandler) *Router {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes = append(r.routes, route{predicate: predicate, handler: handler})
+	return r
+}
+
+// Default sends events no route matches to handler
+func (r *Router) Default(handler EventHandler) *Router {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.fallback = handler
+	return r
+}
+
+// FanOut sends each event to every matching route instead of the first one
+func (r *Router) FanOut() *Router {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.fanOut = true
+	return r
+}
+
+// Handle routes event (it is an EventHandler). With FanOut the errors of
+// failed routes are returned joined; a panicking route fails with
+// ErrHandlerFailed without keeping the others from running.
+func (r *Router) Handle(event Event) error {
+	r.mutex.RLock()
+	routes := r.routes
+	fallback := r.fallback
+	fanOut := r.fanOut
+	r.mutex.RUnlock()
+
+	var errs []error
+	matched := false
+	for _, rt := range routes {
+		if !rt.predicate(event) {
+			continue
+		}
+		matched = true
+
+		if !fanOut {
+			return rt.handler(event)
+		}
+		if err := CallHandler(rt.handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !matched && fallback != nil {
+		return fallback(event)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers the router on bus for eventType, which may be a
+// pattern such as "payment.*"
+func (r *Router) Subscribe(ctx context.Context, bus EventBus, eventType string) error {
+	if IsPattern(eventType) {
+		return SubscribePattern(bus, ctx, eventType, r.Handle)
+	}
+	return bus.Subscribe(ctx, eventType, r.Handle)
+}
@@ -0,0 +1,181 @@
+package eventx_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+type payment struct {
+	ID      string
+	Amount  int
+	Country string
+}
+
+func paymentOf(e eventx.Event) payment {
+	p, _ := e.Payload().(payment)
+	return p
+}
+
+func isLarge(e eventx.Event) bool   { return paymentOf(e).Amount >= 1000 }
+func isForeign(e eventx.Event) bool { return paymentOf(e).Country != "PE" }
+
+// routeLog records which route received which payment
+type routeLog struct {
+	mutex    sync.Mutex
+	received map[string][]string
+}
+
+func newRouteLog() *routeLog {
+	return &routeLog{received: map[string][]string{}}
+}
+
+func (l *routeLog) handler(route string) eventx.EventHandler {
+	return func(e eventx.Event) error {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		l.received[route] = append(l.received[route], paymentOf(e).ID)
+		return nil
+	}
+}
+
+func (l *routeLog) snapshot() map[string][]string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	received := map[string][]string{}
+	for route, ids := range l.received {
+		received[route] = append([]string(nil), ids...)
+	}
+	return received
+}
+
+var payments = []payment{
+	{ID: "p1", Amount: 50, Country: "PE"},
+	{ID: "p2", Amount: 5000, Country: "PE"},
+	{ID: "p3", Amount: 80, Country: "US"},
+	{ID: "p4", Amount: 9000, Country: "US"},
+}
+
+func TestRouter(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func(log *routeLog) *eventx.Router
+		wantRoutes map[string][]string
+	}{
+		{
+			name: "first matching route with a default",
+			build: func(log *routeLog) *eventx.Router {
+				return eventx.NewRouter().
+					AddRoute(isLarge, log.handler("fraud")).
+					AddRoute(isForeign, log.handler("foreign")).
+					Default(log.handler("settle"))
+			},
+			wantRoutes: map[string][]string{
+				"fraud":   {"p2", "p4"},
+				"foreign": {"p3"},
+				"settle":  {"p1"},
+			},
+		},
+		{
+			name: "fan out to every matching route",
+			build: func(log *routeLog) *eventx.Router {
+				return eventx.NewRouter().
+					AddRoute(isLarge, log.handler("fraud")).
+					AddRoute(isForeign, log.handler("foreign")).
+					Default(log.handler("settle")).
+					FanOut()
+			},
+			wantRoutes: map[string][]string{
+				"fraud":   {"p2", "p4"},
+				"foreign": {"p3", "p4"},
+				"settle":  {"p1"},
+			},
+		},
+		{
+			name: "unmatched events are dropped without a default",
+			build: func(log *routeLog) *eventx.Router {
+				return eventx.NewRouter().AddRoute(isLarge, log.handler("fraud"))
+			},
+			wantRoutes: map[string][]string{"fraud": {"p2", "p4"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newRouteLog()
+			router := tt.build(log)
+
+			for _, p := range payments {
+				if err := router.Handle(eventx.NewEvent("payment.received", p)); err != nil {
+					t.Fatalf("Handle(%s): %v", p.ID, err)
+				}
+			}
+
+			if got := log.snapshot(); !reflect.DeepEqual(got, tt.wantRoutes) {
+				t.Errorf("routes = %v, want %v", got, tt.wantRoutes)
+			}
+		})
+	}
+}
+
+func TestRouterErrors(t *testing.T) {
+	errFraud := errors.New("fraud check unavailable")
+	failing := func(eventx.Event) error { return errFraud }
+	panicking := func(eventx.Event) error { panic("boom") }
+	large := eventx.NewEvent("payment.received", payments[3])
+
+	t.Run("first match returns its error", func(t *testing.T) {
+		router := eventx.NewRouter().AddRoute(isLarge, failing)
+		if err := router.Handle(large); !errors.Is(err, errFraud) {
+			t.Errorf("err = %v, want %v", err, errFraud)
+		}
+	})
+
+	t.Run("fan out runs every route and joins errors", func(t *testing.T) {
+		log := newRouteLog()
+		router := eventx.NewRouter().
+			AddRoute(isLarge, panicking).
+			AddRoute(isLarge, failing).
+			AddRoute(isForeign, log.handler("foreign")).
+			FanOut()
+
+		err := router.Handle(large)
+		if !errors.Is(err, errFraud) || !errx.IsCode(err, eventx.ErrHandlerFailed) {
+			t.Errorf("err = %v, want the route error and ErrHandlerFailed for the panic", err)
+		}
+		if got := log.snapshot()["foreign"]; !reflect.DeepEqual(got, []string{"p4"}) {
+			t.Errorf("foreign route received %v, want [p4]", got)
+		}
+	})
+}
+
+func TestRouterSubscribe(t *testing.T) {
+	cfg := eventx.DefaultBusConfig()
+	cfg.EnableLogging = false
+	bus := eventxmemory.New(cfg)
+
+	log := newRouteLog()
+	router := eventx.NewRouter().
+		AddRoute(isLarge, log.handler("fraud")).
+		Default(log.handler("settle"))
+	if err := router.Subscribe(context.Background(), bus, "payment.*"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	for i, eventType := range []string{"payment.received", "payment.refunded"} {
+		if err := bus.Publish(context.Background(), eventx.NewEvent(eventType, payments[i])); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	want := map[string][]string{"settle": {"p1"}, "fraud": {"p2"}}
+	if got := log.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+}
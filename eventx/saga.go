@@ -0,0 +1,272 @@
+package eventx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Metadata keys correlating saga commands with their replies
+const (
+	MetadataSagaID   = "eventx_saga_id"
+	MetadataSagaStep = "eventx_saga_step"
+)
+
+// DefaultSagaStepTimeout bounds how long a step waits for its reply
+const DefaultSagaStepTimeout = 30 * time.Second
+
+// SagaStep is one step of a saga over state T
+type SagaStep[T any] struct {
+	// Name identifies the step in results and errors
+	Name string
+
+	// Action runs the step. It returns the command event to publish, or nil
+	// for a local step that completes when Action returns.
+	Action func(ctx context.Context, state *T) (Event, error)
+
+	// CompletedOn is the reply event type that completes the step. Handlers
+	// reply with SagaReply so the reply carries the saga ID. Leave empty for
+	// steps that complete once their command is published.
+	CompletedOn string
+
+	// FailedOn is an optional reply event type that fails the step
+	FailedOn string
+
+	// OnReply merges the completion reply into the state before the next step
+	OnReply func(state *T, reply Event) error
+
+	// Compensate undoes the step. It runs, in reverse step order, for every
+	// completed step when a later step fails.
+	Compensate func(ctx context.Context, state *T) error
+
+	// Timeout overrides the saga's step timeout for this step
+	Timeout time.Duration
+}
+
+// SagaStatus is the outcome of a saga run
+type SagaStatus string
+
+const (
+	SagaCompleted   SagaStatus = "completed"
+	SagaCompensated SagaStatus = "compensated" // A step failed and every compensation succeeded
+	SagaFailed      SagaStatus = "failed"      // A step failed and a compensation failed too
+)
+
+// SagaResult reports how a saga run ended
+type SagaResult struct {
+	ID                 string           `json:"id"`
+	Status             SagaStatus       `json:"status"`
+	CompletedSteps     []string         `json:"completed_steps"`
+	FailedStep         string           `json:"failed_step,omitempty"`
+	Err                error            `json:"-"`
+	CompensationErrors map[string]error `json:"-"`
+}
+
+// Saga coordinates a sequence of steps over a bus, running compensations in
+// reverse when a step fails. One Saga can run many times concurrently; replies
+// are routed to their run by the saga ID in their metadata.
+type Saga[T any] struct {
+	name        string
+	bus         EventBus
+	steps       []SagaStep[T]
+	stepTimeout time.Duration
+
+	subscribeOnce sync.Once
+	subscribeErr  error
+	waiters       map[string]chan Event // saga ID -> reply channel
+	mutex         sync.Mutex
+}
+
+// NewSaga creates a saga named name that publishes its commands to bus
+func NewSaga[T any](bus EventBus, name string) *Saga[T] {
+	return &Saga[T]{
+		name:        name,
+		bus:         bus,
+		stepTimeout: DefaultSagaStepTimeout,
+		waiters:     make(map[string]chan Event),
+	}
+}
+
+// Step appends a step to the saga
+func (s *Saga[T]) Step(step SagaStep[T]) *Saga[T] {
+	s.steps = append(s.steps, step)
+	return s
+}
+
+// WithStepTimeout sets how long steps wait for their reply
+func (s *Saga[T]) WithStepTimeout(timeout time.Duration) *Saga[T] {
+	s.stepTimeout = timeout
+	return s
+}
+
+// SagaReply creates a reply to a saga command, carrying the command's saga
+// correlation metadata so the coordinator can route it
+func SagaReply[D any](command Event, eventType string, data D) TypedEvent[D] {
+	opts := DefaultEventOptions()
+	for _, key := range []string{MetadataSagaID, MetadataSagaStep} {
+		if value, ok := command.Metadata()[key]; ok {
+			opts.Metadata[key] = value
+		}
+	}
+	return NewEvent(eventType, data, opts)
+}
+
+// Run executes the steps in order against state. If a step fails, times out
+// or is rejected with its FailedOn event, the completed steps are compensated
+// in reverse order and an ErrSagaFailed error is returned with the result.
+func (s *Saga[T]) Run(ctx context.Context, state *T) (*SagaResult, error) {
+	if err := s.subscribe(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &SagaResult{ID: generateID(), Status: SagaCompleted}
+	replies := make(chan Event, len(s.steps)*2)
+	s.mutex.Lock()
+	s.waiters[result.ID] = replies
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.waiters, result.ID)
+		s.mutex.Unlock()
+	}()
+
+	for i, step := range s.steps {
+		if err := s.runStep(ctx, result.ID, i, step, state, replies); err != nil {
+			result.FailedStep = step.Name
+			result.Err = err
+			s.compensate(ctx, result, state, i)
+			return result, ErrorRegistry.New(ErrSagaFailed).
+				WithCause(err).
+				WithDetail("saga", s.name).
+				WithDetail("saga_id", result.ID).
+				WithDetail("step", step.Name).
+				WithDetail("status", string(result.Status))
+		}
+		result.CompletedSteps = append(result.CompletedSteps, step.Name)
+	}
+
+	return result, nil
+}
+
+// runStep runs one step and waits for its reply
+func (s *Saga[T]) runStep(ctx context.Context, sagaID string, index int, step SagaStep[T], state *T, replies chan Event) error {
+	command, err := step.Action(ctx, state)
+	if err != nil {
+		return err
+	}
+	if command == nil {
+		return nil
+	}
+
+	metadata := command.Metadata()
+	if metadata == nil {
+		return ErrorRegistry.New(ErrInvalidConfiguration).
+			WithDetail("saga", s.name).
+			WithDetail("step", step.Name).
+			WithDetail("reason", "command event has no metadata map")
+	}
+	metadata[MetadataSagaID] = sagaID
+	metadata[MetadataSagaStep] = index
+
+	if err := s.bus.Publish(ctx, command); err != nil {
+		return err
+	}
+	if step.CompletedOn == "" {
+		return nil
+	}
+
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = s.stepTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case reply := <-replies:
+			switch reply.Type() {
+			case step.CompletedOn:
+				if step.OnReply != nil {
+					return step.OnReply(state, reply)
+				}
+				return nil
+			case step.FailedOn:
+				return fmt.Errorf("step %s rejected: %s: %v", step.Name, reply.Type(), reply.Payload())
+			}
+			// A late reply to an earlier step; keep waiting
+		case <-timer.C:
+			return ErrorRegistry.New(ErrTimeout).
+				WithDetail("saga", s.name).
+				WithDetail("step", step.Name).
+				WithDetail("timeout", timeout.String())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// compensate undoes the steps before failed in reverse order. Compensations
+// run even if ctx is cancelled, since they restore consistency.
+func (s *Saga[T]) compensate(ctx context.Context, result *SagaResult, state *T, failed int) {
+	result.Status = SagaCompensated
+	compensationCtx := context.WithoutCancel(ctx)
+
+	for i := failed - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := step.Compensate(compensationCtx, state); err != nil {
+			if result.CompensationErrors == nil {
+				result.CompensationErrors = make(map[string]error)
+			}
+			result.CompensationErrors[step.Name] = err
+			result.Status = SagaFailed
+		}
+	}
+}
+
+// subscribe registers the reply handlers once for all runs
+func (s *Saga[T]) subscribe(ctx context.Context) error {
+	s.subscribeOnce.Do(func() {
+		subscribed := make(map[string]bool)
+		for _, step := range s.steps {
+			for _, eventType := range []string{step.CompletedOn, step.FailedOn} {
+				if eventType == "" || subscribed[eventType] {
+					continue
+				}
+				subscribed[eventType] = true
+				if err := s.bus.Subscribe(ctx, eventType, s.route); err != nil {
+					s.subscribeErr = err
+					return
+				}
+			}
+		}
+	})
+	return s.subscribeErr
+}
+
+// route delivers a reply to the run waiting for it. Replies for unknown or
+// finished runs are ignored.
+func (s *Saga[T]) route(event Event) error {
+	sagaID, _ := event.Metadata()[MetadataSagaID].(string)
+	if sagaID == "" {
+		return nil
+	}
+
+	s.mutex.Lock()
+	replies, ok := s.waiters[sagaID]
+	s.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case replies <- event:
+	default:
+		// The run isn't waiting for this many replies; drop duplicates
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package eventx_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+type orderState struct {
+	ReservationID string
+	ChargeID      string
+}
+
+// orderSaga reserves stock, charges the payment and ships, against services
+// subscribed to bus. Its journal records actions and compensations in order.
+type orderSaga struct {
+	mutex   sync.Mutex
+	journal []string
+}
+
+func (o *orderSaga) record(entry string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.journal = append(o.journal, entry)
+}
+
+func (o *orderSaga) entries() []string {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return append([]string(nil), o.journal...)
+}
+
+func (o *orderSaga) build(bus eventx.EventBus, releaseErr error) *eventx.Saga[orderState] {
+	return eventx.NewSaga[orderState](bus, "place-order").
+		WithStepTimeout(50 * time.Millisecond).
+		Step(eventx.SagaStep[orderState]{
+			Name: "reserve",
+			Action: func(ctx context.Context, state *orderState) (eventx.Event, error) {
+				o.record("reserve")
+				return eventx.NewEvent("inventory.reserve", "sku-1"), nil
+			},
+			CompletedOn: "inventory.reserved",
+			FailedOn:    "inventory.rejected",
+			OnReply: func(state *orderState, reply eventx.Event) error {
+				state.ReservationID, _ = reply.Payload().(string)
+				return nil
+			},
+			Compensate: func(ctx context.Context, state *orderState) error {
+				o.record("release " + state.ReservationID)
+				return releaseErr
+			},
+		}).
+		Step(eventx.SagaStep[orderState]{
+			Name: "charge",
+			Action: func(ctx context.Context, state *orderState) (eventx.Event, error) {
+				o.record("charge")
+				return eventx.NewEvent("payment.charge", 100), nil
+			},
+			CompletedOn: "payment.charged",
+			FailedOn:    "payment.declined",
+			OnReply: func(state *orderState, reply eventx.Event) error {
+				state.ChargeID, _ = reply.Payload().(string)
+				return nil
+			},
+			Compensate: func(ctx context.Context, state *orderState) error {
+				o.record("refund " + state.ChargeID)
+				return nil
+			},
+		}).
+		Step(eventx.SagaStep[orderState]{
+			Name: "ship",
+			Action: func(ctx context.Context, state *orderState) (eventx.Event, error) {
+				o.record("ship")
+				return eventx.NewEvent("shipment.create", state.ReservationID), nil
+			},
+			CompletedOn: "shipment.created",
+		})
+}
+
+// serveCommand subscribes a service that answers commandType with replyType, or
+// doesn't answer when replyType is empty
+func serveCommand(t *testing.T, bus eventx.EventBus, commandType, replyType string, data any) {
+	t.Helper()
+
+	err := bus.Subscribe(context.Background(), commandType, func(command eventx.Event) error {
+		if replyType == "" {
+			return nil
+		}
+		return bus.Publish(context.Background(), eventx.SagaReply(command, replyType, data))
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+}
+
+func TestSaga(t *testing.T) {
+	tests := []struct {
+		name          string
+		paymentReply  string
+		shipmentReply string
+		releaseErr    error
+		wantStatus    eventx.SagaStatus
+		wantCompleted []string
+		wantFailed    string
+		wantJournal   []string
+		wantState     orderState
+	}{
+		{
+			name:          "all steps complete",
+			paymentReply:  "payment.charged",
+			shipmentReply: "shipment.created",
+			wantStatus:    eventx.SagaCompleted,
+			wantCompleted: []string{"reserve", "charge", "ship"},
+			wantJournal:   []string{"reserve", "charge", "ship"},
+			wantState:     orderState{ReservationID: "res-1", ChargeID: "ch-1"},
+		},
+		{
+			name:          "step 2 fails and step 1 is compensated",
+			paymentReply:  "payment.declined",
+			wantStatus:    eventx.SagaCompensated,
+			wantCompleted: []string{"reserve"},
+			wantFailed:    "charge",
+			wantJournal:   []string{"reserve", "charge", "release res-1"},
+			wantState:     orderState{ReservationID: "res-1"},
+		},
+		{
+			name:          "step 3 times out and compensations run in reverse",
+			paymentReply:  "payment.charged",
+			wantStatus:    eventx.SagaCompensated,
+			wantCompleted: []string{"reserve", "charge"},
+			wantFailed:    "ship",
+			wantJournal:   []string{"reserve", "charge", "ship", "refund ch-1", "release res-1"},
+			wantState:     orderState{ReservationID: "res-1", ChargeID: "ch-1"},
+		},
+		{
+			name:          "failed compensation fails the saga",
+			paymentReply:  "payment.declined",
+			releaseErr:    errors.New("inventory unavailable"),
+			wantStatus:    eventx.SagaFailed,
+			wantCompleted: []string{"reserve"},
+			wantFailed:    "charge",
+			wantJournal:   []string{"reserve", "charge", "release res-1"},
+			wantState:     orderState{ReservationID: "res-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := eventx.DefaultBusConfig()
+			cfg.EnableLogging = false
+			bus := eventxmemory.New(cfg)
+			if err := bus.Connect(context.Background()); err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			t.Cleanup(func() { bus.Disconnect(context.Background()) })
+
+			serveCommand(t, bus, "inventory.reserve", "inventory.reserved", "res-1")
+			serveCommand(t, bus, "payment.charge", tt.paymentReply, "ch-1")
+			serveCommand(t, bus, "shipment.create", tt.shipmentReply, "sh-1")
+
+			saga := &orderSaga{}
+			var state orderState
+			result, err := saga.build(bus, tt.releaseErr).Run(context.Background(), &state)
+
+			if tt.wantStatus == eventx.SagaCompleted {
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+			} else if !eventx.IsSagaFailed(err) {
+				t.Fatalf("err = %v, want ErrSagaFailed", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(result.CompletedSteps, tt.wantCompleted) {
+				t.Errorf("completed steps = %v, want %v", result.CompletedSteps, tt.wantCompleted)
+			}
+			if result.FailedStep != tt.wantFailed {
+				t.Errorf("failed step = %q, want %q", result.FailedStep, tt.wantFailed)
+			}
+			if got := saga.entries(); !reflect.DeepEqual(got, tt.wantJournal) {
+				t.Errorf("journal = %v, want %v", got, tt.wantJournal)
+			}
+			if state != tt.wantState {
+				t.Errorf("state = %+v, want %+v", state, tt.wantState)
+			}
+			if tt.releaseErr != nil && !errors.Is(result.CompensationErrors["reserve"], tt.releaseErr) {
+				t.Errorf("compensation errors = %v, want reserve: %v", result.CompensationErrors, tt.releaseErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package eventx
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// SequenceScope controls how a bus numbers published events
+type SequenceScope string
+
+const (
+	// SequenceDisabled leaves events unnumbered (default)
+	SequenceDisabled SequenceScope = ""
+	// SequencePerType numbers events independently for each event type
+	SequencePerType SequenceScope = "per_type"
+	// SequenceGlobal numbers all events from a single counter
+	SequenceGlobal SequenceScope = "global"
+)
+
+// Metadata keys set by buses that number events
+const (
+	// MetadataSequence holds the bus-assigned sequence number (starting at 1)
+	MetadataSequence = "eventx_sequence"
+	// MetadataOffset holds the backend's native offset, e.g. the SQS FIFO SequenceNumber
+	MetadataOffset = "eventx_offset"
+)
+
+// Sequencer assigns monotonic sequence numbers to events. It is safe for
+// concurrent use; numbers are monotonic in the order Assign is called.
+type Sequencer struct {
+	scope   SequenceScope
+	global  atomic.Uint64
+	mutex   sync.Mutex
+	perType map[string]*atomic.Uint64
+}
+
+// NewSequencer creates a sequencer for the given scope
+func NewSequencer(scope SequenceScope) *Sequencer {
+	return &Sequencer{
+		scope:   scope,
+		perType: make(map[string]*atomic.Uint64),
+	}
+}
+
+// Enabled reports whether the sequencer assigns numbers
+func (s *Sequencer) Enabled() bool {
+	return s != nil && (s.scope == SequencePerType || s.scope == SequenceGlobal)
+}
+
+// Next returns the next sequence number for an event type
+func (s *Sequencer) Next(eventType string) (uint64, bool) {
+	switch {
+	case !s.Enabled():
+		return 0, false
+	case s.scope == SequenceGlobal:
+		return s.global.Add(1), true
+	}
+
+	s.mutex.Lock()
+	counter, exists := s.perType[eventType]
+	if !exists {
+		counter = &atomic.Uint64{}
+		s.perType[eventType] = counter
+	}
+	s.mutex.Unlock()
+
+	return counter.Add(1), true
+}
+
+// Assign stores the next sequence number in the event metadata. It returns
+// false when sequencing is disabled or the event has no metadata map.
+func (s *Sequencer) Assign(event Event) (uint64, bool) {
+	metadata := event.Metadata()
+	if metadata == nil {
+		return 0, false
+	}
+
+	seq, ok := s.Next(event.Type())
+	if !ok {
+		return 0, false
+	}
+
+	metadata[MetadataSequence] = seq
+	return seq, true
+}
+
+// Sequence returns the bus-assigned sequence number of an event. It accepts
+// the numeric forms metadata takes after a JSON round trip.
+func Sequence(event Event) (uint64, bool) {
+	value, exists := event.Metadata()[MetadataSequence]
+	if !exists {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case int:
+		return uint64(v), v >= 0
+	case int64:
+		return uint64(v), v >= 0
+	case float64:
+		return uint64(v), v >= 0 && v <= math.MaxUint64 && v == math.Trunc(v)
+	case json.Number:
+		seq, err := strconv.ParseUint(v.String(), 10, 64)
+		return seq, err == nil
+	case string:
+		seq, err := strconv.ParseUint(v, 10, 64)
+		return seq, err == nil
+	}
+
+	return 0, false
+}
+
+// Offset returns the backend's native offset for an event, if the bus provides one
+func Offset(event Event) (string, bool) {
+	offset, ok := event.Metadata()[MetadataOffset].(string)
+	return offset, ok && offset != ""
+}
@@ -0,0 +1,61 @@
+package eventx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/eventx"
+)
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    uint64
+		wantSet bool
+	}{
+		{name: "uint64", value: uint64(7), want: 7, wantSet: true},
+		{name: "int", value: 7, want: 7, wantSet: true},
+		{name: "float64 after JSON", value: float64(7), want: 7, wantSet: true},
+		{name: "json.Number", value: json.Number("7"), want: 7, wantSet: true},
+		{name: "string", value: "7", want: 7, wantSet: true},
+		{name: "negative", value: -1},
+		{name: "fractional", value: 7.5},
+		{name: "not a number", value: "seven"},
+		{name: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := eventx.DefaultEventOptions()
+			if tt.value != nil {
+				opts = opts.WithMetadata(eventx.MetadataSequence, tt.value)
+			}
+			event := eventx.NewEvent("tick", 1, opts)
+
+			got, ok := eventx.Sequence(event)
+			if ok != tt.wantSet || (ok && got != tt.want) {
+				t.Errorf("Sequence() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestSequencerAssign(t *testing.T) {
+	sequencer := eventx.NewSequencer(eventx.SequencePerType)
+
+	for want := uint64(1); want <= 3; want++ {
+		event := eventx.NewEvent("tick", 1)
+		if got, ok := sequencer.Assign(event); !ok || got != want {
+			t.Fatalf("Assign() = %d, %v, want %d", got, ok, want)
+		}
+		if got, _ := eventx.Sequence(event); got != want {
+			t.Errorf("Sequence() after Assign = %d, want %d", got, want)
+		}
+	}
+
+	var disabled *eventx.Sequencer
+	if _, ok := disabled.Next("tick"); ok {
+		t.Error("nil sequencer assigned a number")
+	}
+}
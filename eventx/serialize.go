@@ -14,6 +14,11 @@ type SerializableEvent struct {
 	Version   string          `json:"version"`
 	Data      json.RawMessage `json:"data"`
 	Metadata  map[string]any  `json:"metadata"`
+
+	// Encoding and DataRef are set by MarshalEvent for compressed and
+	// claim-checked payloads; UnmarshalEvent clears them
+	Encoding string `json:"encoding,omitempty"`
+	DataRef  string `json:"data_ref,omitempty"`
 }
 
 // ToSerializable converts an event to a serializable format
@@ -0,0 +1,166 @@
+package eventx
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// HandlerEventBus extends EventBus with removing a single handler, where
+// Unsubscribe removes every handler of an event type
+type HandlerEventBus interface {
+	EventBus
+
+	// SubscribeHandler registers handler for an event type, or a pattern when
+	// the bus implements PatternEventBus, and returns an ID for UnsubscribeHandler
+	SubscribeHandler(ctx context.Context, eventType string, handler EventHandler) (string, error)
+
+	// UnsubscribeHandler removes the handler registered under id. Unknown IDs,
+	// including already removed ones, are ignored.
+	UnsubscribeHandler(ctx context.Context, id string) error
+}
+
+// Subscription is a handle to one registered handler, returned by
+// SubscribeHandle and SubscribeTypedHandle
+type Subscription struct {
+	eventType   string
+	removed     atomic.Bool
+	mutex       sync.Mutex
+	running     map[uint64]int // Handler calls in progress per goroutine, guarded by mutex
+	idle        *sync.Cond     // Signalled when a goroutine's last call returns
+	once        sync.Once
+	err         error
+	unsubscribe func() error
+}
+
+// newSubscription creates an active subscription to eventType
+func newSubscription(eventType string) *Subscription {
+	sub := &Subscription{eventType: eventType, running: make(map[uint64]int)}
+	sub.idle = sync.NewCond(&sub.mutex)
+	return sub
+}
+
+// EventType returns the event type or pattern subscribed to
+func (s *Subscription) EventType() string {
+	return s.eventType
+}
+
+// Unsubscribe removes the handler. It waits for handler calls already
+// running to finish, and once it returns the handler is not invoked again,
+// even for events a concurrent Publish had already picked up. A handler may
+// unsubscribe its own subscription; called from inside a handler call,
+// Unsubscribe doesn't wait. Calling it again returns the first call's result.
+func (s *Subscription) Unsubscribe() error {
+	self := goroutineID()
+
+	s.mutex.Lock()
+	s.removed.Store(true)
+	if s.running[self] == 0 {
+		for len(s.running) > 0 {
+			s.idle.Wait()
+		}
+	}
+	s.mutex.Unlock()
+
+	s.once.Do(func() {
+		s.err = s.unsubscribe()
+	})
+	return s.err
+}
+
+// call runs handler unless the subscription was removed, keeping Unsubscribe
+// from returning until it does. Handlers may publish events that call it
+// again on the same goroutine.
+func (s *Subscription) call(handler EventHandler, event Event) error {
+	if s.removed.Load() {
+		return nil
+	}
+
+	self := goroutineID()
+	s.mutex.Lock()
+	if s.removed.Load() {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.running[self]++
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		if s.running[self]--; s.running[self] == 0 {
+			delete(s.running, self)
+			s.idle.Broadcast()
+		}
+		s.mutex.Unlock()
+	}()
+	return handler(event)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [...]" header of its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+// Close is Unsubscribe, so a Subscription can be used as an io.Closer
+func (s *Subscription) Close() error {
+	return s.Unsubscribe()
+}
+
+// SubscribeHandle registers handler for eventType, or for a pattern such as
+// "user.*", and returns a Subscription that removes just this handler, e.g.
+// for request-scoped listeners or plugins. On a HandlerEventBus the handler is
+// removed from the bus; on other buses it stays registered but is no longer
+// called.
+//
+//	sub, err := eventx.SubscribeHandle(bus, ctx, "order.shipped", notifyClient)
+//	if err != nil {
+//		return err
+//	}
+//	defer sub.Close()
+func SubscribeHandle(bus EventBus, ctx context.Context, eventType string, handler EventHandler) (*Subscription, error) {
+	if IsPattern(eventType) {
+		if err := ValidatePattern(eventType); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := newSubscription(eventType)
+	guarded := func(event Event) error {
+		return sub.call(handler, event)
+	}
+
+	if hb, ok := bus.(HandlerEventBus); ok {
+		id, err := hb.SubscribeHandler(ctx, eventType, guarded)
+		if err != nil {
+			return nil, err
+		}
+		sub.unsubscribe = func() error {
+			return hb.UnsubscribeHandler(context.WithoutCancel(ctx), id)
+		}
+		return sub, nil
+	}
+
+	var err error
+	if IsPattern(eventType) {
+		err = SubscribePattern(bus, ctx, eventType, guarded)
+	} else {
+		err = bus.Subscribe(ctx, eventType, guarded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub.unsubscribe = func() error { return nil }
+	return sub, nil
+}
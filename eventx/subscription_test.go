@@ -0,0 +1,223 @@
+package eventx_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/eventx"
+	"github.com/Abraxas-365/craftable/eventx/providers/eventxmemory"
+)
+
+func newSubscriptionBus(t *testing.T) eventx.EventBus {
+	t.Helper()
+	cfg := eventx.DefaultBusConfig()
+	cfg.EnableLogging = false
+	return eventxmemory.New(cfg)
+}
+
+// within fails the test when fn doesn't return within timeout
+func within(t *testing.T, timeout time.Duration, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("%s didn't return within %v", what, timeout)
+	}
+}
+
+func TestUnsubscribeWaitsForRunningHandler(t *testing.T) {
+	ctx := context.Background()
+	bus := newSubscriptionBus(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	sub, err := eventx.SubscribeHandle(bus, ctx, "job.run", func(eventx.Event) error {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandle: %v", err)
+	}
+
+	go bus.Publish(ctx, eventx.NewEvent("job.run", 1))
+	<-started
+
+	unsubscribed := make(chan error, 1)
+	go func() { unsubscribed <- sub.Unsubscribe() }()
+
+	select {
+	case <-unsubscribed:
+		t.Fatal("Unsubscribe returned while the handler was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-unsubscribed:
+		if err != nil {
+			t.Fatalf("Unsubscribe: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Unsubscribe didn't return after the handler finished")
+	}
+
+	if err := bus.Publish(ctx, eventx.NewEvent("job.run", 2)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler calls = %d, want 1", got)
+	}
+}
+
+func TestUnsubscribeRacingPublish(t *testing.T) {
+	ctx := context.Background()
+	bus := newSubscriptionBus(t)
+
+	var returned atomic.Bool
+	var late atomic.Int32
+	sub, err := eventx.SubscribeHandle(bus, ctx, "tick", func(eventx.Event) error {
+		if returned.Load() {
+			late.Add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandle: %v", err)
+	}
+
+	stop := make(chan struct{})
+	var publishers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		publishers.Add(1)
+		go func() {
+			defer publishers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					bus.Publish(ctx, eventx.NewEvent("tick", i))
+				}
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	within(t, time.Second, "Unsubscribe", func() {
+		if err := sub.Unsubscribe(); err != nil {
+			t.Errorf("Unsubscribe: %v", err)
+		}
+		returned.Store(true)
+	})
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	publishers.Wait()
+
+	if got := late.Load(); got != 0 {
+		t.Errorf("handler invoked %d times after Unsubscribe returned", got)
+	}
+}
+
+func TestHandlerUnsubscribesItself(t *testing.T) {
+	ctx := context.Background()
+	bus := newSubscriptionBus(t)
+
+	var sub *eventx.Subscription
+	var calls atomic.Int32
+	sub, err := eventx.SubscribeHandle(bus, ctx, "once", func(eventx.Event) error {
+		calls.Add(1)
+		return sub.Unsubscribe()
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandle: %v", err)
+	}
+
+	within(t, time.Second, "Publish", func() {
+		for i := 0; i < 3; i++ {
+			if err := bus.Publish(ctx, eventx.NewEvent("once", i)); err != nil {
+				t.Errorf("Publish: %v", err)
+			}
+		}
+	})
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler calls = %d, want 1", got)
+	}
+}
+
+func TestHandlersUnsubscribingConcurrently(t *testing.T) {
+	ctx := context.Background()
+	bus := newSubscriptionBus(t)
+
+	// Two calls run at once and both unsubscribe; neither may wait for the other
+	var sub *eventx.Subscription
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	sub, err := eventx.SubscribeHandle(bus, ctx, "pair", func(eventx.Event) error {
+		arrived.Done()
+		arrived.Wait()
+		return sub.Unsubscribe()
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandle: %v", err)
+	}
+
+	within(t, time.Second, "Publish", func() {
+		var publishers sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			publishers.Add(1)
+			go func() {
+				defer publishers.Done()
+				bus.Publish(ctx, eventx.NewEvent("pair", i))
+			}()
+		}
+		publishers.Wait()
+	})
+}
+
+func TestHandlerPublishesBeforeUnsubscribing(t *testing.T) {
+	ctx := context.Background()
+	bus := newSubscriptionBus(t)
+
+	// The nested call runs on the handler's goroutine, so it is let through
+	// while the outer call is still in progress
+	var sub *eventx.Subscription
+	var depths []int
+	sub, err := eventx.SubscribeHandle(bus, ctx, "nested", func(e eventx.Event) error {
+		depth := e.Payload().(int)
+		depths = append(depths, depth)
+		if depth == 0 {
+			if err := bus.Publish(ctx, eventx.NewEvent("nested", 1)); err != nil {
+				return err
+			}
+			return sub.Unsubscribe()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeHandle: %v", err)
+	}
+
+	within(t, time.Second, "Publish", func() {
+		if err := bus.Publish(ctx, eventx.NewEvent("nested", 0)); err != nil {
+			t.Errorf("Publish: %v", err)
+		}
+		if err := bus.Publish(ctx, eventx.NewEvent("nested", 0)); err != nil {
+			t.Errorf("Publish: %v", err)
+		}
+	})
+	if len(depths) != 2 || depths[0] != 0 || depths[1] != 1 {
+		t.Errorf("handled depths = %v, want [0 1]", depths)
+	}
+}
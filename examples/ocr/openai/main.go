@@ -40,6 +40,7 @@ func main() {
 
 	fmt.Printf("Extracted Text:\n%s\n\n", result.Text)
 	fmt.Printf("Overall Confidence: %.2f\n", result.Confidence)
+	fmt.Printf("Detected Language: %s\n", result.DetectedLanguage)
 	fmt.Printf("Number of Text Blocks: %d\n", len(result.Blocks))
 	fmt.Printf("Processing Time: %d ms\n", result.Usage.ProcessingTime)
 	fmt.Printf("Token Usage: %+v\n", result.Usage)
@@ -0,0 +1,292 @@
+package fmtx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// changeKind classifies a difference found by compareValues
+type changeKind int
+
+const (
+	changeReplaced changeKind = iota // Leaf value differs
+	changeRemoved                    // Map key, or omitempty field, only in a
+	changeAdded                      // Map key, or omitempty field, only in b
+	changeResized                    // Slice length differs; the extra elements are in tail
+)
+
+// pathSegment is one step from the root to a compared value
+type pathSegment struct {
+	field    string // Go field name, or the formatted map key
+	jsonName string // JSON member name for struct fields
+	index    int
+	isIndex  bool
+	isKey    bool
+	inline   bool // Embedded struct whose fields JSON flattens into the parent
+	omitted  bool // Field excluded from JSON with `json:"-"`
+}
+
+// fieldOmission is when encoding/json leaves a struct field out
+type fieldOmission int
+
+const (
+	omitNever fieldOmission = iota
+	omitEmpty               // `json:",omitempty"`
+	omitZero                // `json:",omitzero"`
+)
+
+// valueChange is a single difference reported by compareValues
+type valueChange struct {
+	kind     changeKind
+	path     []pathSegment
+	old, new reflect.Value
+	oldLen   int
+	newLen   int
+	tail     []reflect.Value // Removed elements when the slice shrank, added ones when it grew
+}
+
+// compareValues walks a and b together and reports every changed leaf to
+// visit. Diff and JSONPatch render the same changes differently.
+func compareValues(a, b reflect.Value, path []pathSegment, visit func(valueChange)) {
+	if a.IsValid() && a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.IsValid() && b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if !a.IsValid() && !b.IsValid() {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		visit(valueChange{kind: changeReplaced, path: path, old: a, new: b})
+		return
+	}
+
+	// Compare what pointers point to, so changed pointees report their
+	// changed leaves rather than replacing the whole value
+	if a.Kind() == reflect.Pointer && !a.IsNil() && !b.IsNil() {
+		if a.Pointer() != b.Pointer() {
+			compareValues(a.Elem(), b.Elem(), path, visit)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		compareStructs(a, b, path, visit)
+	case reflect.Slice, reflect.Array:
+		compareSlices(a, b, path, visit)
+	case reflect.Map:
+		compareMaps(a, b, path, visit)
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			visit(valueChange{kind: changeReplaced, path: path, old: a, new: b})
+		}
+	}
+}
+
+func compareStructs(a, b reflect.Value, path []pathSegment, visit func(valueChange)) {
+	t := a.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		aField := a.Field(i)
+		bField := b.Field(i)
+		if !aField.CanInterface() || !bField.CanInterface() {
+			continue
+		}
+
+		segment := pathSegment{field: field.Name, jsonName: field.Name}
+		omission := omitNever
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			name, options, _ := strings.Cut(tag, ",")
+			for option := range strings.SplitSeq(options, ",") {
+				switch option {
+				case "omitempty":
+					omission = omitEmpty
+				case "omitzero":
+					omission = omitZero
+				}
+			}
+			switch {
+			case tag == "-":
+				segment.omitted = true
+			case name != "":
+				segment.jsonName = name
+			case field.Anonymous && field.Type.Kind() == reflect.Struct:
+				segment.inline = true
+			}
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			segment.inline = true
+		}
+
+		fieldPath := appendPath(path, segment)
+
+		// A field JSON omits when empty appears or disappears rather than
+		// changing value
+		aOmitted, bOmitted := omission.omits(aField), omission.omits(bField)
+		switch {
+		case aOmitted && bOmitted:
+			continue
+		case aOmitted:
+			visit(valueChange{kind: changeAdded, path: fieldPath, new: bField})
+			continue
+		case bOmitted:
+			visit(valueChange{kind: changeRemoved, path: fieldPath, old: aField})
+			continue
+		}
+
+		compareValues(aField, bField, fieldPath, visit)
+	}
+}
+
+// omits reports whether encoding/json leaves out a field holding v
+func (o fieldOmission) omits(v reflect.Value) bool {
+	switch o {
+	case omitEmpty:
+		switch v.Kind() {
+		case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+			return v.Len() == 0
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+			return v.IsZero()
+		}
+	case omitZero:
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return true
+		}
+		if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+		return v.IsZero()
+	}
+	return false
+}
+
+func compareSlices(a, b reflect.Value, path []pathSegment, visit func(valueChange)) {
+	aLen := a.Len()
+	bLen := b.Len()
+
+	for i := 0; i < min(aLen, bLen); i++ {
+		compareValues(a.Index(i), b.Index(i), appendPath(path, pathSegment{index: i, isIndex: true}), visit)
+	}
+
+	if aLen == bLen {
+		return
+	}
+
+	change := valueChange{kind: changeResized, path: path, oldLen: aLen, newLen: bLen}
+	if aLen > bLen {
+		for i := bLen; i < aLen; i++ {
+			change.tail = append(change.tail, a.Index(i))
+		}
+	} else {
+		for i := aLen; i < bLen; i++ {
+			change.tail = append(change.tail, b.Index(i))
+		}
+	}
+	visit(change)
+}
+
+func compareMaps(a, b reflect.Value, path []pathSegment, visit func(valueChange)) {
+	// Sorted keys keep the output stable between runs
+	keys := make(map[string]reflect.Value)
+	for _, key := range a.MapKeys() {
+		keys[fmt.Sprint(key.Interface())] = key
+	}
+	for _, key := range b.MapKeys() {
+		keys[fmt.Sprint(key.Interface())] = key
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := keys[name]
+		keyPath := appendPath(path, pathSegment{field: name, isKey: true})
+		aValue := a.MapIndex(key)
+		bValue := b.MapIndex(key)
+
+		switch {
+		case !bValue.IsValid():
+			visit(valueChange{kind: changeRemoved, path: keyPath, old: aValue})
+		case !aValue.IsValid():
+			visit(valueChange{kind: changeAdded, path: keyPath, new: bValue})
+		default:
+			compareValues(aValue, bValue, keyPath, visit)
+		}
+	}
+}
+
+// appendPath returns a new path so sibling branches never share a backing array
+func appendPath(path []pathSegment, segment pathSegment) []pathSegment {
+	next := make([]pathSegment, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, segment)
+}
+
+// Diff functionality
+func diffValues(a, b reflect.Value) string {
+	var result strings.Builder
+
+	compareValues(a, b, nil, func(change valueChange) {
+		path := diffPath(change.path)
+		switch change.kind {
+		case changeReplaced:
+			switch {
+			case !change.old.IsValid():
+				result.WriteString(fmt.Sprintf("- %s: <missing>\n", path))
+				result.WriteString(fmt.Sprintf("+ %s: %v\n", path, change.new.Interface()))
+			case !change.new.IsValid():
+				result.WriteString(fmt.Sprintf("- %s: %v\n", path, change.old.Interface()))
+				result.WriteString(fmt.Sprintf("+ %s: <missing>\n", path))
+			case change.old.Type() != change.new.Type():
+				result.WriteString(fmt.Sprintf("- %s: %v (%s)\n", path, change.old.Interface(), change.old.Type()))
+				result.WriteString(fmt.Sprintf("+ %s: %v (%s)\n", path, change.new.Interface(), change.new.Type()))
+			default:
+				result.WriteString(fmt.Sprintf("- %s: %v\n", path, change.old.Interface()))
+				result.WriteString(fmt.Sprintf("+ %s: %v\n", path, change.new.Interface()))
+			}
+		case changeRemoved:
+			result.WriteString(fmt.Sprintf("- %s: %v\n", path, change.old.Interface()))
+		case changeAdded:
+			result.WriteString(fmt.Sprintf("+ %s: %v\n", path, change.new.Interface()))
+		case changeResized:
+			result.WriteString(fmt.Sprintf("~ %s: length differs (was %d, now %d)\n", path, change.oldLen, change.newLen))
+			sign, start := "+", change.oldLen
+			if change.oldLen > change.newLen {
+				sign, start = "-", change.newLen
+			}
+			for i, elem := range change.tail {
+				result.WriteString(fmt.Sprintf("%s %s[%d]: %v\n", sign, path, start+i, elem.Interface()))
+			}
+		}
+	})
+
+	return result.String()
+}
+
+// diffPath renders a path as Field.Nested[0][key]
+func diffPath(path []pathSegment) string {
+	var sb strings.Builder
+	for _, segment := range path {
+		switch {
+		case segment.isIndex:
+			sb.WriteString(fmt.Sprintf("[%d]", segment.index))
+		case segment.isKey:
+			sb.WriteString("[" + segment.field + "]")
+		default:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(segment.field)
+		}
+	}
+	return sb.String()
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -37,12 +38,29 @@ type DebugOptions struct {
 	UseColors        bool                                        // Use ANSI colors for output
 	MaxStringLength  int                                         // Truncate strings longer than this (0 = no limit)
 	MaxSliceLength   int                                         // Truncate slices longer than this (0 = no limit)
+	TruncateMode     TruncateMode                                // Which slice items survive truncation (default: TruncateTail)
 	SortMapKeys      bool                                        // Sort map keys for consistent output
 	CustomFormatters map[reflect.Type]func(reflect.Value) string // Custom formatters for specific types
 	FieldFilter      func(reflect.StructField) bool              // Filter which fields to show
 	Indent           string                                      // Custom indentation string (default: "    ")
+	Humanize         *HumanizeOptions                            // Human-friendly numbers and byte sizes (nil = raw digits)
+	SafeMode         bool                                        // Recover from panics per value and skip address printing
 }
 
+// TruncateMode selects which items of a long slice are shown
+type TruncateMode int
+
+const (
+	// TruncateTail shows the first MaxSliceLength items and drops the rest
+	TruncateTail TruncateMode = iota
+	// TruncateHeadTail shows the first and last MaxSliceLength items with the
+	// omitted count in between
+	TruncateHeadTail
+)
+
+// Unformattable is rendered in SafeMode for values whose formatting panicked
+const Unformattable = "<unformattable>"
+
 // DefaultOptions returns sensible default options
 func DefaultOptions() DebugOptions {
 	return DebugOptions{
@@ -78,11 +96,24 @@ func CompactOptions() DebugOptions {
 	return opts
 }
 
+// SafeOptions returns default options with SafeMode enabled, for formatting
+// values of unknown shape without risking a panic
+func SafeOptions() DebugOptions {
+	opts := DefaultOptions()
+	opts.SafeMode = true
+	return opts
+}
+
 // Debug prints a value in debug format (basic version)
 func Debug(v any) string {
 	return DebugWithOptions(v, DefaultOptions())
 }
 
+// Safe prints a value in debug format, never panicking
+func Safe(v any) string {
+	return DebugWithOptions(v, SafeOptions())
+}
+
 // Pretty prints a value with colors and extra info
 func Pretty(v any) string {
 	return DebugWithOptions(v, PrettyOptions())
@@ -128,7 +159,7 @@ func JSONPrint(v any) {
 
 // Diff compares two values and shows differences
 func Diff(a, b any) string {
-	return diffValues(reflect.ValueOf(a), reflect.ValueOf(b), "")
+	return diffValues(reflect.ValueOf(a), reflect.ValueOf(b))
 }
 
 func DiffPrint(a, b any) {
@@ -185,7 +216,17 @@ func DebugWithOptions(v any, opts DebugOptions) string {
 	return debugValueWithOptions(reflect.ValueOf(v), 0, opts)
 }
 
-func debugValueWithOptions(v reflect.Value, depth int, opts DebugOptions) string {
+func debugValueWithOptions(v reflect.Value, depth int, opts DebugOptions) (result string) {
+	if opts.SafeMode {
+		// Each nested field/element goes through here, so a panic only
+		// replaces the offending value rather than the whole output
+		defer func() {
+			if r := recover(); r != nil {
+				result = colorize(Unformattable, Red, opts.UseColors)
+			}
+		}()
+	}
+
 	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
 		return colorize("...", Gray, opts.UseColors)
 	}
@@ -194,6 +235,10 @@ func debugValueWithOptions(v reflect.Value, depth int, opts DebugOptions) string
 		return colorize("<invalid>", Red, opts.UseColors)
 	}
 
+	if isSecretType(v.Type()) {
+		return colorize(Redacted, Gray, opts.UseColors)
+	}
+
 	// Check for custom formatter
 	if opts.CustomFormatters != nil {
 		if formatter, exists := opts.CustomFormatters[v.Type()]; exists {
@@ -228,11 +273,22 @@ func debugValueWithOptions(v reflect.Value, depth int, opts DebugOptions) string
 		}
 		return colorize(fmt.Sprintf("%v", v.Interface()), color, opts.UseColors)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if humanized, ok := humanizeInteger(v, nil, opts.Humanize); ok {
+			return colorize(humanized, Cyan, opts.UseColors)
+		}
 		return colorize(fmt.Sprintf("%d", v.Int()), Cyan, opts.UseColors)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if humanized, ok := humanizeInteger(v, nil, opts.Humanize); ok {
+			return colorize(humanized, Cyan, opts.UseColors)
+		}
 		return colorize(fmt.Sprintf("%d", v.Uint()), Cyan, opts.UseColors)
 	case reflect.Float32, reflect.Float64:
 		return colorize(fmt.Sprintf("%g", v.Float()), Cyan, opts.UseColors)
+	case reflect.UnsafePointer:
+		if opts.SafeMode {
+			return colorize(v.Type().String(), Gray, opts.UseColors)
+		}
+		return fmt.Sprintf("%v", v.Interface())
 	default:
 		return fmt.Sprintf("%v", v.Interface())
 	}
@@ -289,8 +345,14 @@ func debugStructWithOptions(v reflect.Value, depth int, opts DebugOptions) strin
 		result.WriteString(colorize(fieldName, Blue, opts.UseColors))
 		result.WriteString(": ")
 
-		if fieldValue.CanInterface() {
-			result.WriteString(debugValueWithOptions(fieldValue, depth+1, opts))
+		if isRedactedField(field) {
+			result.WriteString(colorize(Redacted, Gray, opts.UseColors))
+		} else if fieldValue.CanInterface() {
+			if humanized, ok := humanizeInteger(fieldValue, &field, opts.Humanize); ok {
+				result.WriteString(colorize(humanized, Cyan, opts.UseColors))
+			} else {
+				result.WriteString(debugValueWithOptions(fieldValue, depth+1, opts))
+			}
 		} else {
 			result.WriteString(colorize("<unexported>", Gray, opts.UseColors))
 		}
@@ -318,7 +380,7 @@ func debugPointerWithOptions(v reflect.Value, depth int, opts DebugOptions) stri
 
 	var result strings.Builder
 
-	if opts.ShowAddresses {
+	if opts.ShowAddresses && !opts.SafeMode {
 		result.WriteString(colorize(fmt.Sprintf("(%p) ", v.Interface()), Gray, opts.UseColors))
 	}
 
@@ -362,38 +424,60 @@ func debugSliceWithOptions(v reflect.Value, depth int, opts DebugOptions) string
 
 	result.WriteString(colorize(prefix, Magenta, opts.UseColors))
 
-	maxLen := length
-	truncated := false
-	if opts.MaxSliceLength > 0 && length > opts.MaxSliceLength {
-		maxLen = opts.MaxSliceLength
-		truncated = true
+	head, tail := truncationWindow(length, opts)
+	omitted := length - head - tail
+	marker := ""
+	if omitted > 0 {
+		if tail > 0 {
+			marker = fmt.Sprintf("... +%d omitted", omitted)
+		} else if opts.CompactMode {
+			marker = fmt.Sprintf("... +%d more", omitted)
+		} else {
+			marker = fmt.Sprintf("... +%d more items", omitted)
+		}
 	}
 
 	if opts.CompactMode {
-		for i := 0; i < maxLen; i++ {
-			if i > 0 {
+		written := 0
+		writeItem := func(s string) {
+			if written > 0 {
 				result.WriteString(", ")
 			}
-			result.WriteString(debugValueWithOptions(v.Index(i), depth+1, opts))
+			result.WriteString(s)
+			written++
 		}
-		if truncated {
-			result.WriteString(colorize(fmt.Sprintf(", ... +%d more", length-maxLen), Gray, opts.UseColors))
+		for i := 0; i < head; i++ {
+			writeItem(debugValueWithOptions(v.Index(i), depth+1, opts))
+		}
+		if marker != "" {
+			writeItem(colorize(marker, Gray, opts.UseColors))
+		}
+		for i := length - tail; i < length; i++ {
+			writeItem(debugValueWithOptions(v.Index(i), depth+1, opts))
 		}
 		result.WriteString(colorize("]", Magenta, opts.UseColors))
 	} else {
-		if maxLen > 0 {
+		if length > 0 {
 			result.WriteString("\n")
 		}
-		for i := 0; i < maxLen; i++ {
+		for i := 0; i < head; i++ {
 			result.WriteString(strings.Repeat(opts.Indent, depth+1))
 			result.WriteString(debugValueWithOptions(v.Index(i), depth+1, opts))
 			result.WriteString(",\n")
 		}
-		if truncated {
+		if marker != "" {
 			result.WriteString(strings.Repeat(opts.Indent, depth+1))
-			result.WriteString(colorize(fmt.Sprintf("... +%d more items", length-maxLen), Gray, opts.UseColors))
+			result.WriteString(colorize(marker, Gray, opts.UseColors))
+			if tail > 0 {
+				result.WriteString(",")
+			}
 			result.WriteString("\n")
 		}
+		for i := length - tail; i < length; i++ {
+			result.WriteString(strings.Repeat(opts.Indent, depth+1))
+			result.WriteString(debugValueWithOptions(v.Index(i), depth+1, opts))
+			result.WriteString(",\n")
+		}
 		result.WriteString(strings.Repeat(opts.Indent, depth))
 		result.WriteString(colorize("]", Magenta, opts.UseColors))
 	}
@@ -401,6 +485,25 @@ func debugSliceWithOptions(v reflect.Value, depth int, opts DebugOptions) string
 	return result.String()
 }
 
+// truncationWindow returns how many leading and trailing items of a slice of
+// the given length are shown; the rest are omitted
+func truncationWindow(length int, opts DebugOptions) (head, tail int) {
+	limit := opts.MaxSliceLength
+	if limit <= 0 {
+		return length, 0
+	}
+	if opts.TruncateMode == TruncateHeadTail {
+		if length <= 2*limit {
+			return length, 0
+		}
+		return limit, limit
+	}
+	if length <= limit {
+		return length, 0
+	}
+	return limit, 0
+}
+
 func debugMapWithOptions(v reflect.Value, depth int, opts DebugOptions) string {
 	var result strings.Builder
 
@@ -493,7 +596,7 @@ func debugFuncWithOptions(v reflect.Value, opts DebugOptions) string {
 	if opts.ShowTypes {
 		result = fmt.Sprintf("func(%s)", v.Type().String())
 	}
-	if opts.ShowAddresses {
+	if opts.ShowAddresses && !opts.SafeMode {
 		result = fmt.Sprintf("%s@0x%x", result, v.Pointer())
 	}
 
@@ -506,6 +609,8 @@ type TableOptions struct {
 	ShowTypes      bool
 	UseColors      bool
 	Separator      string
+	Humanize       *HumanizeOptions // Human-friendly numbers and byte sizes (nil = raw digits)
+	WrapCells      bool             // Wrap long cells over several lines instead of truncating
 }
 
 func TableWithOptions(slice any, opts TableOptions) string {
@@ -572,7 +677,18 @@ func formatTable(v reflect.Value, opts TableOptions) string {
 
 		for j, field := range fields {
 			fieldValue := item.FieldByName(field.Name)
-			cellValue := fmt.Sprintf("%v", fieldValue.Interface())
+			cellValue, ok := humanizeInteger(fieldValue, &field, opts.Humanize)
+			if !ok {
+				cellValue = fmt.Sprintf("%v", fieldValue.Interface())
+			}
+
+			if opts.WrapCells {
+				row[j] = cellValue
+				for _, line := range wrapCell(cellValue, opts.MaxColumnWidth) {
+					widths[j] = max(widths[j], utf8.RuneCountInString(line))
+				}
+				continue
+			}
 
 			if opts.MaxColumnWidth > 0 && len(cellValue) > opts.MaxColumnWidth {
 				cellValue = cellValue[:opts.MaxColumnWidth-3] + "..."
@@ -602,13 +718,19 @@ func formatTable(v reflect.Value, opts TableOptions) string {
 	result.WriteString("\n")
 
 	// Separator line
+	separatorLine := ""
 	for i, width := range widths {
 		if i > 0 {
-			result.WriteString(strings.Repeat("-", len(opts.Separator)))
+			separatorLine += strings.Repeat("-", len(opts.Separator))
 		}
-		result.WriteString(strings.Repeat("-", width))
+		separatorLine += strings.Repeat("-", width)
+	}
+	result.WriteString(separatorLine + "\n")
+
+	if opts.WrapCells {
+		writeWrappedRows(&result, rows, widths, opts, separatorLine)
+		return result.String()
 	}
-	result.WriteString("\n")
 
 	// Data rows
 	for _, row := range rows {
@@ -624,12 +746,90 @@ func formatTable(v reflect.Value, opts TableOptions) string {
 	return result.String()
 }
 
+// writeWrappedRows renders each row over as many lines as its tallest cell,
+// padding shorter cells so columns stay aligned, with a separator between rows
+func writeWrappedRows(result *strings.Builder, rows [][]string, widths []int, opts TableOptions, separatorLine string) {
+	for r, row := range rows {
+		if r > 0 {
+			result.WriteString(separatorLine + "\n")
+		}
+
+		cells := make([][]string, len(row))
+		height := 1
+		for i, cell := range row {
+			cells[i] = wrapCell(cell, opts.MaxColumnWidth)
+			height = max(height, len(cells[i]))
+		}
+
+		for line := 0; line < height; line++ {
+			for i := range cells {
+				if i > 0 {
+					result.WriteString(opts.Separator)
+				}
+				text := ""
+				if line < len(cells[i]) {
+					text = cells[i][line]
+				}
+				result.WriteString(fmt.Sprintf("%-*s", widths[i], text))
+			}
+			result.WriteString("\n")
+		}
+	}
+}
+
+// wrapCell splits text into lines of at most width characters (runes),
+// breaking at spaces where possible and inside words that are longer than width
+func wrapCell(text string, width int) []string {
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, field := range strings.Fields(paragraph) {
+			word := []rune(field)
+			for len(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, string(word[:width]))
+				word = word[width:]
+			}
+			switch {
+			case line == "":
+				line = string(word)
+			case utf8.RuneCountInString(line)+1+len(word) <= width:
+				line += " " + string(word)
+			default:
+				lines = append(lines, line)
+				line = string(word)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // JSON-like formatting
-func jsonLikeValue(v reflect.Value, depth int, opts DebugOptions) string {
+func jsonLikeValue(v reflect.Value, depth int, opts DebugOptions) (result string) {
+	if opts.SafeMode {
+		defer func() {
+			if r := recover(); r != nil {
+				result = fmt.Sprintf(`"%s"`, Unformattable)
+			}
+		}()
+	}
+
 	if !v.IsValid() {
 		return "null"
 	}
 
+	if isSecretType(v.Type()) {
+		return fmt.Sprintf(`"%s"`, Redacted)
+	}
+
 	switch v.Kind() {
 	case reflect.Struct:
 		return jsonLikeStruct(v, depth, opts)
@@ -684,7 +884,9 @@ func jsonLikeStruct(v reflect.Value, depth int, opts DebugOptions) string {
 		result.WriteString(strings.Repeat(opts.Indent, depth+1))
 		result.WriteString(fmt.Sprintf(`"%s": `, field.Name))
 
-		if fieldValue.CanInterface() {
+		if isRedactedField(field) {
+			result.WriteString(fmt.Sprintf(`"%s"`, Redacted))
+		} else if fieldValue.CanInterface() {
 			result.WriteString(jsonLikeValue(fieldValue, depth+1, opts))
 		} else {
 			result.WriteString("null")
@@ -734,18 +936,23 @@ func jsonLikeSlice(v reflect.Value, depth int, opts DebugOptions) string {
 	result.WriteString("[\n")
 
 	length := v.Len()
-	maxLen := length
-	if opts.MaxSliceLength > 0 && length > opts.MaxSliceLength {
-		maxLen = opts.MaxSliceLength
-	}
-
-	for i := 0; i < maxLen; i++ {
-		if i > 0 {
+	head, tail := truncationWindow(length, opts)
+
+	written := 0
+	for i := 0; i < length; i++ {
+		if i == head {
+			i = length - tail
+			if i >= length {
+				break
+			}
+		}
+		if written > 0 {
 			result.WriteString(",\n")
 		}
 
 		result.WriteString(strings.Repeat(opts.Indent, depth+1))
 		result.WriteString(jsonLikeValue(v.Index(i), depth+1, opts))
+		written++
 	}
 
 	result.WriteString("\n")
@@ -755,145 +962,6 @@ func jsonLikeSlice(v reflect.Value, depth int, opts DebugOptions) string {
 	return result.String()
 }
 
-// Diff functionality
-func diffValues(a, b reflect.Value, path string) string {
-	var result strings.Builder
-
-	if !a.IsValid() && !b.IsValid() {
-		return ""
-	}
-
-	if !a.IsValid() {
-		result.WriteString(fmt.Sprintf("- %s: <missing>\n", path))
-		result.WriteString(fmt.Sprintf("+ %s: %v\n", path, b.Interface()))
-		return result.String()
-	}
-
-	if !b.IsValid() {
-		result.WriteString(fmt.Sprintf("- %s: %v\n", path, a.Interface()))
-		result.WriteString(fmt.Sprintf("+ %s: <missing>\n", path))
-		return result.String()
-	}
-
-	if a.Type() != b.Type() {
-		result.WriteString(fmt.Sprintf("- %s: %v (%s)\n", path, a.Interface(), a.Type()))
-		result.WriteString(fmt.Sprintf("+ %s: %v (%s)\n", path, b.Interface(), b.Type()))
-		return result.String()
-	}
-
-	switch a.Kind() {
-	case reflect.Struct:
-		return diffStructs(a, b, path)
-	case reflect.Slice, reflect.Array:
-		return diffSlices(a, b, path)
-	case reflect.Map:
-		return diffMaps(a, b, path)
-	default:
-		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
-			result.WriteString(fmt.Sprintf("- %s: %v\n", path, a.Interface()))
-			result.WriteString(fmt.Sprintf("+ %s: %v\n", path, b.Interface()))
-		}
-	}
-
-	return result.String()
-}
-
-func diffStructs(a, b reflect.Value, path string) string {
-	var result strings.Builder
-	t := a.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		fieldPath := path + "." + field.Name
-		if path == "" {
-			fieldPath = field.Name
-		}
-
-		aField := a.Field(i)
-		bField := b.Field(i)
-
-		if aField.CanInterface() && bField.CanInterface() {
-			result.WriteString(diffValues(aField, bField, fieldPath))
-		}
-	}
-
-	return result.String()
-}
-
-func diffSlices(a, b reflect.Value, path string) string {
-	var result strings.Builder
-
-	aLen := a.Len()
-	bLen := b.Len()
-
-	minLen := aLen
-	if bLen < minLen {
-		minLen = bLen
-	}
-
-	for i := 0; i < minLen; i++ {
-		indexPath := fmt.Sprintf("%s[%d]", path, i)
-		result.WriteString(diffValues(a.Index(i), b.Index(i), indexPath))
-	}
-
-	if aLen != bLen {
-		result.WriteString(fmt.Sprintf("~ %s: length differs (was %d, now %d)\n", path, aLen, bLen))
-
-		if aLen > bLen {
-			for i := bLen; i < aLen; i++ {
-				indexPath := fmt.Sprintf("%s[%d]", path, i)
-				result.WriteString(fmt.Sprintf("- %s: %v\n", indexPath, a.Index(i).Interface()))
-			}
-		} else {
-			for i := aLen; i < bLen; i++ {
-				indexPath := fmt.Sprintf("%s[%d]", path, i)
-				result.WriteString(fmt.Sprintf("+ %s: %v\n", indexPath, b.Index(i).Interface()))
-			}
-		}
-	}
-
-	return result.String()
-}
-
-func diffMaps(a, b reflect.Value, path string) string {
-	var result strings.Builder
-
-	aKeys := make(map[any]bool)
-	for _, key := range a.MapKeys() {
-		aKeys[key.Interface()] = true
-	}
-
-	bKeys := make(map[any]bool)
-	for _, key := range b.MapKeys() {
-		bKeys[key.Interface()] = true
-	}
-
-	// Check common keys
-	for _, key := range a.MapKeys() {
-		keyInterface := key.Interface()
-		if bKeys[keyInterface] {
-			keyPath := fmt.Sprintf("%s[%v]", path, keyInterface)
-			aValue := a.MapIndex(key)
-			bValue := b.MapIndex(key)
-			result.WriteString(diffValues(aValue, bValue, keyPath))
-		} else {
-			keyPath := fmt.Sprintf("%s[%v]", path, keyInterface)
-			result.WriteString(fmt.Sprintf("- %s: %v\n", keyPath, a.MapIndex(key).Interface()))
-		}
-	}
-
-	// Check keys only in b
-	for _, key := range b.MapKeys() {
-		keyInterface := key.Interface()
-		if !aKeys[keyInterface] {
-			keyPath := fmt.Sprintf("%s[%v]", path, keyInterface)
-			result.WriteString(fmt.Sprintf("+ %s: %v\n", keyPath, b.MapIndex(key).Interface()))
-		}
-	}
-
-	return result.String()
-}
-
 // Utility functions
 func colorize(text, color string, useColors bool) string {
 	if !useColors {
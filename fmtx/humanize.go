@@ -0,0 +1,128 @@
+package fmtx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ByteUnits selects the unit system used when humanizing byte sizes
+type ByteUnits int
+
+const (
+	ByteUnitsIEC ByteUnits = iota // Powers of 1024: KiB, MiB, GiB...
+	ByteUnitsSI                   // Powers of 1000: kB, MB, GB...
+)
+
+// HumanizeOptions controls human-friendly rendering of numbers.
+// Fields tagged with `fmtx:"bytes"` are rendered as byte sizes.
+type HumanizeOptions struct {
+	Numbers   bool      // Render integers with thousands separators
+	Bytes     bool      // Render integer fields tagged `fmtx:"bytes"` as sizes
+	ByteUnits ByteUnits // Unit system for byte sizes
+	Separator string    // Thousands separator (default: ",")
+}
+
+// DefaultHumanizeOptions returns options that humanize both numbers and byte sizes
+func DefaultHumanizeOptions() *HumanizeOptions {
+	return &HumanizeOptions{
+		Numbers:   true,
+		Bytes:     true,
+		ByteUnits: ByteUnitsIEC,
+		Separator: ",",
+	}
+}
+
+// HumanizeNumber formats an integer with thousands separators, e.g. 1234567 -> "1,234,567"
+func HumanizeNumber(n int64) string {
+	return humanizeDigits(strconv.FormatInt(n, 10), ",")
+}
+
+// HumanizeBytes formats a byte count using the given unit system, e.g. "1.2 GiB" or "1.2 GB"
+func HumanizeBytes(n uint64, units ByteUnits) string {
+	base := uint64(1024)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	if units == ByteUnitsSI {
+		base = 1000
+		suffixes = []string{"kB", "MB", "GB", "TB", "PB", "EB"}
+	}
+
+	if n < base {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := base, 0
+	for m := n / base; m >= base && exp < len(suffixes)-1; m /= base {
+		div *= base
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %s", float64(n)/float64(div), suffixes[exp])
+}
+
+func humanizeDigits(digits, sep string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= 3 {
+		return sign + digits
+	}
+
+	var result strings.Builder
+	result.WriteString(sign)
+	head := len(digits) % 3
+	if head > 0 {
+		result.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if i > 0 {
+			result.WriteString(sep)
+		}
+		result.WriteString(digits[i : i+3])
+	}
+	return result.String()
+}
+
+// isBytesField reports whether a struct field is tagged as holding a byte size
+func isBytesField(field reflect.StructField) bool {
+	for _, part := range strings.Split(field.Tag.Get("fmtx"), ",") {
+		if strings.TrimSpace(part) == "bytes" {
+			return true
+		}
+	}
+	return false
+}
+
+// humanizeInteger renders an integer value according to the options.
+// It returns false when the value should be rendered the usual way.
+func humanizeInteger(v reflect.Value, field *reflect.StructField, h *HumanizeOptions) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+
+	sep := h.Separator
+	if sep == "" {
+		sep = ","
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if h.Bytes && field != nil && isBytesField(*field) && v.Int() >= 0 {
+			return HumanizeBytes(uint64(v.Int()), h.ByteUnits), true
+		}
+		if h.Numbers {
+			return humanizeDigits(strconv.FormatInt(v.Int(), 10), sep), true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if h.Bytes && field != nil && isBytesField(*field) {
+			return HumanizeBytes(v.Uint(), h.ByteUnits), true
+		}
+		if h.Numbers {
+			return humanizeDigits(strconv.FormatUint(v.Uint(), 10), sep), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,129 @@
+package fmtx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch operation types
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"` // JSON Pointer (RFC 6901)
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON always includes the value of add and replace operations, even
+// when it is null, false or zero
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == PatchRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// JSONPatch returns the operations that turn a into b, touching only changed
+// leaves. Paths use JSON member names from struct tags and skip `json:"-"`
+// fields, so the patch applies to the JSON encoding of a. Pointers are
+// compared by what they point to, and omitempty or omitzero fields that turn
+// empty or stop being empty are removed or added. Slice elements are removed
+// from the end first, so the operations apply in order.
+//
+//	ops := fmtx.JSONPatch(oldConfig, newConfig)
+//	// [{"op":"replace","path":"/database/pool_size","value":20}]
+func JSONPatch(a, b any) []Operation {
+	var ops []Operation
+
+	compareValues(reflect.ValueOf(a), reflect.ValueOf(b), nil, func(change valueChange) {
+		path, ok := jsonPointer(change.path)
+		if !ok {
+			return
+		}
+
+		switch change.kind {
+		case changeReplaced:
+			ops = append(ops, Operation{Op: PatchReplace, Path: path, Value: valueInterface(change.new)})
+		case changeRemoved:
+			ops = append(ops, Operation{Op: PatchRemove, Path: path})
+		case changeAdded:
+			ops = append(ops, Operation{Op: PatchAdd, Path: path, Value: valueInterface(change.new)})
+		case changeResized:
+			if change.oldLen > change.newLen {
+				for i := change.oldLen - 1; i >= change.newLen; i-- {
+					ops = append(ops, Operation{Op: PatchRemove, Path: path + "/" + strconv.Itoa(i)})
+				}
+				return
+			}
+			for i, elem := range change.tail {
+				ops = append(ops, Operation{
+					Op:    PatchAdd,
+					Path:  path + "/" + strconv.Itoa(change.oldLen+i),
+					Value: elem.Interface(),
+				})
+			}
+		}
+	})
+
+	return ops
+}
+
+// JSONPatchString returns the JSON Patch between a and b as indented JSON
+func JSONPatchString(a, b any) string {
+	ops := JSONPatch(a, b)
+	if ops == nil {
+		ops = []Operation{}
+	}
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return "<error: " + err.Error() + ">"
+	}
+	return string(data)
+}
+
+// jsonPointer renders a path as an RFC 6901 pointer. It reports false for
+// paths through fields excluded from JSON.
+func jsonPointer(path []pathSegment) (string, bool) {
+	var sb strings.Builder
+	for _, segment := range path {
+		switch {
+		case segment.omitted:
+			return "", false
+		case segment.inline:
+			continue
+		case segment.isIndex:
+			sb.WriteString("/" + strconv.Itoa(segment.index))
+		case segment.isKey:
+			sb.WriteString("/" + escapePointer(segment.field))
+		default:
+			sb.WriteString("/" + escapePointer(segment.jsonName))
+		}
+	}
+	return sb.String(), true
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// valueInterface returns v's value, or nil for a missing value
+func valueInterface(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,154 @@
+package fmtx_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+type dbConfig struct {
+	Host     string `json:"host"`
+	PoolSize int    `json:"pool_size"`
+}
+
+type appConfig struct {
+	Name     string            `json:"name"`
+	Database dbConfig          `json:"database"`
+	Labels   map[string]string `json:"labels"`
+	Hosts    []string          `json:"hosts"`
+	Timeout  *int              `json:"timeout"`
+	Note     string            `json:"note,omitempty"`
+	Secret   string            `json:"-"`
+}
+
+func TestJSONPatch(t *testing.T) {
+	thirty, sixty := 30, 60
+	base := func() appConfig {
+		return appConfig{
+			Name:     "api",
+			Database: dbConfig{Host: "db.local", PoolSize: 10},
+			Labels:   map[string]string{"env": "prod"},
+			Hosts:    []string{"a.local", "b.local", "c.local"},
+			Timeout:  &thirty,
+			Secret:   "s3cret",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *appConfig)
+		want   []fmtx.Operation
+	}{
+		{
+			name:   "unchanged",
+			change: func(c *appConfig) {},
+		},
+		{
+			name:   "changed nested field",
+			change: func(c *appConfig) { c.Database.PoolSize = 20 },
+			want:   []fmtx.Operation{{Op: fmtx.PatchReplace, Path: "/database/pool_size", Value: 20}},
+		},
+		{
+			name:   "added map key",
+			change: func(c *appConfig) { c.Labels = map[string]string{"env": "prod", "team": "core"} },
+			want:   []fmtx.Operation{{Op: fmtx.PatchAdd, Path: "/labels/team", Value: "core"}},
+		},
+		{
+			name:   "removed map key",
+			change: func(c *appConfig) { c.Labels = map[string]string{} },
+			want:   []fmtx.Operation{{Op: fmtx.PatchRemove, Path: "/labels/env"}},
+		},
+		{
+			name:   "map key is escaped",
+			change: func(c *appConfig) { c.Labels = map[string]string{"env": "prod", "a/b~c": "x"} },
+			want:   []fmtx.Operation{{Op: fmtx.PatchAdd, Path: "/labels/a~1b~0c", Value: "x"}},
+		},
+		{
+			name:   "removed last slice element",
+			change: func(c *appConfig) { c.Hosts = c.Hosts[:2] },
+			want:   []fmtx.Operation{{Op: fmtx.PatchRemove, Path: "/hosts/2"}},
+		},
+		{
+			name:   "removed middle slice element",
+			change: func(c *appConfig) { c.Hosts = []string{"a.local", "c.local"} },
+			want: []fmtx.Operation{
+				{Op: fmtx.PatchReplace, Path: "/hosts/1", Value: "c.local"},
+				{Op: fmtx.PatchRemove, Path: "/hosts/2"},
+			},
+		},
+		{
+			name:   "removed slice elements from the end first",
+			change: func(c *appConfig) { c.Hosts = c.Hosts[:1] },
+			want: []fmtx.Operation{
+				{Op: fmtx.PatchRemove, Path: "/hosts/2"},
+				{Op: fmtx.PatchRemove, Path: "/hosts/1"},
+			},
+		},
+		{
+			name:   "appended slice element",
+			change: func(c *appConfig) { c.Hosts = append(c.Hosts, "d.local") },
+			want:   []fmtx.Operation{{Op: fmtx.PatchAdd, Path: "/hosts/3", Value: "d.local"}},
+		},
+		{
+			name:   "pointer compared by value",
+			change: func(c *appConfig) { c.Timeout = &sixty },
+			want:   []fmtx.Operation{{Op: fmtx.PatchReplace, Path: "/timeout", Value: 60}},
+		},
+		{
+			name:   "omitempty field added",
+			change: func(c *appConfig) { c.Note = "migrated" },
+			want:   []fmtx.Operation{{Op: fmtx.PatchAdd, Path: "/note", Value: "migrated"}},
+		},
+		{
+			name:   "fields excluded from JSON are skipped",
+			change: func(c *appConfig) { c.Secret = "rotated" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := base(), base()
+			tt.change(&b)
+
+			if got := fmtx.JSONPatch(a, b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JSONPatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatchString(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+		want string
+	}{
+		{
+			name: "no changes",
+			a:    dbConfig{Host: "db.local"},
+			b:    dbConfig{Host: "db.local"},
+			want: "[]",
+		},
+		{
+			name: "zero value is kept",
+			a:    dbConfig{Host: "db.local", PoolSize: 10},
+			b:    dbConfig{Host: "db.local"},
+			want: "[\n  {\n    \"op\": \"replace\",\n    \"path\": \"/pool_size\",\n    \"value\": 0\n  }\n]",
+		},
+		{
+			name: "remove has no value",
+			a:    map[string]int{"a": 1},
+			b:    map[string]int{},
+			want: "[\n  {\n    \"op\": \"remove\",\n    \"path\": \"/a\"\n  }\n]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmtx.JSONPatchString(tt.a, tt.b); got != tt.want {
+				t.Errorf("JSONPatchString() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package fmtx
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the values of redacted fields and secret types in Debug,
+// JSON and RedactJSON output
+const Redacted = "[REDACTED]"
+
+// maxRedactDepth bounds RedactJSON's recursion, so cyclic values fail instead
+// of overflowing the stack
+const maxRedactDepth = 100
+
+var (
+	secretTypes sync.Map // reflect.Type -> struct{}
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// RegisterSecretType marks a type as secret: its values are always printed as
+// Redacted, wherever they appear in a formatted value
+//
+//	type APIKey string
+//
+//	fmtx.RegisterSecretType(reflect.TypeOf(APIKey("")))
+func RegisterSecretType(t reflect.Type) {
+	secretTypes.Store(t, struct{}{})
+}
+
+// isSecretType reports whether t was registered with RegisterSecretType
+func isSecretType(t reflect.Type) bool {
+	_, ok := secretTypes.Load(t)
+	return ok
+}
+
+// isRedactedField reports whether a struct field is tagged `fmtx:"redact"`
+func isRedactedField(field reflect.StructField) bool {
+	for _, part := range strings.Split(field.Tag.Get("fmtx"), ",") {
+		if strings.TrimSpace(part) == "redact" {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactJSON encodes v as JSON like encoding/json, honoring json tags, but
+// with fields tagged `fmtx:"redact"` and values of secret types (see
+// RegisterSecretType) replaced by Redacted, so values can be logged without
+// leaking secrets. Values implementing json.Marshaler or encoding.TextMarshaler
+// are encoded by their own methods, so secrets inside them aren't seen.
+//
+//	type Login struct {
+//		User     string `json:"user"`
+//		Password string `json:"password" fmtx:"redact"`
+//	}
+//
+//	out, err := fmtx.RedactJSON(Login{User: "ana", Password: "hunter2"})
+//	// {"user":"ana","password":"[REDACTED]"}
+func RedactJSON(v any) (string, error) {
+	tree, err := redactValue(reflect.ValueOf(v), 0)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// redactValue converts v into values encoding/json encodes the same way,
+// with redacted fields and secret types replaced
+func redactValue(v reflect.Value, depth int) (any, error) {
+	if depth > maxRedactDepth {
+		return nil, fmt.Errorf("fmtx: value nested more than %d levels deep (cyclic?)", maxRedactDepth)
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if isSecretType(v.Type()) {
+		return Redacted, nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, nil
+	}
+
+	// Types with their own encoding are leaves. An unexported embedded struct
+	// can't be handed to its methods, so it is walked like other structs.
+	if v.CanInterface() {
+		if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+			return v.Interface(), nil
+		}
+		if v.CanAddr() {
+			if pt := reflect.PointerTo(v.Type()); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+				return v.Addr().Interface(), nil
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return redactValue(v.Elem(), depth+1)
+	case reflect.Struct:
+		object := jsonObject{}
+		if err := redactStructFields(v, depth, &object); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		object := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value, err := redactValue(iter.Value(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(iter.Key().Interface())] = value
+		}
+		return object, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && !isSecretType(v.Type().Elem()) {
+			return v.Interface(), nil // Base64, as encoding/json does
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			item, err := redactValue(v.Index(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// redactStructFields appends the JSON members of struct v to object,
+// flattening embedded structs the way encoding/json does
+func redactStructFields(v reflect.Value, depth int, object *jsonObject) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !isEmbeddedStruct(field) {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldValue := v.Field(i)
+
+		// Untagged embedded structs contribute their fields
+		if field.Anonymous && name == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !isSecretType(embedded.Type()) {
+				if err := redactStructFields(embedded, depth, object); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && fieldValue.IsZero() && isEmptyJSONValue(fieldValue) {
+			continue
+		}
+
+		if isRedactedField(field) {
+			*object = append(*object, jsonMember{name: name, value: Redacted})
+			continue
+		}
+
+		value, err := redactValue(fieldValue, depth+1)
+		if err != nil {
+			return err
+		}
+		*object = append(*object, jsonMember{name: name, value: value})
+	}
+	return nil
+}
+
+// isEmptyJSONValue reports whether omitempty drops v in encoding/json
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	default:
+		return v.IsZero()
+	}
+}
+
+// isEmbeddedStruct reports whether field embeds a struct or a pointer to one,
+// whose exported fields encoding/json promotes even when the type is unexported
+func isEmbeddedStruct(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return field.Anonymous && t.Kind() == reflect.Struct
+}
+
+// jsonMember is a name/value pair of a jsonObject
+type jsonMember struct {
+	name  string
+	value any
+}
+
+// jsonObject is a JSON object that keeps its members in struct field order
+type jsonObject []jsonMember
+
+// MarshalJSON encodes the members in order
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(member.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
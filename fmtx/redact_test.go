@@ -0,0 +1,170 @@
+package fmtx_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+// apiKey is registered as a secret type
+type apiKey string
+
+func init() {
+	fmtx.RegisterSecretType(reflect.TypeOf(apiKey("")))
+}
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" fmtx:"redact"`
+}
+
+type auditInfo struct {
+	RequestID string `json:"request_id"`
+}
+
+type integration struct {
+	auditInfo
+	Name      string            `json:"name"`
+	Key       apiKey            `json:"key"`
+	Backups   []apiKey          `json:"backups,omitempty"`
+	Login     *credentials      `json:"login"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Internal  string            `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func TestRedactJSON(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			name: "redacted field",
+			v:    credentials{User: "ana", Password: "hunter2"},
+			want: `{"user":"ana","password":"[REDACTED]"}`,
+		},
+		{
+			name: "pointer to struct",
+			v:    &credentials{User: "ana", Password: "hunter2"},
+			want: `{"user":"ana","password":"[REDACTED]"}`,
+		},
+		{
+			name: "secret types, nesting, embedding and json tags",
+			v: integration{
+				auditInfo: auditInfo{RequestID: "req-1"},
+				Name:      "crm",
+				Key:       "sk-live-123",
+				Backups:   []apiKey{"sk-old-1", "sk-old-2"},
+				Login:     &credentials{User: "bot", Password: "s3cret"},
+				Internal:  "hidden",
+				CreatedAt: created,
+			},
+			want: `{"request_id":"req-1","name":"crm","key":"[REDACTED]","backups":["[REDACTED]","[REDACTED]"],` +
+				`"login":{"user":"bot","password":"[REDACTED]"},"created_at":"2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name: "nil pointer and omitted empty fields",
+			v:    integration{Name: "crm", CreatedAt: created},
+			want: `{"request_id":"","name":"crm","key":"[REDACTED]","login":null,"created_at":"2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name: "secrets inside maps and slices",
+			v:    map[string]any{"keys": []apiKey{"sk-1"}, "user": credentials{User: "ana", Password: "x"}},
+			want: `{"keys":["[REDACTED]"],"user":{"user":"ana","password":"[REDACTED]"}}`,
+		},
+		{
+			name: "nil",
+			v:    nil,
+			want: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fmtx.RedactJSON(tt.v)
+			if err != nil {
+				t.Fatalf("RedactJSON: %v", err)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Fatalf("RedactJSON() = %s, not valid JSON", got)
+			}
+			if got != tt.want {
+				t.Errorf("RedactJSON() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			for _, secret := range []string{"hunter2", "s3cret", "sk-live-123", "sk-old-1"} {
+				if strings.Contains(got, secret) {
+					t.Errorf("RedactJSON() leaks %q: %s", secret, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactJSONCycle(t *testing.T) {
+	type node struct {
+		Next *node `json:"next"`
+	}
+	cycle := &node{}
+	cycle.Next = cycle
+
+	if _, err := fmtx.RedactJSON(cycle); err == nil {
+		t.Fatal("RedactJSON of a cyclic value succeeded, want an error")
+	}
+}
+
+func TestDebugRedacts(t *testing.T) {
+	v := integration{Name: "crm", Key: "sk-live-123", Login: &credentials{User: "bot", Password: "s3cret"}}
+
+	for name, out := range map[string]string{
+		"Debug": fmtx.DebugWithOptions(v, fmtx.CompactOptions()),
+		"JSON":  fmtx.JSON(v),
+	} {
+		if strings.Contains(out, "sk-live-123") || strings.Contains(out, "s3cret") {
+			t.Errorf("%s leaks a secret: %s", name, out)
+		}
+		if !strings.Contains(out, fmtx.Redacted) {
+			t.Errorf("%s = %s, want %s in place of the secrets", name, out, fmtx.Redacted)
+		}
+	}
+}
+
+func TestRedactJSONMatchesEncodingJSON(t *testing.T) {
+	type tagged struct {
+		auditInfo `json:"audit"`
+		Count     int `json:"count,omitempty"`
+	}
+	type pointerEmbed struct {
+		*auditInfo
+		Tags []string `json:"tags"`
+		Raw  []byte   `json:"raw"`
+	}
+
+	for _, v := range []any{
+		integration{auditInfo: auditInfo{RequestID: "req-1"}, Name: "crm", Headers: map[string]string{"x": "y"}},
+		tagged{auditInfo: auditInfo{RequestID: "req-2"}},
+		pointerEmbed{auditInfo: &auditInfo{RequestID: "req-3"}, Raw: []byte("hi")},
+		pointerEmbed{},
+	} {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+		// encoding/json doesn't know Key is a secret type
+		wantText := strings.Replace(string(want), `"key":""`, `"key":"[REDACTED]"`, 1)
+
+		got, err := fmtx.RedactJSON(v)
+		if err != nil {
+			t.Fatalf("RedactJSON: %v", err)
+		}
+		if got != wantText {
+			t.Errorf("RedactJSON() = %s, want %s", got, wantText)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package fmtx_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+// brokenError panics when its message is read
+type brokenError struct{}
+
+func (brokenError) Error() string { panic("broken error") }
+
+type panicky struct {
+	Name  string
+	Value int
+}
+
+type withBrokenError struct {
+	Name string
+	Err  *brokenError
+}
+
+type withPointers struct {
+	Name    string
+	Next    *panicky
+	Raw     unsafe.Pointer
+	Handler func()
+}
+
+func TestSafeModeRecovers(t *testing.T) {
+	panickingFormatter := fmtx.DefaultOptions()
+	panickingFormatter.CustomFormatters = map[reflect.Type]func(reflect.Value) string{
+		reflect.TypeOf(0): func(reflect.Value) string { panic("formatter") },
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		opts  fmtx.DebugOptions
+		want  []string
+	}{
+		{
+			name:  "panicking custom formatter",
+			value: panicky{Name: "kept", Value: 1},
+			opts:  panickingFormatter,
+			want:  []string{"kept", "Value: " + fmtx.Unformattable},
+		},
+		{
+			name:  "panicking error method",
+			value: withBrokenError{Name: "kept", Err: &brokenError{}},
+			opts:  fmtx.DefaultOptions(),
+			want:  []string{"kept", "Err: " + fmtx.Unformattable},
+		},
+		{
+			name:  "panicking slice element",
+			value: []int{1, 2},
+			opts:  panickingFormatter,
+			want:  []string{fmtx.Unformattable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatal("formatting without SafeMode didn't panic; the case doesn't exercise SafeMode")
+					}
+				}()
+				fmtx.DebugWithOptions(tt.value, tt.opts)
+			}()
+
+			opts := tt.opts
+			opts.SafeMode = true
+			var got string
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("SafeMode panicked: %v", r)
+					}
+				}()
+				got = fmtx.DebugWithOptions(tt.value, opts)
+			}()
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output %q doesn't contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSafeModeSkipsAddresses(t *testing.T) {
+	value := withPointers{
+		Name:    "node",
+		Next:    &panicky{Name: "next"},
+		Raw:     unsafe.Pointer(&panicky{}),
+		Handler: func() {},
+	}
+
+	opts := fmtx.SafeOptions()
+	opts.ShowAddresses = true
+	got := fmtx.DebugWithOptions(value, opts)
+
+	if strings.Contains(got, "0x") {
+		t.Errorf("SafeMode printed an address: %q", got)
+	}
+	for _, want := range []string{"node", "next", "Raw: unsafe.Pointer", "Handler: func"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q doesn't contain %q", got, want)
+		}
+	}
+
+	if got := fmtx.Safe(value); !strings.Contains(got, "node") {
+		t.Errorf("Safe() = %q, want the value formatted", got)
+	}
+}
@@ -0,0 +1,149 @@
+package fmtx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schemaIndent is the indentation per nesting level of Schema output
+const schemaIndent = "    "
+
+// Schema prints the shape of v's type: every exported field with its type and
+// struct tags, recursing into nested structs, including those behind pointers,
+// slices and maps. Only the type matters, so zero values, nil pointers such as
+// (*User)(nil) and reflect.Type values all work.
+//
+//	fmtx.Schema(User{})
+//	// User {
+//	//     ID       int64     `json:"id" db:"id"`
+//	//     Email    string    `json:"email" validate:"required,email"`
+//	//     Address  *Address  `json:"address"` {
+//	//         City  string  `json:"city"`
+//	//     }
+//	// }
+//
+// Types from other packages than v's are qualified, as in time.Time. Structs
+// without exported fields are printed as a type only, and so are recursive
+// references to a struct already being printed.
+func Schema(v any) string {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	if t == nil {
+		return "nil"
+	}
+
+	root := t
+	for root.Kind() == reflect.Ptr || root.Kind() == reflect.Slice || root.Kind() == reflect.Array || root.Kind() == reflect.Map {
+		root = root.Elem()
+	}
+	pkg := root.PkgPath()
+
+	var result strings.Builder
+	result.WriteString(schemaTypeName(t, pkg))
+
+	if st := schemaStruct(t); st != nil {
+		result.WriteString(" ")
+		writeSchemaFields(&result, st, pkg, 0, map[reflect.Type]bool{})
+	}
+	return result.String()
+}
+
+func SchemaPrint(v any) {
+	fmt.Println(Schema(v))
+}
+
+// writeSchemaFields writes the braced field list of struct type t
+func writeSchemaFields(result *strings.Builder, t reflect.Type, pkg string, depth int, visiting map[reflect.Type]bool) {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	fields := schemaFields(t)
+	nameWidth, typeWidth := 0, 0
+	for _, field := range fields {
+		nameWidth = max(nameWidth, len(field.Name))
+		typeWidth = max(typeWidth, len(schemaTypeName(field.Type, pkg)))
+	}
+
+	result.WriteString("{\n")
+	for _, field := range fields {
+		typeName := schemaTypeName(field.Type, pkg)
+		line := fmt.Sprintf("%-*s  %-*s", nameWidth, field.Name, typeWidth, typeName)
+		if field.Tag != "" {
+			line += "  `" + string(field.Tag) + "`"
+		}
+
+		result.WriteString(strings.Repeat(schemaIndent, depth+1))
+		result.WriteString(strings.TrimRight(line, " "))
+		nested := schemaStruct(field.Type)
+		if nested == nil || visiting[nested] {
+			result.WriteString("\n")
+			continue
+		}
+
+		result.WriteString(" ")
+		writeSchemaFields(result, nested, pkg, depth+1, visiting)
+		result.WriteString("\n")
+	}
+	result.WriteString(strings.Repeat(schemaIndent, depth))
+	result.WriteString("}")
+}
+
+// schemaFields returns the exported fields of t in declaration order
+func schemaFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// schemaStruct returns the struct type reached through t's pointers, slices,
+// arrays and map values, or nil when there is none with exported fields
+func schemaStruct(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			if len(schemaFields(t)) == 0 {
+				return nil
+			}
+			return t
+		default:
+			return nil
+		}
+	}
+}
+
+// schemaTypeName renders t like Go source, qualifying named types only when
+// they come from another package than pkg
+func schemaTypeName(t reflect.Type, pkg string) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" || t.PkgPath() == pkg {
+			return t.Name()
+		}
+		return t.String()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + schemaTypeName(t.Elem(), pkg)
+	case reflect.Slice:
+		return "[]" + schemaTypeName(t.Elem(), pkg)
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + schemaTypeName(t.Elem(), pkg)
+	case reflect.Map:
+		return "map[" + schemaTypeName(t.Key(), pkg) + "]" + schemaTypeName(t.Elem(), pkg)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any"
+		}
+	}
+	return t.String()
+}
@@ -0,0 +1,104 @@
+package fmtx_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+type schemaAddress struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city"`
+}
+
+type schemaUser struct {
+	ID        int64         `json:"id" db:"id"`
+	Email     string        `json:"email" validate:"required,email"`
+	Address   schemaAddress `json:"address"`
+	CreatedAt time.Time     `json:"created_at"`
+	password  string
+}
+
+type schemaNode struct {
+	Value    int
+	Children []*schemaNode
+}
+
+func TestSchema(t *testing.T) {
+	user := []string{
+		"schemaUser {",
+		"    ID         int64          `json:\"id\" db:\"id\"`",
+		"    Email      string         `json:\"email\" validate:\"required,email\"`",
+		"    Address    schemaAddress  `json:\"address\"` {",
+		"        Street  string  `json:\"street\" validate:\"required\"`",
+		"        City    string  `json:\"city\"`",
+		"    }",
+		"    CreatedAt  time.Time      `json:\"created_at\"`",
+		"}",
+	}
+
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{
+			name: "zero value with tags and a nested struct",
+			v:    schemaUser{},
+			want: user,
+		},
+		{
+			name: "nil pointer",
+			v:    (*schemaUser)(nil),
+			want: append([]string{"*" + user[0]}, user[1:]...),
+		},
+		{
+			name: "reflect.Type",
+			v:    reflect.TypeOf(schemaUser{}),
+			want: user,
+		},
+		{
+			name: "slice of structs",
+			v:    []schemaAddress{},
+			want: []string{
+				"[]schemaAddress {",
+				"    Street  string  `json:\"street\" validate:\"required\"`",
+				"    City    string  `json:\"city\"`",
+				"}",
+			},
+		},
+		{
+			name: "recursive type is printed once",
+			v:    schemaNode{},
+			want: []string{
+				"schemaNode {",
+				"    Value     int",
+				"    Children  []*schemaNode",
+				"}",
+			},
+		},
+		{
+			name: "struct without exported fields",
+			v:    time.Time{},
+			want: []string{"Time"},
+		},
+		{
+			name: "nil",
+			v:    nil,
+			want: []string{"nil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmtx.Schema(tt.v)
+			want := strings.Join(tt.want, "\n")
+			if got != want {
+				t.Errorf("Schema() =\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package fmtx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+type product struct {
+	ID          int
+	Name        string
+	Description string
+}
+
+func TestTableWrapCells(t *testing.T) {
+	products := []product{
+		{ID: 1, Name: "Widget", Description: "A small widget used for testing wraps"},
+		{ID: 2, Name: "Gadget", Description: "Short"},
+	}
+
+	tests := []struct {
+		name  string
+		items []product
+		opts  fmtx.TableOptions
+		want  []string
+	}{
+		{
+			name:  "long cell wraps and other columns stay aligned",
+			items: products,
+			opts:  fmtx.TableOptions{MaxColumnWidth: 12, WrapCells: true},
+			want: []string{
+				"ID | Name   | Description",
+				"-------------------------",
+				"1  | Widget | A small    ",
+				"   |        | widget used",
+				"   |        | for testing",
+				"   |        | wraps      ",
+				"-------------------------",
+				"2  | Gadget | Short      ",
+			},
+		},
+		{
+			name:  "word longer than the column is split",
+			items: []product{{ID: 7, Name: "Part", Description: "SKU-0123456789"}},
+			opts:  fmtx.TableOptions{MaxColumnWidth: 5, WrapCells: true},
+			want: []string{
+				"ID | Name | Description",
+				"-----------------------",
+				"7  | Part | SKU-0      ",
+				"   |      | 12345      ",
+				"   |      | 6789       ",
+			},
+		},
+		{
+			name:  "explicit line breaks are kept",
+			items: []product{{ID: 3, Name: "Kit", Description: "first\nsecond"}},
+			opts:  fmtx.TableOptions{MaxColumnWidth: 20, WrapCells: true},
+			want: []string{
+				"ID | Name | Description",
+				"-----------------------",
+				"3  | Kit  | first      ",
+				"   |      | second     ",
+			},
+		},
+		{
+			name:  "accented text is measured in characters",
+			items: []product{{ID: 4, Name: "Café", Description: "Señal única añadida"}},
+			opts:  fmtx.TableOptions{MaxColumnWidth: 8, WrapCells: true},
+			want: []string{
+				"ID | Name | Description",
+				"-----------------------",
+				"4  | Café | Señal      ",
+				"   |      | única      ",
+				"   |      | añadida    ",
+			},
+		},
+		{
+			name:  "CJK words are split between characters",
+			items: []product{{ID: 5, Name: "茶", Description: "東京都渋谷区 駅前"}},
+			opts:  fmtx.TableOptions{MaxColumnWidth: 4, WrapCells: true},
+			want: []string{
+				"ID | Name | Description",
+				"-----------------------",
+				"5  | 茶    | 東京都渋       ",
+				"   |      | 谷区         ",
+				"   |      | 駅前         ",
+			},
+		},
+		{
+			name:  "without WrapCells long cells are truncated",
+			items: products,
+			opts:  fmtx.TableOptions{MaxColumnWidth: 12},
+			want: []string{
+				"ID | Name   | Description ",
+				"--------------------------",
+				"1  | Widget | A small w...",
+				"2  | Gadget | Short       ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmtx.TableWithOptions(tt.items, tt.opts)
+			want := strings.Join(tt.want, "\n") + "\n"
+			if got != want {
+				t.Errorf("TableWithOptions() =\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
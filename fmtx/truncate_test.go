@@ -0,0 +1,73 @@
+package fmtx_test
+
+import (
+	"testing"
+
+	"github.com/Abraxas-365/craftable/fmtx"
+)
+
+func TestSliceTruncation(t *testing.T) {
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = i
+	}
+
+	compact := func(limit int, mode fmtx.TruncateMode) fmtx.DebugOptions {
+		opts := fmtx.CompactOptions()
+		opts.MaxSliceLength = limit
+		opts.TruncateMode = mode
+		return opts
+	}
+	expanded := func(limit int, mode fmtx.TruncateMode) fmtx.DebugOptions {
+		opts := fmtx.DefaultOptions()
+		opts.MaxSliceLength = limit
+		opts.TruncateMode = mode
+		return opts
+	}
+
+	tests := []struct {
+		name  string
+		value []int
+		opts  fmtx.DebugOptions
+		want  string
+	}{
+		{
+			name:  "head and tail of 100 items",
+			value: hundred,
+			opts:  compact(3, fmtx.TruncateHeadTail),
+			want:  "[0, 1, 2, ... +94 omitted, 97, 98, 99]",
+		},
+		{
+			name:  "tail truncation stays the default",
+			value: hundred,
+			opts:  compact(3, fmtx.TruncateTail),
+			want:  "[0, 1, 2, ... +97 more]",
+		},
+		{
+			name:  "head and tail expanded",
+			value: hundred[:6],
+			opts:  expanded(2, fmtx.TruncateHeadTail),
+			want:  "[\n    0,\n    1,\n    ... +2 omitted,\n    4,\n    5,\n]",
+		},
+		{
+			name:  "short enough for head and tail",
+			value: hundred[:4],
+			opts:  expanded(2, fmtx.TruncateHeadTail),
+			want:  "[\n    0,\n    1,\n    2,\n    3,\n]",
+		},
+		{
+			name:  "no limit",
+			value: hundred[:5],
+			opts:  compact(0, fmtx.TruncateHeadTail),
+			want:  "[0, 1, 2, 3, 4]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmtx.DebugWithOptions(tt.value, tt.opts); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
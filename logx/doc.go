@@ -6,6 +6,7 @@
 //   - LOG_LEVEL: Set the minimum log level (TRACE, DEBUG, INFO, WARN, ERROR, OFF)
 //   - LOG_FORMAT: Set output format (console, cloudwatch, json)
 //   - LOG_COLOR: Enable/disable colored output (true/false, default: true)
+//   - LOG_THEME: Built-in color theme (default, solarized, monochrome)
 //   - LOG_CALLER: Enable/disable caller information (true/false, default: true)
 //
 // Basic Usage:
@@ -22,6 +23,43 @@
 //	logx.DebugStruct("user", user)
 //	logx.TraceStruct("config", config)
 //
+// Large Values:
+//
+//	// Struct formatting is bounded so huge or cyclic values stay cheap;
+//	// truncated parts read "... (N more)", cycles read <cycle>
+//	limits := logx.DefaultFormatLimits() // depth 8, 50 items, 1KB strings, 16KB output
+//	limits.MaxItems = 10
+//	logx.SetFormatLimits(limits)
+//
+// Values are only formatted when the level is enabled, so DebugStruct at INFO
+// costs a level check. Guard building the value itself with logx.IsLevelEnabled.
+//
+// Structured Entries:
+//
+//	// Emit a pre-built entry; the printf helpers are thin wrappers over Log
+//	logx.Log(logx.Entry{
+//		Level:   logx.WarnLevel,
+//		Message: "payment retried",
+//		Fields:  map[string]any{"order_id": id, "attempt": 2},
+//		Err:     err,
+//	})
+//	// Console: [2025-06-08 18:57:52] [WARN] main.go:64: payment retried attempt=2 order_id=42 error="card declined"
+//	// JSON:    {"level":"WARN","message":"payment retried","fields":{"attempt":2,"order_id":42},"error":"card declined",...}
+//
+// Libraries forwarding logs from elsewhere can set Entry.Time and Entry.Caller.
+//
+// Color Themes:
+//
+//	// Built-in themes: DefaultTheme, SolarizedTheme, MonochromeTheme
+//	logx.SetTheme(logx.SolarizedTheme())
+//
+//	// Or customize levels and struct elements (keys, strings, numbers, ...)
+//	theme := logx.MonochromeTheme()
+//	theme.Levels[logx.ErrorLevel] = "\033[1;31m"
+//	logx.SetTheme(theme)
+//
+// Themes never turn colors on: LOG_COLOR=false keeps output plain.
+//
 // Format Examples:
 //
 //	Console Format (default - beautiful for local development):
@@ -44,10 +82,11 @@
 //   - Environment variable configuration
 //   - Beautiful struct formatting (similar to Rust's {:?})
 //   - Multiple output formats (console, cloudwatch, json)
-//   - Colored output with customizable colors
+//   - Colored output with customizable themes
 //   - Caller information (file:line)
 //   - Multiple log levels (TRACE, DEBUG, INFO, WARN, ERROR)
 //   - Support for nested structs, maps, slices, and pointers
+//   - Size, depth and cycle limits on struct formatting
 //   - Special formatting for errors and time.Time
 //   - Both global and instance-based loggers
 //   - AWS CloudWatch optimized output
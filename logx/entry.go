@@ -0,0 +1,116 @@
+package logx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single log record. Build one to emit pre-structured logs with
+// Logger.Log; the printf-style helpers create entries the same way.
+type Entry struct {
+	Level   Level
+	Message string
+
+	// Fields are key/value pairs rendered after the message, or under
+	// "fields" in JSON output
+	Fields map[string]any
+
+	// Time defaults to now when zero
+	Time time.Time
+
+	// Err is rendered as the "error" field when set
+	Err error
+
+	// Caller ("file.go:42") defaults to the first caller outside logx when
+	// caller information is enabled. Set it when forwarding logs from elsewhere.
+	Caller string
+
+	// data holds the debug-formatted printf arguments for JSON output
+	data []any
+}
+
+// WithField returns a copy of the entry with key set to value
+func (e Entry) WithField(key string, value any) Entry {
+	fields := make(map[string]any, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	e.Fields = fields
+	return e
+}
+
+// WithError returns a copy of the entry carrying err
+func (e Entry) WithError(err error) Entry {
+	e.Err = err
+	return e
+}
+
+// Log emits entry through the logger's configured format. Entries below the
+// logger's level are dropped.
+func (l *Logger) Log(entry Entry) {
+	if !l.IsLevelEnabled(entry.Level) {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Caller == "" {
+		entry.Caller = strings.TrimSpace(l.findCaller())
+	}
+
+	switch l.format {
+	case FormatJSON:
+		l.logJSON(entry)
+	case FormatCloudWatch:
+		l.logCloudWatch(entry)
+	default:
+		l.logConsole(entry)
+	}
+}
+
+// renderFields formats fields as sorted key=value pairs followed by the error
+func (l *Logger) renderFields(entry Entry) string {
+	if len(entry.Fields) == 0 && entry.Err == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(" ")
+		sb.WriteString(key)
+		sb.WriteString("=")
+		sb.WriteString(l.fieldValue(entry.Fields[key]))
+	}
+	if entry.Err != nil {
+		sb.WriteString(" error=")
+		sb.WriteString(l.fieldValue(entry.Err.Error()))
+	}
+	return sb.String()
+}
+
+// fieldValue renders a field on a single line, quoting strings that need it
+func (l *Logger) fieldValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			return strconv.Quote(v)
+		}
+		return v
+	case error:
+		return strconv.Quote(v.Error())
+	case fmt.Stringer:
+		return l.fieldValue(v.String())
+	default:
+		return l.cloudFormatter.formatCompact(value)
+	}
+}
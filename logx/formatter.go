@@ -11,12 +11,14 @@ import (
 // CloudWatchFormatter formats logs for AWS CloudWatch
 type CloudWatchFormatter struct {
 	useJSON bool
+	limits  FormatLimits
 }
 
 // NewCloudWatchFormatter creates a CloudWatch-optimized formatter
 func NewCloudWatchFormatter(useJSON bool) *CloudWatchFormatter {
 	return &CloudWatchFormatter{
 		useJSON: useJSON,
+		limits:  DefaultFormatLimits(),
 	}
 }
 
@@ -43,7 +45,7 @@ func (cf *CloudWatchFormatter) formatJSON(v any) string {
 
 	// Try to marshal to JSON
 	if data, err := json.Marshal(v); err == nil {
-		return string(data)
+		return truncateString(string(data), cf.limits.MaxOutput)
 	}
 
 	// Fallback to string representation
@@ -52,13 +54,17 @@ func (cf *CloudWatchFormatter) formatJSON(v any) string {
 
 // formatCompact creates a single-line compact representation
 func (cf *CloudWatchFormatter) formatCompact(v any) string {
-	return cf.formatValueCompact(reflect.ValueOf(v))
+	formatted := cf.formatValueCompact(reflect.ValueOf(v), 0, newFormatState(cf.limits))
+	return truncateString(formatted, cf.limits.MaxOutput)
 }
 
-func (cf *CloudWatchFormatter) formatValueCompact(v reflect.Value) string {
+func (cf *CloudWatchFormatter) formatValueCompact(v reflect.Value, depth int, state *formatState) string {
 	if !v.IsValid() {
 		return "<nil>"
 	}
+	if state.tooDeep(depth) || state.exhausted() {
+		return "..."
+	}
 
 	if v.Kind() == reflect.Ptr && v.IsNil() {
 		return "nil"
@@ -74,12 +80,16 @@ func (cf *CloudWatchFormatter) formatValueCompact(v reflect.Value) string {
 	}
 
 	if v.Kind() == reflect.Ptr {
-		return "&" + cf.formatValueCompact(v.Elem())
+		if !state.enter(v) {
+			return "<cycle>"
+		}
+		defer state.leave(v)
+		return "&" + cf.formatValueCompact(v.Elem(), depth, state)
 	}
 
 	switch v.Kind() {
 	case reflect.String:
-		return fmt.Sprintf("%q", v.String())
+		return fmt.Sprintf("%q", truncateString(v.String(), state.limits.MaxStringLen))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return fmt.Sprintf("%d", v.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -89,16 +99,16 @@ func (cf *CloudWatchFormatter) formatValueCompact(v reflect.Value) string {
 	case reflect.Bool:
 		return fmt.Sprintf("%t", v.Bool())
 	case reflect.Slice, reflect.Array:
-		return cf.formatSliceCompact(v)
+		return cf.formatSliceCompact(v, depth, state)
 	case reflect.Map:
-		return cf.formatMapCompact(v)
+		return cf.formatMapCompact(v, depth, state)
 	case reflect.Struct:
-		return cf.formatStructCompact(v)
+		return cf.formatStructCompact(v, depth, state)
 	case reflect.Interface:
 		if v.IsNil() {
 			return "<nil>"
 		}
-		return cf.formatValueCompact(v.Elem())
+		return cf.formatValueCompact(v.Elem(), depth, state)
 	default:
 		if v.CanInterface() {
 			return fmt.Sprintf("%v", v.Interface())
@@ -107,7 +117,7 @@ func (cf *CloudWatchFormatter) formatValueCompact(v reflect.Value) string {
 	}
 }
 
-func (cf *CloudWatchFormatter) formatStructCompact(v reflect.Value) string {
+func (cf *CloudWatchFormatter) formatStructCompact(v reflect.Value, depth int, state *formatState) string {
 	t := v.Type()
 
 	// Handle special types
@@ -132,7 +142,7 @@ func (cf *CloudWatchFormatter) formatStructCompact(v reflect.Value) string {
 			continue
 		}
 
-		fieldStr := fmt.Sprintf("%s:%s", field.Name, cf.formatValueCompact(fieldValue))
+		fieldStr := fmt.Sprintf("%s:%s", field.Name, cf.formatValueCompact(fieldValue, depth+1, state))
 		parts = append(parts, fieldStr)
 	}
 
@@ -143,7 +153,7 @@ func (cf *CloudWatchFormatter) formatStructCompact(v reflect.Value) string {
 	return fmt.Sprintf("%s{%s}", typeName, strings.Join(parts, ","))
 }
 
-func (cf *CloudWatchFormatter) formatSliceCompact(v reflect.Value) string {
+func (cf *CloudWatchFormatter) formatSliceCompact(v reflect.Value, depth int, state *formatState) string {
 	length := v.Len()
 	if length == 0 {
 		return "[]"
@@ -152,30 +162,42 @@ func (cf *CloudWatchFormatter) formatSliceCompact(v reflect.Value) string {
 	// Handle byte slices
 	if v.Type().Elem().Kind() == reflect.Uint8 {
 		if data, ok := v.Interface().([]byte); ok {
-			return fmt.Sprintf("[]byte(%q)", string(data))
+			return fmt.Sprintf("[]byte(%q)", truncateString(string(data), state.limits.MaxStringLen))
 		}
 	}
 
+	shown := state.items(length)
 	var parts []string
-	for i := 0; i < length; i++ {
-		parts = append(parts, cf.formatValueCompact(v.Index(i)))
+	for i := 0; i < shown; i++ {
+		parts = append(parts, cf.formatValueCompact(v.Index(i), depth+1, state))
+	}
+	if shown < length {
+		parts = append(parts, moreItems(length-shown))
 	}
 
 	return fmt.Sprintf("[%s]", strings.Join(parts, ","))
 }
 
-func (cf *CloudWatchFormatter) formatMapCompact(v reflect.Value) string {
+func (cf *CloudWatchFormatter) formatMapCompact(v reflect.Value, depth int, state *formatState) string {
 	keys := v.MapKeys()
 	if len(keys) == 0 {
 		return "map{}"
 	}
+	if !state.enter(v) {
+		return "<cycle>"
+	}
+	defer state.leave(v)
 
+	shown := state.items(len(keys))
 	var parts []string
-	for _, key := range keys {
-		keyStr := cf.formatValueCompact(key)
-		valueStr := cf.formatValueCompact(v.MapIndex(key))
+	for _, key := range keys[:shown] {
+		keyStr := cf.formatValueCompact(key, depth+1, state)
+		valueStr := cf.formatValueCompact(v.MapIndex(key), depth+1, state)
 		parts = append(parts, fmt.Sprintf("%s:%s", keyStr, valueStr))
 	}
+	if shown < len(keys) {
+		parts = append(parts, moreItems(len(keys)-shown))
+	}
 
 	return fmt.Sprintf("map{%s}", strings.Join(parts, ","))
 }
@@ -183,16 +205,17 @@ func (cf *CloudWatchFormatter) formatMapCompact(v reflect.Value) string {
 // DebugFormatter handles pretty printing of complex types for console output
 type DebugFormatter struct {
 	indent        int
-	maxDepth      int
+	limits        FormatLimits
 	showTypes     bool
 	compactArrays bool
+	theme         Theme // Struct element colors; the zero Theme leaves output plain
 }
 
 // NewDebugFormatter creates a new debug formatter
 func NewDebugFormatter() *DebugFormatter {
 	return &DebugFormatter{
 		indent:        0,
-		maxDepth:      10,
+		limits:        DefaultFormatLimits(),
 		showTypes:     true,
 		compactArrays: true,
 	}
@@ -200,22 +223,22 @@ func NewDebugFormatter() *DebugFormatter {
 
 // Format formats a value with debug information
 func (df *DebugFormatter) Format(v any) string {
-	return df.formatValue(reflect.ValueOf(v), 0)
+	formatted := df.formatValue(reflect.ValueOf(v), 0, newFormatState(df.limits))
+	return truncateString(formatted, df.limits.MaxOutput)
 }
 
 // formatValue recursively formats a reflect.Value
-func (df *DebugFormatter) formatValue(v reflect.Value, depth int) string {
-	if depth > df.maxDepth {
-		return "..."
-	}
-
+func (df *DebugFormatter) formatValue(v reflect.Value, depth int, state *formatState) string {
 	if !v.IsValid() {
-		return "<nil>"
+		return paint(df.theme.Nil, "<nil>")
+	}
+	if state.tooDeep(depth) || state.exhausted() {
+		return "..."
 	}
 
 	// Handle nil pointers
 	if v.Kind() == reflect.Ptr && v.IsNil() {
-		return "nil"
+		return paint(df.theme.Nil, "nil")
 	}
 
 	// Handle error interface FIRST, before checking for structs
@@ -229,39 +252,43 @@ func (df *DebugFormatter) formatValue(v reflect.Value, depth int) string {
 
 	// Dereference pointers
 	if v.Kind() == reflect.Ptr {
-		return "&" + df.formatValue(v.Elem(), depth)
+		if !state.enter(v) {
+			return paint(df.theme.Nil, "<cycle>")
+		}
+		defer state.leave(v)
+		return "&" + df.formatValue(v.Elem(), depth, state)
 	}
 
 	switch v.Kind() {
 	case reflect.String:
-		return fmt.Sprintf("%q", v.String())
+		return paint(df.theme.String, fmt.Sprintf("%q", truncateString(v.String(), state.limits.MaxStringLen)))
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return fmt.Sprintf("%d", v.Int())
+		return paint(df.theme.Number, fmt.Sprintf("%d", v.Int()))
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return fmt.Sprintf("%d", v.Uint())
+		return paint(df.theme.Number, fmt.Sprintf("%d", v.Uint()))
 
 	case reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("%g", v.Float())
+		return paint(df.theme.Number, fmt.Sprintf("%g", v.Float()))
 
 	case reflect.Bool:
-		return fmt.Sprintf("%t", v.Bool())
+		return paint(df.theme.Bool, fmt.Sprintf("%t", v.Bool()))
 
 	case reflect.Slice, reflect.Array:
-		return df.formatSlice(v, depth)
+		return df.formatSlice(v, depth, state)
 
 	case reflect.Map:
-		return df.formatMap(v, depth)
+		return df.formatMap(v, depth, state)
 
 	case reflect.Struct:
-		return df.formatStruct(v, depth)
+		return df.formatStruct(v, depth, state)
 
 	case reflect.Interface:
 		if v.IsNil() {
-			return "<nil>"
+			return paint(df.theme.Nil, "<nil>")
 		}
-		return df.formatValue(v.Elem(), depth)
+		return df.formatValue(v.Elem(), depth, state)
 
 	default:
 		// For unknown types, try to get the interface and format it
@@ -273,7 +300,7 @@ func (df *DebugFormatter) formatValue(v reflect.Value, depth int) string {
 }
 
 // formatStruct formats a struct with field names and values
-func (df *DebugFormatter) formatStruct(v reflect.Value, depth int) string {
+func (df *DebugFormatter) formatStruct(v reflect.Value, depth int, state *formatState) string {
 	t := v.Type()
 
 	// Handle special types
@@ -289,6 +316,7 @@ func (df *DebugFormatter) formatStruct(v reflect.Value, depth int) string {
 	if typeName == "" {
 		typeName = "struct"
 	}
+	typeName = paint(df.theme.Type, typeName)
 
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
@@ -300,8 +328,8 @@ func (df *DebugFormatter) formatStruct(v reflect.Value, depth int) string {
 		}
 
 		fieldStr := fmt.Sprintf("%s: %s",
-			field.Name,
-			df.formatValue(fieldValue, depth+1))
+			paint(df.theme.Key, field.Name),
+			df.formatValue(fieldValue, depth+1, state))
 		parts = append(parts, fieldStr)
 	}
 
@@ -327,7 +355,7 @@ func (df *DebugFormatter) formatStruct(v reflect.Value, depth int) string {
 }
 
 // formatSlice formats slices and arrays
-func (df *DebugFormatter) formatSlice(v reflect.Value, depth int) string {
+func (df *DebugFormatter) formatSlice(v reflect.Value, depth int, state *formatState) string {
 	length := v.Len()
 	if length == 0 {
 		return "[]"
@@ -336,13 +364,17 @@ func (df *DebugFormatter) formatSlice(v reflect.Value, depth int) string {
 	// Handle byte slices specially
 	if v.Type().Elem().Kind() == reflect.Uint8 {
 		if data, ok := v.Interface().([]byte); ok {
-			return fmt.Sprintf("[]byte(%q)", string(data))
+			return fmt.Sprintf("[]byte(%q)", truncateString(string(data), state.limits.MaxStringLen))
 		}
 	}
 
+	shown := state.items(length)
 	var parts []string
-	for i := 0; i < length; i++ {
-		parts = append(parts, df.formatValue(v.Index(i), depth+1))
+	for i := 0; i < shown; i++ {
+		parts = append(parts, df.formatValue(v.Index(i), depth+1, state))
+	}
+	if shown < length {
+		parts = append(parts, moreItems(length-shown))
 	}
 
 	// Compact format for simple types or short arrays
@@ -363,18 +395,29 @@ func (df *DebugFormatter) formatSlice(v reflect.Value, depth int) string {
 }
 
 // formatMap formats maps
-func (df *DebugFormatter) formatMap(v reflect.Value, depth int) string {
+func (df *DebugFormatter) formatMap(v reflect.Value, depth int, state *formatState) string {
 	keys := v.MapKeys()
 	if len(keys) == 0 {
 		return "map{}"
 	}
+	if !state.enter(v) {
+		return paint(df.theme.Nil, "<cycle>")
+	}
+	defer state.leave(v)
 
+	shown := state.items(len(keys))
 	var parts []string
-	for _, key := range keys {
-		keyStr := df.formatValue(key, depth+1)
-		valueStr := df.formatValue(v.MapIndex(key), depth+1)
+	for _, key := range keys[:shown] {
+		keyStr := df.formatValue(key, depth+1, state)
+		if key.Kind() == reflect.String {
+			keyStr = paint(df.theme.Key, fmt.Sprintf("%q", key.String()))
+		}
+		valueStr := df.formatValue(v.MapIndex(key), depth+1, state)
 		parts = append(parts, fmt.Sprintf("%s: %s", keyStr, valueStr))
 	}
+	if shown < len(keys) {
+		parts = append(parts, moreItems(len(keys)-shown))
+	}
 
 	// Compact format for small maps
 	if len(parts) <= 3 && depth > 0 {
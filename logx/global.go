@@ -30,6 +30,13 @@ func init() {
 		}
 	}
 
+	// Select a built-in color theme
+	if themeEnv := os.Getenv("LOG_THEME"); themeEnv != "" {
+		if theme, ok := ThemeByName(themeEnv); ok {
+			defaultLogger.SetTheme(theme)
+		}
+	}
+
 	// Check for colored output (can be disabled with LOG_COLOR=false)
 	if colorEnv := os.Getenv("LOG_COLOR"); colorEnv != "" {
 		colored := strings.ToLower(colorEnv) != "false"
@@ -68,6 +75,16 @@ func SetColored(colored bool) {
 	defaultLogger.SetColored(colored)
 }
 
+// SetTheme sets the global color theme. LOG_COLOR=false still disables colors.
+func SetTheme(theme Theme) {
+	defaultLogger.SetTheme(theme)
+}
+
+// SetFormatLimits bounds struct formatting for the global logger
+func SetFormatLimits(limits FormatLimits) {
+	defaultLogger.SetFormatLimits(limits)
+}
+
 // SetFormat sets the global log format
 func SetFormat(format OutputFormat) {
 	defaultLogger.SetFormat(format)
@@ -103,6 +120,11 @@ func Fatal(msg string, args ...any) {
 	defaultLogger.Fatal(msg, args...)
 }
 
+// Log emits a pre-built entry through the default logger
+func Log(entry Entry) {
+	defaultLogger.Log(entry)
+}
+
 // DebugStruct logs a struct with full debug formatting globally
 func DebugStruct(name string, value any) {
 	defaultLogger.DebugStruct(name, value)
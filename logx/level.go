@@ -57,20 +57,10 @@ func ParseLevel(s string) (Level, error) {
 	}
 }
 
-// Color returns ANSI color code for the level
+// Color returns the level's ANSI color code in the default theme
 func (l Level) Color() string {
-	switch l {
-	case TraceLevel:
-		return "\033[90m" // Bright Black (Gray)
-	case DebugLevel:
-		return "\033[36m" // Cyan
-	case InfoLevel:
-		return "\033[32m" // Green
-	case WarnLevel:
-		return "\033[33m" // Yellow
-	case ErrorLevel:
-		return "\033[31m" // Red
-	default:
-		return "\033[0m" // Reset
+	if color, ok := DefaultTheme().Levels[l]; ok {
+		return color
 	}
+	return ansiReset
 }
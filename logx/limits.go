@@ -0,0 +1,101 @@
+package logx
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// FormatLimits bounds how much of a value the struct formatters render, so a
+// huge or deeply nested value can't flood the log or stall the caller.
+// Zero fields are unlimited.
+type FormatLimits struct {
+	MaxDepth     int // Nesting levels rendered before "..."
+	MaxItems     int // Slice, array and map entries rendered per collection
+	MaxStringLen int // Bytes of a string or []byte rendered
+	MaxValues    int // Values rendered in total; formatting stops early past it
+	MaxOutput    int // Bytes of formatted output kept
+}
+
+// DefaultFormatLimits returns limits tuned for log lines: enough to debug a
+// request payload, small enough to keep the hot path cheap
+func DefaultFormatLimits() FormatLimits {
+	return FormatLimits{
+		MaxDepth:     8,
+		MaxItems:     50,
+		MaxStringLen: 1024,
+		MaxValues:    2000,
+		MaxOutput:    16 * 1024,
+	}
+}
+
+// formatState tracks one Format call. Formatters are shared by goroutines, so
+// per-call bookkeeping lives here rather than on the formatter.
+type formatState struct {
+	limits  FormatLimits
+	values  int
+	visited map[uintptr]bool // Pointers and maps on the current path
+}
+
+func newFormatState(limits FormatLimits) *formatState {
+	return &formatState{limits: limits, visited: make(map[uintptr]bool)}
+}
+
+// exhausted counts a value and reports whether the value budget is spent
+func (s *formatState) exhausted() bool {
+	s.values++
+	return s.limits.MaxValues > 0 && s.values > s.limits.MaxValues
+}
+
+// tooDeep reports whether depth is past the depth limit
+func (s *formatState) tooDeep(depth int) bool {
+	return s.limits.MaxDepth > 0 && depth > s.limits.MaxDepth
+}
+
+// enter marks a pointer or map as being formatted and reports false if it is
+// already on the current path, i.e. the value is cyclic. Call leave afterwards.
+func (s *formatState) enter(v reflect.Value) bool {
+	ptr := v.Pointer()
+	if s.visited[ptr] {
+		return false
+	}
+	s.visited[ptr] = true
+	return true
+}
+
+func (s *formatState) leave(v reflect.Value) {
+	delete(s.visited, v.Pointer())
+}
+
+// items returns how many of length entries to render
+func (s *formatState) items(length int) int {
+	if s.limits.MaxItems > 0 && length > s.limits.MaxItems {
+		return s.limits.MaxItems
+	}
+	return length
+}
+
+// moreItems describes the entries left out of a collection
+func moreItems(omitted int) string {
+	return fmt.Sprintf("... (%d more)", omitted)
+}
+
+// truncateString cuts str to max bytes on a rune boundary
+func truncateString(str string, max int) string {
+	if max <= 0 || len(str) <= max {
+		return str
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(str[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d bytes)", str[:cut], len(str))
+}
+
+// SetFormatLimits bounds struct formatting for DebugStruct, TraceStruct and
+// values passed to Debug and Trace
+func (l *Logger) SetFormatLimits(limits FormatLimits) {
+	l.limits = limits
+	l.debugFormatter.limits = limits
+	l.cloudFormatter.limits = limits
+}
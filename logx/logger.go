@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -27,7 +28,9 @@ type Logger struct {
 	prefix         string
 	showCaller     bool
 	colored        bool
+	theme          Theme
 	format         OutputFormat
+	limits         FormatLimits
 	debugFormatter *DebugFormatter
 	cloudFormatter *CloudWatchFormatter
 }
@@ -40,7 +43,9 @@ func New() *Logger {
 		prefix:         "",
 		showCaller:     true,
 		colored:        true,
+		theme:          DefaultTheme(),
 		format:         FormatConsole,
+		limits:         DefaultFormatLimits(),
 		debugFormatter: NewDebugFormatter(),
 		cloudFormatter: NewCloudWatchFormatter(false),
 	}
@@ -69,6 +74,24 @@ func (l *Logger) SetShowCaller(show bool) {
 // SetColored enables or disables colored output
 func (l *Logger) SetColored(colored bool) {
 	l.colored = colored
+	l.syncFormatterTheme()
+}
+
+// SetTheme sets the colors used for levels and debug-formatted structs.
+// It does not enable colors: with SetColored(false), LOG_COLOR=false or a
+// non-console format the output stays plain.
+func (l *Logger) SetTheme(theme Theme) {
+	l.theme = theme
+	l.syncFormatterTheme()
+}
+
+// syncFormatterTheme gives the debug formatter the theme while colors are on
+func (l *Logger) syncFormatterTheme() {
+	if l.colored {
+		l.debugFormatter.theme = l.theme
+	} else {
+		l.debugFormatter.theme = Theme{}
+	}
 }
 
 // SetFormat sets the output format
@@ -77,10 +100,12 @@ func (l *Logger) SetFormat(format OutputFormat) {
 	// Disable colors for CloudWatch and JSON formats
 	if format == FormatCloudWatch || format == FormatJSON {
 		l.colored = false
+		l.syncFormatterTheme()
 	}
 	// Update CloudWatch formatter for JSON mode
 	if format == FormatJSON {
 		l.cloudFormatter = NewCloudWatchFormatter(true)
+		l.cloudFormatter.limits = l.limits
 	}
 }
 
@@ -114,6 +139,7 @@ func (l *Logger) findCaller() string {
 		// Skip logx package files (more robust check)
 		if strings.Contains(file, "logx") &&
 			(strings.HasSuffix(file, "/logger.go") ||
+				strings.HasSuffix(file, "/entry.go") ||
 				strings.HasSuffix(file, "/global.go") ||
 				strings.HasSuffix(file, "/formatter.go") ||
 				strings.HasSuffix(file, "/level.go")) {
@@ -127,77 +153,90 @@ func (l *Logger) findCaller() string {
 	return ""
 }
 
-// logInternal is the core logging function
+// logInternal formats a printf-style message into an entry and logs it
 func (l *Logger) logInternal(level Level, formatArgs bool, msg string, args ...any) {
 	if !l.IsLevelEnabled(level) {
 		return
 	}
 
-	// Handle different output formats
-	switch l.format {
-	case FormatJSON:
-		l.logJSON(level, msg, args...)
-	case FormatCloudWatch:
-		l.logCloudWatch(level, formatArgs, msg, args...)
+	entry := Entry{Level: level}
+	switch {
+	case l.format == FormatJSON:
+		entry.Message = fmt.Sprintf(msg, args...)
+		// Add structured data for debug/trace levels
+		if level <= DebugLevel && len(args) > 0 {
+			entry.data = make([]any, len(args))
+			for i, arg := range args {
+				entry.data[i] = l.cloudFormatter.Format(arg)
+			}
+		}
+	case formatArgs && level <= DebugLevel:
+		formatter := l.debugFormatter.Format
+		if l.format == FormatCloudWatch {
+			formatter = l.cloudFormatter.Format
+		}
+		processedArgs := make([]any, len(args))
+		for i, arg := range args {
+			processedArgs[i] = formatter(arg)
+		}
+		entry.Message = fmt.Sprintf(msg, processedArgs...)
 	default:
-		l.logConsole(level, formatArgs, msg, args...)
+		entry.Message = fmt.Sprintf(msg, args...)
 	}
+
+	l.Log(entry)
 }
 
 // logJSON outputs structured JSON logs
-func (l *Logger) logJSON(level Level, msg string, args ...any) {
+func (l *Logger) logJSON(entry Entry) {
 	logEntry := map[string]any{
-		"timestamp": time.Now().Format(time.RFC3339),
-		"level":     level.String(),
-		"message":   fmt.Sprintf(msg, args...),
+		"timestamp": entry.Time.Format(time.RFC3339),
+		"level":     entry.Level.String(),
+		"message":   entry.Message,
 	}
 
 	if l.prefix != "" {
 		logEntry["prefix"] = l.prefix
 	}
 
-	if l.showCaller {
-		caller := l.findCaller()
-		if caller != "" {
-			logEntry["caller"] = strings.TrimSpace(caller)
-		}
+	if entry.Caller != "" {
+		logEntry["caller"] = entry.Caller
 	}
 
-	// Add structured data for debug/trace levels
-	if level <= DebugLevel && len(args) > 0 {
-		processedArgs := make([]any, len(args))
-		for i, arg := range args {
-			processedArgs[i] = l.cloudFormatter.Format(arg)
-		}
-		logEntry["data"] = processedArgs
+	if len(entry.Fields) > 0 {
+		logEntry["fields"] = entry.Fields
 	}
 
-	if data, err := json.Marshal(logEntry); err == nil {
-		fmt.Fprintln(l.out, string(data))
+	if entry.Err != nil {
+		logEntry["error"] = entry.Err.Error()
 	}
-}
 
-// logCloudWatch outputs CloudWatch-optimized logs
-func (l *Logger) logCloudWatch(level Level, formatArgs bool, msg string, args ...any) {
-	var processedArgs []any
-	if formatArgs && level <= DebugLevel {
-		processedArgs = make([]any, len(args))
-		for i, arg := range args {
-			processedArgs[i] = l.cloudFormatter.Format(arg)
+	if len(entry.data) > 0 {
+		logEntry["data"] = entry.data
+	}
+
+	data, err := json.Marshal(logEntry)
+	if err != nil {
+		// Fields that can't be marshaled are rendered as text instead
+		logEntry["fields"] = strings.TrimSpace(l.renderFields(Entry{Fields: entry.Fields}))
+		if data, err = json.Marshal(logEntry); err != nil {
+			return
 		}
-	} else {
-		processedArgs = args
 	}
+	fmt.Fprintln(l.out, string(data))
+}
 
-	timestamp := time.Now().Format("2006-01-02T15:04:05.000Z")
-	levelStr := level.String()
+// logCloudWatch outputs CloudWatch-optimized logs
+func (l *Logger) logCloudWatch(entry Entry) {
+	timestamp := entry.Time.Format("2006-01-02T15:04:05.000Z")
+	levelStr := entry.Level.String()
 
 	var caller string
-	if l.showCaller {
-		caller = l.findCaller()
+	if entry.Caller != "" {
+		caller = " " + entry.Caller
 	}
 
-	message := fmt.Sprintf(msg, processedArgs...)
+	message := entry.Message + l.renderFields(entry)
 
 	var fullMessage string
 	if l.prefix != "" {
@@ -212,26 +251,20 @@ func (l *Logger) logCloudWatch(level Level, formatArgs bool, msg string, args ..
 }
 
 // logConsole outputs beautiful console logs
-func (l *Logger) logConsole(level Level, formatArgs bool, msg string, args ...any) {
-	var processedArgs []any
-	if formatArgs && level <= DebugLevel {
-		processedArgs = make([]any, len(args))
-		for i, arg := range args {
-			processedArgs[i] = l.debugFormatter.Format(arg)
-		}
-	} else {
-		processedArgs = args
-	}
-
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	levelStr := level.String()
+func (l *Logger) logConsole(entry Entry) {
+	timestamp := entry.Time.Format("2006-01-02 15:04:05")
+	levelStr := entry.Level.String()
 
 	if l.colored {
-		levelStr = level.Color() + levelStr + "\033[0m"
+		levelStr = paint(l.theme.Levels[entry.Level], levelStr)
+	}
+
+	var caller string
+	if entry.Caller != "" {
+		caller = " " + entry.Caller
 	}
 
-	caller := l.findCaller()
-	message := fmt.Sprintf(msg, processedArgs...)
+	message := entry.Message + l.renderFields(entry)
 
 	var fullMessage string
 	if l.prefix != "" {
@@ -284,27 +317,13 @@ func (l *Logger) DebugStruct(name string, value any) {
 
 	switch l.format {
 	case FormatJSON:
-		logEntry := map[string]any{
-			"timestamp": time.Now().Format(time.RFC3339),
-			"level":     "DEBUG",
-			"message":   fmt.Sprintf("%s = %s", name, l.cloudFormatter.Format(value)),
-			"struct":    value,
-		}
-		if l.showCaller {
-			caller := l.findCaller()
-			if caller != "" {
-				logEntry["caller"] = strings.TrimSpace(caller)
-			}
-		}
-		if data, err := json.Marshal(logEntry); err == nil {
-			fmt.Fprintln(l.out, string(data))
-		}
+		l.logStructJSON(DebugLevel, name, value)
 	case FormatCloudWatch:
 		formatted := l.cloudFormatter.Format(value)
-		l.logCloudWatch(DebugLevel, false, "%s = %s", name, formatted)
+		l.Log(Entry{Level: DebugLevel, Message: fmt.Sprintf("%s = %s", name, formatted)})
 	default:
 		formatted := l.debugFormatter.Format(value)
-		l.logConsole(DebugLevel, false, "%s = %s", name, formatted)
+		l.Log(Entry{Level: DebugLevel, Message: fmt.Sprintf("%s = %s", name, formatted)})
 	}
 }
 
@@ -316,26 +335,40 @@ func (l *Logger) TraceStruct(name string, value any) {
 
 	switch l.format {
 	case FormatJSON:
-		logEntry := map[string]any{
-			"timestamp": time.Now().Format(time.RFC3339),
-			"level":     "TRACE",
-			"message":   fmt.Sprintf("%s = %s", name, l.cloudFormatter.Format(value)),
-			"struct":    value,
-		}
-		if l.showCaller {
-			caller := l.findCaller()
-			if caller != "" {
-				logEntry["caller"] = strings.TrimSpace(caller)
-			}
-		}
-		if data, err := json.Marshal(logEntry); err == nil {
-			fmt.Fprintln(l.out, string(data))
-		}
+		l.logStructJSON(TraceLevel, name, value)
 	case FormatCloudWatch:
 		formatted := l.cloudFormatter.Format(value)
-		l.logCloudWatch(TraceLevel, false, "%s = %s", name, formatted)
+		l.Log(Entry{Level: TraceLevel, Message: fmt.Sprintf("%s = %s", name, formatted)})
 	default:
 		formatted := l.debugFormatter.Format(value)
-		l.logConsole(TraceLevel, false, "%s = %s", name, formatted)
+		l.Log(Entry{Level: TraceLevel, Message: fmt.Sprintf("%s = %s", name, formatted)})
+	}
+}
+
+// logStructJSON emits a struct as JSON once, embedding it as "struct" unless
+// it is over the output limit, in which case only the truncated message is kept
+func (l *Logger) logStructJSON(level Level, name string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		data = []byte(strconv.Quote(fmt.Sprintf("%v", value)))
+	}
+
+	logEntry := map[string]any{
+		"timestamp": time.Now().Format(time.RFC3339),
+		"level":     level.String(),
+		"message":   fmt.Sprintf("%s = %s", name, truncateString(string(data), l.limits.MaxOutput)),
+	}
+	if l.limits.MaxOutput <= 0 || len(data) <= l.limits.MaxOutput {
+		logEntry["struct"] = json.RawMessage(data)
+	} else {
+		logEntry["truncated"] = true
+	}
+	if l.showCaller {
+		if caller := l.findCaller(); caller != "" {
+			logEntry["caller"] = strings.TrimSpace(caller)
+		}
+	}
+	if out, err := json.Marshal(logEntry); err == nil {
+		fmt.Fprintln(l.out, string(out))
 	}
 }
@@ -0,0 +1,94 @@
+package logx
+
+import "strings"
+
+// ANSI reset sequence ending every colored span
+const ansiReset = "\033[0m"
+
+// Theme maps log levels and debug-formatted struct elements to ANSI color
+// sequences. An empty sequence leaves that element uncolored.
+type Theme struct {
+	Name   string
+	Levels map[Level]string
+
+	// Struct elements in DebugFormatter output
+	Key    string // Struct field names and map keys
+	String string
+	Number string
+	Bool   string
+	Nil    string
+	Type   string // Struct type names
+}
+
+// DefaultTheme colors levels only, as logx always has
+func DefaultTheme() Theme {
+	return Theme{
+		Name: "default",
+		Levels: map[Level]string{
+			TraceLevel: "\033[90m", // Bright Black (Gray)
+			DebugLevel: "\033[36m", // Cyan
+			InfoLevel:  "\033[32m", // Green
+			WarnLevel:  "\033[33m", // Yellow
+			ErrorLevel: "\033[31m", // Red
+		},
+	}
+}
+
+// SolarizedTheme uses the Solarized accent colors (256-color terminals) for
+// levels and struct elements
+func SolarizedTheme() Theme {
+	return Theme{
+		Name: "solarized",
+		Levels: map[Level]string{
+			TraceLevel: "\033[38;5;240m", // base01
+			DebugLevel: "\033[38;5;37m",  // cyan
+			InfoLevel:  "\033[38;5;64m",  // green
+			WarnLevel:  "\033[38;5;136m", // yellow
+			ErrorLevel: "\033[38;5;160m", // red
+		},
+		Key:    "\033[38;5;33m",  // blue
+		String: "\033[38;5;64m",  // green
+		Number: "\033[38;5;125m", // magenta
+		Bool:   "\033[38;5;61m",  // violet
+		Nil:    "\033[38;5;240m", // base01
+		Type:   "\033[38;5;166m", // orange
+	}
+}
+
+// MonochromeTheme avoids hues entirely and marks severity with weight, for
+// colorblind users and terminals with unusual backgrounds
+func MonochromeTheme() Theme {
+	return Theme{
+		Name: "monochrome",
+		Levels: map[Level]string{
+			TraceLevel: "\033[2m", // Dim
+			DebugLevel: "\033[2m",
+			WarnLevel:  "\033[1m", // Bold
+			ErrorLevel: "\033[1;4m",
+		},
+		Key:  "\033[1m",
+		Type: "\033[1m",
+	}
+}
+
+// ThemeByName returns a built-in theme ("default", "solarized", "monochrome")
+func ThemeByName(name string) (Theme, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "default":
+		return DefaultTheme(), true
+	case "solarized":
+		return SolarizedTheme(), true
+	case "monochrome", "mono":
+		return MonochromeTheme(), true
+	default:
+		return Theme{}, false
+	}
+}
+
+// paint wraps text in color, or returns it unchanged when color is empty
+func paint(color, text string) string {
+	if color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
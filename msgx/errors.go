@@ -79,4 +79,96 @@ var (
 		http.StatusServiceUnavailable,
 		"Messaging provider is currently unavailable",
 	)
+
+	ErrUnsupportedFeature = Registry.Register(
+		"UNSUPPORTED_FEATURE",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Feature not supported by the configured provider API version",
+	)
+
+	ErrTemplateNotFound = Registry.Register(
+		"TEMPLATE_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Message template not found",
+	)
+
+	ErrTemplateVariableMissing = Registry.Register(
+		"TEMPLATE_VARIABLE_MISSING",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Template variables missing for recipient",
+	)
 )
+
+// IsInvalidMessage reports whether the message was rejected as malformed,
+// e.g. missing content or an invalid recipient. Retrying won't help.
+func IsInvalidMessage(err error) bool {
+	return errx.IsCode(err, ErrInvalidMessage)
+}
+
+// IsProviderNotFound reports whether no provider is registered under the requested name
+func IsProviderNotFound(err error) bool {
+	return errx.IsCode(err, ErrProviderNotFound)
+}
+
+// IsSendFailed reports whether the provider failed to deliver the message
+func IsSendFailed(err error) bool {
+	return errx.IsCode(err, ErrSendFailed)
+}
+
+// IsWebhookVerificationFailed reports whether a webhook signature or
+// verification token did not match
+func IsWebhookVerificationFailed(err error) bool {
+	return errx.IsCode(err, ErrWebhookVerificationFailed)
+}
+
+// IsWebhookParseFailed reports whether a webhook payload could not be decoded
+func IsWebhookParseFailed(err error) bool {
+	return errx.IsCode(err, ErrWebhookParseFailed)
+}
+
+// IsProviderConfigInvalid reports whether the provider is misconfigured, e.g.
+// missing credentials. This needs operator attention rather than a retry.
+func IsProviderConfigInvalid(err error) bool {
+	return errx.IsCode(err, ErrProviderConfigInvalid)
+}
+
+// IsNumberValidationFailed reports whether a phone number failed validation
+func IsNumberValidationFailed(err error) bool {
+	return errx.IsCode(err, ErrNumberValidationFailed)
+}
+
+// IsUnsupportedMessageType reports whether the provider cannot send this kind of message
+func IsUnsupportedMessageType(err error) bool {
+	return errx.IsCode(err, ErrUnsupportedMessageType)
+}
+
+// IsRateLimited reports whether the provider throttled the request. Back off
+// before retrying.
+func IsRateLimited(err error) bool {
+	return errx.IsCode(err, ErrRateLimitExceeded)
+}
+
+// IsProviderUnavailable reports whether the provider is temporarily down.
+// The request may succeed if retried later.
+func IsProviderUnavailable(err error) bool {
+	return errx.IsCode(err, ErrProviderUnavailable)
+}
+
+// IsUnsupportedFeature reports whether the feature needs a newer provider API version
+func IsUnsupportedFeature(err error) bool {
+	return errx.IsCode(err, ErrUnsupportedFeature)
+}
+
+// IsTemplateNotFound reports whether the referenced message template doesn't exist
+func IsTemplateNotFound(err error) bool {
+	return errx.IsCode(err, ErrTemplateNotFound)
+}
+
+// IsTemplateVariableMissing reports whether a local text template referenced
+// a variable the recipient didn't provide
+func IsTemplateVariableMissing(err error) bool {
+	return errx.IsCode(err, ErrTemplateVariableMissing)
+}
@@ -0,0 +1,79 @@
+package msgx
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// ProviderFactory builds a provider from a configuration map, as decoded from
+// a JSON or YAML config file. Factories validate the config before
+// constructing the provider.
+type ProviderFactory func(config map[string]any) (Provider, error)
+
+var (
+	factoriesMutex sync.RWMutex
+	factories      = map[string]ProviderFactory{}
+)
+
+// RegisterProviderFactory makes a provider constructible by name with
+// NewProvider. Provider packages register themselves when imported, so
+// applications import them for their side effect:
+//
+//	import _ "github.com/Abraxas-365/craftable/msgx/providers/msgxwhatsapp"
+func RegisterProviderFactory(name string, factory ProviderFactory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+	factories[name] = factory
+}
+
+// NewProvider constructs the provider registered under name from config. It
+// returns an ErrProviderNotFound error for unknown names and an
+// ErrProviderConfigInvalid error when the config doesn't meet the provider's
+// requirements.
+//
+//	provider, err := msgx.NewProvider(tenant.Channel, tenant.ChannelConfig)
+//	if err != nil {
+//		return err
+//	}
+//	service.RegisterProvider(tenant.ID, provider, false)
+func NewProvider(name string, config map[string]any) (Provider, error) {
+	factoriesMutex.RLock()
+	factory, ok := factories[name]
+	factoriesMutex.RUnlock()
+
+	if !ok {
+		return nil, Registry.New(ErrProviderNotFound).
+			WithDetail("provider", name).
+			WithDetail("available_providers", ProviderFactoryNames())
+	}
+	return factory(config)
+}
+
+// ProviderFactoryNames returns the registered provider names in sorted order
+func ProviderFactoryNames() []string {
+	factoriesMutex.RLock()
+	defer factoriesMutex.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeProviderConfig decodes a configuration map into a provider's config
+// struct using its json tags. Factories use it before validating the result.
+func DecodeProviderConfig(provider string, config map[string]any, target any) error {
+	data, err := json.Marshal(config)
+	if err == nil {
+		err = json.Unmarshal(data, target)
+	}
+	if err != nil {
+		return Registry.New(ErrProviderConfigInvalid).
+			WithCause(err).
+			WithDetail("provider", provider)
+	}
+	return nil
+}
@@ -75,6 +75,12 @@ const (
 	MessageTypeAudio    MessageType = "audio"
 	MessageTypeVideo    MessageType = "video"
 	MessageTypeTemplate MessageType = "template"
+	MessageTypeSticker  MessageType = "sticker"
+	MessageTypeFlow     MessageType = "flow"
+	MessageTypeProduct  MessageType = "product"
+
+	// MessageTypeInteractive is an incoming button or list selection
+	MessageTypeInteractive MessageType = "interactive"
 )
 
 // Content holds the message content based on type
@@ -82,6 +88,8 @@ type Content struct {
 	Text     *TextContent     `json:"text,omitempty"`
 	Media    *MediaContent    `json:"media,omitempty"`
 	Template *TemplateContent `json:"template,omitempty"`
+	Flow     *FlowContent     `json:"flow,omitempty"`
+	Product  *ProductContent  `json:"product,omitempty"`
 }
 
 // TextContent for text messages
@@ -105,6 +113,51 @@ type TemplateContent struct {
 	Parameters map[string]any `json:"parameters,omitempty"`
 }
 
+// FlowAction defines what happens when the user opens a flow
+type FlowAction string
+
+const (
+	FlowActionNavigate     FlowAction = "navigate"      // Open Screen with the given Data
+	FlowActionDataExchange FlowAction = "data_exchange" // Ask the flow endpoint for the first screen
+)
+
+// FlowContent for interactive flow (multi-screen form) messages
+type FlowContent struct {
+	FlowID    string         `json:"flow_id" validate:"required"`
+	FlowToken string         `json:"flow_token,omitempty"` // Echoed back on completion to correlate the response
+	CTA       string         `json:"cta" validate:"required,max=20"`
+	Body      string         `json:"body" validate:"required"`
+	Header    string         `json:"header,omitempty"`
+	Footer    string         `json:"footer,omitempty"`
+	Action    FlowAction     `json:"action,omitempty"` // Defaults to FlowActionNavigate
+	Screen    string         `json:"screen,omitempty"` // First screen, required for FlowActionNavigate
+	Data      map[string]any `json:"data,omitempty"`   // Initial screen data
+	Draft     bool           `json:"draft,omitempty"`  // Send an unpublished flow for testing
+}
+
+// ProductContent for catalog product messages. Set ProductRetailerID to show
+// a single product, or Sections to show a multi-product list.
+type ProductContent struct {
+	CatalogID         string           `json:"catalog_id" validate:"required"`
+	ProductRetailerID string           `json:"product_retailer_id,omitempty"` // Single product (the item's SKU)
+	Sections          []ProductSection `json:"sections,omitempty"`            // Multi-product list
+	Header            string           `json:"header,omitempty"`              // Required for multi-product lists
+	Body              string           `json:"body,omitempty"`                // Required for multi-product lists
+	Footer            string           `json:"footer,omitempty"`
+}
+
+// ProductSection groups products under a title in a multi-product list
+type ProductSection struct {
+	Title              string   `json:"title" validate:"required,max=24"`
+	ProductRetailerIDs []string `json:"product_retailer_ids" validate:"required"`
+}
+
+// ProductReference identifies a catalog product
+type ProductReference struct {
+	CatalogID         string `json:"catalog_id"`
+	ProductRetailerID string `json:"product_retailer_id"`
+}
+
 // MessageOptions for additional message settings
 type MessageOptions struct {
 	Priority    Priority  `json:"priority,omitempty"`
@@ -147,6 +200,9 @@ type ResolvedContent struct {
 	Header          string         `json:"header,omitempty"`
 	Footer          string         `json:"footer,omitempty"`
 	ParameterCount  int            `json:"parameter_count"`
+
+	// MissingParameters lists placeholders left unresolved, e.g. "{{2}}"
+	MissingParameters []string `json:"missing_parameters,omitempty"`
 }
 
 // BulkResponse for bulk operations
@@ -222,6 +278,31 @@ type IncomingContent struct {
 	Media    *IncomingMediaContent `json:"media,omitempty"`
 	Location *LocationContent      `json:"location,omitempty"`
 	Contact  *ContactContent       `json:"contact,omitempty"`
+	Flow     *FlowResponse         `json:"flow,omitempty"`
+
+	Interactive *InteractiveReply `json:"interactive,omitempty"`
+}
+
+// InteractiveReplyType identifies how the user made an interactive selection
+type InteractiveReplyType string
+
+const (
+	// InteractiveReplyQuickReply is a tap on a template quick-reply button
+	InteractiveReplyQuickReply InteractiveReplyType = "quick_reply"
+	// InteractiveReplyButton is a tap on a reply button of an interactive message
+	InteractiveReplyButton InteractiveReplyType = "button_reply"
+	// InteractiveReplyList is a row picked from an interactive list message
+	InteractiveReplyList InteractiveReplyType = "list_reply"
+)
+
+// InteractiveReply is the option a user selected, so bots can branch on ID
+// (or Payload for template quick replies) instead of the displayed title
+type InteractiveReply struct {
+	Type        InteractiveReplyType `json:"type"`
+	ID          string               `json:"id,omitempty"`          // Button or row ID
+	Title       string               `json:"title"`                 // Text shown on the button or row
+	Description string               `json:"description,omitempty"` // List rows only
+	Payload     string               `json:"payload,omitempty"`     // Template quick replies only
 }
 
 // IncomingTextContent for incoming text messages
@@ -236,6 +317,17 @@ type IncomingMediaContent struct {
 	Filename string `json:"filename,omitempty"`
 	MimeType string `json:"mime_type,omitempty"`
 	Size     int64  `json:"size,omitempty"`
+	ID       string `json:"id,omitempty"`       // Provider media ID, used to download the media
+	Animated bool   `json:"animated,omitempty"` // Only set for animated stickers
+}
+
+// FlowResponse carries the fields a user submitted when completing a flow
+type FlowResponse struct {
+	FlowToken    string         `json:"flow_token,omitempty"`
+	Name         string         `json:"name,omitempty"`
+	Body         string         `json:"body,omitempty"`
+	Fields       map[string]any `json:"fields,omitempty"` // Submitted fields, excluding flow_token
+	ResponseJSON string         `json:"response_json,omitempty"`
 }
 
 // LocationContent for location messages
@@ -259,6 +351,10 @@ type MessageContext struct {
 	ReplyToID      string `json:"reply_to_id,omitempty"`
 	IsForwarded    bool   `json:"is_forwarded,omitempty"`
 	ForwardedFrom  string `json:"forwarded_from,omitempty"`
+
+	// ReferredProduct is the catalog product the user asked about, set when
+	// the message was sent from a product's "Message business" button
+	ReferredProduct *ProductReference `json:"referred_product,omitempty"`
 }
 
 // ========== Event Handling ==========
@@ -41,6 +41,56 @@ type TwilioProvider struct {
 	baseURL    string
 }
 
+func init() {
+	msgx.RegisterProviderFactory(twilioProvider, func(config map[string]any) (msgx.Provider, error) {
+		var cfg TwilioConfig
+		if err := msgx.DecodeProviderConfig(twilioProvider, config, &cfg); err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return registeredProvider{NewTwilioProvider(cfg)}, nil
+	})
+}
+
+// registeredProvider adapts TwilioProvider, whose ParseIncomingMessage takes
+// the parsed form, to msgx.Provider for msgx.NewProvider
+type registeredProvider struct {
+	*TwilioProvider
+}
+
+// ParseIncomingMessage parses a form-encoded webhook body
+func (p registeredProvider) ParseIncomingMessage(data []byte) (*msgx.IncomingMessage, error) {
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, msgx.Registry.New(msgx.ErrWebhookParseFailed).
+			WithCause(err).
+			WithDetail("provider", twilioProvider)
+	}
+	return p.TwilioProvider.ParseIncomingMessage(form)
+}
+
+// Validate checks the required fields
+func (c TwilioConfig) Validate() error {
+	var missing []string
+	if c.AccountSID == "" {
+		missing = append(missing, "account_sid")
+	}
+	if c.AuthToken == "" {
+		missing = append(missing, "auth_token")
+	}
+	if c.FromNumber == "" {
+		missing = append(missing, "from_number")
+	}
+	if len(missing) > 0 {
+		return msgx.Registry.New(msgx.ErrProviderConfigInvalid).
+			WithDetail("provider", twilioProvider).
+			WithDetail("missing_fields", missing)
+	}
+	return nil
+}
+
 // NewTwilioProvider creates a new Twilio provider
 func NewTwilioProvider(config TwilioConfig) *TwilioProvider {
 	if config.APIVersion == "" {
@@ -0,0 +1,102 @@
+package msgxwhatsapp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// capturedRequest is a request received by the test Graph API server
+type capturedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// testServer fakes the Graph API and records the requests it receives
+type testServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests []capturedRequest
+}
+
+// lastRequest returns the most recent request, failing the test when there was none
+func (s *testServer) lastRequest(t *testing.T) capturedRequest {
+	t.Helper()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.requests) == 0 {
+		t.Fatal("no request reached the test server")
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// all returns the requests received so far
+func (s *testServer) all() []capturedRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]capturedRequest(nil), s.requests...)
+}
+
+// sendResponse is a successful messages API response
+const sendResponse = `{"messaging_product":"whatsapp","contacts":[{"input":"15551234567","wa_id":"15551234567"}],"messages":[{"id":"wamid.TEST"}]}`
+
+// newTestProvider returns a provider whose API calls go to a test server
+// answering with handler, or with sendResponse when handler is nil
+func newTestProvider(t *testing.T, handler http.HandlerFunc) (*WhatsAppProvider, *testServer) {
+	t.Helper()
+
+	srv := &testServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		captured := capturedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		if len(data) > 0 {
+			_ = json.Unmarshal(data, &captured.Body)
+		}
+		srv.mutex.Lock()
+		srv.requests = append(srv.requests, captured)
+		srv.mutex.Unlock()
+
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, sendResponse)
+	}))
+	t.Cleanup(srv.Close)
+
+	provider := NewWhatsAppProvider(WhatsAppConfig{
+		AccessToken:       "test-token",
+		PhoneNumberID:     "PHONE_ID",
+		BusinessAccountID: "WABA_ID",
+	})
+	provider.baseURL = srv.URL + "/PHONE_ID"
+	provider.businessAPIURL = srv.URL + "/WABA_ID"
+	return provider, srv
+}
+
+// webhookPayload wraps a single inbound message as a messages webhook
+func webhookPayload(message string) []byte {
+	return []byte(`{"object":"whatsapp_business_account","entry":[{"id":"WABA_ID","changes":[{"field":"messages","value":{` +
+		`"messaging_product":"whatsapp","metadata":{"display_phone_number":"15550000000","phone_number_id":"PHONE_ID"},` +
+		`"contacts":[{"profile":{"name":"Ada"},"wa_id":"15551234567"}],"messages":[` + message + `]}}]}]}`)
+}
+
+// jsonPath returns the value at a path of nested JSON objects, or nil
+func jsonPath(body map[string]any, keys ...string) any {
+	var current any = body
+	for _, key := range keys {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
@@ -0,0 +1,56 @@
+package msgxwhatsapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+func TestParseIncomingInteractiveReplies(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    *msgx.InteractiveReply
+	}{
+		{
+			name: "quick reply button",
+			message: `{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"button",` +
+				`"context":{"from":"15550000000","id":"wamid.TEMPLATE"},` +
+				`"button":{"payload":"CONFIRM_ORDER","text":"Confirm"}}`,
+			want: &msgx.InteractiveReply{Type: msgx.InteractiveReplyQuickReply, Title: "Confirm", Payload: "CONFIRM_ORDER"},
+		},
+		{
+			name: "reply button",
+			message: `{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"interactive",` +
+				`"interactive":{"type":"button_reply","button_reply":{"id":"yes","title":"Yes"}}}`,
+			want: &msgx.InteractiveReply{Type: msgx.InteractiveReplyButton, ID: "yes", Title: "Yes"},
+		},
+		{
+			name: "list reply",
+			message: `{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"interactive",` +
+				`"interactive":{"type":"list_reply","list_reply":{"id":"plan_pro","title":"Pro","description":"Unlimited seats"}}}`,
+			want: &msgx.InteractiveReply{Type: msgx.InteractiveReplyList, ID: "plan_pro", Title: "Pro", Description: "Unlimited seats"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(t, nil)
+
+			msg, err := provider.ParseIncomingMessage(webhookPayload(tt.message))
+			if err != nil {
+				t.Fatalf("ParseIncomingMessage: %v", err)
+			}
+			if msg.Type != msgx.MessageTypeInteractive {
+				t.Fatalf("Type = %q, want interactive", msg.Type)
+			}
+			if !reflect.DeepEqual(msg.Content.Interactive, tt.want) {
+				t.Errorf("Interactive = %+v, want %+v", msg.Content.Interactive, tt.want)
+			}
+			if msg.Content.Text == nil || msg.Content.Text.Body != tt.want.Title {
+				t.Errorf("Text = %+v, want the selected title %q", msg.Content.Text, tt.want.Title)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package msgxwhatsapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+// orderTemplate is a template with placeholders in every text component
+const orderTemplate = `{"data":[{"name":"order_update","language":"en_US","status":"APPROVED","components":[
+	{"type":"HEADER","format":"TEXT","text":"Hi {{1}}"},
+	{"type":"BODY","text":"Order {{2}} ships on {{3}}."},
+	{"type":"FOOTER","text":"Reply {{4}} to stop"}]}]}`
+
+// templateAPI answers template lookups with orderTemplate, or with no
+// templates for any other name
+func templateAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Query().Get("name") != "order_update" {
+		io.WriteString(w, `{"data":[]}`)
+		return
+	}
+	io.WriteString(w, orderTemplate)
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		parameters  map[string]any
+		wantHeader  string
+		wantBody    string
+		wantMessage string
+		wantMissing []string
+	}{
+		{
+			name:        "all parameters",
+			parameters:  map[string]any{"1": "Ada", "2": "A-42", "3": "Monday", "4": "STOP"},
+			wantBody:    "Order A-42 ships on Monday.",
+			wantMessage: "Hi Ada\n\nOrder A-42 ships on Monday.\n\nReply STOP to stop",
+		},
+		{
+			name:        "missing body parameter",
+			parameters:  map[string]any{"1": "Ada", "2": "A-42", "4": "STOP"},
+			wantBody:    "Order A-42 ships on {{3}}.",
+			wantMessage: "Hi Ada\n\nOrder A-42 ships on {{3}}.\n\nReply STOP to stop",
+			wantMissing: []string{"{{3}}"},
+		},
+		{
+			name:        "no parameters",
+			wantBody:    "Order {{2}} ships on {{3}}.",
+			wantMessage: "Hi {{1}}\n\nOrder {{2}} ships on {{3}}.\n\nReply {{4}} to stop",
+			wantMissing: []string{"{{1}}", "{{2}}", "{{3}}", "{{4}}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, templateAPI)
+
+			preview, err := provider.PreviewTemplate(context.Background(), &msgx.TemplateContent{
+				Name:       "order_update",
+				Language:   "en_US",
+				Parameters: tt.parameters,
+			})
+			if err != nil {
+				t.Fatalf("PreviewTemplate: %v", err)
+			}
+
+			if preview.Header != "Hi {{1}}" || preview.Footer != "Reply {{4}} to stop" {
+				t.Errorf("original header/footer = %q / %q", preview.Header, preview.Footer)
+			}
+			if preview.ResolvedBody != tt.wantBody {
+				t.Errorf("ResolvedBody = %q, want %q", preview.ResolvedBody, tt.wantBody)
+			}
+			if preview.ResolvedMessage != tt.wantMessage {
+				t.Errorf("ResolvedMessage = %q, want %q", preview.ResolvedMessage, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(preview.MissingParameters, tt.wantMissing) {
+				t.Errorf("MissingParameters = %v, want %v", preview.MissingParameters, tt.wantMissing)
+			}
+
+			// Previews never send a message
+			for _, req := range srv.all() {
+				if req.Method != http.MethodGet || !strings.HasSuffix(req.Path, "/message_templates") {
+					t.Errorf("unexpected %s %s", req.Method, req.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestPreviewTemplateNotFound(t *testing.T) {
+	provider, _ := newTestProvider(t, templateAPI)
+
+	_, err := provider.PreviewTemplate(context.Background(), &msgx.TemplateContent{Name: "unknown", Language: "en_US"})
+	if !errx.IsCode(err, msgx.ErrTemplateNotFound) {
+		t.Fatalf("err = %v, want ErrTemplateNotFound", err)
+	}
+}
+
+func TestPreviewTemplates(t *testing.T) {
+	provider, srv := newTestProvider(t, templateAPI)
+	provider.config.CacheTemplates = false
+
+	previews, err := provider.PreviewTemplates(context.Background(), []*msgx.TemplateContent{
+		{Name: "order_update", Language: "en_US", Parameters: map[string]any{"1": "Ada", "2": "A-1", "3": "Monday", "4": "STOP"}},
+		{Name: "order_update", Language: "en_US", Parameters: map[string]any{"1": "Grace", "2": "A-2", "3": "Friday", "4": "STOP"}},
+	})
+	if err != nil {
+		t.Fatalf("PreviewTemplates: %v", err)
+	}
+
+	want := []string{"Order A-1 ships on Monday.", "Order A-2 ships on Friday."}
+	for i, preview := range previews {
+		if preview.ResolvedBody != want[i] {
+			t.Errorf("preview %d body = %q, want %q", i, preview.ResolvedBody, want[i])
+		}
+	}
+	if len(srv.all()) != 1 {
+		t.Errorf("fetched the template %d times, want once", len(srv.all()))
+	}
+
+	_, err = provider.PreviewTemplates(context.Background(), []*msgx.TemplateContent{
+		{Name: "order_update", Language: "en_US"},
+		{Name: "unknown", Language: "en_US"},
+	})
+	if !errx.IsCode(err, msgx.ErrTemplateNotFound) {
+		t.Fatalf("err = %v, want ErrTemplateNotFound", err)
+	}
+}
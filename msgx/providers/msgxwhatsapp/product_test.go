@@ -0,0 +1,174 @@
+package msgxwhatsapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+func TestSendProductMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		product         *msgx.ProductContent
+		wantInteractive map[string]any
+	}{
+		{
+			name:    "single product",
+			product: &msgx.ProductContent{CatalogID: "CATALOG_1", ProductRetailerID: "SKU-42", Body: "Back in stock"},
+			wantInteractive: map[string]any{
+				"type":   "product",
+				"body":   map[string]any{"text": "Back in stock"},
+				"action": map[string]any{"catalog_id": "CATALOG_1", "product_retailer_id": "SKU-42"},
+			},
+		},
+		{
+			name: "multi-product list",
+			product: &msgx.ProductContent{
+				CatalogID: "CATALOG_1",
+				Header:    "Summer sale",
+				Body:      "Picked for you",
+				Footer:    "While stocks last",
+				Sections: []msgx.ProductSection{
+					{Title: "Shirts", ProductRetailerIDs: []string{"SKU-1", "SKU-2"}},
+					{Title: "Hats", ProductRetailerIDs: []string{"SKU-3"}},
+				},
+			},
+			wantInteractive: map[string]any{
+				"type":   "product_list",
+				"header": map[string]any{"type": "text", "text": "Summer sale"},
+				"body":   map[string]any{"text": "Picked for you"},
+				"footer": map[string]any{"text": "While stocks last"},
+				"action": map[string]any{
+					"catalog_id": "CATALOG_1",
+					"sections": []any{
+						map[string]any{"title": "Shirts", "product_items": []any{
+							map[string]any{"product_retailer_id": "SKU-1"},
+							map[string]any{"product_retailer_id": "SKU-2"},
+						}},
+						map[string]any{"title": "Hats", "product_items": []any{
+							map[string]any{"product_retailer_id": "SKU-3"},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, nil)
+
+			resp, err := provider.Send(context.Background(), msgx.Message{
+				To:      "+15551234567",
+				Type:    msgx.MessageTypeProduct,
+				Content: msgx.Content{Product: tt.product},
+			})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if resp.MessageID != "wamid.TEST" {
+				t.Errorf("MessageID = %q, want wamid.TEST", resp.MessageID)
+			}
+
+			req := srv.lastRequest(t)
+			if got := req.Body["type"]; got != "interactive" {
+				t.Errorf("type = %v, want interactive", got)
+			}
+			if got := req.Body["interactive"]; !reflect.DeepEqual(got, tt.wantInteractive) {
+				t.Errorf("interactive = %v, want %v", got, tt.wantInteractive)
+			}
+		})
+	}
+}
+
+func TestSendProductMessageInvalid(t *testing.T) {
+	sections := func(n int) []msgx.ProductSection {
+		out := make([]msgx.ProductSection, n)
+		for i := range out {
+			out[i] = msgx.ProductSection{Title: "Section", ProductRetailerIDs: []string{"SKU"}}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		product *msgx.ProductContent
+	}{
+		{name: "no product content"},
+		{name: "no catalog", product: &msgx.ProductContent{ProductRetailerID: "SKU-42"}},
+		{name: "neither product nor sections", product: &msgx.ProductContent{CatalogID: "CATALOG_1"}},
+		{
+			name: "both product and sections",
+			product: &msgx.ProductContent{
+				CatalogID: "CATALOG_1", ProductRetailerID: "SKU-42", Header: "h", Body: "b", Sections: sections(1),
+			},
+		},
+		{name: "list without header", product: &msgx.ProductContent{CatalogID: "CATALOG_1", Body: "b", Sections: sections(1)}},
+		{name: "too many sections", product: &msgx.ProductContent{CatalogID: "CATALOG_1", Header: "h", Body: "b", Sections: sections(11)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, nil)
+
+			_, err := provider.Send(context.Background(), msgx.Message{
+				To:      "+15551234567",
+				Type:    msgx.MessageTypeProduct,
+				Content: msgx.Content{Product: tt.product},
+			})
+			if err == nil {
+				t.Fatal("Send succeeded, want an invalid product message error")
+			}
+			if n := len(srv.all()); n != 0 {
+				t.Errorf("%d requests reached the API, want none", n)
+			}
+		})
+	}
+}
+
+func TestParseProductInquiry(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantReplyTo string
+		wantProduct *msgx.ProductReference
+	}{
+		{
+			name: "message about a product",
+			message: `{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"text",` +
+				`"context":{"from":"15550000000","id":"wamid.PRODUCT",` +
+				`"referred_product":{"catalog_id":"CATALOG_1","product_retailer_id":"SKU-42"}},` +
+				`"text":{"body":"Is this available in blue?"}}`,
+			wantReplyTo: "wamid.PRODUCT",
+			wantProduct: &msgx.ProductReference{CatalogID: "CATALOG_1", ProductRetailerID: "SKU-42"},
+		},
+		{
+			name: "plain reply",
+			message: `{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"text",` +
+				`"context":{"from":"15550000000","id":"wamid.EARLIER"},"text":{"body":"Thanks"}}`,
+			wantReplyTo: "wamid.EARLIER",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(t, nil)
+
+			msg, err := provider.ParseIncomingMessage(webhookPayload(tt.message))
+			if err != nil {
+				t.Fatalf("ParseIncomingMessage: %v", err)
+			}
+			if msg.Context == nil {
+				t.Fatal("Context = nil, want the replied-to message")
+			}
+			if msg.Context.ReplyToID != tt.wantReplyTo {
+				t.Errorf("ReplyToID = %q, want %q", msg.Context.ReplyToID, tt.wantReplyTo)
+			}
+			if !reflect.DeepEqual(msg.Context.ReferredProduct, tt.wantProduct) {
+				t.Errorf("ReferredProduct = %+v, want %+v", msg.Context.ReferredProduct, tt.wantProduct)
+			}
+		})
+	}
+}
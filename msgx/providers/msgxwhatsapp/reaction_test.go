@@ -0,0 +1,105 @@
+package msgxwhatsapp
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+func TestSendReaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		send  func(p *WhatsAppProvider) (*msgx.Response, error)
+		emoji string
+	}{
+		{
+			name: "react with an emoji",
+			send: func(p *WhatsAppProvider) (*msgx.Response, error) {
+				return p.SendReaction(context.Background(), "+1 (555) 123-4567", "wamid.IN", "👍")
+			},
+			emoji: "👍",
+		},
+		{
+			name: "empty emoji removes the reaction",
+			send: func(p *WhatsAppProvider) (*msgx.Response, error) {
+				return p.SendReaction(context.Background(), "+15551234567", "wamid.IN", "")
+			},
+		},
+		{
+			name: "RemoveReaction",
+			send: func(p *WhatsAppProvider) (*msgx.Response, error) {
+				return p.RemoveReaction(context.Background(), "+15551234567", "wamid.IN")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, nil)
+
+			resp, err := tt.send(provider)
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			if resp.MessageID != "wamid.TEST" {
+				t.Errorf("MessageID = %q, want wamid.TEST", resp.MessageID)
+			}
+
+			req := srv.lastRequest(t)
+			if req.Method != http.MethodPost || req.Path != "/PHONE_ID/messages" {
+				t.Errorf("request = %s %s, want POST /PHONE_ID/messages", req.Method, req.Path)
+			}
+			want := map[string]any{
+				"messaging_product": "whatsapp",
+				"recipient_type":    "individual",
+				"to":                "+15551234567",
+				"type":              "reaction",
+				"reaction":          map[string]any{"message_id": "wamid.IN", "emoji": tt.emoji},
+			}
+			if !reflect.DeepEqual(req.Body, want) {
+				t.Errorf("body = %v, want %v", req.Body, want)
+			}
+		})
+	}
+}
+
+func TestSendReactionInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		to        string
+		messageID string
+	}{
+		{name: "invalid phone number", to: "abc", messageID: "wamid.IN"},
+		{name: "missing message ID", to: "+15551234567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, nil)
+
+			_, err := provider.SendReaction(context.Background(), tt.to, tt.messageID, "👍")
+			if !errx.IsCode(err, msgx.ErrInvalidMessage) {
+				t.Errorf("err = %v, want ErrInvalidMessage", err)
+			}
+			if n := len(srv.all()); n != 0 {
+				t.Errorf("%d requests reached the API, want none", n)
+			}
+		})
+	}
+}
+
+func TestSendReactionAPIError(t *testing.T) {
+	provider, _ := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Invalid parameter","type":"OAuthException","code":100}}`))
+	})
+
+	if _, err := provider.SendReaction(context.Background(), "+15551234567", "wamid.IN", "👍"); err == nil {
+		t.Fatal("SendReaction succeeded, want the API error")
+	}
+}
@@ -0,0 +1,99 @@
+package msgxwhatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+func TestSendSticker(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		wantID   any
+		wantLink any
+	}{
+		{name: "by media id", url: "media_id:STICKER_123", wantID: "STICKER_123"},
+		{name: "by link", url: "https://example.com/sticker.webp", wantLink: "https://example.com/sticker.webp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, srv := newTestProvider(t, nil)
+
+			resp, err := provider.Send(context.Background(), msgx.Message{
+				To:   "+1 555 123 4567",
+				Type: msgx.MessageTypeSticker,
+				Content: msgx.Content{
+					Media: &msgx.MediaContent{URL: tt.url, Caption: "ignored"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if resp.MessageID != "wamid.TEST" {
+				t.Errorf("MessageID = %q, want wamid.TEST", resp.MessageID)
+			}
+
+			req := srv.lastRequest(t)
+			if req.Path != "/PHONE_ID/messages" {
+				t.Errorf("path = %q, want /PHONE_ID/messages", req.Path)
+			}
+			if got := req.Body["type"]; got != "sticker" {
+				t.Errorf("type = %v, want sticker", got)
+			}
+			if got := jsonPath(req.Body, "sticker", "id"); got != tt.wantID {
+				t.Errorf("sticker.id = %v, want %v", got, tt.wantID)
+			}
+			if got := jsonPath(req.Body, "sticker", "link"); got != tt.wantLink {
+				t.Errorf("sticker.link = %v, want %v", got, tt.wantLink)
+			}
+			if got := jsonPath(req.Body, "sticker", "caption"); got != nil {
+				t.Errorf("sticker.caption = %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestSendStickerRequiresMedia(t *testing.T) {
+	provider, _ := newTestProvider(t, nil)
+
+	_, err := provider.Send(context.Background(), msgx.Message{To: "15551234567", Type: msgx.MessageTypeSticker})
+	if !msgx.IsInvalidMessage(err) {
+		t.Fatalf("error = %v, want invalid message", err)
+	}
+}
+
+func TestParseIncomingSticker(t *testing.T) {
+	tests := []struct {
+		name     string
+		sticker  string
+		animated bool
+	}{
+		{name: "static", sticker: `{"mime_type":"image/webp","sha256":"abc","id":"STICKER_1","animated":false}`},
+		{name: "animated", sticker: `{"mime_type":"image/webp","sha256":"def","id":"STICKER_1","animated":true}`, animated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(t, nil)
+
+			msg, err := provider.ParseIncomingMessage(webhookPayload(
+				`{"from":"15551234567","id":"wamid.IN","timestamp":"1700000000","type":"sticker","sticker":` + tt.sticker + `}`))
+			if err != nil {
+				t.Fatalf("ParseIncomingMessage: %v", err)
+			}
+			if msg.Type != msgx.MessageTypeSticker {
+				t.Fatalf("Type = %q, want sticker", msg.Type)
+			}
+			media := msg.Content.Media
+			if media == nil {
+				t.Fatal("Content.Media is nil")
+			}
+			if media.ID != "STICKER_1" || media.MimeType != "image/webp" || media.Animated != tt.animated {
+				t.Errorf("media = %+v, want id STICKER_1, image/webp, animated %v", media, tt.animated)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package msgxwhatsapp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+// slowHandler answers with sendResponse after delay, or gives up when the
+// client goes away first
+func slowHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, sendResponse)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	send := func(ctx context.Context, w *WhatsAppProvider) error {
+		_, err := w.Send(ctx, msgx.Message{
+			To:      "+15551234567",
+			Type:    msgx.MessageTypeText,
+			Content: msgx.Content{Text: &msgx.TextContent{Body: "hello"}},
+		})
+		return err
+	}
+	typing := func(ctx context.Context, w *WhatsAppProvider) error {
+		return w.SendTypingIndicator(ctx, "+15551234567", true)
+	}
+
+	tests := []struct {
+		name       string
+		config     func(cfg *WhatsAppConfig)
+		callCtx    time.Duration // per-call context deadline; 0 for none
+		delay      time.Duration // how long the API takes to answer
+		call       func(ctx context.Context, w *WhatsAppProvider) error
+		wantErr    bool
+		maxElapsed time.Duration
+	}{
+		{
+			name:       "short call deadline cancels a send despite a long client timeout",
+			config:     func(cfg *WhatsAppConfig) { cfg.HTTPTimeout = 30 },
+			callCtx:    50 * time.Millisecond,
+			delay:      10 * time.Second,
+			call:       send,
+			wantErr:    true,
+			maxElapsed: 2 * time.Second,
+		},
+		{
+			name:       "short call deadline cancels a typing indicator",
+			config:     func(cfg *WhatsAppConfig) { cfg.TypingTimeout = 30 },
+			callCtx:    50 * time.Millisecond,
+			delay:      10 * time.Second,
+			call:       typing,
+			wantErr:    true,
+			maxElapsed: 2 * time.Second,
+		},
+		{
+			name: "typing timeout is shorter than the default",
+			config: func(cfg *WhatsAppConfig) {
+				cfg.HTTPTimeout = 30
+				cfg.TypingTimeout = 1
+			},
+			delay:      10 * time.Second,
+			call:       typing,
+			wantErr:    true,
+			maxElapsed: 3 * time.Second,
+		},
+		{
+			name: "operation timeout outlasts a shorter default",
+			config: func(cfg *WhatsAppConfig) {
+				cfg.HTTPTimeout = 1
+				cfg.SendTimeout = 5
+			},
+			delay:      1500 * time.Millisecond,
+			call:       send,
+			maxElapsed: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newTestProvider(t, slowHandler(tt.delay))
+			provider.config.MaxRetries = 0
+			tt.config(&provider.config)
+
+			ctx := context.Background()
+			if tt.callCtx > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callCtx)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := tt.call(ctx, provider)
+			elapsed := time.Since(start)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("call succeeded, want it cut off by its deadline")
+				}
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("err = %v, want it to wrap context.DeadlineExceeded", err)
+				}
+			} else if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if elapsed > tt.maxElapsed {
+				t.Errorf("call took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package msgxwhatsapp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Abraxas-365/craftable/msgx"
+)
+
+// apiVersionRegex accepts "v23.0", "v23" and "23.0"
+var apiVersionRegex = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?$`)
+
+// Feature identifies a Cloud API capability that is only available from a
+// given Graph API version onwards
+type Feature string
+
+const (
+	// FeatureFlows enables interactive flow messages (v18.0+)
+	FeatureFlows Feature = "flows"
+	// FeatureTypingIndicator enables typing indicators (v22.0+)
+	FeatureTypingIndicator Feature = "typing_indicator"
+)
+
+// featureMinVersions lists the minimum Graph API version for each feature
+var featureMinVersions = map[Feature]apiVersion{
+	FeatureFlows:           {major: 18},
+	FeatureTypingIndicator: {major: 22},
+}
+
+// apiVersion is a parsed Graph API version such as v23.0
+type apiVersion struct {
+	major int
+	minor int
+}
+
+// String formats the version the way the Graph API expects it in URLs
+func (v apiVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.major, v.minor)
+}
+
+// atLeast reports whether v is the same as or newer than other
+func (v apiVersion) atLeast(other apiVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+	return v.minor >= other.minor
+}
+
+// parseAPIVersion parses and normalizes a configured API version
+func parseAPIVersion(version string) (apiVersion, error) {
+	matches := apiVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return apiVersion{}, fmt.Errorf("invalid WhatsApp API version %q, expected a form like %q", version, whatsappAPIVersion)
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor := 0
+	if matches[2] != "" {
+		minor, _ = strconv.Atoi(matches[2])
+	}
+
+	return apiVersion{major: major, minor: minor}, nil
+}
+
+// MinAPIVersion returns the minimum Graph API version required by a feature
+func MinAPIVersion(feature Feature) (string, bool) {
+	version, exists := featureMinVersions[feature]
+	if !exists {
+		return "", false
+	}
+	return version.String(), true
+}
+
+// APIVersion returns the normalized Graph API version the provider uses
+func (w *WhatsAppProvider) APIVersion() string {
+	return w.config.APIVersion
+}
+
+// SupportsFeature reports whether the configured API version supports a feature
+func (w *WhatsAppProvider) SupportsFeature(feature Feature) bool {
+	return w.requireFeature(feature) == nil
+}
+
+// requireFeature returns an ErrUnsupportedFeature error when the configured
+// API version predates the feature, or the configuration error when the
+// version could not be parsed
+func (w *WhatsAppProvider) requireFeature(feature Feature) error {
+	if w.versionErr != nil {
+		return w.versionErr
+	}
+
+	minVersion, exists := featureMinVersions[feature]
+	if !exists || w.version.atLeast(minVersion) {
+		return nil
+	}
+
+	return msgx.Registry.New(msgx.ErrUnsupportedFeature).
+		WithDetail("provider", whatsappProvider).
+		WithDetail("feature", string(feature)).
+		WithDetail("api_version", w.version.String()).
+		WithDetail("min_api_version", minVersion.String())
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -28,6 +29,9 @@ const (
 	whatsappAPIVersion      = "v23.0"
 )
 
+// templatePlaceholderRegex matches placeholders like {{name}} or {{1}}
+var templatePlaceholderRegex = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
 // ========== Template API Structures ==========
 
 // TemplateFromAPI represents template structure from WhatsApp API
@@ -67,6 +71,31 @@ type TemplateButton struct {
 	URL  string `json:"url,omitempty"`
 }
 
+// Template approval statuses reported by the Business API
+const (
+	TemplateStatusApproved = "APPROVED"
+	TemplateStatusPending  = "PENDING"
+	TemplateStatusRejected = "REJECTED"
+	TemplateStatusPaused   = "PAUSED"
+	TemplateStatusDisabled = "DISABLED"
+)
+
+// TemplateListOptions filters and pages ListTemplates
+type TemplateListOptions struct {
+	Statuses []string // Only templates in one of these statuses (empty = all)
+	Category string   // MARKETING, UTILITY or AUTHENTICATION
+	Language string   // Exact language code, e.g. "en_US"
+	Name     string   // Templates whose name contains this text
+	Limit    int      // Page size (default 100)
+	After    string   // Cursor from a previous TemplatePage; empty starts at the first page
+}
+
+// TemplatePage is one page of templates
+type TemplatePage struct {
+	Templates []TemplateFromAPI
+	After     string // Cursor for the next page; empty on the last page
+}
+
 // TemplateCache holds cached template data
 type TemplateCache struct {
 	Template  TemplateFromAPI `json:"template"`
@@ -82,8 +111,12 @@ type WhatsAppConfig struct {
 	BusinessAccountID string `json:"business_account_id" validate:"required"` // Required for template API
 	WebhookSecret     string `json:"webhook_secret,omitempty"`
 	VerifyToken       string `json:"verify_token,omitempty"`
-	APIVersion        string `json:"api_version,omitempty"`
-	HTTPTimeout       int    `json:"http_timeout,omitempty"`
+	APIVersion        string `json:"api_version,omitempty"`      // Graph API version, e.g. "v23.0" (default)
+	HTTPTimeout       int    `json:"http_timeout,omitempty"`     // Default per-request timeout in seconds
+	SendTimeout       int    `json:"send_timeout,omitempty"`     // Message send timeout in seconds (defaults to HTTPTimeout)
+	TypingTimeout     int    `json:"typing_timeout,omitempty"`   // Typing indicator timeout in seconds (defaults to HTTPTimeout)
+	MediaTimeout      int    `json:"media_timeout,omitempty"`    // Media upload timeout in seconds (defaults to HTTPTimeout)
+	TemplateTimeout   int    `json:"template_timeout,omitempty"` // Template API timeout in seconds (defaults to HTTPTimeout)
 	MaxRetries        int    `json:"max_retries,omitempty"`
 	CacheTemplates    bool   `json:"cache_templates,omitempty"`    // Cache templates to avoid repeated API calls
 	TemplateCacheTTL  int    `json:"template_cache_ttl,omitempty"` // Cache TTL in minutes
@@ -96,6 +129,51 @@ type WhatsAppProvider struct {
 	baseURL        string
 	businessAPIURL string
 	templateCache  map[string]TemplateCache
+	version        apiVersion
+	versionErr     error
+}
+
+func init() {
+	msgx.RegisterProviderFactory(whatsappProvider, func(config map[string]any) (msgx.Provider, error) {
+		var cfg WhatsAppConfig
+		if err := msgx.DecodeProviderConfig(whatsappProvider, config, &cfg); err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return NewWhatsAppProvider(cfg), nil
+	})
+}
+
+// Validate checks the required fields and the API version format
+func (c WhatsAppConfig) Validate() error {
+	var missing []string
+	if c.AccessToken == "" {
+		missing = append(missing, "access_token")
+	}
+	if c.PhoneNumberID == "" {
+		missing = append(missing, "phone_number_id")
+	}
+	if c.BusinessAccountID == "" {
+		missing = append(missing, "business_account_id")
+	}
+	if len(missing) > 0 {
+		return msgx.Registry.New(msgx.ErrProviderConfigInvalid).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("missing_fields", missing)
+	}
+
+	if c.APIVersion != "" {
+		if _, err := parseAPIVersion(c.APIVersion); err != nil {
+			return msgx.Registry.New(msgx.ErrProviderConfigInvalid).
+				WithCause(err).
+				WithDetail("provider", whatsappProvider).
+				WithDetail("api_version", c.APIVersion)
+		}
+	}
+
+	return nil
 }
 
 // NewWhatsAppProvider creates a new WhatsApp provider
@@ -113,14 +191,29 @@ func NewWhatsAppProvider(config WhatsAppConfig) *WhatsAppProvider {
 		config.TemplateCacheTTL = 60 // 1 hour default
 	}
 
+	// An invalid version is reported on first use rather than failing opaquely at the API
+	version, err := parseAPIVersion(config.APIVersion)
+	var versionErr error
+	if err != nil {
+		logx.Warn("WhatsApp provider configured with invalid API version: %v", err)
+		versionErr = msgx.Registry.New(msgx.ErrProviderConfigInvalid).
+			WithCause(err).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("api_version", config.APIVersion)
+	} else {
+		config.APIVersion = version.String()
+	}
+
 	return &WhatsAppProvider{
 		config: config,
-		httpClient: &http.Client{
-			Timeout: time.Duration(config.HTTPTimeout) * time.Second,
-		},
+		// Deadlines come from the request context (see requestContext), so a
+		// per-operation timeout isn't cut short by a client-wide one
+		httpClient:     &http.Client{},
 		baseURL:        fmt.Sprintf("%s/%s/%s", whatsappAPIURL, config.APIVersion, config.PhoneNumberID),
 		businessAPIURL: fmt.Sprintf("%s/%s/%s", whatsappAPIURL, config.APIVersion, config.BusinessAccountID),
 		templateCache:  make(map[string]TemplateCache),
+		version:        version,
+		versionErr:     versionErr,
 	}
 }
 
@@ -128,6 +221,10 @@ func NewWhatsAppProvider(config WhatsAppConfig) *WhatsAppProvider {
 
 // GetTemplate fetches template from WhatsApp API
 func (w *WhatsAppProvider) GetTemplate(ctx context.Context, templateName, language string) (*TemplateFromAPI, error) {
+	if w.versionErr != nil {
+		return nil, w.versionErr
+	}
+
 	// Check cache first
 	if w.config.CacheTemplates {
 		cacheKey := fmt.Sprintf("%s_%s", templateName, language)
@@ -138,6 +235,9 @@ func (w *WhatsAppProvider) GetTemplate(ctx context.Context, templateName, langua
 	}
 
 	logx.Debug("Fetching template from API: %s, lang: %s", templateName, language)
+	ctx, cancel := w.requestContext(ctx, w.config.TemplateTimeout)
+	defer cancel()
+
 	// Fetch from API
 	url := fmt.Sprintf("%s/message_templates?name=%s&language=%s", w.businessAPIURL, templateName, language)
 
@@ -186,6 +286,109 @@ func (w *WhatsAppProvider) GetTemplate(ctx context.Context, templateName, langua
 	return &template, nil
 }
 
+// ListTemplates returns every template of the business account matching opts,
+// following pagination from opts.After to the last page
+func (w *WhatsAppProvider) ListTemplates(ctx context.Context, opts TemplateListOptions) ([]TemplateFromAPI, error) {
+	var templates []TemplateFromAPI
+	for {
+		page, err := w.ListTemplatesPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, page.Templates...)
+
+		if page.After == "" {
+			return templates, nil
+		}
+		opts.After = page.After
+	}
+}
+
+// ListTemplatesPage fetches a single page of templates matching opts
+func (w *WhatsAppProvider) ListTemplatesPage(ctx context.Context, opts TemplateListOptions) (*TemplatePage, error) {
+	if w.versionErr != nil {
+		return nil, w.versionErr
+	}
+
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(100))
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if len(opts.Statuses) == 1 {
+		params.Set("status", opts.Statuses[0])
+	}
+	if opts.Category != "" {
+		params.Set("category", opts.Category)
+	}
+	if opts.Language != "" {
+		params.Set("language", opts.Language)
+	}
+	if opts.Name != "" {
+		params.Set("name", opts.Name)
+	}
+	if opts.After != "" {
+		params.Set("after", opts.After)
+	}
+
+	ctx, cancel := w.requestContext(ctx, w.config.TemplateTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", w.businessAPIURL+"/message_templates?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+w.config.AccessToken)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var listResponse struct {
+		Data   []TemplateFromAPI `json:"data"`
+		Paging struct {
+			Cursors struct {
+				After string `json:"after"`
+			} `json:"cursors"`
+			Next string `json:"next"`
+		} `json:"paging"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&listResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode template list response: %w", err)
+	}
+
+	page := &TemplatePage{Templates: listResponse.Data}
+
+	// The API filters by a single status; several are filtered here
+	if len(opts.Statuses) > 1 {
+		page.Templates = page.Templates[:0]
+		for _, template := range listResponse.Data {
+			for _, status := range opts.Statuses {
+				if strings.EqualFold(template.Status, status) {
+					page.Templates = append(page.Templates, template)
+					break
+				}
+			}
+		}
+	}
+
+	// Only a "next" link means more pages; the after cursor is always present
+	if listResponse.Paging.Next != "" {
+		page.After = listResponse.Paging.Cursors.After
+	}
+
+	return page, nil
+}
+
 // buildComponentsFromAPITemplate is the universal builder that constructs components
 // based on the official template structure from the API.
 func (w *WhatsAppProvider) buildComponentsFromAPITemplate(
@@ -337,6 +540,69 @@ func (w *WhatsAppProvider) ResolveTemplateFromAPI(ctx context.Context, templateC
 		}, nil
 	}
 
+	return w.resolveTemplate(template, templateContent), nil
+}
+
+// PreviewTemplate resolves a template with the given parameters without sending
+// it. Unlike ResolveTemplateFromAPI it fails when the template can't be fetched.
+// Unresolved placeholders are kept verbatim and listed in MissingParameters.
+func (w *WhatsAppProvider) PreviewTemplate(ctx context.Context, templateContent *msgx.TemplateContent) (*msgx.ResolvedContent, error) {
+	if templateContent == nil {
+		return nil, msgx.Registry.New(msgx.ErrInvalidMessage).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("reason", "template content is required")
+	}
+
+	template, err := w.GetTemplate(ctx, templateContent.Name, templateContent.Language)
+	if err != nil {
+		return nil, msgx.Registry.New(msgx.ErrTemplateNotFound).
+			WithCause(err).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("template", templateContent.Name).
+			WithDetail("language", templateContent.Language)
+	}
+
+	return w.resolveTemplate(template, templateContent), nil
+}
+
+// PreviewTemplates resolves one template content per recipient without sending,
+// fetching each distinct template only once. Results are in input order.
+func (w *WhatsAppProvider) PreviewTemplates(ctx context.Context, templateContents []*msgx.TemplateContent) ([]*msgx.ResolvedContent, error) {
+	templates := make(map[string]*TemplateFromAPI)
+	previews := make([]*msgx.ResolvedContent, 0, len(templateContents))
+
+	for i, templateContent := range templateContents {
+		if templateContent == nil {
+			return nil, msgx.Registry.New(msgx.ErrInvalidMessage).
+				WithDetail("provider", whatsappProvider).
+				WithDetail("index", i).
+				WithDetail("reason", "template content is required")
+		}
+
+		key := templateContent.Name + "_" + templateContent.Language
+		template, ok := templates[key]
+		if !ok {
+			var err error
+			template, err = w.GetTemplate(ctx, templateContent.Name, templateContent.Language)
+			if err != nil {
+				return nil, msgx.Registry.New(msgx.ErrTemplateNotFound).
+					WithCause(err).
+					WithDetail("provider", whatsappProvider).
+					WithDetail("index", i).
+					WithDetail("template", templateContent.Name).
+					WithDetail("language", templateContent.Language)
+			}
+			templates[key] = template
+		}
+
+		previews = append(previews, w.resolveTemplate(template, templateContent))
+	}
+
+	return previews, nil
+}
+
+// resolveTemplate substitutes parameters into an API-fetched template
+func (w *WhatsAppProvider) resolveTemplate(template *TemplateFromAPI, templateContent *msgx.TemplateContent) *msgx.ResolvedContent {
 	resolved := &msgx.ResolvedContent{
 		TemplateName:   templateContent.Name,
 		Language:       templateContent.Language,
@@ -358,7 +624,7 @@ func (w *WhatsAppProvider) ResolveTemplateFromAPI(ctx context.Context, templateC
 			resolvedBody = w.resolveTemplateText(component.Text, templateContent.Parameters)
 		case "FOOTER":
 			footer = component.Text
-			resolvedFooter = component.Text // Footer usually doesn't have parameters
+			resolvedFooter = w.resolveTemplateText(component.Text, templateContent.Parameters)
 		}
 	}
 
@@ -381,8 +647,22 @@ func (w *WhatsAppProvider) ResolveTemplateFromAPI(ctx context.Context, templateC
 	}
 
 	resolved.ResolvedMessage = fullMessage.String()
+	resolved.MissingParameters = unresolvedPlaceholders(resolved.ResolvedMessage)
 
-	return resolved, nil
+	return resolved
+}
+
+// unresolvedPlaceholders returns the distinct {{...}} placeholders left in text
+func unresolvedPlaceholders(text string) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, placeholder := range templatePlaceholderRegex.FindAllString(text, -1) {
+		if !seen[placeholder] {
+			seen[placeholder] = true
+			missing = append(missing, placeholder)
+		}
+	}
+	return missing
 }
 
 // resolveTemplateText replaces parameters in template text with proper ordering for numbered placeholders
@@ -447,6 +727,15 @@ func (w *WhatsAppProvider) resolveTemplateText(text string, parameters map[strin
 
 // Send sends a message via WhatsApp Business API
 func (w *WhatsAppProvider) Send(ctx context.Context, message msgx.Message) (*msgx.Response, error) {
+	if w.versionErr != nil {
+		return nil, w.versionErr
+	}
+	if message.Type == msgx.MessageTypeFlow {
+		if err := w.requireFeature(FeatureFlows); err != nil {
+			return nil, err
+		}
+	}
+
 	// Convert to WhatsApp API format
 	whatsappMsg, err := w.convertToWhatsAppMessage(ctx, message)
 	if err != nil {
@@ -581,6 +870,44 @@ func (w *WhatsAppProvider) convertToWhatsAppMessage(
 			}
 		}
 
+	case msgx.MessageTypeSticker:
+		if msg.Content.Media == nil {
+			return nil, fmt.Errorf("media content is required for sticker messages")
+		}
+		whatsappMsg.Type = "sticker"
+		// Stickers must be WebP and don't support captions
+		if id, ok := w.parseMediaIDURL(msg.Content.Media.URL); ok {
+			whatsappMsg.Sticker = &whatsappMediaMessage{
+				ID: id,
+			}
+		} else {
+			whatsappMsg.Sticker = &whatsappMediaMessage{
+				Link: msg.Content.Media.URL,
+			}
+		}
+
+	case msgx.MessageTypeFlow:
+		if msg.Content.Flow == nil {
+			return nil, fmt.Errorf("flow content is required for flow messages")
+		}
+		interactive, err := w.buildFlowInteractive(msg.Content.Flow)
+		if err != nil {
+			return nil, err
+		}
+		whatsappMsg.Type = "interactive"
+		whatsappMsg.Interactive = interactive
+
+	case msgx.MessageTypeProduct:
+		if msg.Content.Product == nil {
+			return nil, fmt.Errorf("product content is required for product messages")
+		}
+		interactive, err := w.buildProductInteractive(msg.Content.Product)
+		if err != nil {
+			return nil, err
+		}
+		whatsappMsg.Type = "interactive"
+		whatsappMsg.Interactive = interactive
+
 	case msgx.MessageTypeTemplate:
 		if msg.Content.Template == nil {
 			return nil, fmt.Errorf("template content is required for template messages")
@@ -614,6 +941,128 @@ func (w *WhatsAppProvider) convertToWhatsAppMessage(
 	return whatsappMsg, nil
 }
 
+// buildFlowInteractive converts flow content into an interactive "flow" payload
+func (w *WhatsAppProvider) buildFlowInteractive(flow *msgx.FlowContent) (*whatsappInteractive, error) {
+	if flow.FlowID == "" {
+		return nil, fmt.Errorf("flow id is required for flow messages")
+	}
+	if flow.CTA == "" {
+		return nil, fmt.Errorf("flow cta is required for flow messages")
+	}
+	if flow.Body == "" {
+		return nil, fmt.Errorf("body is required for flow messages")
+	}
+
+	action := flow.Action
+	if action == "" {
+		action = msgx.FlowActionNavigate
+	}
+
+	params := &whatsappFlowParameters{
+		FlowMessageVersion: "3",
+		FlowToken:          flow.FlowToken,
+		FlowID:             flow.FlowID,
+		FlowCTA:            flow.CTA,
+		FlowAction:         string(action),
+	}
+	if flow.Draft {
+		params.Mode = "draft"
+	}
+
+	if action == msgx.FlowActionNavigate {
+		if flow.Screen == "" {
+			return nil, fmt.Errorf("screen is required for navigate flow messages")
+		}
+		params.FlowActionPayload = &whatsappFlowActionPayload{
+			Screen: flow.Screen,
+			Data:   flow.Data,
+		}
+	}
+
+	interactive := &whatsappInteractive{
+		Type: "flow",
+		Body: &whatsappInteractiveText{Text: flow.Body},
+		Action: &whatsappInteractiveAction{
+			Name:       "flow",
+			Parameters: params,
+		},
+	}
+	if flow.Header != "" {
+		interactive.Header = &whatsappInteractiveHeader{Type: "text", Text: flow.Header}
+	}
+	if flow.Footer != "" {
+		interactive.Footer = &whatsappInteractiveText{Text: flow.Footer}
+	}
+
+	return interactive, nil
+}
+
+// Limits WhatsApp enforces on multi-product messages
+const (
+	maxProductSections = 10
+	maxProductItems    = 30
+)
+
+// buildProductInteractive converts product content into an interactive
+// "product" (single item) or "product_list" (multi-product) payload
+func (w *WhatsAppProvider) buildProductInteractive(product *msgx.ProductContent) (*whatsappInteractive, error) {
+	if product.CatalogID == "" {
+		return nil, fmt.Errorf("catalog id is required for product messages")
+	}
+	if (product.ProductRetailerID == "") == (len(product.Sections) == 0) {
+		return nil, fmt.Errorf("product messages need either a product retailer id or sections")
+	}
+
+	interactive := &whatsappInteractive{
+		Action: &whatsappInteractiveAction{CatalogID: product.CatalogID},
+	}
+	if product.Body != "" {
+		interactive.Body = &whatsappInteractiveText{Text: product.Body}
+	}
+	if product.Footer != "" {
+		interactive.Footer = &whatsappInteractiveText{Text: product.Footer}
+	}
+
+	if product.ProductRetailerID != "" {
+		interactive.Type = "product"
+		interactive.Action.ProductRetailerID = product.ProductRetailerID
+		return interactive, nil
+	}
+
+	if product.Header == "" || product.Body == "" {
+		return nil, fmt.Errorf("header and body are required for multi-product messages")
+	}
+	if len(product.Sections) > maxProductSections {
+		return nil, fmt.Errorf("multi-product messages allow at most %d sections, got %d", maxProductSections, len(product.Sections))
+	}
+
+	itemCount := 0
+	for _, section := range product.Sections {
+		if section.Title == "" {
+			return nil, fmt.Errorf("section title is required for multi-product messages")
+		}
+		if len(section.ProductRetailerIDs) == 0 {
+			return nil, fmt.Errorf("section %q has no products", section.Title)
+		}
+		items := make([]whatsappProductItem, len(section.ProductRetailerIDs))
+		for i, id := range section.ProductRetailerIDs {
+			items[i] = whatsappProductItem{ProductRetailerID: id}
+		}
+		itemCount += len(items)
+		interactive.Action.Sections = append(interactive.Action.Sections, whatsappProductSection{
+			Title:        section.Title,
+			ProductItems: items,
+		})
+	}
+	if itemCount > maxProductItems {
+		return nil, fmt.Errorf("multi-product messages allow at most %d products, got %d", maxProductItems, itemCount)
+	}
+
+	interactive.Type = "product_list"
+	interactive.Header = &whatsappInteractiveHeader{Type: "text", Text: product.Header}
+	return interactive, nil
+}
+
 func (w *WhatsAppProvider) buildComponentsWithoutAPI(parameters map[string]any) []whatsappTemplateComponent {
 	components := []whatsappTemplateComponent{
 		{
@@ -903,6 +1352,9 @@ func (w *WhatsAppProvider) sendMessage(ctx context.Context, message *whatsappMes
 
 	logx.Debug("Sending WhatsApp message: %s", string(jsonData))
 
+	ctx, cancel := w.requestContext(ctx, w.config.SendTimeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, msgx.Registry.New(msgx.ErrSendFailed).
@@ -939,6 +1391,18 @@ func (w *WhatsAppProvider) sendMessage(ctx context.Context, message *whatsappMes
 	return &sendResp, nil
 }
 
+// requestContext bounds a single API call by timeoutSeconds, falling back to
+// HTTPTimeout when it is zero. A shorter deadline already on ctx still wins.
+func (w *WhatsAppProvider) requestContext(ctx context.Context, timeoutSeconds int) (context.Context, context.CancelFunc) {
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = w.config.HTTPTimeout
+	}
+	if timeoutSeconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+}
+
 func (w *WhatsAppProvider) handleAPIError(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
 
@@ -1003,6 +1467,16 @@ func (w *WhatsAppProvider) convertWhatsAppMessage(message whatsappIncomingMessag
 		RawData:   map[string]any{"whatsapp_message": message},
 	}
 
+	if message.Context != nil {
+		incomingMsg.Context = &msgx.MessageContext{ReplyToID: message.Context.ID}
+		if product := message.Context.ReferredProduct; product != nil {
+			incomingMsg.Context.ReferredProduct = &msgx.ProductReference{
+				CatalogID:         product.CatalogID,
+				ProductRetailerID: product.ProductRetailerID,
+			}
+		}
+	}
+
 	// Parse message content based on type
 	switch message.Type {
 	case "text":
@@ -1014,6 +1488,7 @@ func (w *WhatsAppProvider) convertWhatsAppMessage(message whatsappIncomingMessag
 	case "image":
 		incomingMsg.Type = msgx.MessageTypeImage
 		incomingMsg.Content.Media = &msgx.IncomingMediaContent{
+			ID:       message.Image.ID,
 			Caption:  message.Image.Caption,
 			MimeType: message.Image.MimeType,
 		}
@@ -1022,6 +1497,7 @@ func (w *WhatsAppProvider) convertWhatsAppMessage(message whatsappIncomingMessag
 	case "document":
 		incomingMsg.Type = msgx.MessageTypeDocument
 		incomingMsg.Content.Media = &msgx.IncomingMediaContent{
+			ID:       message.Document.ID,
 			Caption:  message.Document.Caption,
 			Filename: message.Document.Filename,
 			MimeType: message.Document.MimeType,
@@ -1030,16 +1506,28 @@ func (w *WhatsAppProvider) convertWhatsAppMessage(message whatsappIncomingMessag
 	case "audio":
 		incomingMsg.Type = msgx.MessageTypeAudio
 		incomingMsg.Content.Media = &msgx.IncomingMediaContent{
+			ID:       message.Audio.ID,
 			MimeType: message.Audio.MimeType,
 		}
 
 	case "video":
 		incomingMsg.Type = msgx.MessageTypeVideo
 		incomingMsg.Content.Media = &msgx.IncomingMediaContent{
+			ID:       message.Video.ID,
 			Caption:  message.Video.Caption,
 			MimeType: message.Video.MimeType,
 		}
 
+	case "sticker":
+		incomingMsg.Type = msgx.MessageTypeSticker
+		if message.Sticker != nil {
+			incomingMsg.Content.Media = &msgx.IncomingMediaContent{
+				ID:       message.Sticker.ID,
+				MimeType: message.Sticker.MimeType,
+				Animated: message.Sticker.Animated,
+			}
+		}
+
 	case "location":
 		incomingMsg.Content.Location = &msgx.LocationContent{
 			Latitude:  message.Location.Latitude,
@@ -1068,19 +1556,86 @@ func (w *WhatsAppProvider) convertWhatsAppMessage(message whatsappIncomingMessag
 		}
 
 	case "button":
-		// Handle button responses
+		// Template quick-reply button
 		incomingMsg.Type = msgx.MessageTypeText
-		// Button handling would need additional parsing
+		if message.Button != nil {
+			incomingMsg.Type = msgx.MessageTypeInteractive
+			incomingMsg.Content.Interactive = &msgx.InteractiveReply{
+				Type:    msgx.InteractiveReplyQuickReply,
+				Title:   message.Button.Text,
+				Payload: message.Button.Payload,
+			}
+		}
 
 	case "interactive":
 		// Handle interactive message responses
 		incomingMsg.Type = msgx.MessageTypeText
-		// Interactive handling would need additional parsing
+		if message.Interactive != nil {
+			w.parseInteractiveReply(message.Interactive, incomingMsg)
+		}
+	}
+
+	// Expose the selected option's title to text-only consumers too
+	if reply := incomingMsg.Content.Interactive; reply != nil {
+		incomingMsg.Content.Text = &msgx.IncomingTextContent{Body: reply.Title}
 	}
 
 	return incomingMsg, nil
 }
 
+// parseInteractiveReply fills in a flow completion, reply button or list selection
+func (w *WhatsAppProvider) parseInteractiveReply(interactive *whatsappIncomingInteractive, incomingMsg *msgx.IncomingMessage) {
+	switch {
+	case interactive.Type == "nfm_reply" && interactive.NfmReply != nil:
+		incomingMsg.Type = msgx.MessageTypeFlow
+		incomingMsg.Content.Flow = w.parseFlowReply(interactive.NfmReply)
+
+	case interactive.Type == "button_reply" && interactive.ButtonReply != nil:
+		incomingMsg.Type = msgx.MessageTypeInteractive
+		incomingMsg.Content.Interactive = &msgx.InteractiveReply{
+			Type:  msgx.InteractiveReplyButton,
+			ID:    interactive.ButtonReply.ID,
+			Title: interactive.ButtonReply.Title,
+		}
+
+	case interactive.Type == "list_reply" && interactive.ListReply != nil:
+		incomingMsg.Type = msgx.MessageTypeInteractive
+		incomingMsg.Content.Interactive = &msgx.InteractiveReply{
+			Type:        msgx.InteractiveReplyList,
+			ID:          interactive.ListReply.ID,
+			Title:       interactive.ListReply.Title,
+			Description: interactive.ListReply.Description,
+		}
+	}
+}
+
+// parseFlowReply converts a flow completion (nfm_reply) into a FlowResponse
+func (w *WhatsAppProvider) parseFlowReply(reply *whatsappNfmReply) *msgx.FlowResponse {
+	flowResp := &msgx.FlowResponse{
+		Name:         reply.Name,
+		Body:         reply.Body,
+		ResponseJSON: reply.ResponseJSON,
+	}
+
+	if reply.ResponseJSON == "" {
+		return flowResp
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(reply.ResponseJSON), &fields); err != nil {
+		logx.Warn("Failed to parse WhatsApp flow response_json: %v", err)
+		return flowResp
+	}
+
+	if token, ok := fields["flow_token"].(string); ok {
+		flowResp.FlowToken = token
+	}
+	delete(fields, "flow_token")
+	flowResp.Fields = fields
+
+	return flowResp
+}
+
 func (w *WhatsAppProvider) cleanPhoneNumber(phoneNumber string) string {
 	// Remove all non-digit characters except '+'
 	cleaned := ""
@@ -1108,7 +1663,13 @@ func (w *WhatsAppProvider) isValidPhoneFormat(phoneNumber string) bool {
 
 // ========== Typing Indicator Methods ==========
 
+// SendTypingIndicator sends "typing_on" or "typing_off". Typing indicators
+// require API version v22.0 or later; older versions get ErrUnsupportedFeature.
 func (w *WhatsAppProvider) SendTypingIndicator(ctx context.Context, to string, isTyping bool) error {
+	if err := w.requireFeature(FeatureTypingIndicator); err != nil {
+		return err
+	}
+
 	typingType := "typing_on"
 	if !isTyping {
 		typingType = "typing_off"
@@ -1194,6 +1755,9 @@ func (w *WhatsAppProvider) sendTypingIndicator(ctx context.Context, typingMsg wh
 	// Create the request URL (same endpoint as regular messages)
 	url := fmt.Sprintf("%s/messages", w.baseURL)
 
+	ctx, cancel := w.requestContext(ctx, w.config.TypingTimeout)
+	defer cancel()
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
@@ -1272,6 +1836,71 @@ func (w *WhatsAppProvider) SendWithTyping(ctx context.Context, message msgx.Mess
 	return response, err
 }
 
+// ========== Reaction Methods ==========
+
+// SendReaction reacts to a received message with an emoji. An empty emoji
+// removes the reaction previously sent to that message.
+func (w *WhatsAppProvider) SendReaction(ctx context.Context, to, messageID, emoji string) (*msgx.Response, error) {
+	if w.versionErr != nil {
+		return nil, w.versionErr
+	}
+
+	cleanedTo := w.cleanPhoneNumber(to)
+	if !w.isValidPhoneFormat(cleanedTo) {
+		return nil, msgx.Registry.New(msgx.ErrInvalidMessage).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("phone_number", to).
+			WithDetail("cleaned", cleanedTo).
+			WithDetail("reason", "Invalid phone number format")
+	}
+	if messageID == "" {
+		return nil, msgx.Registry.New(msgx.ErrInvalidMessage).
+			WithDetail("provider", whatsappProvider).
+			WithDetail("reason", "message ID is required for reactions")
+	}
+
+	reactionMsg := &whatsappMessage{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               cleanedTo,
+		Type:             "reaction",
+		Reaction: &whatsappReaction{
+			MessageID: messageID,
+			Emoji:     emoji,
+		},
+	}
+
+	response, err := w.sendMessage(ctx, reactionMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgxResponse := &msgx.Response{
+		Provider:  whatsappProvider,
+		To:        to,
+		Status:    msgx.StatusPending,
+		Timestamp: time.Now(),
+		ProviderData: map[string]any{
+			"reacted_message_id": messageID,
+			"emoji":              emoji,
+		},
+	}
+	if len(response.Messages) > 0 {
+		msgxResponse.MessageID = response.Messages[0].ID
+		msgxResponse.ProviderData["whatsapp_id"] = response.Messages[0].ID
+	}
+	if len(response.Contacts) > 0 {
+		msgxResponse.ProviderData["wa_id"] = response.Contacts[0].WaID
+	}
+
+	return msgxResponse, nil
+}
+
+// RemoveReaction removes the reaction previously sent to a message
+func (w *WhatsAppProvider) RemoveReaction(ctx context.Context, to, messageID string) (*msgx.Response, error) {
+	return w.SendReaction(ctx, to, messageID, "")
+}
+
 // ========== WhatsApp API Structures ==========
 
 // Send message structures
@@ -1285,7 +1914,17 @@ type whatsappMessage struct {
 	Document         *whatsappDocumentMessage `json:"document,omitempty"`
 	Audio            *whatsappMediaMessage    `json:"audio,omitempty"`
 	Video            *whatsappMediaMessage    `json:"video,omitempty"`
+	Sticker          *whatsappMediaMessage    `json:"sticker,omitempty"`
 	Template         *whatsappTemplateMessage `json:"template,omitempty"`
+	Interactive      *whatsappInteractive     `json:"interactive,omitempty"`
+	Reaction         *whatsappReaction        `json:"reaction,omitempty"`
+}
+
+// whatsappReaction reacts to a message; an empty Emoji removes the reaction,
+// so it is always serialized
+type whatsappReaction struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
 }
 
 type whatsappTextMessage struct {
@@ -1306,6 +1945,57 @@ type whatsappDocumentMessage struct {
 	ID       string `json:"id,omitempty"` // NEW: support sending document by id
 }
 
+type whatsappInteractive struct {
+	Type   string                     `json:"type"` // "flow", "product", "product_list"
+	Header *whatsappInteractiveHeader `json:"header,omitempty"`
+	Body   *whatsappInteractiveText   `json:"body,omitempty"`
+	Footer *whatsappInteractiveText   `json:"footer,omitempty"`
+	Action *whatsappInteractiveAction `json:"action"`
+}
+
+type whatsappInteractiveHeader struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type whatsappInteractiveText struct {
+	Text string `json:"text"`
+}
+
+type whatsappInteractiveAction struct {
+	Name       string                  `json:"name,omitempty"`
+	Parameters *whatsappFlowParameters `json:"parameters,omitempty"`
+
+	// Product and product_list messages
+	CatalogID         string                   `json:"catalog_id,omitempty"`
+	ProductRetailerID string                   `json:"product_retailer_id,omitempty"`
+	Sections          []whatsappProductSection `json:"sections,omitempty"`
+}
+
+type whatsappProductSection struct {
+	Title        string                `json:"title"`
+	ProductItems []whatsappProductItem `json:"product_items"`
+}
+
+type whatsappProductItem struct {
+	ProductRetailerID string `json:"product_retailer_id"`
+}
+
+type whatsappFlowParameters struct {
+	FlowMessageVersion string                     `json:"flow_message_version"`
+	FlowToken          string                     `json:"flow_token,omitempty"`
+	FlowID             string                     `json:"flow_id"`
+	FlowCTA            string                     `json:"flow_cta"`
+	FlowAction         string                     `json:"flow_action"`
+	FlowActionPayload  *whatsappFlowActionPayload `json:"flow_action_payload,omitempty"`
+	Mode               string                     `json:"mode,omitempty"` // "draft" for unpublished flows
+}
+
+type whatsappFlowActionPayload struct {
+	Screen string         `json:"screen"`
+	Data   map[string]any `json:"data,omitempty"`
+}
+
 // upload response
 type whatsappMediaUploadResponse struct {
 	ID string `json:"id"`
@@ -1405,29 +2095,65 @@ type whatsappProfile struct {
 
 // Incoming message structures
 type whatsappIncomingMessage struct {
-	From      string                    `json:"from"`
-	ID        string                    `json:"id"`
-	Timestamp string                    `json:"timestamp"`
-	Type      string                    `json:"type"`
-	Context   *whatsappMessageContext   `json:"context,omitempty"`
-	Text      *whatsappIncomingText     `json:"text,omitempty"`
-	Image     *whatsappIncomingMedia    `json:"image,omitempty"`
-	Document  *whatsappIncomingDocument `json:"document,omitempty"`
-	Audio     *whatsappIncomingMedia    `json:"audio,omitempty"`
-	Video     *whatsappIncomingMedia    `json:"video,omitempty"`
-	Location  *whatsappIncomingLocation `json:"location,omitempty"`
-	Contacts  []whatsappIncomingContact `json:"contacts,omitempty"`
+	From        string                       `json:"from"`
+	ID          string                       `json:"id"`
+	Timestamp   string                       `json:"timestamp"`
+	Type        string                       `json:"type"`
+	Context     *whatsappMessageContext      `json:"context,omitempty"`
+	Text        *whatsappIncomingText        `json:"text,omitempty"`
+	Image       *whatsappIncomingMedia       `json:"image,omitempty"`
+	Document    *whatsappIncomingDocument    `json:"document,omitempty"`
+	Audio       *whatsappIncomingMedia       `json:"audio,omitempty"`
+	Video       *whatsappIncomingMedia       `json:"video,omitempty"`
+	Sticker     *whatsappIncomingSticker     `json:"sticker,omitempty"`
+	Location    *whatsappIncomingLocation    `json:"location,omitempty"`
+	Contacts    []whatsappIncomingContact    `json:"contacts,omitempty"`
+	Interactive *whatsappIncomingInteractive `json:"interactive,omitempty"`
+	Button      *whatsappIncomingButton      `json:"button,omitempty"`
+}
+
+type whatsappIncomingInteractive struct {
+	Type        string               `json:"type"` // "nfm_reply", "button_reply", "list_reply"
+	NfmReply    *whatsappNfmReply    `json:"nfm_reply,omitempty"`
+	ButtonReply *whatsappButtonReply `json:"button_reply,omitempty"`
+	ListReply   *whatsappListReply   `json:"list_reply,omitempty"`
+}
+
+// whatsappIncomingButton is sent when a user taps a template quick-reply button
+type whatsappIncomingButton struct {
+	Payload string `json:"payload"`
+	Text    string `json:"text"`
+}
+
+// whatsappButtonReply is sent when a user taps a reply button of an interactive message
+type whatsappButtonReply struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// whatsappListReply is sent when a user picks a row of an interactive list message
+type whatsappListReply struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// whatsappNfmReply is sent when a user completes a flow
+type whatsappNfmReply struct {
+	Name         string `json:"name"`
+	Body         string `json:"body"`
+	ResponseJSON string `json:"response_json"` // JSON-encoded submitted fields, including flow_token
 }
 
 type whatsappMessageContext struct {
-	From     string `json:"from"`
-	ID       string `json:"id"`
-	Referred struct {
-		Product struct {
-			CatalogID         string `json:"catalog_id"`
-			ProductRetailerID string `json:"product_retailer_id"`
-		} `json:"product"`
-	} `json:"referred"`
+	From            string                   `json:"from"`
+	ID              string                   `json:"id"`
+	ReferredProduct *whatsappReferredProduct `json:"referred_product,omitempty"`
+}
+
+type whatsappReferredProduct struct {
+	CatalogID         string `json:"catalog_id"`
+	ProductRetailerID string `json:"product_retailer_id"`
 }
 
 type whatsappIncomingText struct {
@@ -1449,6 +2175,13 @@ type whatsappIncomingDocument struct {
 	ID       string `json:"id"`
 }
 
+type whatsappIncomingSticker struct {
+	MimeType string `json:"mime_type"`
+	Sha256   string `json:"sha256"`
+	ID       string `json:"id"`
+	Animated bool   `json:"animated"`
+}
+
 type whatsappIncomingLocation struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
@@ -1579,6 +2312,9 @@ func (w *WhatsAppProvider) UploadMedia(ctx context.Context, filename string, mim
 
 	_ = writer.Close()
 
+	ctx, cancel := w.requestContext(ctx, w.config.MediaTimeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
 	if err != nil {
 		return "", fmt.Errorf("failed to create upload request: %w", err)
@@ -14,6 +14,7 @@ type Service struct {
 	defaultReceiver string
 	eventHandler    EventHandler
 	webhookServer   *WebhookServer
+	textTemplates   map[string]*TextTemplate
 }
 
 // NewService creates a new messaging service
@@ -225,7 +226,8 @@ func (s *Service) validateMessage(message Message) error {
 	if message.Type == "" {
 		return fmt.Errorf("message type is required")
 	}
-	if message.Content.Text == nil && message.Content.Media == nil && message.Content.Template == nil {
+	if message.Content.Text == nil && message.Content.Media == nil && message.Content.Template == nil &&
+		message.Content.Flow == nil && message.Content.Product == nil {
 		return fmt.Errorf("message content is required")
 	}
 	if message.Type == MessageTypeText && message.Content.Text == nil {
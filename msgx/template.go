@@ -0,0 +1,169 @@
+package msgx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// textPlaceholderRegex matches placeholders like {{name}} or {{ 1 }}
+var textPlaceholderRegex = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// TextTemplate is a local free-form text template with {{var}} placeholders.
+// Unlike TemplateContent it needs no provider approval, so it only reaches
+// recipients inside the provider's session window (24 hours on WhatsApp).
+type TextTemplate struct {
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	PreviewURL bool   `json:"preview_url,omitempty"`
+}
+
+// Recipient is one addressee of a templated message with its variables
+type Recipient struct {
+	To        string            `json:"to"`
+	Variables map[string]any    `json:"variables,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// NewTextTemplate creates a text template
+func NewTextTemplate(name, body string) *TextTemplate {
+	return &TextTemplate{Name: name, Body: body}
+}
+
+// WithPreviewURL enables link previews in rendered messages
+func (t *TextTemplate) WithPreviewURL() *TextTemplate {
+	t.PreviewURL = true
+	return t
+}
+
+// Variables returns the distinct placeholder names in the body, in order of appearance
+func (t *TextTemplate) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range textPlaceholderRegex.FindAllStringSubmatch(t.Body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Render replaces every placeholder with its variable. Placeholders without a
+// variable fail the render rather than reaching the recipient verbatim.
+func (t *TextTemplate) Render(variables map[string]any) (string, error) {
+	var missing []string
+	rendered := textPlaceholderRegex.ReplaceAllStringFunc(t.Body, func(placeholder string) string {
+		name := textPlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		value, ok := variables[name]
+		if !ok {
+			missing = append(missing, name)
+			return placeholder
+		}
+		return fmt.Sprintf("%v", value)
+	})
+
+	if len(missing) > 0 {
+		return "", Registry.New(ErrTemplateVariableMissing).
+			WithDetail("template", t.Name).
+			WithDetail("missing_variables", missing)
+	}
+	return rendered, nil
+}
+
+// Message renders the template for a recipient into a text message
+func (t *TextTemplate) Message(recipient Recipient) (Message, error) {
+	body, err := t.Render(recipient.Variables)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		To:   recipient.To,
+		Type: MessageTypeText,
+		Content: Content{
+			Text: &TextContent{Body: body, PreviewURL: t.PreviewURL},
+		},
+		Metadata: recipient.Metadata,
+	}, nil
+}
+
+// ========== Service Integration ==========
+
+// RegisterTextTemplate makes a local text template available by name
+func (s *Service) RegisterTextTemplate(template *TextTemplate) *Service {
+	if s.textTemplates == nil {
+		s.textTemplates = make(map[string]*TextTemplate)
+	}
+	s.textTemplates[template.Name] = template
+	return s
+}
+
+// SendTextTemplate renders a registered text template for the recipient and
+// sends it with the default sender
+func (s *Service) SendTextTemplate(ctx context.Context, templateName string, recipient Recipient) (*Response, error) {
+	template, err := s.textTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+	message, err := template.Message(recipient)
+	if err != nil {
+		return nil, err
+	}
+	return s.Send(ctx, message)
+}
+
+// SendBulkTextTemplate renders a registered text template once per recipient
+// and sends the results with the default sender. Recipients whose variables
+// don't cover the template are reported in FailedItems without being sent;
+// failure indexes refer to recipients.
+func (s *Service) SendBulkTextTemplate(ctx context.Context, templateName string, recipients []Recipient) (*BulkResponse, error) {
+	template, err := s.textTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	var indexes []int // message index -> recipient index
+	var renderFailures []BulkFailure
+	for i, recipient := range recipients {
+		message, err := template.Message(recipient)
+		if err != nil {
+			renderFailures = append(renderFailures, BulkFailure{
+				Index:   i,
+				Message: recipient.To,
+				Error:   err.Error(),
+			})
+			continue
+		}
+		messages = append(messages, message)
+		indexes = append(indexes, i)
+	}
+
+	response := &BulkResponse{}
+	if len(messages) > 0 {
+		response, err = s.SendBulk(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+		for i := range response.FailedItems {
+			if index := response.FailedItems[i].Index; index >= 0 && index < len(indexes) {
+				response.FailedItems[i].Index = indexes[index]
+			}
+		}
+	}
+
+	response.FailedItems = append(response.FailedItems, renderFailures...)
+	response.TotalFailed += len(renderFailures)
+	return response, nil
+}
+
+func (s *Service) textTemplate(name string) (*TextTemplate, error) {
+	template, ok := s.textTemplates[strings.TrimSpace(name)]
+	if !ok {
+		return nil, Registry.New(ErrTemplateNotFound).
+			WithDetail("template", name).
+			WithDetail("source", "local")
+	}
+	return template, nil
+}
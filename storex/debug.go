@@ -0,0 +1,150 @@
+package storex
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+)
+
+// SQLStatement is a generated SQL query together with its placeholder arguments
+type SQLStatement struct {
+	Operation string // create, find_by_id, find_one, update, delete, paginate, count, ...
+	Query     string // Query with driver placeholders ($1, $2, ...)
+	Args      []any  // Placeholder arguments in order
+}
+
+// String renders the statement with argument values masked
+func (s SQLStatement) String() string {
+	return s.format(false)
+}
+
+// StringWithArgs renders the statement including argument values.
+// Avoid logging this in production since arguments may contain sensitive data.
+func (s SQLStatement) StringWithArgs() string {
+	return s.format(true)
+}
+
+func (s SQLStatement) format(includeArgs bool) string {
+	if len(s.Args) == 0 {
+		return s.Query
+	}
+
+	args := make([]string, len(s.Args))
+	for i, arg := range s.Args {
+		if includeArgs {
+			args[i] = fmt.Sprintf("$%d=%v", i+1, arg)
+		} else {
+			args[i] = fmt.Sprintf("$%d=***", i+1)
+		}
+	}
+	return fmt.Sprintf("%s [%s]", s.Query, strings.Join(args, ", "))
+}
+
+// SQLLogOptions configures logging of generated SQL statements
+type SQLLogOptions struct {
+	IncludeArgs bool                          // Log argument values (masked by default)
+	Logger      func(format string, a ...any) // Log function (default: logx.Debug)
+}
+
+// Log writes the statement using the configured logger
+func (o SQLLogOptions) Log(stmt SQLStatement) {
+	logger := o.Logger
+	if logger == nil {
+		logger = logx.Debug
+	}
+	logger("storex %s: %s", stmt.Operation, stmt.format(o.IncludeArgs))
+}
+
+// debugSQLPrefix marks interpolated SQL so it isn't mistaken for a runnable query
+const debugSQLPrefix = "/* debug only, not for execution */ "
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// Interpolate renders the statement with its arguments inlined as SQL
+// literals, for reading in logs while debugging. String values are quoted and
+// escaped, but the result is marked "debug only, not for execution" and must
+// never be run: always execute Query with Args.
+func (s SQLStatement) Interpolate() string {
+	query := placeholderPattern.ReplaceAllStringFunc(s.Query, func(placeholder string) string {
+		n, err := strconv.Atoi(placeholder[1:])
+		if err != nil || n < 1 || n > len(s.Args) {
+			return placeholder
+		}
+		return sqlLiteral(s.Args[n-1])
+	})
+	return debugSQLPrefix + query
+}
+
+// sqlLiteral formats a value as a PostgreSQL literal
+func sqlLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	case []byte:
+		return `'\x` + hex.EncodeToString(v) + `'`
+	case time.Time:
+		return quoteLiteral(v.Format(time.RFC3339Nano))
+	case *time.Time:
+		if v == nil {
+			return "NULL"
+		}
+		return quoteLiteral(v.Format(time.RFC3339Nano))
+	case string:
+		return quoteLiteral(v)
+	case fmt.Stringer:
+		return quoteLiteral(v.String())
+	default:
+		return quoteLiteral(fmt.Sprint(v))
+	}
+}
+
+// quoteLiteral single-quotes s, doubling embedded quotes
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+type queryDebugKey struct{}
+
+// WithQueryDebug enables debug SQL logging for operations run with the
+// returned context: providers log each statement with its arguments inlined
+// (see SQLStatement.Interpolate) at trace level. Scope it to a single request
+// or test, since arguments may contain sensitive data.
+//
+//	ctx = storex.WithQueryDebug(ctx)
+//	user, err := userRepo.FindOne(ctx, map[string]any{"email": email})
+//	// TRACE storex find_one: /* debug only, not for execution */ SELECT * FROM users WHERE email = 'a@b.com' LIMIT 1
+func WithQueryDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryDebugKey{}, true)
+}
+
+// QueryDebugEnabled reports whether ctx was created by WithQueryDebug
+func QueryDebugEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(queryDebugKey{}).(bool)
+	return enabled
+}
+
+// LogDebugSQL logs the interpolated statement at trace level when ctx has
+// query debugging enabled. Providers call it for every statement they run.
+func LogDebugSQL(ctx context.Context, stmt SQLStatement) {
+	if !QueryDebugEnabled(ctx) {
+		return
+	}
+	// Log the entry directly so trace-level argument formatting doesn't quote the SQL
+	logx.Log(logx.Entry{
+		Level:   logx.TraceLevel,
+		Message: fmt.Sprintf("storex %s: %s", stmt.Operation, stmt.Interpolate()),
+	})
+}
@@ -291,6 +291,18 @@
 //		}
 //	}
 //
+// Bulk operations are all-or-nothing. For imports where partial success is fine,
+// the BestEffortBulkOperator variants apply each item independently and report
+// per-item results instead of failing the whole batch:
+//
+//	result, err := bulkOp.BulkInsertBestEffort(ctx, users)
+//	if err != nil {
+//		// Context cancelled; result holds the items processed so far
+//	}
+//	for _, failed := range result.Failed() {
+//		log.Printf("row %d not imported: %v", failed.Index, failed.Error)
+//	}
+//
 // Transaction Support:
 //
 //	import (
@@ -334,6 +346,15 @@
 //		}
 //	}
 //
+// Under SERIALIZABLE isolation PostgreSQL aborts transactions that lose a conflict.
+// RetryTx runs the whole transaction again on serialization failures and deadlocks,
+// with backoff, so fn must be safe to repeat:
+//
+//	txManager := storexpostgres.NewPgTxManager(db)
+//	err := txManager.RetryTx(ctx, 5, func(txCtx context.Context) error {
+//		return transferFunds(txCtx, from, to, amount)
+//	})
+//
 // Query Builder:
 //
 //	import (
@@ -452,6 +473,154 @@
 //		// Continue with application logic...
 //	}
 //
+// Debugging Generated SQL:
+//
+// PostgreSQL repositories can return the statement an operation would run without
+// executing it, or log every executed statement. Argument values are masked unless
+// IncludeArgs is set, so logs don't leak sensitive data by default.
+//
+//	stmt, err := userRepo.ExplainCreate(newUser)
+//	fmt.Println(stmt)                  // INSERT INTO users (name, email) VALUES ($1, $2) RETURNING * [$1=***, $2=***]
+//	fmt.Println(stmt.StringWithArgs()) // ... [$1=John, $2=john@example.com]
+//
+//	userRepo := storexpostgres.NewPgRepository[User](db, "users", "id").
+//		WithSQLLogging(storex.SQLLogOptions{IncludeArgs: false})
+//
+// Context-scoped debugging logs the statements of selected operations with their
+// arguments inlined, at trace level. The output is marked "debug only, not for
+// execution"; never run it.
+//
+//	ctx = storex.WithQueryDebug(ctx)
+//	users, err := userRepo.Paginate(ctx, opts)
+//	// storex paginate: /* debug only, not for execution */ SELECT * FROM users WHERE status = 'active' ...
+//
+// IN Filters and Composite Keys:
+//
+// A filter value built with In matches any of its elements (IN in PostgreSQL,
+// $in in MongoDB); other values, plain slices included, match by equality.
+// PostgreSQL repositories keyed by several columns take IDs built with CompositeID.
+//
+//	opts := storex.DefaultPaginationOptions().WithFilter("status", storex.In("active", "trial"))
+//
+//	memberships := storexpostgres.NewPgRepository[Membership](db, "memberships", "").
+//		WithIDColumns("user_id", "group_id")
+//	membership, err := memberships.FindByID(ctx, storex.CompositeID(userID, groupID))
+//
+// In-Memory Store for Tests:
+//
+// storexinmemory.MemoryStore implements the same interfaces without a database. It
+// keys entities by the field tagged db:"id", json:"id" or bson:"_id", and applies
+// filters and OrderBy to struct fields by tag or name, including In filters.
+//
+//	users := storexinmemory.NewMemoryStore[User]()
+//	svc := NewUserService(users) // accepts storex.Repository[User]
+//
+// Advisory Locks:
+//
+// The PostgreSQL provider exposes session-level advisory locks for singleton jobs and
+// migrations. They are PostgreSQL-specific; string keys are hashed to the int64 key.
+//
+//	err := storexpostgres.WithAdvisoryLock(ctx, db, "nightly-report", func(ctx context.Context) error {
+//		return runNightlyReport(ctx)
+//	})
+//
+//	acquired, release, err := storexpostgres.TryAdvisoryLock(ctx, db, "migrations")
+//	if err == nil && acquired {
+//		defer release()
+//		// Only this instance runs migrations
+//	}
+//
+// Query Timeouts:
+//
+// Every PostgreSQL operation runs on the caller's context, so cancelling it aborts the
+// query. WithQueryTimeout additionally bounds each operation; expired operations fail
+// with ErrQueryTimeout.
+//
+//	userRepo := storexpostgres.NewPgRepository[User](db, "users", "id").
+//		WithQueryTimeout(2 * time.Second)
+//
+//	if _, err := userRepo.FindByID(ctx, id); storex.IsQueryTimeout(err) {
+//		// Shed load or retry later
+//	}
+//
+// Pagination Without Counting:
+//
+// Paginate counts every matching record to report Total and Pages, which can
+// cost as much as the page itself on large tables. With SkipCount it fetches one
+// row beyond the page instead; HasNext still works, Total and Pages stay zero.
+//
+//	opts := storex.DefaultPaginationOptions().WithoutCount()
+//	result, err := userRepo.Paginate(ctx, opts)
+//	if result.HasNext() {
+//		// Offer a "next" link
+//	}
+//
+// Sorting:
+//
+// Paginate orders by PaginationOptions.Sort, or OrderBy when Sort is empty, and
+// always finishes with the ID columns so offset pages are deterministic. PostgreSQL
+// sort columns must be db tags of the entity or plain column names, optionally
+// table-qualified; expressions such as "lower(name)" fail with ErrInvalidQuery.
+//
+//	opts := storex.DefaultPaginationOptions().
+//		WithSort("created_at", true).
+//		WithSort("name", false)
+//	// ORDER BY created_at DESC, name ASC, id ASC
+//
+// Validation Before Writes:
+//
+// WithValidation checks validate tags (required, min, max, len, oneof, omitempty)
+// before Create and Update, so obviously bad data fails without a round-trip:
+//
+//	type User struct {
+//		ID   string `db:"id"`
+//		Name string `db:"name" validate:"required,max=100"`
+//		Role string `db:"role" validate:"oneof=admin member"`
+//	}
+//
+//	userRepo := storexpostgres.NewPgRepository[User](db, "users", "id").WithValidation()
+//
+//	_, err := userRepo.Create(ctx, User{Role: "root"})
+//	if storex.IsValidationFailed(err) {
+//		// err's "violations" detail lists each storex.FieldViolation
+//	}
+//
+// The in-memory store takes storexinmemory.WithValidation[User](), and
+// storex.ValidateStruct can be called directly.
+//
+// Embedded Structs:
+//
+// PostgreSQL repositories flatten untagged embedded structs, so shared columns can
+// live in a common base type. Embedding with a db tag keeps the field as a single column.
+//
+//	type Base struct {
+//		ID        string    `db:"id"`
+//		CreatedAt time.Time `db:"created_at"`
+//	}
+//
+//	type User struct {
+//		Base
+//		Name string `db:"name"`
+//	}
+//
+// Repositories From Struct Tags:
+//
+// storexpostgres.NewRepository derives the table and primary key from the entity,
+// so the common case needs no configuration. The table comes from a TableName
+// method, a table tag or the pluralized snake_case type name (see TableName); the
+// key from fields tagged storex:"pk", defaulting to "id". Options override both.
+// Besides the Repository methods it offers Upsert and Count.
+//
+//	type Product struct {
+//		ID    int     `db:"id" storex:"pk"`
+//		Name  string  `db:"name"`
+//		Price float64 `db:"price"`
+//	}
+//
+//	products := storexpostgres.NewRepository[Product](db) // table "products"
+//	saved, err := products.Upsert(ctx, Product{ID: 7, Name: "Lamp", Price: 30})
+//	lamps, err := products.Count(ctx, map[string]any{"name": "Lamp"})
+//
 // Error Handling:
 //
 //	import (
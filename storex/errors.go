@@ -18,12 +18,15 @@ var (
 	ErrTxRollbackFailed = StoreErrors.Register("TX_ROLLBACK_FAILED", errx.TypeInternal, 500, "Failed to rollback transaction")
 	ErrBulkOpFailed     = StoreErrors.Register("BULK_OPERATION_FAILED", errx.TypeInternal, 500, "Bulk operation failed")
 	ErrSearchFailed     = StoreErrors.Register("SEARCH_FAILED", errx.TypeInternal, 500, "Search operation failed")
+	ErrValidationFailed = StoreErrors.Register("VALIDATION_FAILED", errx.TypeValidation, 400, "Validation failed")
 
 	// SQL-specific errors
 	ErrSQLScanFailed  = StoreErrors.Register("SQL_SCAN_FAILED", errx.TypeInternal, 500, "Failed to scan SQL results")
 	ErrSQLQueryFailed = StoreErrors.Register("SQL_QUERY_FAILED", errx.TypeInternal, 500, "SQL query execution failed")
 	ErrSQLCountFailed = StoreErrors.Register("SQL_COUNT_FAILED", errx.TypeInternal, 500, "Failed to count SQL records")
 	ErrSQLExecFailed  = StoreErrors.Register("SQL_EXEC_FAILED", errx.TypeInternal, 500, "SQL exec operation failed")
+	ErrLockFailed     = StoreErrors.Register("LOCK_FAILED", errx.TypeInternal, 500, "Advisory lock operation failed")
+	ErrQueryTimeout   = StoreErrors.Register("QUERY_TIMEOUT", errx.TypeTimeout, 504, "Query timed out")
 
 	// MongoDB-specific errors
 	ErrMongoFindFailed   = StoreErrors.Register("MONGO_FIND_FAILED", errx.TypeInternal, 500, "MongoDB find operation failed")
@@ -51,3 +54,11 @@ func IsInvalidQuery(err error) bool {
 func IsInvalidID(err error) bool {
 	return errx.IsCode(err, ErrInvalidID)
 }
+
+func IsQueryTimeout(err error) bool {
+	return errx.IsCode(err, ErrQueryTimeout)
+}
+
+func IsValidationFailed(err error) bool {
+	return errx.IsCode(err, ErrValidationFailed)
+}
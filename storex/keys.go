@@ -0,0 +1,63 @@
+package storex
+
+import (
+	"strings"
+)
+
+// CompositeIDSeparator separates the parts of a composite ID
+const CompositeIDSeparator = "|"
+
+// CompositeID builds the string ID of a row keyed by several columns, in the
+// order the repository's ID columns were declared. Separators and backslashes
+// inside parts are escaped, so any values round-trip through SplitCompositeID.
+func CompositeID(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		escaped[i] = strings.ReplaceAll(part, CompositeIDSeparator, `\`+CompositeIDSeparator)
+	}
+	return strings.Join(escaped, CompositeIDSeparator)
+}
+
+// SplitCompositeID splits an ID built by CompositeID back into its parts
+func SplitCompositeID(id string) []string {
+	parts := []string{}
+	var current strings.Builder
+	for i := 0; i < len(id); i++ {
+		switch {
+		case id[i] == '\\' && i+1 < len(id):
+			i++
+			current.WriteByte(id[i])
+		case strings.HasPrefix(id[i:], CompositeIDSeparator):
+			parts = append(parts, current.String())
+			current.Reset()
+			i += len(CompositeIDSeparator) - 1
+		default:
+			current.WriteByte(id[i])
+		}
+	}
+	return append(parts, current.String())
+}
+
+// InValues is a filter value that matches any of its elements, built with In
+type InValues []any
+
+// In returns a filter value matching any of values, as IN in PostgreSQL and
+// $in in MongoDB. No values match nothing. Any other filter value, slices
+// included, is compared for equality.
+//
+//	opts := storex.DefaultPaginationOptions().WithFilter("status", storex.In("active", "trial"))
+func In[T any](values ...T) InValues {
+	in := make(InValues, len(values))
+	for i, value := range values {
+		in[i] = value
+	}
+	return in
+}
+
+// FilterValues returns the elements of a filter value built with In. It
+// reports false for any other value, which is matched as a whole.
+func FilterValues(value any) ([]any, bool) {
+	in, ok := value.(InValues)
+	return in, ok
+}
@@ -0,0 +1,72 @@
+package storex
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// TableNamer is implemented by entities that name their table or collection
+type TableNamer interface {
+	TableName() string
+}
+
+// TableName returns the table or collection of entity type T: the result of
+// its TableName method (on T or *T), else the value of a `table:"..."` tag on
+// any of its fields, conventionally a blank one, else the type name in
+// snake_case with an "s" appended. A pointer T is named like the type it
+// points to.
+//
+//	type OrderLine struct {
+//		_  struct{} `table:"order_items"`
+//		ID string   `db:"id"`
+//	}
+//
+//	storex.TableName[OrderLine]() // "order_items" ("order_lines" without the tag)
+func TableName[T any]() string {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		// Call through a new value, as the zero T is a nil pointer
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if namer, ok := reflect.New(t).Interface().(TableNamer); ok {
+			return namer.TableName()
+		}
+	} else {
+		var zero T
+		if namer, ok := any(zero).(TableNamer); ok {
+			return namer.TableName()
+		}
+		if namer, ok := any(&zero).(TableNamer); ok {
+			return namer.TableName()
+		}
+	}
+
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if name := t.Field(i).Tag.Get("table"); name != "" {
+				return name
+			}
+		}
+	}
+	return snakeCase(t.Name()) + "s"
+}
+
+// snakeCase converts a Go identifier such as "HTTPRequestLog" to "http_request_log"
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+			if startsWord {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package storex_test
+
+import (
+	"testing"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+type HTTPRequestLog struct {
+	ID string `db:"id"`
+}
+
+type orderLine struct {
+	_  struct{} `table:"order_items"`
+	ID string   `db:"id"`
+}
+
+type legacyUser struct {
+	ID string `db:"id"`
+}
+
+func (legacyUser) TableName() string { return "tbl_users" }
+
+type auditEntry struct {
+	ID string `db:"id"`
+}
+
+func (*auditEntry) TableName() string { return "audit_log" }
+
+func TestTableName(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "snake_case plural of the type", got: storex.TableName[HTTPRequestLog](), want: "http_request_logs"},
+		{name: "table tag", got: storex.TableName[orderLine](), want: "order_items"},
+		{name: "TableName method", got: storex.TableName[legacyUser](), want: "tbl_users"},
+		{name: "TableName method on the pointer", got: storex.TableName[auditEntry](), want: "audit_log"},
+		{name: "pointer type", got: storex.TableName[*legacyUser](), want: "tbl_users"},
+		{name: "pointer type with a pointer method", got: storex.TableName[*auditEntry](), want: "audit_log"},
+		{name: "pointer type without a method", got: storex.TableName[*orderLine](), want: "order_items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("TableName() = %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package storexinmemory
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldTags are checked in order when resolving a filter key or ID field
+var fieldTags = []string{"db", "json", "bson"}
+
+// structValue dereferences pointers down to the struct an entity holds
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid() && v.Kind() == reflect.Struct
+}
+
+// lookupField finds the field a filter key or OrderBy names: by db, json or
+// bson tag first, then by Go field name ignoring case. Embedded structs are
+// searched like the SQL providers flatten them.
+func lookupField(v reflect.Value, name string) (reflect.Value, bool) {
+	v, ok := structValue(v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		for _, tag := range fieldTags {
+			tagName, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if tagName == name {
+				return v.Field(i), true
+			}
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && strings.EqualFold(field.Name, name) {
+			return v.Field(i), true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.IsExported() {
+			if found, ok := lookupField(v.Field(i), name); ok {
+				return found, true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// matchesFilter reports whether every filter key matches the item's field.
+// storex.In filter values match any of their elements, like IN in the SQL
+// providers. Keys naming no field never match.
+func matchesFilter[T any](item T, filter map[string]any) bool {
+	v := reflect.ValueOf(item)
+	for key, want := range filter {
+		field, ok := lookupField(v, key)
+		if !ok {
+			return false
+		}
+
+		if in, ok := storex.FilterValues(want); ok {
+			matched := false
+			for _, candidate := range in {
+				if valuesEqual(field, candidate) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+			continue
+		}
+
+		if !valuesEqual(field, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares a field with a filter value, converting between
+// numeric types so an int filter matches an int64 column
+func valuesEqual(field reflect.Value, want any) bool {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return want == nil
+		}
+		field = field.Elem()
+	}
+	if want == nil {
+		return false
+	}
+
+	wantValue := reflect.ValueOf(want)
+	for wantValue.Kind() == reflect.Ptr {
+		if wantValue.IsNil() {
+			return false
+		}
+		wantValue = wantValue.Elem()
+	}
+
+	if cmp, ok := compareFields(field, wantValue); ok {
+		return cmp == 0
+	}
+	return reflect.DeepEqual(field.Interface(), wantValue.Interface())
+}
+
+// compareFields orders two scalar values, reporting false when they can't be compared
+func compareFields(a, b reflect.Value) (int, bool) {
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+
+	if af, ok := numericValue(a); ok {
+		if bf, ok := numericValue(b); ok {
+			switch {
+			case af < bf:
+				return -1, true
+			case af > bf:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		switch {
+		case a.Bool() == b.Bool():
+			return 0, true
+		case !a.Bool():
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// compareByField orders two entities by the named field. Nil values sort first.
+func compareByField[T any](a, b T, name string) int {
+	af, aok := lookupField(reflect.ValueOf(a), name)
+	bf, bok := lookupField(reflect.ValueOf(b), name)
+	if !aok || !bok {
+		return 0
+	}
+
+	af, aok = derefField(af)
+	bf, bok = derefField(bf)
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return -1
+	case !bok:
+		return 1
+	}
+
+	if cmp, ok := compareFields(af, bf); ok {
+		return cmp
+	}
+	return strings.Compare(fmt.Sprint(af.Interface()), fmt.Sprint(bf.Interface()))
+}
+
+func derefField(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// detectIDField returns the index path of T's ID field: the field tagged
+// db:"id", json:"id" or bson:"_id", or else a field named ID
+func detectIDField[T any]() []int {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, want := range []struct{ tag, name string }{{"db", "id"}, {"json", "id"}, {"bson", "_id"}} {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tagName, _, _ := strings.Cut(field.Tag.Get(want.tag), ",")
+			if field.IsExported() && tagName == want.name {
+				return field.Index
+			}
+		}
+	}
+
+	if field, ok := t.FieldByName("ID"); ok && field.IsExported() {
+		return field.Index
+	}
+	return nil
+}
+
+// idFieldExtractor reads the detected ID field as a string; zero IDs read as ""
+func idFieldExtractor[T any](index []int) func(T) string {
+	return func(item T) string {
+		v, ok := structValue(reflect.ValueOf(item))
+		if !ok {
+			return ""
+		}
+		field, err := v.FieldByIndexErr(index)
+		if err != nil || field.IsZero() {
+			return ""
+		}
+		if field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+// setIDField writes a generated ID back into a string ID field, so callers
+// get the stored entity's ID from Create
+func setIDField[T any](item *T, index []int, id string) {
+	v := reflect.ValueOf(item).Elem()
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	field, err := v.FieldByIndexErr(index)
+	if err != nil || !field.CanSet() || field.Kind() != reflect.String {
+		return
+	}
+	field.SetString(id)
+}
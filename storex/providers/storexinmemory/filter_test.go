@@ -0,0 +1,195 @@
+package storexinmemory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+type Audit struct {
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type item struct {
+	Audit
+	ID       string  `db:"id"`
+	Name     string  `json:"name"`
+	Kind     string  `db:"kind" json:"type"`
+	Qty      int     `db:"qty"`
+	Price    float64 `json:"price"`
+	Score    *int    `json:"score"`
+	Archived bool
+}
+
+func TestMatchesFilter(t *testing.T) {
+	score := 7
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	it := item{
+		Audit: Audit{CreatedBy: "ana", CreatedAt: created},
+		ID:    "1", Name: "bolt", Kind: "hardware", Qty: 3, Price: 2.5, Score: &score,
+	}
+
+	tests := []struct {
+		name   string
+		filter map[string]any
+		want   bool
+	}{
+		{"json tag", map[string]any{"name": "bolt"}, true},
+		{"db tag", map[string]any{"qty": 3}, true},
+		{"db tag before json tag", map[string]any{"kind": "hardware"}, true},
+		{"second tag", map[string]any{"type": "hardware"}, true},
+		{"field name ignoring case", map[string]any{"archived": false}, true},
+		{"field name", map[string]any{"Name": "bolt"}, true},
+		{"wrong value", map[string]any{"name": "nut"}, false},
+		{"unknown key", map[string]any{"color": "red"}, false},
+		{"all keys match", map[string]any{"name": "bolt", "qty": 3}, true},
+		{"one key differs", map[string]any{"name": "bolt", "qty": 4}, false},
+
+		{"int64 against int", map[string]any{"qty": int64(3)}, true},
+		{"float against int", map[string]any{"qty": 3.0}, true},
+		{"fractional float against int", map[string]any{"qty": 3.5}, false},
+		{"int against float", map[string]any{"price": 2}, false},
+		{"float against float", map[string]any{"price": 2.5}, true},
+		{"uint against int", map[string]any{"qty": uint(3)}, true},
+		{"pointer field", map[string]any{"score": 7}, true},
+		{"pointer filter", map[string]any{"qty": &it.Qty}, true},
+		{"string against int", map[string]any{"qty": "3"}, false},
+
+		{"in matches one", map[string]any{"qty": storex.In(1, 2, 3)}, true},
+		{"in matches none", map[string]any{"qty": storex.In(1, 2)}, false},
+		{"in with strings", map[string]any{"type": storex.In("tools", "hardware")}, true},
+		{"in excludes others", map[string]any{"type": storex.In("tools", "garden")}, false},
+		{"empty in", map[string]any{"name": storex.In[string]()}, false},
+		{"in mixed numbers", map[string]any{"price": storex.In(1, 2.5)}, true},
+		{"slice is compared whole", map[string]any{"name": []string{"bolt"}}, false},
+
+		{"embedded db tag", map[string]any{"created_by": "ana"}, true},
+		{"embedded json tag", map[string]any{"created_at": created}, true},
+		{"embedded field name", map[string]any{"createdby": "ana"}, true},
+		{"embedded wrong value", map[string]any{"created_by": "luis"}, false},
+		{"embedded time differs", map[string]any{"created_at": created.Add(time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFilter(it, tt.filter); got != tt.want {
+				t.Errorf("matchesFilter(%v) = %v, want %v", tt.filter, got, tt.want)
+			}
+			if got := matchesFilter(&it, tt.filter); got != tt.want {
+				t.Errorf("matchesFilter(&item, %v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesFilterNilValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		item   any
+		filter map[string]any
+		want   bool
+	}{
+		{"nil pointer field matches nil", item{}, map[string]any{"score": nil}, true},
+		{"nil pointer field against value", item{}, map[string]any{"score": 1}, false},
+		{"set field against nil", item{Name: "bolt"}, map[string]any{"name": nil}, false},
+		{"nil entity", (*item)(nil), map[string]any{"name": "bolt"}, false},
+		{"non-struct entity", "bolt", map[string]any{"name": "bolt"}, false},
+		{"empty filter", "bolt", map[string]any{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFilter(tt.item, tt.filter); got != tt.want {
+				t.Errorf("matchesFilter(%v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareByField(t *testing.T) {
+	low, high := 1, 2
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		a, b  item
+		field string
+		want  int
+	}{
+		{"strings", item{Name: "a"}, item{Name: "b"}, "name", -1},
+		{"equal strings", item{Name: "a"}, item{Name: "a"}, "name", 0},
+		{"ints numerically", item{Qty: 10}, item{Qty: 9}, "qty", 1},
+		{"floats", item{Price: 1.5}, item{Price: 1.25}, "price", 1},
+		{"bools", item{Archived: false}, item{Archived: true}, "archived", -1},
+		{"pointers", item{Score: &high}, item{Score: &low}, "score", 1},
+		{"nil sorts first", item{}, item{Score: &low}, "score", -1},
+		{"both nil", item{}, item{}, "score", 0},
+		{"embedded time", item{Audit: Audit{CreatedAt: early.Add(time.Hour)}}, item{Audit: Audit{CreatedAt: early}}, "created_at", 1},
+		{"unknown field", item{Name: "a"}, item{Name: "b"}, "color", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareByField(tt.a, tt.b, tt.field); got != tt.want {
+				t.Errorf("compareByField(%s) = %d, want %d", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectIDField(t *testing.T) {
+	type dbTagged struct {
+		Key string `db:"id"`
+		ID  string
+	}
+	type jsonTagged struct {
+		Key string `json:"id,omitempty"`
+	}
+	type bsonTagged struct {
+		Key string `bson:"_id"`
+	}
+	type named struct {
+		ID int
+	}
+	type embedded struct {
+		Audit
+		named
+	}
+	type noID struct {
+		Name string
+	}
+	type unexported struct {
+		id string `db:"id"`
+	}
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"db tag before field name", detectIDField[dbTagged](), []int{0}},
+		{"json tag with options", detectIDField[jsonTagged](), []int{0}},
+		{"bson tag", detectIDField[bsonTagged](), []int{0}},
+		{"field named ID", detectIDField[named](), []int{0}},
+		{"pointer type", detectIDField[*named](), []int{0}},
+		{"promoted ID", detectIDField[embedded](), []int{1, 0}},
+		{"no ID field", detectIDField[noID](), nil},
+		{"unexported ID field", detectIDField[unexported](), nil},
+		{"not a struct", detectIDField[string](), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("index = %v, want %v", tt.got, tt.want)
+			}
+			for i := range tt.got {
+				if tt.got[i] != tt.want[i] {
+					t.Fatalf("index = %v, want %v", tt.got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package storexinmemory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,6 +17,8 @@ type MemoryStore[T any] struct {
 	idExtractor       func(T) string
 	changeSubscribers []chan storex.ChangeEvent[T]
 	idGenerator       func() string
+	idField           []int // Index of the ID field detected from struct tags
+	validate          bool
 }
 
 // MemoryStoreOption defines a functional option for configuring MemoryStore
@@ -35,7 +38,18 @@ func WithIDGenerator[T any](generator func() string) MemoryStoreOption[T] {
 	}
 }
 
-// NewMemoryStore creates a new in-memory store
+// WithValidation checks the validate tags of entities with storex.ValidateStruct
+// before Create and Update, so tests catch the same bad data as the database stores
+func WithValidation[T any]() MemoryStoreOption[T] {
+	return func(ms *MemoryStore[T]) {
+		ms.validate = true
+	}
+}
+
+// NewMemoryStore creates a new in-memory store. Entities are keyed by the field
+// tagged db:"id", json:"id" or bson:"_id" (or named ID) unless WithIDExtractor
+// is given. Filters match struct fields by tag or name, with the same equality
+// and IN semantics as the database providers.
 func NewMemoryStore[T any](options ...MemoryStoreOption[T]) *MemoryStore[T] {
 	ms := &MemoryStore[T]{
 		data: make(map[string]T),
@@ -43,6 +57,10 @@ func NewMemoryStore[T any](options ...MemoryStoreOption[T]) *MemoryStore[T] {
 		idGenerator: func() string {
 			return fmt.Sprintf("%d", time.Now().UnixNano())
 		},
+		idField: detectIDField[T](),
+	}
+	if ms.idField != nil {
+		ms.idExtractor = idFieldExtractor[T](ms.idField)
 	}
 
 	// Apply options
@@ -55,6 +73,13 @@ func NewMemoryStore[T any](options ...MemoryStoreOption[T]) *MemoryStore[T] {
 
 // Create adds a new entity to the store
 func (ms *MemoryStore[T]) Create(ctx context.Context, item T) (T, error) {
+	if ms.validate {
+		if err := storex.ValidateStruct(item); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
@@ -65,10 +90,11 @@ func (ms *MemoryStore[T]) Create(ctx context.Context, item T) (T, error) {
 	}
 
 	if id == "" {
-		// This requires the ability to set the ID back to the item,
-		// which may not be possible with a generic T
-		// We'd need a more specific approach for this
 		id = ms.idGenerator()
+		// String ID fields get the generated ID; others are keyed without it
+		if ms.idField != nil {
+			setIDField(&item, ms.idField, id)
+		}
 	}
 
 	// Check if record already exists
@@ -103,8 +129,9 @@ func (ms *MemoryStore[T]) FindOne(ctx context.Context, filter map[string]any) (T
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 
-	for _, item := range ms.data {
-		if ms.matchesFilter(item, filter) {
+	// Lowest ID first, so repeated calls return the same match
+	for _, item := range ms.sortedItems(nil) {
+		if matchesFilter(item, filter) {
 			return item, nil
 		}
 	}
@@ -113,17 +140,38 @@ func (ms *MemoryStore[T]) FindOne(ctx context.Context, filter map[string]any) (T
 	return zero, storex.StoreErrors.New(storex.ErrRecordNotFound).WithDetail("filter", filter)
 }
 
-// matchesFilter checks if an item matches the given filter
-func (ms *MemoryStore[T]) matchesFilter(item T, filter map[string]any) bool {
-	// This is a simplified implementation that would need to be expanded
-	// based on the actual structure of T and how filters should work
-	// In a real implementation, you'd use reflection or another mechanism
-	// to check if the item's fields match the filter criteria
-	return true
+// sortedItems returns all items ordered by the sort fields, then by ID
+func (ms *MemoryStore[T]) sortedItems(order []storex.Sort) []T {
+	ids := make([]string, 0, len(ms.data))
+	for id := range ms.data {
+		ids = append(ids, id)
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		for _, s := range order {
+			if cmp := compareByField(ms.data[ids[i]], ms.data[ids[j]], s.Field); cmp != 0 {
+				return (cmp < 0) != s.Desc
+			}
+		}
+		return ids[i] < ids[j]
+	})
+
+	items := make([]T, len(ids))
+	for i, id := range ids {
+		items[i] = ms.data[id]
+	}
+	return items
 }
 
 // Update modifies an existing entity
 func (ms *MemoryStore[T]) Update(ctx context.Context, id string, item T) (T, error) {
+	if ms.validate {
+		if err := storex.ValidateStruct(item); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
@@ -164,23 +212,24 @@ func (ms *MemoryStore[T]) Paginate(ctx context.Context, opts storex.PaginationOp
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 
-	// Filter items
+	// Sort, then filter, so pages are stable across calls
 	var filteredItems []T
-	for _, item := range ms.data {
-		if ms.matchesFilter(item, opts.Filters) {
+	for _, item := range ms.sortedItems(opts.SortOrder()) {
+		if matchesFilter(item, opts.Filters) {
 			filteredItems = append(filteredItems, item)
 		}
 	}
 
-	// Sort items (simplified - would need to be expanded for real use)
-	// In a real implementation, you'd use reflection or another mechanism
-	// to sort based on the orderBy field
-
 	// Apply pagination
 	total := len(filteredItems)
 	start := (opts.Page - 1) * opts.PageSize
 	end := start + opts.PageSize
 
+	if opts.SkipCount {
+		rows := filteredItems[min(start, total):min(start+opts.FetchLimit(), total)]
+		return storex.NewUncountedPaginated(rows, opts), nil
+	}
+
 	if start >= total {
 		return storex.NewPaginated([]T{}, opts.Page, opts.PageSize, total), nil
 	}
@@ -275,6 +324,67 @@ func (ms *MemoryStore[T]) BulkDelete(ctx context.Context, ids []string) error {
 	return nil
 }
 
+// BulkInsertBestEffort inserts each entity independently
+func (ms *MemoryStore[T]) BulkInsertBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		_, err := ms.Create(ctx, item)
+		result.Add(i, ms.extractID(item), err)
+	}
+
+	return result, nil
+}
+
+// BulkUpdateBestEffort updates each entity independently
+func (ms *MemoryStore[T]) BulkUpdateBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		id := ms.extractID(item)
+		if id == "" {
+			result.Add(i, "", storex.StoreErrors.New(storex.ErrInvalidID).WithDetail("reason", "item has no ID"))
+			continue
+		}
+
+		_, err := ms.Update(ctx, id, item)
+		result.Add(i, id, err)
+	}
+
+	return result, nil
+}
+
+// BulkDeleteBestEffort deletes each ID independently
+func (ms *MemoryStore[T]) BulkDeleteBestEffort(ctx context.Context, ids []string) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(ids))
+
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Add(i, id, ms.Delete(ctx, id))
+	}
+
+	return result, nil
+}
+
+// extractID returns the entity ID using the configured extractor, or ""
+func (ms *MemoryStore[T]) extractID(item T) string {
+	if ms.idExtractor == nil {
+		return ""
+	}
+	return ms.idExtractor(item)
+}
+
 // WithTransaction executes operations within a transaction
 // For the in-memory implementation, we just run the function directly
 // since there's no actual transaction support needed
@@ -372,7 +482,7 @@ func (ms *MemoryStore[T]) Count(ctx context.Context, filter map[string]any) (int
 
 	count := 0
 	for _, item := range ms.data {
-		if ms.matchesFilter(item, filter) {
+		if matchesFilter(item, filter) {
 			count++
 		}
 	}
@@ -0,0 +1,143 @@
+package storexinmemory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+func seedItems(t *testing.T, store *MemoryStore[item], items ...item) {
+	t.Helper()
+	for _, it := range items {
+		if _, err := store.Create(context.Background(), it); err != nil {
+			t.Fatalf("Create(%s): %v", it.ID, err)
+		}
+	}
+}
+
+func ids(items []item) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = it.ID
+	}
+	return out
+}
+
+func TestPaginateSortsByEveryKey(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[item]()
+	seedItems(t, store,
+		item{ID: "1", Kind: "tools", Qty: 5, Name: "saw"},
+		item{ID: "2", Kind: "hardware", Qty: 5, Name: "nut"},
+		item{ID: "3", Kind: "tools", Qty: 9, Name: "drill"},
+		item{ID: "4", Kind: "hardware", Qty: 10, Name: "bolt"},
+		item{ID: "5", Kind: "tools", Qty: 5, Name: "axe"},
+	)
+
+	tests := []struct {
+		name string
+		opts storex.PaginationOptions
+		want []string
+	}{
+		{"no order sorts by ID", storex.DefaultPaginationOptions(), []string{"1", "2", "3", "4", "5"}},
+		{"one key, ties by ID",
+			storex.DefaultPaginationOptions().WithSort("qty", false),
+			[]string{"1", "2", "5", "3", "4"}},
+		{"numbers not text",
+			storex.DefaultPaginationOptions().WithSort("qty", true),
+			[]string{"4", "3", "1", "2", "5"}},
+		{"two keys",
+			storex.DefaultPaginationOptions().WithSort("kind", false).WithSort("qty", true),
+			[]string{"4", "2", "3", "1", "5"}},
+		{"three keys",
+			storex.DefaultPaginationOptions().WithSort("type", true).WithSort("qty", false).WithSort("name", false),
+			[]string{"5", "1", "3", "2", "4"}},
+		{"filtered",
+			storex.DefaultPaginationOptions().WithFilter("kind", storex.In("tools")).WithSort("name", false),
+			[]string{"5", "3", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.Paginate(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if got := ids(page.Data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindOneAndCountUseFilters(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[item]()
+	seedItems(t, store,
+		item{ID: "1", Kind: "tools", Qty: 5},
+		item{ID: "2", Kind: "hardware", Qty: 5},
+		item{ID: "3", Kind: "tools", Qty: 9},
+	)
+
+	found, err := store.FindOne(ctx, map[string]any{"kind": "tools", "qty": 9.0})
+	if err != nil || found.ID != "3" {
+		t.Errorf("FindOne = %q, %v, want 3", found.ID, err)
+	}
+
+	if _, err := store.FindOne(ctx, map[string]any{"kind": "garden"}); !errx.IsCode(err, storex.ErrRecordNotFound) {
+		t.Errorf("FindOne(no match) error = %v, want ErrRecordNotFound", err)
+	}
+
+	count, err := store.Count(ctx, map[string]any{"qty": storex.In(int64(5), int64(7))})
+	if err != nil || count != 2 {
+		t.Errorf("Count = %d, %v, want 2", count, err)
+	}
+}
+
+func TestStoreWithoutIDField(t *testing.T) {
+	type note struct {
+		Text string `json:"text"`
+	}
+
+	ctx := context.Background()
+	store := NewMemoryStore[note]()
+
+	// Create still works, keyed by a generated ID
+	if _, err := store.Create(ctx, note{Text: "a"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if count, _ := store.Count(ctx, map[string]any{"text": "a"}); count != 1 {
+		t.Errorf("Count = %d, want 1", count)
+	}
+
+	if err := store.BulkUpdate(ctx, []note{{Text: "b"}}); !errx.IsCode(err, storex.ErrBulkOpFailed) {
+		t.Errorf("BulkUpdate error = %v, want ErrBulkOpFailed", err)
+	}
+
+	result, err := store.BulkUpdateBestEffort(ctx, []note{{Text: "b"}})
+	if err != nil {
+		t.Fatalf("BulkUpdateBestEffort: %v", err)
+	}
+	if failed := result.Failed(); result.TotalFailed != 1 || len(failed) != 1 || !errx.IsCode(failed[0].Error, storex.ErrInvalidID) {
+		t.Errorf("BulkUpdateBestEffort = %+v, want one ErrInvalidID failure", result)
+	}
+}
+
+func TestStoreOfNonStructs(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore[string](WithIDExtractor(func(s string) string { return s }))
+	if _, err := store.Create(ctx, "a"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	page, err := store.Paginate(ctx, storex.DefaultPaginationOptions().WithSort("name", false))
+	if err != nil || len(page.Data) != 1 {
+		t.Errorf("Paginate = %v, %v, want one item", page.Data, err)
+	}
+	if count, _ := store.Count(ctx, map[string]any{"name": "a"}); count != 0 {
+		t.Errorf("Count = %d, want 0 for a filter on a non-struct", count)
+	}
+}
@@ -18,6 +18,7 @@ import (
 type MongoRepository[T any] struct {
 	collection *mongo.Collection
 	idField    string
+	validate   bool
 }
 
 // NewMongoRepository creates a new MongoDB repository
@@ -31,9 +32,22 @@ func NewMongoRepository[T any](collection *mongo.Collection, idField string) *Mo
 	}
 }
 
+// WithValidation checks the validate tags of entities with storex.ValidateStruct
+// before Create and Update, failing with storex.ErrValidationFailed without a
+// database round-trip
+func (r *MongoRepository[T]) WithValidation() *MongoRepository[T] {
+	r.validate = true
+	return r
+}
+
 // Create adds a new entity to the database
 func (r *MongoRepository[T]) Create(ctx context.Context, item T) (T, error) {
 	var empty T
+	if r.validate {
+		if err := storex.ValidateStruct(item); err != nil {
+			return empty, err
+		}
+	}
 
 	// Handle ID generation if using ObjectID
 	v := reflect.ValueOf(item)
@@ -109,11 +123,7 @@ func (r *MongoRepository[T]) FindOne(ctx context.Context, filter map[string]any)
 		return empty, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No filter provided")
 	}
 
-	// Convert map to BSON Document
-	bsonFilter := bson.M{}
-	for k, v := range filter {
-		bsonFilter[k] = v
-	}
+	bsonFilter := toBSONFilter(filter)
 
 	err := r.collection.FindOne(ctx, bsonFilter).Decode(&result)
 	if err != nil {
@@ -129,6 +139,11 @@ func (r *MongoRepository[T]) FindOne(ctx context.Context, filter map[string]any)
 // Update modifies an existing entity
 func (r *MongoRepository[T]) Update(ctx context.Context, id string, item T) (T, error) {
 	var empty T
+	if r.validate {
+		if err := storex.ValidateStruct(item); err != nil {
+			return empty, err
+		}
+	}
 
 	// Prepare filter based on ID type
 	var filter bson.M
@@ -187,27 +202,26 @@ func (r *MongoRepository[T]) Delete(ctx context.Context, id string) error {
 // Paginate retrieves entities with pagination
 func (r *MongoRepository[T]) Paginate(ctx context.Context, opts storex.PaginationOptions) (storex.Paginated[T], error) {
 	// Convert filters to BSON
-	filter := bson.M{}
-	for k, v := range opts.Filters {
-		filter[k] = v
-	}
+	filter := toBSONFilter(opts.Filters)
 
 	// Build options
 	findOptions := options.Find()
 
-	// Sorting
-	if opts.OrderBy != "" {
+	// Sorting, with the ID as tiebreaker so pages are stable
+	sortSpec := bson.D{}
+	for _, s := range opts.SortOrder(r.idField) {
 		sortDir := 1
-		if opts.Desc {
+		if s.Desc {
 			sortDir = -1
 		}
-		findOptions.SetSort(bson.D{{Key: opts.OrderBy, Value: sortDir}})
+		sortSpec = append(sortSpec, bson.E{Key: s.Field, Value: sortDir})
 	}
+	findOptions.SetSort(sortSpec)
 
 	// Pagination
 	offset := (opts.Page - 1) * opts.PageSize
 	findOptions.SetSkip(int64(offset))
-	findOptions.SetLimit(int64(opts.PageSize))
+	findOptions.SetLimit(int64(opts.FetchLimit()))
 
 	// Field selection if specified
 	if len(opts.Fields) > 0 {
@@ -231,6 +245,10 @@ func (r *MongoRepository[T]) Paginate(ctx context.Context, opts storex.Paginatio
 		return storex.Paginated[T]{}, storex.StoreErrors.NewWithCause(storex.ErrSQLQueryFailed, err)
 	}
 
+	if opts.SkipCount {
+		return storex.NewUncountedPaginated(items, opts), nil
+	}
+
 	// Count total documents
 	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -357,6 +375,93 @@ func (b *MongoBulkOperator[T]) BulkDelete(ctx context.Context, ids []string) err
 	return nil
 }
 
+// BulkInsertBestEffort inserts each entity independently, outside a transaction
+func (b *MongoBulkOperator[T]) BulkInsertBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		created, err := b.Create(ctx, item)
+		if err != nil {
+			result.Add(i, b.entityID(item), err)
+			continue
+		}
+		result.Add(i, b.entityID(created), nil)
+	}
+
+	return result, nil
+}
+
+// BulkUpdateBestEffort updates each entity independently, outside a transaction
+func (b *MongoBulkOperator[T]) BulkUpdateBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		id := b.entityID(item)
+		if id == "" {
+			result.Add(i, "", storex.StoreErrors.NewWithMessage(storex.ErrInvalidID, "Missing ID for bulk update"))
+			continue
+		}
+
+		_, err := b.Update(ctx, id, item)
+		result.Add(i, id, err)
+	}
+
+	return result, nil
+}
+
+// BulkDeleteBestEffort deletes each ID independently, outside a transaction
+func (b *MongoBulkOperator[T]) BulkDeleteBestEffort(ctx context.Context, ids []string) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(ids))
+
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Add(i, id, b.Delete(ctx, id))
+	}
+
+	return result, nil
+}
+
+// entityID returns the ID field of an entity as a string, or "" when unset
+func (r *MongoRepository[T]) entityID(item T) string {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("bson")
+		if tag != r.idField && !strings.HasPrefix(tag, r.idField+",") {
+			continue
+		}
+
+		field := v.Field(i)
+		if isEmptyValue(field) {
+			return ""
+		}
+		if objID, ok := field.Interface().(primitive.ObjectID); ok {
+			return objID.Hex()
+		}
+		return fmt.Sprintf("%v", field.Interface())
+	}
+
+	return ""
+}
+
 // MongoTxManager provides transaction support for MongoDB
 type MongoTxManager struct {
 	client *mongo.Client
@@ -557,3 +662,17 @@ func isEmptyValue(v reflect.Value) bool {
 	}
 	return false
 }
+
+// toBSONFilter converts an equality filter to BSON. storex.In values match any
+// of their elements through $in, like IN in the SQL providers.
+func toBSONFilter(filter map[string]any) bson.M {
+	bsonFilter := bson.M{}
+	for k, v := range filter {
+		if in, ok := storex.FilterValues(v); ok {
+			bsonFilter[k] = bson.M{"$in": in}
+			continue
+		}
+		bsonFilter[k] = v
+	}
+	return bsonFilter
+}
@@ -0,0 +1,84 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/jmoiron/sqlx"
+)
+
+// Advisory locks are PostgreSQL-specific: they are held by a database session,
+// not a transaction, so each lock pins one pooled connection until released.
+
+// AdvisoryLockKey hashes a lock name into the int64 key used by pg_advisory_lock
+func AdvisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// TryAdvisoryLock attempts to take the session-level advisory lock for key
+// without waiting. When acquired is false the lock is held elsewhere and
+// release is nil. Otherwise release must be called to unlock and return the
+// connection to the pool.
+func TryAdvisoryLock(ctx context.Context, db *sqlx.DB, key string) (acquired bool, release func() error, err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, nil, storex.StoreErrors.NewWithCause(storex.ErrConnectionFailed, err)
+	}
+
+	lockKey := AdvisoryLockKey(key)
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, nil, storex.StoreErrors.NewWithCause(storex.ErrLockFailed, err).WithDetail("key", key)
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil, nil
+	}
+
+	return true, advisoryUnlock(conn, key, lockKey), nil
+}
+
+// WithAdvisoryLock waits for the session-level advisory lock for key, runs fn
+// and releases the lock, so fn runs on at most one instance at a time. Waiting
+// stops with an error when ctx is cancelled.
+func WithAdvisoryLock(ctx context.Context, db *sqlx.DB, key string, fn func(ctx context.Context) error) (err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return storex.StoreErrors.NewWithCause(storex.ErrConnectionFailed, err)
+	}
+
+	lockKey := AdvisoryLockKey(key)
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		conn.Close()
+		return storex.StoreErrors.NewWithCause(storex.ErrLockFailed, err).WithDetail("key", key)
+	}
+
+	release := advisoryUnlock(conn, key, lockKey)
+	defer func() {
+		if releaseErr := release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// advisoryUnlock returns a release func that unlocks key and closes conn. It
+// uses a fresh context so the lock is released even when the caller's was cancelled.
+func advisoryUnlock(conn *sql.Conn, key string, lockKey int64) func() error {
+	return func() error {
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			// Discard the connection so the session, and its lock, ends
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			return storex.StoreErrors.NewWithCause(storex.ErrLockFailed, err).WithDetail("key", key)
+		}
+		return nil
+	}
+}
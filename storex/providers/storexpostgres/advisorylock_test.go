@@ -0,0 +1,177 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/jmoiron/sqlx"
+)
+
+// advisoryLocks emulates PostgreSQL advisory locks for the fake database
+type advisoryLocks struct {
+	mutex sync.Mutex
+	held  map[int64]bool
+}
+
+func (l *advisoryLocks) tryLock(key int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.held[key] {
+		return false
+	}
+	l.held[key] = true
+	return true
+}
+
+func (l *advisoryLocks) handle(ctx context.Context, query fakeQuery) (fakeResult, error) {
+	key, _ := query.Args[0].(int64)
+	switch {
+	case strings.Contains(query.SQL, "pg_try_advisory_lock"):
+		return fakeResult{Columns: []string{"acquired"}, Rows: [][]driver.Value{{l.tryLock(key)}}}, nil
+	case strings.Contains(query.SQL, "pg_advisory_lock"):
+		for !l.tryLock(key) {
+			select {
+			case <-ctx.Done():
+				return fakeResult{}, ctx.Err()
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return fakeResult{}, nil
+	case strings.Contains(query.SQL, "pg_advisory_unlock"):
+		l.mutex.Lock()
+		delete(l.held, key)
+		l.mutex.Unlock()
+		return fakeResult{}, nil
+	}
+	return fakeResult{}, nil
+}
+
+// newLockDB returns a database whose advisory locks are emulated, or a real
+// PostgreSQL database when STOREX_POSTGRES_DSN is set
+func newLockDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	if dsn := os.Getenv("STOREX_POSTGRES_DSN"); dsn != "" {
+		db, err := sqlx.Connect("postgres", dsn)
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	locks := &advisoryLocks{held: map[int64]bool{}}
+	db, _ := newFakeDB(t, locks.handle)
+	return db
+}
+
+func TestTryAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+	db := newLockDB(t)
+
+	acquired, release, err := TryAdvisoryLock(ctx, db, "jobs:nightly")
+	if err != nil || !acquired {
+		t.Fatalf("first lock: acquired = %v, err = %v", acquired, err)
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		wantAcquired bool
+	}{
+		{name: "same key while held", key: "jobs:nightly", wantAcquired: false},
+		{name: "other key", key: "jobs:hourly", wantAcquired: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acquired, release, err := TryAdvisoryLock(ctx, db, tt.key)
+			if err != nil {
+				t.Fatalf("TryAdvisoryLock: %v", err)
+			}
+			if acquired != tt.wantAcquired {
+				t.Fatalf("acquired = %v, want %v", acquired, tt.wantAcquired)
+			}
+			if !acquired {
+				if release != nil {
+					t.Error("release is set for a lock that wasn't acquired")
+				}
+				return
+			}
+			if err := release(); err != nil {
+				t.Errorf("release: %v", err)
+			}
+		})
+	}
+
+	if err := release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	acquired, release, err = TryAdvisoryLock(ctx, db, "jobs:nightly")
+	if err != nil || !acquired {
+		t.Fatalf("lock after release: acquired = %v, err = %v", acquired, err)
+	}
+	release()
+}
+
+func TestWithAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+	db := newLockDB(t)
+
+	acquired, release, err := TryAdvisoryLock(ctx, db, "migrations")
+	if err != nil || !acquired {
+		t.Fatalf("lock: acquired = %v, err = %v", acquired, err)
+	}
+
+	// While held elsewhere, WithAdvisoryLock waits until its context ends
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	ran := false
+	err = WithAdvisoryLock(waitCtx, db, "migrations", func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if ran {
+		t.Fatal("fn ran while the lock was held elsewhere")
+	}
+	if !errx.IsCode(err, storex.ErrLockFailed) {
+		t.Fatalf("err = %v, want ErrLockFailed", err)
+	}
+
+	// Once released, it runs fn and releases the lock again
+	done := make(chan error, 1)
+	go func() {
+		done <- WithAdvisoryLock(ctx, db, "migrations", func(context.Context) error {
+			ran = true
+			return nil
+		})
+	}()
+	if err := release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if err := <-done; err != nil || !ran {
+		t.Fatalf("WithAdvisoryLock after release: ran = %v, err = %v", ran, err)
+	}
+
+	acquired, release, err = TryAdvisoryLock(ctx, db, "migrations")
+	if err != nil || !acquired {
+		t.Fatalf("WithAdvisoryLock didn't release: acquired = %v, err = %v", acquired, err)
+	}
+	release()
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	if AdvisoryLockKey("jobs:nightly") != AdvisoryLockKey("jobs:nightly") {
+		t.Error("the same name hashed to different keys")
+	}
+	if AdvisoryLockKey("jobs:nightly") == AdvisoryLockKey("jobs:hourly") {
+		t.Error("different names hashed to the same key")
+	}
+}
@@ -0,0 +1,74 @@
+package storexpostgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+// shipment declares its key fields in another order than the key columns
+type shipment struct {
+	Line    int    `db:"line"`
+	OrderID string `db:"order_id"`
+	Status  string `db:"status"`
+}
+
+func TestBulkUpdateCompositeKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []shipment
+		wantErr  bool
+		wantArgs [][]any
+	}{
+		{
+			name:     "key values follow the key column order",
+			items:    []shipment{{Line: 2, OrderID: "o1", Status: "shipped"}, {Line: 1, OrderID: "o2", Status: "lost"}},
+			wantArgs: [][]any{{"shipped", "o1", int64(2)}, {"lost", "o2", int64(1)}},
+		},
+		{
+			name:    "zero key part",
+			items:   []shipment{{OrderID: "o1", Status: "shipped"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty key part",
+			items:   []shipment{{Line: 2, Status: "shipped"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, func(context.Context, fakeQuery) (fakeResult, error) {
+				return fakeResult{Affected: 1}, nil
+			})
+			repo := NewPgRepository[shipment](db, "shipments", "").WithIDColumns("order_id", "line")
+
+			err := NewPgBulkOperator(repo).BulkUpdate(context.Background(), tt.items)
+			if tt.wantErr {
+				if !storex.IsInvalidID(err) {
+					t.Fatalf("err = %v, want ErrInvalidID", err)
+				}
+				if got, want := fake.statements(), []string{"BEGIN", "ROLLBACK"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("statements = %q, want %q", got, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BulkUpdate: %v", err)
+			}
+
+			var args [][]any
+			for _, query := range fake.all() {
+				if query.SQL == "UPDATE shipments SET status = $1 WHERE order_id = $2 AND line = $3" {
+					args = append(args, query.Args)
+				}
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
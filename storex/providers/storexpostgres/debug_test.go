@@ -0,0 +1,128 @@
+package storexpostgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/logx"
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+// captureLogs sends trace-level global logs to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logx.SetOutput(&buf)
+	logx.SetLevel(logx.TraceLevel)
+	logx.SetColored(false)
+	t.Cleanup(func() {
+		logx.SetOutput(os.Stdout)
+		logx.SetLevel(logx.InfoLevel)
+		logx.SetColored(true)
+	})
+	return &buf
+}
+
+func TestQueryDebugLogging(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db, _ := newFakeDB(t, func(_ context.Context, query fakeQuery) (fakeResult, error) {
+		if strings.Contains(query.SQL, "COUNT(") {
+			return fakeResult{Columns: []string{"count"}, Rows: [][]driver.Value{{int64(1)}}}, nil
+		}
+		return fakeResult{
+			Columns: []string{"id", "created_at", "name"},
+			Rows:    [][]driver.Value{{"a1", created, "O'Brien"}},
+		}, nil
+	})
+	repo := NewPgRepository[account](db, "accounts", "id")
+	item := account{Base: Base{ID: "a1", CreatedAt: created}, Name: "O'Brien"}
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+		want []string
+	}{
+		{
+			name: "create",
+			run: func(ctx context.Context) error {
+				_, err := repo.Create(ctx, item)
+				return err
+			},
+			want: []string{"storex create:", "VALUES ('a1', '2024-01-02T03:04:05Z', 'O''Brien')"},
+		},
+		{
+			name: "update",
+			run: func(ctx context.Context) error {
+				_, err := repo.Update(ctx, "a1", item)
+				return err
+			},
+			want: []string{"storex update:", "name = 'O''Brien'", "WHERE id = 'a1'"},
+		},
+		{
+			name: "find by id",
+			run: func(ctx context.Context) error {
+				_, err := repo.FindByID(ctx, "a1")
+				return err
+			},
+			want: []string{"WHERE id = 'a1'"},
+		},
+		{
+			name: "find one",
+			run: func(ctx context.Context) error {
+				_, err := repo.FindOne(ctx, map[string]any{"name": "O'Brien"})
+				return err
+			},
+			want: []string{"WHERE name = 'O''Brien'"},
+		},
+		{
+			name: "paginate",
+			run: func(ctx context.Context) error {
+				_, err := repo.Paginate(ctx, storex.PaginationOptions{
+					Page: 2, PageSize: 10, Filters: map[string]any{"name": "O'Brien"},
+				})
+				return err
+			},
+			want: []string{"storex paginate:", "name = 'O''Brien'", "LIMIT 10 OFFSET 10", "COUNT("},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/debug on", func(t *testing.T) {
+			logs := captureLogs(t)
+
+			if err := tt.run(storex.WithQueryDebug(context.Background())); err != nil {
+				t.Fatalf("operation failed: %v", err)
+			}
+
+			out := logs.String()
+			if !strings.Contains(out, "/* debug only, not for execution */") {
+				t.Errorf("log is not marked as debug only:\n%s", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("log does not contain %q:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "$1") {
+				t.Errorf("log still has placeholders:\n%s", out)
+			}
+		})
+
+		t.Run(tt.name+"/debug off", func(t *testing.T) {
+			logs := captureLogs(t)
+
+			if err := tt.run(context.Background()); err != nil {
+				t.Fatalf("operation failed: %v", err)
+			}
+			if logs.Len() != 0 {
+				t.Errorf("logged without query debugging:\n%s", logs.String())
+			}
+		})
+	}
+}
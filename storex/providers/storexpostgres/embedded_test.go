@@ -0,0 +1,152 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type baseModel struct {
+	ID        string    `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type embeddedAccount struct {
+	baseModel
+	Name string `db:"name"`
+}
+
+type Base struct {
+	ID        string    `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type account struct {
+	Base
+	Name string `db:"name"`
+}
+
+type pointerAccount struct {
+	*Base
+	Name string `db:"name"`
+}
+
+func TestEmbeddedStructColumns(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		item        any
+		wantColumns []string
+	}{
+		{
+			name:        "embedded base",
+			item:        account{Base: Base{ID: "a1", CreatedAt: created}, Name: "Ada"},
+			wantColumns: []string{"id", "created_at", "name"},
+		},
+		{
+			name:        "embedded pointer",
+			item:        pointerAccount{Base: &Base{ID: "a1", CreatedAt: created}, Name: "Ada"},
+			wantColumns: []string{"id", "created_at", "name"},
+		},
+		{
+			name:        "nil embedded pointer",
+			item:        pointerAccount{Name: "Ada"},
+			wantColumns: []string{"name"},
+		},
+		{
+			name:        "unexported embedded struct",
+			item:        embeddedAccount{baseModel: baseModel{ID: "a1"}, Name: "Ada"},
+			wantColumns: []string{"name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, col := range dbColumns(reflect.ValueOf(tt.item)) {
+				names = append(names, col.name)
+			}
+			if !reflect.DeepEqual(names, tt.wantColumns) {
+				t.Errorf("columns = %v, want %v", names, tt.wantColumns)
+			}
+		})
+	}
+}
+
+func TestEmbeddedStructCreateAndScan(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	row := fakeResult{
+		Columns: []string{"id", "created_at", "name"},
+		Rows:    [][]driver.Value{{"a1", created, "Ada"}},
+	}
+	db, fake := newFakeDB(t, func(context.Context, fakeQuery) (fakeResult, error) {
+		return row, nil
+	})
+	repo := NewPgRepository[account](db, "accounts", "id")
+
+	tests := []struct {
+		name     string
+		run      func() (account, error)
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "create",
+			run: func() (account, error) {
+				return repo.Create(context.Background(), account{Base: Base{ID: "a1", CreatedAt: created}, Name: "Ada"})
+			},
+			wantSQL:  "INSERT INTO accounts (id, created_at, name) VALUES ($1, $2, $3) RETURNING *",
+			wantArgs: []any{"a1", created, "Ada"},
+		},
+		{
+			name: "create with generated id",
+			run: func() (account, error) {
+				return repo.Create(context.Background(), account{Base: Base{CreatedAt: created}, Name: "Ada"})
+			},
+			wantSQL:  "INSERT INTO accounts (created_at, name) VALUES ($1, $2) RETURNING *",
+			wantArgs: []any{created, "Ada"},
+		},
+		{
+			name: "update",
+			run: func() (account, error) {
+				return repo.Update(context.Background(), "a1", account{Base: Base{CreatedAt: created}, Name: "Ada"})
+			},
+			wantSQL:  "UPDATE accounts SET created_at = $1, name = $2 WHERE id = $3 RETURNING *",
+			wantArgs: []any{created, "Ada", "a1"},
+		},
+		{
+			name: "find",
+			run: func() (account, error) {
+				return repo.FindByID(context.Background(), "a1")
+			},
+			wantSQL:  "SELECT * FROM accounts WHERE id = $1",
+			wantArgs: []any{"a1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			queries := fake.all()
+			last := queries[len(queries)-1]
+			if last.SQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", last.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(last.Args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", last.Args, tt.wantArgs)
+			}
+
+			want := account{Base: Base{ID: "a1", CreatedAt: created}, Name: "Ada"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("scanned %+v, want %+v", got, want)
+			}
+		})
+	}
+}
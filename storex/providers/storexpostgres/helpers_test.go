@@ -0,0 +1,173 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeQuery is a statement received by the fake database. Transactions are
+// recorded as BEGIN, COMMIT and ROLLBACK statements.
+type fakeQuery struct {
+	SQL  string
+	Args []any
+}
+
+// fakeResult is the answer to a fakeQuery
+type fakeResult struct {
+	Columns  []string
+	Rows     [][]driver.Value
+	Affected int64
+}
+
+// fakeHandler answers a query; it runs under the query's context
+type fakeHandler func(ctx context.Context, query fakeQuery) (fakeResult, error)
+
+// fakeDB is an in-process database/sql driver that records statements and
+// answers them with a handler, for testing without PostgreSQL
+type fakeDB struct {
+	mutex   sync.Mutex
+	queries []fakeQuery
+	handler fakeHandler
+}
+
+// newFakeDB returns a sqlx handle, using PostgreSQL bind vars, backed by a
+// fakeDB that answers with handler
+func newFakeDB(t *testing.T, handler fakeHandler) (*sqlx.DB, *fakeDB) {
+	t.Helper()
+
+	fake := &fakeDB{handler: handler}
+	db := sqlx.NewDb(sql.OpenDB(fake), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+// all returns the recorded statements
+func (f *fakeDB) all() []fakeQuery {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]fakeQuery(nil), f.queries...)
+}
+
+// statements returns the SQL of the recorded statements
+func (f *fakeDB) statements() []string {
+	var statements []string
+	for _, query := range f.all() {
+		statements = append(statements, query.SQL)
+	}
+	return statements
+}
+
+func (f *fakeDB) run(ctx context.Context, sqlText string, args []driver.NamedValue) (fakeResult, error) {
+	query := fakeQuery{SQL: sqlText}
+	for _, arg := range args {
+		query.Args = append(query.Args, arg.Value)
+	}
+
+	f.mutex.Lock()
+	f.queries = append(f.queries, query)
+	f.mutex.Unlock()
+
+	if f.handler == nil {
+		return fakeResult{}, nil
+	}
+	return f.handler(ctx, query)
+}
+
+// Connect implements driver.Connector
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+// Driver implements driver.Connector
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use the connector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	if _, err := c.db.run(ctx, "BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return &fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.db.run(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: result.Columns, rows: result.Rows}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.db.run(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(result.Affected), nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx *fakeTx) Commit() error {
+	_, err := tx.conn.db.run(context.Background(), "COMMIT", nil)
+	return err
+}
+
+func (tx *fakeTx) Rollback() error {
+	_, err := tx.conn.db.run(context.Background(), "ROLLBACK", nil)
+	return err
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,210 @@
+package storexpostgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+	"github.com/jmoiron/sqlx"
+)
+
+// RepositoryOption overrides a default of NewRepository
+type RepositoryOption[T any] func(*PgRepository[T])
+
+// WithTableName sets the table instead of deriving it with storex.TableName
+func WithTableName[T any](name string) RepositoryOption[T] {
+	return func(r *PgRepository[T]) {
+		r.tableName = name
+	}
+}
+
+// WithIDColumns sets the primary key columns instead of deriving them from
+// storex:"pk" tags
+func WithIDColumns[T any](columns ...string) RepositoryOption[T] {
+	return func(r *PgRepository[T]) {
+		r.WithIDColumns(columns...)
+	}
+}
+
+// WithQueryTimeout bounds every operation by timeout (see PgRepository.WithQueryTimeout)
+func WithQueryTimeout[T any](timeout time.Duration) RepositoryOption[T] {
+	return func(r *PgRepository[T]) {
+		r.WithQueryTimeout(timeout)
+	}
+}
+
+// WithValidation checks validate tags before writes (see PgRepository.WithValidation)
+func WithValidation[T any]() RepositoryOption[T] {
+	return func(r *PgRepository[T]) {
+		r.WithValidation()
+	}
+}
+
+// NewRepository creates a repository for T with its table and primary key
+// derived from T, so the common case needs no configuration. The table comes
+// from storex.TableName. The primary key is the db columns of the fields
+// tagged storex:"pk", several of them forming a composite key, or "id" when no
+// field is tagged.
+//
+//	type Product struct {
+//		ID    int     `db:"id" storex:"pk"`
+//		Name  string  `db:"name"`
+//		Price float64 `db:"price"`
+//	}
+//
+//	products := storexpostgres.NewRepository[Product](db) // table "products", key "id"
+//
+//	catalog := storexpostgres.NewRepository[Product](db,
+//		storexpostgres.WithTableName[Product]("catalog"),
+//		storexpostgres.WithQueryTimeout[Product](2*time.Second))
+func NewRepository[T any](db *sqlx.DB, opts ...RepositoryOption[T]) *PgRepository[T] {
+	repo := NewPgRepository[T](db, storex.TableName[T](), "")
+	repo.WithIDColumns(primaryKeyColumns[T]()...)
+
+	for _, opt := range opts {
+		opt(repo)
+	}
+	return repo
+}
+
+// primaryKeyColumns returns the db columns of T's fields tagged storex:"pk",
+// including those of flattened embedded structs
+func primaryKeyColumns[T any]() []string {
+	var zero T
+	t := reflect.TypeOf(&zero).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return taggedPrimaryKeys(t)
+}
+
+// taggedPrimaryKeys walks the fields of t like dbColumns
+func taggedPrimaryKeys(t reflect.Type) []string {
+	columns := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := field.Tag.Get("db")
+		if column == "-" {
+			continue
+		}
+
+		if column == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if field.Anonymous && field.IsExported() && embedded.Kind() == reflect.Struct {
+				columns = append(columns, taggedPrimaryKeys(embedded)...)
+			}
+			continue
+		}
+
+		for _, option := range strings.Split(field.Tag.Get("storex"), ",") {
+			if strings.TrimSpace(option) == "pk" {
+				columns = append(columns, column)
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// Upsert inserts an entity or, when a row with the same ID exists, updates
+// that row's other columns, returning the stored row. An entity whose
+// generated ID is empty is always inserted.
+func (r *PgRepository[T]) Upsert(ctx context.Context, item T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var empty T
+	if err := r.validateItem(item); err != nil {
+		return empty, err
+	}
+
+	stmt, err := r.ExplainUpsert(item)
+	if err != nil {
+		return empty, err
+	}
+	r.logSQL(ctx, stmt)
+
+	var result T
+	err = r.db.GetContext(ctx, &result, stmt.Query, stmt.Args...)
+	if err != nil {
+		return empty, r.queryError(ctx, storex.ErrCreateFailed, err)
+	}
+
+	return result, nil
+}
+
+// ExplainUpsert returns the INSERT ... ON CONFLICT statement Upsert would
+// execute, without executing it
+func (r *PgRepository[T]) ExplainUpsert(item T) (storex.SQLStatement, error) {
+	stmt, err := r.ExplainCreate(item)
+	if err != nil {
+		return storex.SQLStatement{}, err
+	}
+	stmt.Operation = "upsert"
+	if r.entityID(item) == "" {
+		return stmt, nil
+	}
+
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	setClause := []string{}
+	for _, col := range dbColumns(v) {
+		if !r.isIDColumn(col.name) {
+			setClause = append(setClause, fmt.Sprintf("%s = EXCLUDED.%s", col.name, col.name))
+		}
+	}
+	if len(setClause) == 0 {
+		// DO NOTHING would return no row for an existing entity
+		setClause = append(setClause, fmt.Sprintf("%s = EXCLUDED.%s", r.idColumns[0], r.idColumns[0]))
+	}
+
+	stmt.Query = fmt.Sprintf(
+		"%s ON CONFLICT (%s) DO UPDATE SET %s RETURNING *",
+		strings.TrimSuffix(stmt.Query, " RETURNING *"),
+		strings.Join(r.idColumns, ", "),
+		strings.Join(setClause, ", "),
+	)
+	return stmt, nil
+}
+
+// Count returns the number of rows matching filter, or of all rows when the
+// filter is empty
+func (r *PgRepository[T]) Count(ctx context.Context, filter map[string]any) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	stmt := r.ExplainCount(filter)
+	r.logSQL(ctx, stmt)
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, stmt.Query, stmt.Args...); err != nil {
+		return 0, r.queryError(ctx, storex.ErrSQLCountFailed, err)
+	}
+	return total, nil
+}
+
+// ExplainCount returns the statement Count would execute, without executing it
+func (r *PgRepository[T]) ExplainCount(filter map[string]any) storex.SQLStatement {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", r.tableName)
+	args := []any{}
+
+	if len(filter) > 0 {
+		var conditions []string
+		conditions, args = buildEqualityConditions(filter)
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return storex.SQLStatement{Operation: "count", Query: query, Args: args}
+}
@@ -0,0 +1,177 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+type product struct {
+	ID    int64   `db:"id" storex:"pk"`
+	SKU   string  `db:"sku"`
+	Price float64 `db:"price"`
+}
+
+type orderLine struct {
+	_       struct{} `table:"order_items"`
+	OrderID string   `db:"order_id" storex:"pk"`
+	Line    int      `db:"line" storex:"pk"`
+	Qty     int      `db:"qty"`
+}
+
+// productTable answers every statement with the stored product row, counts
+// with 1 and deletes with one affected row
+func productTable(ctx context.Context, query fakeQuery) (fakeResult, error) {
+	switch {
+	case strings.HasPrefix(query.SQL, "SELECT COUNT(*)"):
+		return fakeResult{Columns: []string{"count"}, Rows: [][]driver.Value{{int64(1)}}}, nil
+	case strings.HasPrefix(query.SQL, "DELETE"):
+		return fakeResult{Affected: 1}, nil
+	}
+	return fakeResult{
+		Columns: []string{"id", "sku", "price"},
+		Rows:    [][]driver.Value{{int64(7), "SKU-7", 9.5}},
+	}, nil
+}
+
+func TestRepositoryCRUD(t *testing.T) {
+	db, fake := newFakeDB(t, productTable)
+	repo := NewRepository[product](db)
+	ctx := context.Background()
+	stored := product{ID: 7, SKU: "SKU-7", Price: 9.5}
+
+	tests := []struct {
+		name     string
+		run      func() (any, error)
+		want     any
+		wantSQL  []string
+		wantArgs []any
+	}{
+		{
+			name:     "create",
+			run:      func() (any, error) { return repo.Create(ctx, product{SKU: "SKU-7", Price: 9.5}) },
+			want:     stored,
+			wantSQL:  []string{"INSERT INTO products (sku, price) VALUES ($1, $2) RETURNING *"},
+			wantArgs: []any{"SKU-7", 9.5},
+		},
+		{
+			name:     "find",
+			run:      func() (any, error) { return repo.FindByID(ctx, "7") },
+			want:     stored,
+			wantSQL:  []string{"SELECT * FROM products WHERE id = $1"},
+			wantArgs: []any{"7"},
+		},
+		{
+			name:     "update",
+			run:      func() (any, error) { return repo.Update(ctx, "7", product{SKU: "SKU-7", Price: 9.5}) },
+			want:     stored,
+			wantSQL:  []string{"UPDATE products SET sku = $1, price = $2 WHERE id = $3 RETURNING *"},
+			wantArgs: []any{"SKU-7", 9.5, "7"},
+		},
+		{
+			name: "upsert",
+			run:  func() (any, error) { return repo.Upsert(ctx, stored) },
+			want: stored,
+			wantSQL: []string{"INSERT INTO products (id, sku, price) VALUES ($1, $2, $3) " +
+				"ON CONFLICT (id) DO UPDATE SET sku = EXCLUDED.sku, price = EXCLUDED.price RETURNING *"},
+			wantArgs: []any{int64(7), "SKU-7", 9.5},
+		},
+		{
+			name:     "count",
+			run:      func() (any, error) { return repo.Count(ctx, map[string]any{"sku": "SKU-7"}) },
+			want:     1,
+			wantSQL:  []string{"SELECT COUNT(*) FROM products WHERE sku = $1"},
+			wantArgs: []any{"SKU-7"},
+		},
+		{
+			name: "delete",
+			run: func() (any, error) {
+				return nil, repo.Delete(ctx, "7")
+			},
+			wantSQL:  []string{"DELETE FROM products WHERE id = $1"},
+			wantArgs: []any{"7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(fake.all())
+			got, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+
+			queries := fake.all()[before:]
+			var statements []string
+			for _, query := range queries {
+				statements = append(statements, query.SQL)
+			}
+			if !reflect.DeepEqual(statements, tt.wantSQL) {
+				t.Fatalf("SQL = %q, want %q", statements, tt.wantSQL)
+			}
+			if last := queries[len(queries)-1]; !reflect.DeepEqual(last.Args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", last.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	page, err := repo.Paginate(ctx, storex.PaginationOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if !reflect.DeepEqual(page.Data, []product{stored}) || page.Page.Total != 1 {
+		t.Errorf("page = %+v, want the stored product", page)
+	}
+}
+
+func TestNewRepositoryDefaults(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	tests := []struct {
+		name    string
+		explain func() (storex.SQLStatement, error)
+		wantSQL string
+	}{
+		{
+			name: "table and key from the type",
+			explain: func() (storex.SQLStatement, error) {
+				return NewRepository[product](db).ExplainUpdate("7", product{SKU: "a"})
+			},
+			wantSQL: "UPDATE products SET sku = $1, price = $2 WHERE id = $3 RETURNING *",
+		},
+		{
+			name: "table tag and composite key",
+			explain: func() (storex.SQLStatement, error) {
+				return NewRepository[orderLine](db).ExplainUpdate(storex.CompositeID("o1", "2"), orderLine{Qty: 3})
+			},
+			wantSQL: "UPDATE order_items SET qty = $1 WHERE order_id = $2 AND line = $3 RETURNING *",
+		},
+		{
+			name: "options override the derived defaults",
+			explain: func() (storex.SQLStatement, error) {
+				repo := NewRepository[product](db, WithTableName[product]("catalog"), WithIDColumns[product]("sku"))
+				return repo.ExplainUpdate("SKU-7", product{ID: 7, Price: 1})
+			},
+			wantSQL: "UPDATE catalog SET id = $1, price = $2 WHERE sku = $3 RETURNING *",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := tt.explain()
+			if err != nil {
+				t.Fatalf("explain: %v", err)
+			}
+			if stmt.Query != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", stmt.Query, tt.wantSQL)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes that mean the transaction lost a conflict and can
+// be retried as a whole
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+const (
+	retryTxBaseDelay = 10 * time.Millisecond
+	retryTxMaxDelay  = time.Second
+)
+
+// RetryTx runs fn in a SERIALIZABLE transaction, retrying the whole
+// transaction up to maxAttempts times in total when PostgreSQL reports a
+// serialization failure (40001) or deadlock (40P01). Retries back off
+// exponentially with jitter. fn must be safe to run more than once: keep side
+// effects outside the database until RetryTx returns.
+func (tm *PgTxManager) RetryTx(ctx context.Context, maxAttempts int, fn func(txCtx context.Context) error) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = tm.withTx(ctx, opts, fn)
+		if err == nil || attempt >= maxAttempts || !IsSerializationFailure(err) {
+			return err
+		}
+
+		delay := min(retryTxBaseDelay<<(attempt-1), retryTxMaxDelay)
+		delay = delay/2 + rand.N(delay/2+1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// IsSerializationFailure reports whether err carries a PostgreSQL
+// serialization failure or deadlock error, either from lib/pq or from a
+// driver exposing SQLState (such as pgx)
+func IsSerializationFailure(err error) bool {
+	var code string
+
+	var pqErr *pq.Error
+	var stateErr interface{ SQLState() string }
+	switch {
+	case errors.As(err, &pqErr):
+		code = string(pqErr.Code)
+	case errors.As(err, &stateErr):
+		code = stateErr.SQLState()
+	default:
+		return false
+	}
+
+	return code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+}
@@ -0,0 +1,154 @@
+package storexpostgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const retryTxUpdate = "UPDATE accounts SET balance = balance - 10 WHERE id = 'a1'"
+
+// failingStatements fails each statement with its queued errors, one per
+// execution, and succeeds once the queue is empty
+type failingStatements struct {
+	mutex  sync.Mutex
+	errors map[string][]error
+}
+
+func (f *failingStatements) handle(_ context.Context, query fakeQuery) (fakeResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	queued := f.errors[query.SQL]
+	if len(queued) == 0 {
+		return fakeResult{Affected: 1}, nil
+	}
+	f.errors[query.SQL] = queued[1:]
+	return fakeResult{}, queued[0]
+}
+
+func TestRetryTx(t *testing.T) {
+	serialization := &pq.Error{Code: "40001", Message: "could not serialize access"}
+	deadlock := &pq.Error{Code: "40P01", Message: "deadlock detected"}
+	uniqueViolation := &pq.Error{Code: "23505", Message: "duplicate key value"}
+
+	tests := []struct {
+		name           string
+		maxAttempts    int
+		errors         map[string][]error
+		wantStatements []string
+		wantErr        error
+	}{
+		{
+			name:           "commits on the first attempt",
+			maxAttempts:    3,
+			wantStatements: []string{"BEGIN", retryTxUpdate, "COMMIT"},
+		},
+		{
+			name:        "serialization failure is retried and commits",
+			maxAttempts: 3,
+			errors:      map[string][]error{retryTxUpdate: {serialization}},
+			wantStatements: []string{
+				"BEGIN", retryTxUpdate, "ROLLBACK",
+				"BEGIN", retryTxUpdate, "COMMIT",
+			},
+		},
+		{
+			name:        "deadlock is retried and commits",
+			maxAttempts: 3,
+			errors:      map[string][]error{retryTxUpdate: {deadlock}},
+			wantStatements: []string{
+				"BEGIN", retryTxUpdate, "ROLLBACK",
+				"BEGIN", retryTxUpdate, "COMMIT",
+			},
+		},
+		{
+			name:        "serialization failure at commit is retried",
+			maxAttempts: 3,
+			errors:      map[string][]error{"COMMIT": {serialization}},
+			wantStatements: []string{
+				"BEGIN", retryTxUpdate, "COMMIT",
+				"BEGIN", retryTxUpdate, "COMMIT",
+			},
+		},
+		{
+			name:        "gives up after max attempts",
+			maxAttempts: 2,
+			errors:      map[string][]error{retryTxUpdate: {serialization, serialization, serialization}},
+			wantStatements: []string{
+				"BEGIN", retryTxUpdate, "ROLLBACK",
+				"BEGIN", retryTxUpdate, "ROLLBACK",
+			},
+			wantErr: serialization,
+		},
+		{
+			name:           "other errors are not retried",
+			maxAttempts:    3,
+			errors:         map[string][]error{retryTxUpdate: {uniqueViolation}},
+			wantStatements: []string{"BEGIN", retryTxUpdate, "ROLLBACK"},
+			wantErr:        uniqueViolation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing := &failingStatements{errors: tt.errors}
+			if failing.errors == nil {
+				failing.errors = map[string][]error{}
+			}
+			db, fake := newFakeDB(t, failing.handle)
+			tm := NewPgTxManager(db)
+
+			err := tm.RetryTx(context.Background(), tt.maxAttempts, func(txCtx context.Context) error {
+				tx := txCtx.Value("tx").(*sqlx.Tx)
+				_, err := tx.ExecContext(txCtx, retryTxUpdate)
+				return err
+			})
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("RetryTx: %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := fake.statements(); !reflect.DeepEqual(got, tt.wantStatements) {
+				t.Errorf("statements = %q, want %q", got, tt.wantStatements)
+			}
+		})
+	}
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "pq serialization failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "pq deadlock", err: &pq.Error{Code: "40P01"}, want: true},
+		{name: "pq other code", err: &pq.Error{Code: "23505"}},
+		{name: "SQLState error", err: sqlStateError("40001"), want: true},
+		{name: "wrapped", err: errors.Join(errors.New("update"), &pq.Error{Code: "40001"}), want: true},
+		{name: "plain error", err: errors.New("40001")},
+		{name: "nil", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSerializationFailure(tt.err); got != tt.want {
+				t.Errorf("IsSerializationFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sqlStateError mimics drivers, such as pgx, that expose SQLState
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
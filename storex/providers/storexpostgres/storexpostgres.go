@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/Abraxas-365/craftable/errx"
 	"github.com/Abraxas-365/craftable/storex"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -20,7 +24,10 @@ import (
 type PgRepository[T any] struct {
 	db        *sqlx.DB
 	tableName string
-	idField   string
+	idColumns []string
+	sqlLog    *storex.SQLLogOptions
+	timeout   time.Duration
+	validate  bool
 }
 
 // NewPgRepository creates a new PostgreSQL repository
@@ -31,42 +38,115 @@ func NewPgRepository[T any](db *sqlx.DB, tableName string, idField string) *PgRe
 	return &PgRepository[T]{
 		db:        db,
 		tableName: tableName,
-		idField:   idField,
+		idColumns: []string{idField},
 	}
 }
 
+// WithIDColumns keys the repository by several columns, for tables with a
+// composite primary key. IDs passed to FindByID, Update and Delete are then
+// built with storex.CompositeID, with values in the order of columns.
+func (r *PgRepository[T]) WithIDColumns(columns ...string) *PgRepository[T] {
+	if len(columns) > 0 {
+		r.idColumns = columns
+	}
+	return r
+}
+
+// WithQueryTimeout bounds every repository operation by timeout. Operations
+// that exceed it are cancelled and fail with storex.ErrQueryTimeout. Bulk
+// operations count as a single operation; best-effort bulk operations apply
+// the timeout to each item.
+func (r *PgRepository[T]) WithQueryTimeout(timeout time.Duration) *PgRepository[T] {
+	r.timeout = timeout
+	return r
+}
+
+// WithValidation checks the validate tags of entities with storex.ValidateStruct
+// before Create and Update, failing with storex.ErrValidationFailed without a
+// database round-trip
+func (r *PgRepository[T]) WithValidation() *PgRepository[T] {
+	r.validate = true
+	return r
+}
+
+// validateItem runs storex.ValidateStruct when validation is enabled
+func (r *PgRepository[T]) validateItem(item T) error {
+	if !r.validate {
+		return nil
+	}
+	return storex.ValidateStruct(item)
+}
+
+// withTimeout derives the context an operation runs under
+func (r *PgRepository[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// queryError wraps err with code, or reports a timeout when the operation's
+// deadline expired
+func (r *PgRepository[T]) queryError(ctx context.Context, code errx.Code, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		timeoutErr := storex.StoreErrors.New(storex.ErrQueryTimeout).WithCause(err)
+		if r.timeout > 0 {
+			timeoutErr.WithDetail("timeout", r.timeout.String())
+		}
+		return timeoutErr
+	}
+	return storex.StoreErrors.NewWithCause(code, err)
+}
+
 // Create adds a new entity to the database
 func (r *PgRepository[T]) Create(ctx context.Context, item T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var empty T
+	if err := r.validateItem(item); err != nil {
+		return empty, err
+	}
+
+	stmt, err := r.ExplainCreate(item)
+	if err != nil {
+		return empty, err
+	}
+	r.logSQL(ctx, stmt)
+
+	var result T
+	err = r.db.GetContext(ctx, &result, stmt.Query, stmt.Args...)
+	if err != nil {
+		return empty, r.queryError(ctx, storex.ErrCreateFailed, err)
+	}
+
+	return result, nil
+}
+
+// ExplainCreate returns the INSERT statement Create would execute, without executing it
+func (r *PgRepository[T]) ExplainCreate(item T) (storex.SQLStatement, error) {
 	v := reflect.ValueOf(item)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 
-	t := v.Type()
 	fields := []string{}
 	placeholders := []string{}
 	values := []interface{}{}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		tag := field.Tag.Get("db")
-		if tag == "" || tag == "-" {
-			continue
-		}
-
+	for _, col := range dbColumns(v) {
 		// Skip the ID field if it's empty
-		if tag == r.idField && isEmptyValue(v.Field(i)) {
+		if r.isGeneratedID(col) {
 			continue
 		}
 
-		fields = append(fields, tag)
+		fields = append(fields, col.name)
 		placeholders = append(placeholders, fmt.Sprintf("$%d", len(values)+1))
-		values = append(values, v.Field(i).Interface())
+		values = append(values, col.value.Interface())
 	}
 
 	if len(fields) == 0 {
-		return empty, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No fields to insert")
+		return storex.SQLStatement{}, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No fields to insert")
 	}
 
 	query := fmt.Sprintf(
@@ -76,123 +156,164 @@ func (r *PgRepository[T]) Create(ctx context.Context, item T) (T, error) {
 		strings.Join(placeholders, ", "),
 	)
 
-	var result T
-	err := r.db.GetContext(ctx, &result, query, values...)
-	if err != nil {
-		return empty, storex.StoreErrors.NewWithCause(storex.ErrCreateFailed, err)
-	}
-
-	return result, nil
+	return storex.SQLStatement{Operation: "create", Query: query, Args: values}, nil
 }
 
 // FindByID retrieves an entity by its ID
 func (r *PgRepository[T]) FindByID(ctx context.Context, id string) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var result T
 	var empty T
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = $1", r.tableName, r.idField)
-	err := r.db.GetContext(ctx, &result, query, id)
+	if err := r.validateID(id); err != nil {
+		return empty, err
+	}
+
+	stmt := r.ExplainFindByID(id)
+	r.logSQL(ctx, stmt)
+	err := r.db.GetContext(ctx, &result, stmt.Query, stmt.Args...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return empty, storex.StoreErrors.NewWithMessage(storex.ErrRecordNotFound, "ID: "+id)
 		}
-		return empty, storex.StoreErrors.NewWithCause(storex.ErrSQLQueryFailed, err)
+		return empty, r.queryError(ctx, storex.ErrSQLQueryFailed, err)
 	}
 
 	return result, nil
 }
 
+// ExplainFindByID returns the SELECT statement FindByID would execute, without executing it
+func (r *PgRepository[T]) ExplainFindByID(id string) storex.SQLStatement {
+	return storex.SQLStatement{
+		Operation: "find_by_id",
+		Query:     fmt.Sprintf("SELECT * FROM %s WHERE %s", r.tableName, r.idCondition(1)),
+		Args:      r.idArgs(id),
+	}
+}
+
 // FindOne retrieves a single entity that matches the filter
 func (r *PgRepository[T]) FindOne(ctx context.Context, filter map[string]any) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var result T
 	var empty T
 
-	if len(filter) == 0 {
-		return empty, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No filter provided")
+	stmt, err := r.ExplainFindOne(filter)
+	if err != nil {
+		return empty, err
 	}
+	r.logSQL(ctx, stmt)
 
-	conditions := []string{}
-	values := []interface{}{}
-	i := 1
+	err = r.db.GetContext(ctx, &result, stmt.Query, stmt.Args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return empty, storex.StoreErrors.NewWithMessage(storex.ErrRecordNotFound, "Query: "+stmt.Query)
+		}
+		return empty, r.queryError(ctx, storex.ErrSQLQueryFailed, err)
+	}
 
-	for k, v := range filter {
-		conditions = append(conditions, fmt.Sprintf("%s = $%d", k, i))
-		values = append(values, v)
-		i++
+	return result, nil
+}
+
+// ExplainFindOne returns the SELECT statement FindOne would execute, without executing it
+func (r *PgRepository[T]) ExplainFindOne(filter map[string]any) (storex.SQLStatement, error) {
+	if len(filter) == 0 {
+		return storex.SQLStatement{}, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No filter provided")
 	}
 
+	conditions, values := buildEqualityConditions(filter)
 	whereClause := strings.Join(conditions, " AND ")
 	query := fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT 1", r.tableName, whereClause)
 
-	err := r.db.GetContext(ctx, &result, query, values...)
+	return storex.SQLStatement{Operation: "find_one", Query: query, Args: values}, nil
+}
+
+// Update modifies an existing entity
+func (r *PgRepository[T]) Update(ctx context.Context, id string, item T) (T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var empty T
+	if err := r.validateID(id); err != nil {
+		return empty, err
+	}
+	if err := r.validateItem(item); err != nil {
+		return empty, err
+	}
+
+	stmt, err := r.ExplainUpdate(id, item)
+	if err != nil {
+		return empty, err
+	}
+	r.logSQL(ctx, stmt)
+
+	var result T
+	err = r.db.GetContext(ctx, &result, stmt.Query, stmt.Args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return empty, storex.StoreErrors.NewWithMessage(storex.ErrRecordNotFound, "Filter: "+whereClause)
+			return empty, storex.StoreErrors.NewWithMessage(storex.ErrRecordNotFound, "ID: "+id)
 		}
-		return empty, storex.StoreErrors.NewWithCause(storex.ErrSQLQueryFailed, err)
+		return empty, r.queryError(ctx, storex.ErrUpdateFailed, err)
 	}
 
 	return result, nil
 }
 
-// Update modifies an existing entity
-func (r *PgRepository[T]) Update(ctx context.Context, id string, item T) (T, error) {
-	var empty T
+// ExplainUpdate returns the UPDATE statement Update would execute, without executing it
+func (r *PgRepository[T]) ExplainUpdate(id string, item T) (storex.SQLStatement, error) {
 	v := reflect.ValueOf(item)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 
-	t := v.Type()
 	setClause := []string{}
 	values := []interface{}{}
 	i := 1
 
-	for j := 0; j < v.NumField(); j++ {
-		field := t.Field(j)
-		tag := field.Tag.Get("db")
-		if tag == "" || tag == "-" || tag == r.idField {
+	for _, col := range dbColumns(v) {
+		if r.isIDColumn(col.name) {
 			continue
 		}
 
-		setClause = append(setClause, fmt.Sprintf("%s = $%d", tag, i))
-		values = append(values, v.Field(j).Interface())
+		setClause = append(setClause, fmt.Sprintf("%s = $%d", col.name, i))
+		values = append(values, col.value.Interface())
 		i++
 	}
 
 	if len(setClause) == 0 {
-		return empty, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No fields to update")
+		return storex.SQLStatement{}, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No fields to update")
 	}
 
-	values = append(values, id)
+	values = append(values, r.idArgs(id)...)
 	query := fmt.Sprintf(
-		"UPDATE %s SET %s WHERE %s = $%d RETURNING *",
+		"UPDATE %s SET %s WHERE %s RETURNING *",
 		r.tableName,
 		strings.Join(setClause, ", "),
-		r.idField,
-		i,
+		r.idCondition(i),
 	)
 
-	var result T
-	err := r.db.GetContext(ctx, &result, query, values...)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return empty, storex.StoreErrors.NewWithMessage(storex.ErrRecordNotFound, "ID: "+id)
-		}
-		return empty, storex.StoreErrors.NewWithCause(storex.ErrUpdateFailed, err)
-	}
-
-	return result, nil
+	return storex.SQLStatement{Operation: "update", Query: query, Args: values}, nil
 }
 
 // Delete removes an entity from the store
 func (r *PgRepository[T]) Delete(ctx context.Context, id string) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", r.tableName, r.idField)
-	result, err := r.db.ExecContext(ctx, query, id)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if err := r.validateID(id); err != nil {
+		return err
+	}
+
+	stmt := r.ExplainDelete(id)
+	r.logSQL(ctx, stmt)
+	result, err := r.db.ExecContext(ctx, stmt.Query, stmt.Args...)
 
 	if err != nil {
-		return storex.StoreErrors.NewWithCause(storex.ErrDeleteFailed, err)
+		return r.queryError(ctx, storex.ErrDeleteFailed, err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -207,8 +328,54 @@ func (r *PgRepository[T]) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// ExplainDelete returns the DELETE statement Delete would execute, without executing it
+func (r *PgRepository[T]) ExplainDelete(id string) storex.SQLStatement {
+	return storex.SQLStatement{
+		Operation: "delete",
+		Query:     fmt.Sprintf("DELETE FROM %s WHERE %s", r.tableName, r.idCondition(1)),
+		Args:      r.idArgs(id),
+	}
+}
+
 // Paginate retrieves entities with pagination
 func (r *PgRepository[T]) Paginate(ctx context.Context, opts storex.PaginationOptions) (storex.Paginated[T], error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := r.sortOrder(opts); err != nil {
+		return storex.Paginated[T]{}, err
+	}
+	dataStmt, countStmt := r.ExplainPaginate(opts)
+
+	// Execute queries
+	var items []T
+	var total int
+
+	r.logSQL(ctx, dataStmt)
+	err := r.db.SelectContext(ctx, &items, dataStmt.Query, dataStmt.Args...)
+	if err != nil {
+		return storex.Paginated[T]{}, r.queryError(ctx, storex.ErrSQLQueryFailed, err)
+	}
+
+	if opts.SkipCount {
+		return storex.NewUncountedPaginated(items, opts), nil
+	}
+
+	r.logSQL(ctx, countStmt)
+	err = r.db.GetContext(ctx, &total, countStmt.Query, countStmt.Args...)
+	if err != nil {
+		return storex.Paginated[T]{}, r.queryError(ctx, storex.ErrSQLCountFailed, err)
+	}
+
+	return storex.NewPaginated(items, opts.Page, opts.PageSize, total), nil
+}
+
+// ExplainPaginate returns the data and count statements Paginate would execute,
+// without executing them. Rows are ordered by opts.SortOrder with the ID
+// columns as tiebreakers; sort columns Paginate would reject are left out.
+// With opts.SkipCount the data statement fetches one lookahead row and the
+// count statement is not executed.
+func (r *PgRepository[T]) ExplainPaginate(opts storex.PaginationOptions) (storex.SQLStatement, storex.SQLStatement) {
 	// Process fields selection
 	fieldsClause := "*"
 	if len(opts.Fields) > 0 {
@@ -220,31 +387,26 @@ func (r *PgRepository[T]) Paginate(ctx context.Context, opts storex.PaginationOp
 	params := []interface{}{}
 
 	if len(opts.Filters) > 0 {
-		conditions := []string{}
-		i := 1
-
-		for k, v := range opts.Filters {
-			conditions = append(conditions, fmt.Sprintf("%s = $%d", k, i))
-			params = append(params, v)
-			i++
-		}
-
+		var conditions []string
+		conditions, params = buildEqualityConditions(opts.Filters)
 		whereClause = " WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	// Process ordering
-	orderClause := ""
-	if opts.OrderBy != "" {
+	order, _ := r.sortOrder(opts)
+	orderTerms := make([]string, len(order))
+	for i, s := range order {
 		direction := "ASC"
-		if opts.Desc {
+		if s.Desc {
 			direction = "DESC"
 		}
-		orderClause = fmt.Sprintf(" ORDER BY %s %s", opts.OrderBy, direction)
+		orderTerms[i] = s.Field + " " + direction
 	}
+	orderClause := " ORDER BY " + strings.Join(orderTerms, ", ")
 
 	// Calculate pagination
 	offset := (opts.Page - 1) * opts.PageSize
-	limitOffset := fmt.Sprintf(" LIMIT %d OFFSET %d", opts.PageSize, offset)
+	limitOffset := fmt.Sprintf(" LIMIT %d OFFSET %d", opts.FetchLimit(), offset)
 
 	// Build queries
 	dataQuery := fmt.Sprintf(
@@ -254,21 +416,43 @@ func (r *PgRepository[T]) Paginate(ctx context.Context, opts storex.PaginationOp
 
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", r.tableName, whereClause)
 
-	// Execute queries
-	var items []T
-	var total int
+	return storex.SQLStatement{Operation: "paginate", Query: dataQuery, Args: params},
+		storex.SQLStatement{Operation: "count", Query: countQuery, Args: params}
+}
 
-	err := r.db.SelectContext(ctx, &items, dataQuery, params...)
-	if err != nil {
-		return storex.Paginated[T]{}, storex.StoreErrors.NewWithCause(storex.ErrSQLQueryFailed, err)
+// sortIdentifier matches a column name, optionally qualified by its table,
+// that is safe to write into ORDER BY
+var sortIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// sortOrder returns the ORDER BY columns for opts. Since they are written
+// into the query, sort columns must be db tags of T, ID columns or plain
+// (optionally table-qualified) identifiers; anything else, such as an
+// expression, is left out and reported as an ErrInvalidQuery error.
+func (r *PgRepository[T]) sortOrder(opts storex.PaginationOptions) ([]storex.Sort, error) {
+	known := make(map[string]bool)
+	for _, column := range r.idColumns {
+		known[column] = true
 	}
-
-	err = r.db.GetContext(ctx, &total, countQuery, params...)
-	if err != nil {
-		return storex.Paginated[T]{}, storex.StoreErrors.NewWithCause(storex.ErrSQLCountFailed, err)
+	if t := reflect.TypeFor[T](); t.Kind() == reflect.Struct {
+		for _, column := range dbColumnNames(t) {
+			known[column] = true
+		}
 	}
 
-	return storex.NewPaginated(items, opts.Page, opts.PageSize, total), nil
+	var err error
+	order := []storex.Sort{}
+	for _, s := range opts.SortOrder(r.idColumns...) {
+		if !known[s.Field] && !sortIdentifier.MatchString(s.Field) {
+			if err == nil {
+				err = storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "Unknown sort column").
+					WithDetail("column", s.Field).
+					WithDetail("table", r.tableName)
+			}
+			continue
+		}
+		order = append(order, s)
+	}
+	return order, err
 }
 
 // PgBulkOperator implements BulkOperator for PostgreSQL
@@ -283,6 +467,9 @@ func NewPgBulkOperator[T any](repo *PgRepository[T]) *PgBulkOperator[T] {
 
 // BulkInsert adds multiple entities in a single operation
 func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
 	if len(items) == 0 {
 		return nil
 	}
@@ -293,22 +480,15 @@ func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
 		v = v.Elem()
 	}
 
-	t := v.Type()
 	fields := []string{}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		tag := field.Tag.Get("db")
-		if tag == "" || tag == "-" {
-			continue
-		}
-
+	for _, col := range dbColumns(v) {
 		// Skip ID field if it's empty
-		if tag == b.idField && isEmptyValue(v.Field(i)) {
+		if b.isGeneratedID(col) {
 			continue
 		}
 
-		fields = append(fields, tag)
+		fields = append(fields, col.name)
 	}
 
 	if len(fields) == 0 {
@@ -328,22 +508,16 @@ func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
 
 		placeholders := []string{}
 
-		for i := 0; i < v.NumField(); i++ {
-			field := t.Field(i)
-			tag := field.Tag.Get("db")
-			if tag == "" || tag == "-" {
-				continue
-			}
-
+		for _, col := range dbColumns(v) {
 			// Skip ID field if it's empty
-			if tag == b.idField && isEmptyValue(v.Field(i)) {
+			if b.isGeneratedID(col) {
 				continue
 			}
 
 			// Check if this field is in our fields list
 			found := false
 			for _, f := range fields {
-				if f == tag {
+				if f == col.name {
 					found = true
 					break
 				}
@@ -351,7 +525,7 @@ func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
 
 			if found {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", paramIndex))
-				valueParams = append(valueParams, v.Field(i).Interface())
+				valueParams = append(valueParams, col.value.Interface())
 				paramIndex++
 			}
 		}
@@ -366,9 +540,10 @@ func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
 		strings.Join(valueGroups, ", "),
 	)
 
+	b.logSQL(ctx, storex.SQLStatement{Operation: "bulk_insert", Query: query, Args: valueParams})
 	_, err := b.db.ExecContext(ctx, query, valueParams...)
 	if err != nil {
-		return storex.StoreErrors.NewWithCause(storex.ErrBulkOpFailed, err)
+		return b.queryError(ctx, storex.ErrBulkOpFailed, err)
 	}
 
 	return nil
@@ -376,10 +551,13 @@ func (b *PgBulkOperator[T]) BulkInsert(ctx context.Context, items []T) error {
 
 // BulkUpdate modifies multiple entities in a single operation
 func (b *PgBulkOperator[T]) BulkUpdate(ctx context.Context, items []T) error {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
 	// Using transactions for bulk updates
 	tx, err := b.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return storex.StoreErrors.NewWithCause(storex.ErrTxBeginFailed, err)
+		return b.queryError(ctx, storex.ErrTxBeginFailed, err)
 	}
 
 	defer func() {
@@ -394,23 +572,11 @@ func (b *PgBulkOperator[T]) BulkUpdate(ctx context.Context, items []T) error {
 			v = v.Elem()
 		}
 
-		// Find the ID field
-		t := v.Type()
-		var id interface{}
-		var found bool
-
-		for i := 0; i < v.NumField(); i++ {
-			field := t.Field(i)
-			tag := field.Tag.Get("db")
-			if tag == b.idField {
-				id = v.Field(i).Interface()
-				found = true
-				break
-			}
-		}
-
-		if !found || id == nil {
-			return storex.StoreErrors.NewWithMessage(storex.ErrInvalidID, "Missing ID for bulk update")
+		// Find the ID values, in the order of the ID columns
+		columns := dbColumns(v)
+		var ids []any
+		if ids, err = b.idValues(columns); err != nil {
+			return err
 		}
 
 		// Build update for this item
@@ -418,15 +584,13 @@ func (b *PgBulkOperator[T]) BulkUpdate(ctx context.Context, items []T) error {
 		values := []interface{}{}
 		paramIndex := 1
 
-		for i := 0; i < v.NumField(); i++ {
-			field := t.Field(i)
-			tag := field.Tag.Get("db")
-			if tag == "" || tag == "-" || tag == b.idField {
+		for _, col := range columns {
+			if b.isIDColumn(col.name) {
 				continue
 			}
 
-			setClause = append(setClause, fmt.Sprintf("%s = $%d", tag, paramIndex))
-			values = append(values, v.Field(i).Interface())
+			setClause = append(setClause, fmt.Sprintf("%s = $%d", col.name, paramIndex))
+			values = append(values, col.value.Interface())
 			paramIndex++
 		}
 
@@ -434,23 +598,23 @@ func (b *PgBulkOperator[T]) BulkUpdate(ctx context.Context, items []T) error {
 			continue // Nothing to update
 		}
 
-		values = append(values, id)
+		values = append(values, ids...)
 		query := fmt.Sprintf(
-			"UPDATE %s SET %s WHERE %s = $%d",
+			"UPDATE %s SET %s WHERE %s",
 			b.tableName,
 			strings.Join(setClause, ", "),
-			b.idField,
-			paramIndex,
+			b.idCondition(paramIndex),
 		)
 
+		b.logSQL(ctx, storex.SQLStatement{Operation: "bulk_update", Query: query, Args: values})
 		_, err = tx.ExecContext(ctx, query, values...)
 		if err != nil {
-			return storex.StoreErrors.NewWithCause(storex.ErrUpdateFailed, err)
+			return b.queryError(ctx, storex.ErrUpdateFailed, err)
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return storex.StoreErrors.NewWithCause(storex.ErrTxCommitFailed, err)
+		return b.queryError(ctx, storex.ErrTxCommitFailed, err)
 	}
 
 	return nil
@@ -458,28 +622,50 @@ func (b *PgBulkOperator[T]) BulkUpdate(ctx context.Context, items []T) error {
 
 // BulkDelete removes multiple entities in a single operation
 func (b *PgBulkOperator[T]) BulkDelete(ctx context.Context, ids []string) error {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
 	if len(ids) == 0 {
 		return nil
 	}
 
 	placeholders := make([]string, len(ids))
-	params := make([]interface{}, len(ids))
+	params := make([]interface{}, 0, len(ids)*len(b.idColumns))
 
 	for i, id := range ids {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		params[i] = id
+		if err := b.validateID(id); err != nil {
+			return err
+		}
+
+		// Composite keys compare row values: (a, b) IN (($1, $2), ...)
+		group := make([]string, len(b.idColumns))
+		for j := range group {
+			group[j] = fmt.Sprintf("$%d", len(params)+j+1)
+		}
+		params = append(params, b.idArgs(id)...)
+
+		placeholders[i] = strings.Join(group, ", ")
+		if len(group) > 1 {
+			placeholders[i] = "(" + placeholders[i] + ")"
+		}
+	}
+
+	idColumns := strings.Join(b.idColumns, ", ")
+	if len(b.idColumns) > 1 {
+		idColumns = "(" + idColumns + ")"
 	}
 
 	query := fmt.Sprintf(
 		"DELETE FROM %s WHERE %s IN (%s)",
 		b.tableName,
-		b.idField,
+		idColumns,
 		strings.Join(placeholders, ", "),
 	)
 
+	b.logSQL(ctx, storex.SQLStatement{Operation: "bulk_delete", Query: query, Args: params})
 	result, err := b.db.ExecContext(ctx, query, params...)
 	if err != nil {
-		return storex.StoreErrors.NewWithCause(storex.ErrBulkOpFailed, err)
+		return b.queryError(ctx, storex.ErrBulkOpFailed, err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -494,6 +680,170 @@ func (b *PgBulkOperator[T]) BulkDelete(ctx context.Context, ids []string) error
 	return nil
 }
 
+// BulkInsertBestEffort inserts each entity independently, outside a transaction
+func (b *PgBulkOperator[T]) BulkInsertBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		created, err := b.Create(ctx, item)
+		if err != nil {
+			result.Add(i, b.entityID(item), err)
+			continue
+		}
+		result.Add(i, b.entityID(created), nil)
+	}
+
+	return result, nil
+}
+
+// BulkUpdateBestEffort updates each entity independently, outside a transaction
+func (b *PgBulkOperator[T]) BulkUpdateBestEffort(ctx context.Context, items []T) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(items))
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		id := b.entityID(item)
+		if id == "" {
+			result.Add(i, "", storex.StoreErrors.NewWithMessage(storex.ErrInvalidID, "Missing ID for bulk update"))
+			continue
+		}
+
+		_, err := b.Update(ctx, id, item)
+		result.Add(i, id, err)
+	}
+
+	return result, nil
+}
+
+// BulkDeleteBestEffort deletes each ID independently, outside a transaction
+func (b *PgBulkOperator[T]) BulkDeleteBestEffort(ctx context.Context, ids []string) (*storex.BulkResult, error) {
+	result := storex.NewBulkResult(len(ids))
+
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Add(i, id, b.Delete(ctx, id))
+	}
+
+	return result, nil
+}
+
+// entityID returns the ID columns of an entity as a string, or "" when unset.
+// Composite keys are joined with storex.CompositeID.
+func (r *PgRepository[T]) entityID(item T) string {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	values := map[string]string{}
+	for _, col := range dbColumns(v) {
+		if r.isIDColumn(col.name) && !isEmptyValue(col.value) {
+			values[col.name] = fmt.Sprintf("%v", col.value.Interface())
+		}
+	}
+
+	parts := make([]string, len(r.idColumns))
+	for i, column := range r.idColumns {
+		value, ok := values[column]
+		if !ok {
+			return ""
+		}
+		parts[i] = value
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return storex.CompositeID(parts...)
+}
+
+// idValues returns the values of the ID columns in the order of r.idColumns,
+// failing when any of them is missing or empty
+func (r *PgRepository[T]) idValues(columns []dbColumn) ([]any, error) {
+	byName := make(map[string]dbColumn, len(columns))
+	for _, col := range columns {
+		byName[col.name] = col
+	}
+
+	ids := make([]any, len(r.idColumns))
+	for i, column := range r.idColumns {
+		col, ok := byName[column]
+		if !ok || isEmptyValue(col.value) {
+			return nil, storex.StoreErrors.NewWithMessage(storex.ErrInvalidID, "Missing ID for bulk update").
+				WithDetail("column", column)
+		}
+		ids[i] = col.value.Interface()
+	}
+	return ids, nil
+}
+
+// isIDColumn reports whether a column is part of the primary key
+func (r *PgRepository[T]) isIDColumn(name string) bool {
+	for _, column := range r.idColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isGeneratedID reports whether an insert should leave a column to the
+// database: an empty single-column ID. Composite keys are always inserted.
+func (r *PgRepository[T]) isGeneratedID(col dbColumn) bool {
+	return len(r.idColumns) == 1 && col.name == r.idColumns[0] && isEmptyValue(col.value)
+}
+
+// validateID checks that a composite ID has one part per ID column
+func (r *PgRepository[T]) validateID(id string) error {
+	if len(r.idColumns) == 1 {
+		return nil
+	}
+
+	if parts := storex.SplitCompositeID(id); len(parts) != len(r.idColumns) {
+		return storex.StoreErrors.New(storex.ErrInvalidID).
+			WithDetail("id", id).
+			WithDetail("expected_parts", len(r.idColumns)).
+			WithDetail("parts", len(parts))
+	}
+	return nil
+}
+
+// idCondition matches every ID column, with placeholders numbered from start
+func (r *PgRepository[T]) idCondition(start int) string {
+	conditions := make([]string, len(r.idColumns))
+	for i, column := range r.idColumns {
+		conditions[i] = fmt.Sprintf("%s = $%d", column, start+i)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// idArgs returns the values for idCondition
+func (r *PgRepository[T]) idArgs(id string) []any {
+	if len(r.idColumns) == 1 {
+		return []any{id}
+	}
+
+	parts := storex.SplitCompositeID(id)
+	args := make([]any, len(parts))
+	for i, part := range parts {
+		args[i] = part
+	}
+	return args
+}
+
 // PgTxManager provides transaction support for PostgreSQL
 type PgTxManager struct {
 	db *sqlx.DB
@@ -506,7 +856,12 @@ func NewPgTxManager(db *sqlx.DB) *PgTxManager {
 
 // WithTransaction executes operations within a transaction
 func (tm *PgTxManager) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
-	tx, err := tm.db.BeginTxx(ctx, nil)
+	return tm.withTx(ctx, nil, fn)
+}
+
+// withTx runs fn in a transaction started with opts, committing on success
+func (tm *PgTxManager) withTx(ctx context.Context, opts *sql.TxOptions, fn func(txCtx context.Context) error) error {
+	tx, err := tm.db.BeginTxx(ctx, opts)
 	if err != nil {
 		return storex.StoreErrors.NewWithCause(storex.ErrTxBeginFailed, err)
 	}
@@ -546,6 +901,9 @@ func NewPgSearchable[T any](repo *PgRepository[T]) *PgSearchable[T] {
 
 // Search performs a full-text search using PostgreSQL's ts_vector
 func (s *PgSearchable[T]) Search(ctx context.Context, query string, opts storex.SearchOptions) ([]T, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	if len(opts.Fields) == 0 {
 		return nil, storex.StoreErrors.NewWithMessage(storex.ErrInvalidQuery, "No search fields specified")
 	}
@@ -588,10 +946,11 @@ func (s *PgSearchable[T]) Search(ctx context.Context, query string, opts storex.
 		rankClause, s.tableName, whereClause, opts.Limit, opts.Offset,
 	)
 
+	s.logSQL(ctx, storex.SQLStatement{Operation: "search", Query: sqlQuery})
 	var results []T
 	err := s.db.SelectContext(ctx, &results, sqlQuery)
 	if err != nil {
-		return nil, storex.StoreErrors.NewWithCause(storex.ErrSearchFailed, err)
+		return nil, s.queryError(ctx, storex.ErrSearchFailed, err)
 	}
 
 	return results, nil
@@ -698,6 +1057,132 @@ func (cs *PgChangeStream[T]) Watch(ctx context.Context, filter map[string]any) (
 	return events, nil
 }
 
+// WithSQLLogging logs every statement the repository executes.
+// Argument values are masked unless opts.IncludeArgs is set.
+func (r *PgRepository[T]) WithSQLLogging(opts storex.SQLLogOptions) *PgRepository[T] {
+	r.sqlLog = &opts
+	return r
+}
+
+func (r *PgRepository[T]) logSQL(ctx context.Context, stmt storex.SQLStatement) {
+	if r.sqlLog != nil {
+		r.sqlLog.Log(stmt)
+	}
+	storex.LogDebugSQL(ctx, stmt)
+}
+
+// buildEqualityConditions builds "field = $n" conditions with keys sorted for
+// stable SQL. storex.In values become "field IN ($n, ...)"; an empty one matches nothing.
+func buildEqualityConditions(filter map[string]any) ([]string, []any) {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]string, 0, len(keys))
+	values := make([]any, 0, len(keys))
+	for _, k := range keys {
+		in, ok := storex.FilterValues(filter[k])
+		if !ok {
+			values = append(values, filter[k])
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", k, len(values)))
+			continue
+		}
+
+		if len(in) == 0 {
+			conditions = append(conditions, "FALSE")
+			continue
+		}
+
+		placeholders := make([]string, len(in))
+		for i, value := range in {
+			values = append(values, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(values))
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", k, strings.Join(placeholders, ", ")))
+	}
+	return conditions, values
+}
+
+// dbColumn is a struct field mapped to a column through its db tag
+type dbColumn struct {
+	name  string
+	value reflect.Value
+}
+
+// dbColumns returns the db-tagged fields of a struct in declaration order.
+// Untagged anonymous (embedded) structs are flattened so their fields are
+// treated as columns of the parent, matching how sqlx scans them back.
+// Fields of a nil embedded pointer are omitted.
+func dbColumns(v reflect.Value) []dbColumn {
+	t := v.Type()
+	columns := []dbColumn{}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		if tag == "" {
+			if !field.Anonymous || !field.IsExported() {
+				continue
+			}
+
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				columns = append(columns, dbColumns(embedded)...)
+			}
+			continue
+		}
+
+		columns = append(columns, dbColumn{name: tag, value: v.Field(i)})
+	}
+
+	return columns
+}
+
+// dbColumnNames returns the db tags of a struct type like dbColumns, but
+// includes the fields of embedded pointers since it needs no value
+func dbColumnNames(t reflect.Type) []string {
+	names := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		if tag == "" {
+			if !field.Anonymous || !field.IsExported() {
+				continue
+			}
+
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, dbColumnNames(embedded)...)
+			}
+			continue
+		}
+
+		names = append(names, tag)
+	}
+
+	return names
+}
+
 // Helper function to check if a value is empty
 func isEmptyValue(v reflect.Value) bool {
 	switch v.Kind() {
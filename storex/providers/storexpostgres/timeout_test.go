@@ -0,0 +1,122 @@
+package storexpostgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+// slowQuery blocks every statement but transaction control until its
+// context is done, like a query stuck on a lock
+func slowQuery(ctx context.Context, query fakeQuery) (fakeResult, error) {
+	switch query.SQL {
+	case "BEGIN", "COMMIT", "ROLLBACK":
+		return fakeResult{}, nil
+	}
+	<-ctx.Done()
+	return fakeResult{}, ctx.Err()
+}
+
+// repositoryOperations runs each repository query path against repo
+func repositoryOperations(repo *PgRepository[account]) map[string]func(ctx context.Context) error {
+	bulk := NewPgBulkOperator(repo)
+	item := account{Base: Base{ID: "a1"}, Name: "Ada"}
+
+	return map[string]func(ctx context.Context) error{
+		"create": func(ctx context.Context) error {
+			_, err := repo.Create(ctx, item)
+			return err
+		},
+		"find by id": func(ctx context.Context) error {
+			_, err := repo.FindByID(ctx, "a1")
+			return err
+		},
+		"find one": func(ctx context.Context) error {
+			_, err := repo.FindOne(ctx, map[string]any{"name": "Ada"})
+			return err
+		},
+		"update": func(ctx context.Context) error {
+			_, err := repo.Update(ctx, "a1", item)
+			return err
+		},
+		"delete": func(ctx context.Context) error {
+			return repo.Delete(ctx, "a1")
+		},
+		"paginate": func(ctx context.Context) error {
+			_, err := repo.Paginate(ctx, storex.PaginationOptions{Page: 1, PageSize: 10})
+			return err
+		},
+		"bulk insert": func(ctx context.Context) error {
+			return bulk.BulkInsert(ctx, []account{item})
+		},
+		"bulk update": func(ctx context.Context) error {
+			return bulk.BulkUpdate(ctx, []account{item})
+		},
+		"bulk delete": func(ctx context.Context) error {
+			return bulk.BulkDelete(ctx, []string{"a1"})
+		},
+	}
+}
+
+func TestQueryCancellation(t *testing.T) {
+	db, _ := newFakeDB(t, slowQuery)
+	repo := NewPgRepository[account](db, "accounts", "id")
+
+	for name, run := range repositoryOperations(repo) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			done := make(chan error, 1)
+			go func() { done <- run(ctx) }()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("err = %v, want context.Canceled", err)
+				}
+				if storex.IsQueryTimeout(err) {
+					t.Errorf("cancellation reported as a timeout: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("cancelling the context did not abort the query")
+			}
+		})
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	db, _ := newFakeDB(t, slowQuery)
+	repo := NewPgRepository[account](db, "accounts", "id").WithQueryTimeout(20 * time.Millisecond)
+
+	for name, run := range repositoryOperations(repo) {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			err := run(context.Background())
+
+			if !storex.IsQueryTimeout(err) {
+				t.Fatalf("err = %v, want ErrQueryTimeout", err)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("err = %v, want it to wrap context.DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("operation took %v, want it bounded by the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestQueryTimeoutFastQuery(t *testing.T) {
+	db, _ := newFakeDB(t, func(context.Context, fakeQuery) (fakeResult, error) {
+		return fakeResult{Affected: 1}, nil
+	})
+	repo := NewPgRepository[account](db, "accounts", "id").WithQueryTimeout(time.Second)
+
+	if err := repo.Delete(context.Background(), "a1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package storexpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+type contact struct {
+	ID    string `db:"id"`
+	Name  string `db:"name" validate:"required,max=5"`
+	Phone string `db:"phone" validate:"required"`
+}
+
+func TestValidation(t *testing.T) {
+	invalid := contact{ID: "c1", Name: "Too long"}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		wantErr bool
+	}{
+		{name: "enabled rejects without a round-trip", enabled: true, wantErr: true},
+		{name: "disabled writes as before", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, func(context.Context, fakeQuery) (fakeResult, error) {
+				return fakeResult{
+					Columns:  []string{"id", "name", "phone"},
+					Rows:     [][]driver.Value{{"c1", "Too long", ""}},
+					Affected: 1,
+				}, nil
+			})
+			repo := NewPgRepository[contact](db, "contacts", "id")
+			if tt.enabled {
+				repo.WithValidation()
+			}
+
+			_, createErr := repo.Create(context.Background(), invalid)
+			_, updateErr := repo.Update(context.Background(), "c1", invalid)
+
+			for op, err := range map[string]error{"create": createErr, "update": updateErr} {
+				if tt.wantErr && !storex.IsValidationFailed(err) {
+					t.Errorf("%s: err = %v, want ErrValidationFailed", op, err)
+				}
+				if !tt.wantErr && err != nil {
+					t.Errorf("%s: %v", op, err)
+				}
+			}
+			if queries := fake.all(); tt.wantErr && len(queries) != 0 {
+				t.Errorf("queries = %+v, want none", queries)
+			}
+		})
+	}
+}
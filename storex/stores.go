@@ -7,10 +7,11 @@ import (
 
 // Page represents pagination metadata
 type Page struct {
-	Number int `json:"page"`      // Current page number (1-based)
-	Size   int `json:"page_size"` // Number of records per page
-	Total  int `json:"total"`     // Total number of records
-	Pages  int `json:"pages"`     // Total number of pages
+	Number  int  `json:"page"`      // Current page number (1-based)
+	Size    int  `json:"page_size"` // Number of records per page
+	Total   int  `json:"total"`     // Total number of records (0 with SkipCount)
+	Pages   int  `json:"pages"`     // Total number of pages (0 with SkipCount)
+	HasMore bool `json:"has_more"`  // Whether pages follow this one
 }
 
 // Paginated is a generic container for paginated data with metadata
@@ -30,18 +31,39 @@ func NewPaginated[T any](data []T, page, size, total int) Paginated[T] {
 	return Paginated[T]{
 		Data: data,
 		Page: Page{
-			Number: page,
-			Size:   size,
-			Total:  total,
-			Pages:  pages,
+			Number:  page,
+			Size:    size,
+			Total:   total,
+			Pages:   pages,
+			HasMore: page < pages,
 		},
 		Empty: len(data) == 0,
 	}
 }
 
+// NewUncountedPaginated creates a paginated result for a SkipCount query from
+// the rows fetched with opts.FetchLimit: a row beyond the page size means more
+// pages follow, and is dropped
+func NewUncountedPaginated[T any](rows []T, opts PaginationOptions) Paginated[T] {
+	hasMore := opts.PageSize > 0 && len(rows) > opts.PageSize
+	if hasMore {
+		rows = rows[:opts.PageSize]
+	}
+
+	return Paginated[T]{
+		Data: rows,
+		Page: Page{
+			Number:  opts.Page,
+			Size:    opts.PageSize,
+			HasMore: hasMore,
+		},
+		Empty: len(rows) == 0,
+	}
+}
+
 // HasNext returns whether there are more pages after the current one
 func (p Paginated[T]) HasNext() bool {
-	return p.Page.Number < p.Page.Pages
+	return p.Page.HasMore
 }
 
 // HasPrevious returns whether there are pages before the current one
@@ -55,8 +77,14 @@ type PaginationOptions struct {
 	PageSize int            // Number of records per page
 	OrderBy  string         // Field to order by (format depends on database)
 	Desc     bool           // Whether to sort in descending order
+	Sort     []Sort         // Multi-column ordering; takes precedence over OrderBy
 	Filters  map[string]any // Optional filters
 	Fields   []string       // Optional field selection
+
+	// SkipCount skips the query counting all matching records, which can cost
+	// as much as the page itself on large tables. The result then has no Total
+	// or Pages; HasNext comes from fetching one row more than PageSize.
+	SkipCount bool
 }
 
 // DefaultPaginationOptions returns sensible default options
@@ -71,6 +99,52 @@ func DefaultPaginationOptions() PaginationOptions {
 	}
 }
 
+// WithoutCount returns a copy of the options with SkipCount set
+func (o PaginationOptions) WithoutCount() PaginationOptions {
+	o.SkipCount = true
+	return o
+}
+
+// FetchLimit returns how many rows a provider fetches for a page: PageSize,
+// plus one lookahead row with SkipCount
+func (o PaginationOptions) FetchLimit() int {
+	if o.SkipCount {
+		return o.PageSize + 1
+	}
+	return o.PageSize
+}
+
+// WithSort appends a column to the multi-column ordering
+func (o PaginationOptions) WithSort(field string, desc bool) PaginationOptions {
+	o.Sort = append(append([]Sort(nil), o.Sort...), Sort{Field: field, Desc: desc})
+	return o
+}
+
+// SortOrder returns the ordering to apply: Sort, or else OrderBy and Desc,
+// followed by each tiebreaker column not already sorted on, ascending.
+// Providers pass their ID columns as tiebreakers so offset pagination is
+// deterministic and rows are neither repeated nor skipped between pages.
+func (o PaginationOptions) SortOrder(tiebreakers ...string) []Sort {
+	order := append([]Sort(nil), o.Sort...)
+	if len(order) == 0 && o.OrderBy != "" {
+		order = append(order, Sort{Field: o.OrderBy, Desc: o.Desc})
+	}
+
+	for _, column := range tiebreakers {
+		sorted := false
+		for _, s := range order {
+			if s.Field == column {
+				sorted = true
+				break
+			}
+		}
+		if !sorted {
+			order = append(order, Sort{Field: column})
+		}
+	}
+	return order
+}
+
 // WithFilter adds a filter to the pagination options
 func (o PaginationOptions) WithFilter(key string, value any) PaginationOptions {
 	if o.Filters == nil {
@@ -120,6 +194,16 @@ type Repository[T any] interface {
 	Paginate(ctx context.Context, opts PaginationOptions) (Paginated[T], error)
 }
 
+// Upserter creates an entity or, when one with the same ID exists, replaces it
+type Upserter[T any] interface {
+	Upsert(ctx context.Context, item T) (T, error)
+}
+
+// Counter counts the entities matching a filter (all of them for an empty filter)
+type Counter interface {
+	Count(ctx context.Context, filter map[string]any) (int, error)
+}
+
 // BulkOperator provides batch operations for efficiency
 type BulkOperator[T any] interface {
 	// BulkInsert adds multiple entities in a single operation
@@ -132,6 +216,70 @@ type BulkOperator[T any] interface {
 	BulkDelete(ctx context.Context, ids []string) error
 }
 
+// BestEffortBulkOperator provides non-transactional batch operations that apply
+// each item independently, so one bad item doesn't fail the whole batch.
+// Use BulkOperator when the batch must be all-or-nothing.
+type BestEffortBulkOperator[T any] interface {
+	// BulkInsertBestEffort inserts each entity independently
+	BulkInsertBestEffort(ctx context.Context, items []T) (*BulkResult, error)
+
+	// BulkUpdateBestEffort updates each entity independently
+	BulkUpdateBestEffort(ctx context.Context, items []T) (*BulkResult, error)
+
+	// BulkDeleteBestEffort deletes each ID independently
+	BulkDeleteBestEffort(ctx context.Context, ids []string) (*BulkResult, error)
+}
+
+// ItemResult is the outcome of one item in a best-effort bulk operation
+type ItemResult struct {
+	Index int    `json:"index"`        // Position in the input slice
+	ID    string `json:"id,omitempty"` // Entity ID, when known
+	Error error  `json:"-"`            // Nil on success
+}
+
+// Succeeded returns whether the item was applied
+func (r ItemResult) Succeeded() bool {
+	return r.Error == nil
+}
+
+// BulkResult collects per-item outcomes of a best-effort bulk operation
+type BulkResult struct {
+	TotalSucceeded int          `json:"total_succeeded"`
+	TotalFailed    int          `json:"total_failed"`
+	Items          []ItemResult `json:"items"`
+}
+
+// NewBulkResult creates an empty result sized for n items
+func NewBulkResult(n int) *BulkResult {
+	return &BulkResult{Items: make([]ItemResult, 0, n)}
+}
+
+// Add records the outcome of the item at index
+func (r *BulkResult) Add(index int, id string, err error) {
+	r.Items = append(r.Items, ItemResult{Index: index, ID: id, Error: err})
+	if err != nil {
+		r.TotalFailed++
+	} else {
+		r.TotalSucceeded++
+	}
+}
+
+// Failed returns the results of items that were not applied
+func (r *BulkResult) Failed() []ItemResult {
+	failed := make([]ItemResult, 0, r.TotalFailed)
+	for _, item := range r.Items {
+		if !item.Succeeded() {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// HasFailures returns whether any item failed
+func (r *BulkResult) HasFailures() bool {
+	return r.TotalFailed > 0
+}
+
 // TxManager provides transaction support
 type TxManager interface {
 	// WithTransaction executes operations within a transaction
@@ -248,6 +396,7 @@ func (qb *QueryBuilder[T]) ToPaginationOptions() PaginationOptions {
 	if len(qb.sorts) > 0 {
 		opts.OrderBy = qb.sorts[0].Field
 		opts.Desc = qb.sorts[0].Desc
+		opts.Sort = append([]Sort(nil), qb.sorts...)
 	}
 
 	// Add field selection
@@ -0,0 +1,204 @@
+package storex
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldViolation is a single failed rule of a validate tag
+type FieldViolation struct {
+	Field   string `json:"field"`           // Dotted Go field path, e.g. "Address.City"
+	Rule    string `json:"rule"`            // Failed rule: required, min, max, len or oneof
+	Param   string `json:"param,omitempty"` // Rule parameter, e.g. "255" for max=255
+	Message string `json:"message"`
+}
+
+// ValidateStruct checks the `validate:"..."` tags of v, a struct or pointer to
+// one, and returns an ErrValidationFailed error listing every violation, or nil.
+// It covers the rules that catch obviously bad data before a round-trip:
+//
+//   - required: the field is not its zero value (non-empty for slices and maps)
+//   - min=N, max=N, len=N: rune count of strings, length of slices and maps,
+//     or the value of numbers
+//   - oneof=a b c: the value is one of the space-separated options
+//   - omitempty: skip the other rules when the field is zero
+//
+// Nested structs are validated recursively. Other rules, such as email or
+// dive, are left to the database or a full validator.
+func ValidateStruct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	violations := validateFields(value, "")
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.Message
+	}
+	return StoreErrors.NewWithMessage(ErrValidationFailed, "Validation failed: "+strings.Join(messages, "; ")).
+		WithDetail("violations", violations)
+}
+
+func validateFields(v reflect.Value, prefix string) []FieldViolation {
+	var violations []FieldViolation
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + field.Name
+		value := v.Field(i)
+
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			violations = append(violations, validateField(name, value, tag)...)
+		}
+
+		nested := value
+		for nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct {
+			violations = append(violations, validateFields(nested, name+".")...)
+		}
+	}
+
+	return violations
+}
+
+func validateField(name string, value reflect.Value, tag string) []FieldViolation {
+	rules := strings.Split(tag, ",")
+	for _, rule := range rules {
+		if rule == "omitempty" && value.IsZero() {
+			return nil
+		}
+	}
+
+	var violations []FieldViolation
+	for _, rule := range rules {
+		rule, param, _ := strings.Cut(rule, "=")
+		if message, ok := checkRule(value, rule, param); !ok {
+			violations = append(violations, FieldViolation{
+				Field:   name,
+				Rule:    rule,
+				Param:   param,
+				Message: name + " " + message,
+			})
+		}
+	}
+	return violations
+}
+
+// checkRule reports whether value satisfies rule, with a message when it doesn't.
+// Unknown rules and rules that don't apply to the value's kind pass.
+func checkRule(value reflect.Value, rule, param string) (string, bool) {
+	switch rule {
+	case "required":
+		if isBlank(value) {
+			return "is required", false
+		}
+
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", true
+		}
+		size, unit, ok := measure(value)
+		if !ok {
+			return "", true
+		}
+		switch {
+		case rule == "min" && size < limit:
+			return fmt.Sprintf("must be at least %s%s", param, unit), false
+		case rule == "max" && size > limit:
+			return fmt.Sprintf("must be at most %s%s", param, unit), false
+		case rule == "len" && size != limit:
+			return fmt.Sprintf("must be exactly %s%s", param, unit), false
+		}
+
+	case "oneof":
+		actual, ok := scalarString(value)
+		if !ok {
+			return "", true
+		}
+		options := strings.Fields(param)
+		for _, option := range options {
+			if actual == option {
+				return "", true
+			}
+		}
+		return "must be one of " + strings.Join(options, ", "), false
+	}
+
+	return "", true
+}
+
+// isBlank reports whether a required field is missing
+func isBlank(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// measure returns the size min, max and len compare against, with the unit
+// used in messages
+func measure(v reflect.Value) (float64, string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, "", false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// scalarString formats strings and numbers for oneof comparisons
+func scalarString(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+	return "", false
+}
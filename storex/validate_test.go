@@ -0,0 +1,138 @@
+package storex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/craftable/errx"
+	"github.com/Abraxas-365/craftable/storex"
+)
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type customer struct {
+	Name    string   `validate:"required,max=10"`
+	Email   string   `validate:"omitempty,max=20"`
+	Status  string   `validate:"oneof=active blocked"`
+	Tags    []string `validate:"required,max=2"`
+	Age     int      `validate:"min=18"`
+	Address *address
+	notes   string `validate:"required"`
+}
+
+func validCustomer() customer {
+	return customer{
+		Name:    "Ada",
+		Status:  "active",
+		Tags:    []string{"vip"},
+		Age:     36,
+		Address: &address{City: "Lima"},
+	}
+}
+
+func TestValidateStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *customer)
+		want   []storex.FieldViolation
+	}{
+		{
+			name:   "valid",
+			modify: func(c *customer) {},
+		},
+		{
+			name: "required and max length",
+			modify: func(c *customer) {
+				c.Name = "Ada Lovelace King"
+				c.Tags = nil
+			},
+			want: []storex.FieldViolation{
+				{Field: "Name", Rule: "max", Param: "10", Message: "Name must be at most 10 characters"},
+				{Field: "Tags", Rule: "required", Message: "Tags is required"},
+			},
+		},
+		{
+			name:   "max length counts runes",
+			modify: func(c *customer) { c.Name = "ñañañañaña" },
+		},
+		{
+			name:   "omitempty skips empty fields only",
+			modify: func(c *customer) { c.Email = "a-very-long-address@example.com" },
+			want: []storex.FieldViolation{
+				{Field: "Email", Rule: "max", Param: "20", Message: "Email must be at most 20 characters"},
+			},
+		},
+		{
+			name: "enum, minimum and item count",
+			modify: func(c *customer) {
+				c.Status = "deleted"
+				c.Age = 17
+				c.Tags = []string{"a", "b", "c"}
+			},
+			want: []storex.FieldViolation{
+				{Field: "Status", Rule: "oneof", Param: "active blocked", Message: "Status must be one of active, blocked"},
+				{Field: "Tags", Rule: "max", Param: "2", Message: "Tags must be at most 2 items"},
+				{Field: "Age", Rule: "min", Param: "18", Message: "Age must be at least 18"},
+			},
+		},
+		{
+			name:   "nested struct",
+			modify: func(c *customer) { c.Address.City = "" },
+			want: []storex.FieldViolation{
+				{Field: "Address.City", Rule: "required", Message: "Address.City is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validCustomer()
+			tt.modify(&c)
+
+			err := storex.ValidateStruct(&c)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateStruct: %v", err)
+				}
+				return
+			}
+
+			if !storex.IsValidationFailed(err) || !errx.IsType(err, errx.TypeValidation) {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			var xerr *errx.Error
+			if !errors.As(err, &xerr) {
+				t.Fatalf("err = %T, want *errx.Error", err)
+			}
+			if got := xerr.Details["violations"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStructMessage(t *testing.T) {
+	c := validCustomer()
+	c.Name = ""
+	c.Status = "deleted"
+
+	err := storex.ValidateStruct(c)
+	var xerr *errx.Error
+	if !errors.As(err, &xerr) {
+		t.Fatalf("err = %v, want *errx.Error", err)
+	}
+	if want := "Validation failed: Name is required; Status must be one of active, blocked"; xerr.Message != want {
+		t.Errorf("message = %q, want %q", xerr.Message, want)
+	}
+}
+
+func TestValidateStructNonStruct(t *testing.T) {
+	for _, v := range []any{nil, (*customer)(nil), "text", 42} {
+		if err := storex.ValidateStruct(v); err != nil {
+			t.Errorf("ValidateStruct(%#v) = %v, want nil", v, err)
+		}
+	}
+}